@@ -64,7 +64,7 @@ func PrepareServices(ctx context.Context, opts *options) (*supervisor.Supervisor
 	if err != nil {
 		return nil, fmt.Errorf(`ethereum config error: %w`, err)
 	}
-	gof, err := opts.Config.Gofer.ConfigureGofer(cli, log, opts.GoferNoRPC)
+	gof, err := opts.Config.Gofer.ConfigureGofer(ctx, cli, log, opts.GoferNoRPC)
 	if err != nil {
 		return nil, fmt.Errorf(`gofer config error: %w`, err)
 	}
@@ -19,6 +19,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/logrus/flag"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/marshal"
 )
 
 func NewRootCommand(opts *options) *cobra.Command {
@@ -49,6 +50,12 @@ with aggregates that increase reliability in the DeFi environment.`,
 		"f",
 		"output format",
 	)
+	rootCmd.PersistentFlags().IntVar(
+		&opts.Precision,
+		"precision",
+		marshal.DefaultPrecision,
+		"number of decimal places to which prices are rounded, negative value disables rounding",
+	)
 	rootCmd.PersistentFlags().BoolVar(
 		&opts.NoRPC,
 		"norpc",
@@ -16,7 +16,6 @@
 package main
 
 import (
-	"context"
 	"os"
 	"os/signal"
 
@@ -29,8 +28,8 @@ func NewAgentCmd(opts *options) *cobra.Command {
 		Args:  cobra.NoArgs,
 		Short: "Start an RPC server",
 		Long:  `Start an RPC server.`,
-		RunE: func(_ *cobra.Command, args []string) error {
-			ctx, _ := signal.NotifyContext(context.Background(), os.Interrupt)
+		RunE: func(c *cobra.Command, args []string) error {
+			ctx, _ := signal.NotifyContext(c.Context(), os.Interrupt)
 			sup, err := PrepareAgentServices(ctx, opts)
 			if err != nil {
 				return err
@@ -0,0 +1,73 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
+	oracleGeth "github.com/chronicleprotocol/oracle-suite/pkg/price/oracle/geth"
+)
+
+func NewOracleCmd(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "oracle",
+		Args:  cobra.NoArgs,
+		Short: "Commands related to the Oracle contracts",
+		Long:  `Commands related to the Oracle contracts.`,
+	}
+	cmd.AddCommand(NewOracleStatusCmd(opts))
+	return cmd
+}
+
+func NewOracleStatusCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status CONTRACT",
+		Args:  cobra.ExactArgs(1),
+		Short: "Print the current state of the Oracle contract",
+		Long:  `Print the current bar, age, price and the list of authorized feeders of the Oracle contract.`,
+		RunE: func(c *cobra.Command, args []string) (err error) {
+			ctx, ctxCancel := signal.NotifyContext(c.Context(), os.Interrupt)
+			defer ctxCancel()
+			cli, mar, err := PrepareOracleServices(opts)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err != nil {
+					exitCode = 1
+					_ = mar.Write(os.Stderr, err)
+				}
+				_ = mar.Flush()
+				// Set err to nil because error was already handled by marshaller.
+				err = nil
+			}()
+			if !ethereum.IsHexAddress(args[0]) {
+				return errors.New("invalid contract address")
+			}
+			status, err := oracle.ReadStatus(ctx, oracleGeth.NewMedian(cli, ethereum.HexToAddress(args[0])))
+			if err != nil {
+				return err
+			}
+			return mar.Write(os.Stdout, status)
+		},
+	}
+}
@@ -0,0 +1,156 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+	loggerConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/logger"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/origins"
+)
+
+func NewOriginsCmd(opts *options) *cobra.Command {
+	var check bool
+	var timings bool
+	var concurrency int
+	var timeout time.Duration
+	cmd := &cobra.Command{
+		Use:     "origins",
+		Aliases: []string{"origin"},
+		Args:    cobra.ExactArgs(0),
+		Short:   "List origins used by price models",
+		Long: `List origins used by price models together with pairs fetched from them,
+in the format selected by the --format flag.
+
+With the --check flag, every origin is queried once for all of its pairs and
+the result is reported for each of them in the format selected by the
+--format flag. The command exits with a non-zero exit code if any of the
+origins fails.
+
+With the --timings flag, every origin is queried once for all of its pairs and
+the latency of the HTTP requests sent to it is reported in the format
+selected by the --format flag. Responses are not cached while timings are
+measured. The --check and --timings flags cannot be used together.`,
+		RunE: func(c *cobra.Command, _ []string) error {
+			if check && timings {
+				return errors.New("the --check and --timings flags cannot be used together")
+			}
+			if err := config.ParseFile(&opts.Config, opts.ConfigFilePath); err != nil {
+				return fmt.Errorf(`config error: %w`, err)
+			}
+			if !check && !timings {
+				pairs, err := opts.Config.Gofer.OriginPairs()
+				if err != nil {
+					return fmt.Errorf(`gofer config error: %w`, err)
+				}
+				return printOriginPairs(opts, pairs)
+			}
+			log, err := opts.Config.Logger.Configure(loggerConfig.Dependencies{
+				AppName:    "gofer",
+				BaseLogger: opts.Logger(),
+			})
+			if err != nil {
+				return fmt.Errorf(`logger config error: %w`, err)
+			}
+			cli, err := opts.Config.Ethereum.ConfigureEthereumClient(nil, log)
+			if err != nil {
+				return fmt.Errorf(`ethereum config error: %w`, err)
+			}
+			ctx, ctxCancel := signal.NotifyContext(c.Context(), os.Interrupt)
+			defer ctxCancel()
+			if timings {
+				list, err := opts.Config.Gofer.TimeOrigins(ctx, cli, concurrency, timeout)
+				if err != nil {
+					return fmt.Errorf(`gofer config error: %w`, err)
+				}
+				return printTimings(opts, list)
+			}
+			checks, err := opts.Config.Gofer.CheckOrigins(ctx, cli, concurrency, timeout)
+			if err != nil {
+				return fmt.Errorf(`gofer config error: %w`, err)
+			}
+			return printChecks(opts, checks)
+		},
+	}
+	cmd.Flags().BoolVar(&check, "check", false, "query every origin and report whether it returns valid prices")
+	cmd.Flags().BoolVar(&timings, "timings", false, "query every origin and report the latency of its HTTP requests")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 5, "maximum number of origins queried at the same time")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "time limit for a single origin to respond")
+	return cmd
+}
+
+// printOriginPairs writes pairs fetched from each origin, sorted by origin
+// names, using the marshaller for the selected format.
+func printOriginPairs(opts *options, pairs map[string][]origins.Pair) error {
+	mar, err := prepareMarshaller(opts)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(pairs))
+	for name := range pairs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := mar.Write(os.Stdout, &origins.OriginPairs{Origin: name, Pairs: pairs[name]}); err != nil {
+			return err
+		}
+	}
+	return mar.Flush()
+}
+
+// printTimings writes timings using the marshaller for the selected format.
+func printTimings(opts *options, timings []origins.Timing) error {
+	mar, err := prepareMarshaller(opts)
+	if err != nil {
+		return err
+	}
+	for i := range timings {
+		if timings[i].Err != nil {
+			exitCode = 1
+		}
+		if err := mar.Write(os.Stdout, &timings[i]); err != nil {
+			return err
+		}
+	}
+	return mar.Flush()
+}
+
+// printChecks writes results of origin checks using the marshaller for the
+// selected format.
+func printChecks(opts *options, checks []origins.Check) error {
+	mar, err := prepareMarshaller(opts)
+	if err != nil {
+		return err
+	}
+	for i := range checks {
+		if checks[i].Err != nil {
+			exitCode = 1
+		}
+		if err := mar.Write(os.Stdout, &checks[i]); err != nil {
+			return err
+		}
+	}
+	return mar.Flush()
+}
@@ -16,9 +16,10 @@
 package main
 
 import (
-	"context"
+	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -26,14 +27,18 @@ import (
 )
 
 func NewPairsCmd(opts *options) *cobra.Command {
-	return &cobra.Command{
+	var withOrigins bool
+	cmd := &cobra.Command{
 		Use:     "pairs [PAIR...]",
 		Aliases: []string{"pair"},
 		Args:    cobra.MinimumNArgs(0),
 		Short:   "List all supported asset pairs",
-		Long:    `List all supported asset pairs.`,
-		RunE: func(_ *cobra.Command, args []string) (err error) {
-			ctx, ctxCancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		Long: `List all supported asset pairs.
+
+With the --with-origins flag, every pair is listed together with the origins
+used to calculate its price.`,
+		RunE: func(c *cobra.Command, args []string) (err error) {
+			ctx, ctxCancel := signal.NotifyContext(c.Context(), os.Interrupt)
 			sup, gof, mar, _, err := PrepareClientServices(ctx, opts)
 			if err != nil {
 				return err
@@ -56,7 +61,7 @@ func NewPairsCmd(opts *options) *cobra.Command {
 					err = sErr
 				}
 			}()
-			pairs, err := provider.NewPairs(args...)
+			pairs, err := parsePairs(opts, gof, args)
 			if err != nil {
 				return err
 			}
@@ -64,12 +69,39 @@ func NewPairsCmd(opts *options) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			for _, p := range models {
-				if mErr := mar.Write(os.Stdout, p); mErr != nil {
+			for _, pair := range sortPairs(models, pairs) {
+				var item interface{} = models[pair]
+				if withOrigins {
+					item = &provider.PairOrigins{Pair: pair, Origins: models[pair].Origins()}
+				}
+				if mErr := mar.Write(os.Stdout, item); mErr != nil {
 					_ = mar.Write(os.Stderr, mErr)
 				}
 			}
 			return
 		},
 	}
+	cmd.Flags().BoolVar(&withOrigins, "with-origins", false, "list origins used to calculate the price of each pair")
+	return cmd
+}
+
+// parsePairs parses pairs given as command arguments. Pairs may be given
+// in any case, either as "BASE/QUOTE" or as "BASEQUOTE". The latter form is
+// split using pairs supported by the provider, so they are fetched only if
+// needed, and aliases from the config.
+func parsePairs(opts *options, gof provider.Provider, args []string) ([]provider.Pair, error) {
+	aliases, err := provider.NewAliases(opts.Config.Gofer.Aliases)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load aliases: %w", err)
+	}
+	var known []provider.Pair
+	for _, arg := range args {
+		if !strings.Contains(arg, "/") {
+			if known, err = gof.Pairs(); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	return provider.ParsePairs(known, aliases, args...)
 }
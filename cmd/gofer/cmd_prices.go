@@ -16,30 +16,59 @@
 package main
 
 import (
-	"context"
+	"fmt"
 	"os"
 	"os/signal"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/marshal"
+	"github.com/chronicleprotocol/oracle-suite/pkg/supervisor"
 )
 
+//nolint:funlen
 func NewPricesCmd(opts *options) *cobra.Command {
-	return &cobra.Command{
+	var explain bool
+	var server string
+	var minSources int
+	cmd := &cobra.Command{
 		Use:     "prices [PAIR...]",
 		Aliases: []string{"price"},
 		Args:    cobra.MinimumNArgs(0),
 		Short:   "Return prices for given PAIRs",
 		Long:    `Return prices for given PAIRs.`,
 		RunE: func(c *cobra.Command, args []string) (err error) {
-			ctx, ctxCancel := signal.NotifyContext(context.Background(), os.Interrupt)
-			sup, gof, mar, hook, err := PrepareClientServices(ctx, opts)
-			if err != nil {
-				return err
+			if explain {
+				// The trace format renders the whole aggregation tree, including
+				// origin prices and sources included in or excluded from medians.
+				opts.Format.format = marshal.Trace
 			}
-			if err = sup.Start(ctx); err != nil {
-				return err
+			ctx, ctxCancel := signal.NotifyContext(c.Context(), os.Interrupt)
+			var (
+				sup  *supervisor.Supervisor
+				gof  provider.Provider
+				mar  marshal.Marshaller
+				hook provider.PriceHook
+			)
+			if server != "" {
+				// Prices are calculated by the remote agent, so there is
+				// no need to start any services.
+				defer ctxCancel()
+				gof, mar, err = PrepareRemoteClientServices(opts, server)
+				if err != nil {
+					return err
+				}
+			} else {
+				sup, gof, mar, hook, err = PrepareClientServices(ctx, opts)
+				if err != nil {
+					return err
+				}
+				if err = sup.Start(ctx); err != nil {
+					return err
+				}
 			}
 			defer func() {
 				if err != nil {
@@ -50,13 +79,15 @@ func NewPricesCmd(opts *options) *cobra.Command {
 				// Set err to nil because error was already handled by marshaller.
 				err = nil
 			}()
-			defer func() {
-				ctxCancel()
-				if sErr := <-sup.Wait(); err == nil { // Ignore sErr if another error has already occurred.
-					err = sErr
-				}
-			}()
-			pairs, err := provider.NewPairs(args...)
+			if sup != nil {
+				defer func() {
+					ctxCancel()
+					if sErr := <-sup.Wait(); err == nil { // Ignore sErr if another error has already occurred.
+						err = sErr
+					}
+				}()
+			}
+			pairs, err := parsePairs(opts, gof, args)
 			if err != nil {
 				return err
 			}
@@ -64,12 +95,21 @@ func NewPricesCmd(opts *options) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			err = hook.Check(prices)
-			if err != nil {
+			if err = ctx.Err(); err != nil {
+				// Prices fetched after cancellation are incomplete.
 				return err
 			}
-			for _, p := range prices {
-				if mErr := mar.Write(os.Stdout, p); mErr != nil {
+			if hook != nil {
+				err = hook.Check(prices)
+				if err != nil {
+					return err
+				}
+			}
+			if minSources > 0 {
+				checkMinSources(prices, minSources)
+			}
+			for _, pair := range sortPairs(prices, pairs) {
+				if mErr := mar.Write(c.OutOrStdout(), prices[pair]); mErr != nil {
 					_ = mar.Write(os.Stderr, mErr)
 				}
 			}
@@ -83,4 +123,115 @@ func NewPricesCmd(opts *options) *cobra.Command {
 			return
 		},
 	}
+	cmd.Flags().StringVar(
+		&server,
+		"server",
+		"",
+		"URL of a gofer agent, prices are fetched from its JSON-RPC endpoint instead of being calculated locally",
+	)
+	cmd.Flags().BoolVar(
+		&explain,
+		"explain",
+		false,
+		"show how each price was derived (same as --format=trace)",
+	)
+	cmd.Flags().IntVar(
+		&minSources,
+		"min-sources",
+		0,
+		"mark prices calculated from fewer sources as invalid and return a non-zero exit code",
+	)
+	cmd.Flags().StringSliceVar(
+		&opts.Fields,
+		"fields",
+		nil,
+		"comma separated list of price fields to show, e.g. pair,price",
+	)
+	return cmd
+}
+
+// sortPairs returns keys of the given map in a stable order. Requested pairs
+// are returned first, in the order in which they were given, followed by
+// the remaining pairs sorted by their names.
+func sortPairs[T any](m map[provider.Pair]T, requested []provider.Pair) []provider.Pair {
+	var pairs, rest []provider.Pair
+	seen := map[provider.Pair]bool{}
+	for _, p := range requested {
+		if _, ok := m[p]; ok && !seen[p] {
+			seen[p] = true
+			pairs = append(pairs, p)
+		}
+	}
+	for p := range m {
+		if !seen[p] {
+			rest = append(rest, p)
+		}
+	}
+	sort.Slice(rest, func(i, j int) bool {
+		return rest[i].String() < rest[j].String()
+	})
+	return append(pairs, rest...)
+}
+
+// checkMinSources sets an error for prices calculated from fewer than min
+// sources, regardless of the sources required by price models.
+func checkMinSources(prices map[provider.Pair]*provider.Price, min int) {
+	for _, p := range prices {
+		if p.Error != "" {
+			continue
+		}
+		if n := countSources(p); n < min {
+			p.Error = fmt.Sprintf("the price was calculated from %d sources, at least %d are required", n, min)
+		}
+	}
+}
+
+// countSources returns the number of origin prices used to calculate the
+// price. For aggregators which require prices of all their children, e.g.
+// indirect ones, the lowest number of sources among children is returned.
+func countSources(p *provider.Price) int {
+	if p.Error != "" {
+		return 0
+	}
+	if p.Type == "origin" {
+		return 1
+	}
+	n := 0
+	switch p.Parameters["method"] {
+	case "median":
+		// Sources excluded from the median, e.g. because of the maximum
+		// number of sources, are not counted.
+		included := map[string]bool{}
+		for _, name := range strings.Split(p.Parameters["includedSources"], ", ") {
+			included[name] = true
+		}
+		for _, c := range p.Prices {
+			if included[sourceName(c)] {
+				n += countSources(c)
+			}
+		}
+	case "fallback":
+		// Only the selected source is used, other ones have failed.
+		for _, c := range p.Prices {
+			if cn := countSources(c); cn > n {
+				n = cn
+			}
+		}
+	default:
+		for i, c := range p.Prices {
+			if cn := countSources(c); i == 0 || cn < n {
+				n = cn
+			}
+		}
+	}
+	return n
+}
+
+// sourceName returns the name of the source used by the median aggregator
+// in the includedSources parameter.
+func sourceName(p *provider.Price) string {
+	if p.Type == "origin" {
+		return p.Parameters["origin"]
+	}
+	return p.Parameters["method"] + ":" + p.Pair.String()
 }
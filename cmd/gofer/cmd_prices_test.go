@@ -0,0 +1,227 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/marshal"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/mocks"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/rpc"
+)
+
+func TestPricesCmd_Server(t *testing.T) {
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	ts := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	gof := &mocks.Provider{}
+	gof.On("Prices", ab).Return(map[provider.Pair]*provider.Price{
+		ab: {Type: "median", Pair: ab, Price: 1.5, Time: ts},
+	}, nil)
+
+	srv := httptest.NewServer(rpc.NewJSONRPCHandler(gof, null.New()))
+	defer srv.Close()
+
+	tests := []struct {
+		format marshal.FormatType
+		fields []string
+		want   string
+	}{
+		{format: marshal.Plain, want: "A/B 1.50000000\n"},
+		{format: marshal.NDJSON, fields: []string{"pair", "price"}, want: `{"pair":"A/B","price":1.5}` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(formatMap[tt.format], func(t *testing.T) {
+			opts := &options{
+				Format:         formatTypeValue{format: tt.format},
+				Precision:      marshal.DefaultPrecision,
+				ConfigFilePath: "nonexistent.json", // The config file must not be used.
+			}
+			out := &bytes.Buffer{}
+			cmd := NewPricesCmd(opts)
+			cmd.SetOut(out)
+			cmd.SetArgs([]string{"--server", srv.URL, "A/B"})
+			if tt.fields != nil {
+				opts.Fields = tt.fields
+			}
+			require.NoError(t, cmd.Execute())
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestPricesCmd_MinSources(t *testing.T) {
+	defer func() { exitCode = 0 }()
+
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	origin := func(name string, err string) *provider.Price {
+		return &provider.Price{Type: "origin", Pair: ab, Price: 1.5, Error: err, Parameters: map[string]string{"origin": name}}
+	}
+	gof := &mocks.Provider{}
+	gof.On("Prices", ab).Return(map[provider.Pair]*provider.Price{
+		ab: {
+			Type:       "aggregator",
+			Pair:       ab,
+			Price:      1.5,
+			Parameters: map[string]string{"method": "median", "includedSources": "x, y"},
+			Prices:     []*provider.Price{origin("x", ""), origin("y", ""), origin("z", "failed")},
+		},
+	}, nil)
+
+	srv := httptest.NewServer(rpc.NewJSONRPCHandler(gof, null.New()))
+	defer srv.Close()
+
+	tests := []struct {
+		minSources string
+		want       string
+		exitCode   int
+	}{
+		{minSources: "2", want: "A/B 1.50000000\n", exitCode: 0},
+		{minSources: "3", want: "A/B - the price was calculated from 2 sources, at least 3 are required\n", exitCode: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.minSources, func(t *testing.T) {
+			exitCode = 0
+			opts := &options{
+				Format:         formatTypeValue{format: marshal.Plain},
+				Precision:      marshal.DefaultPrecision,
+				ConfigFilePath: "nonexistent.json",
+			}
+			out := &bytes.Buffer{}
+			cmd := NewPricesCmd(opts)
+			cmd.SetOut(out)
+			cmd.SetArgs([]string{"--server", srv.URL, "--min-sources", tt.minSources, "A/B"})
+			require.NoError(t, cmd.Execute())
+			assert.Equal(t, tt.want, out.String())
+			assert.Equal(t, tt.exitCode, exitCode)
+		})
+	}
+}
+
+func TestCountSources(t *testing.T) {
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	bc := provider.Pair{Base: "B", Quote: "C"}
+	origin := func(pair provider.Pair, name string) *provider.Price {
+		return &provider.Price{Type: "origin", Pair: pair, Parameters: map[string]string{"origin": name}}
+	}
+	median := func(pair provider.Pair, included string, prices ...*provider.Price) *provider.Price {
+		return &provider.Price{
+			Type:       "aggregator",
+			Pair:       pair,
+			Parameters: map[string]string{"method": "median", "includedSources": included},
+			Prices:     prices,
+		}
+	}
+
+	// Sources excluded from the median are not counted:
+	abMedian := median(ab, "x, y", origin(ab, "x"), origin(ab, "y"), origin(ab, "z"))
+	assert.Equal(t, 2, countSources(abMedian))
+
+	// Indirect prices are only as good as their weakest part:
+	bcMedian := median(bc, "x, y, z", origin(bc, "x"), origin(bc, "y"), origin(bc, "z"))
+	indirect := &provider.Price{
+		Type:       "aggregator",
+		Parameters: map[string]string{"method": "indirect"},
+		Prices:     []*provider.Price{abMedian, bcMedian},
+	}
+	assert.Equal(t, 2, countSources(indirect))
+
+	// Nested medians:
+	assert.Equal(t, 5, countSources(median(ab, "median:A/B, median:B/C", abMedian, bcMedian)))
+
+	// Failed prices have no sources:
+	indirect.Error = "failed"
+	assert.Equal(t, 0, countSources(indirect))
+}
+
+func TestPricesCmd_Order(t *testing.T) {
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	cd := provider.Pair{Base: "C", Quote: "D"}
+	ef := provider.Pair{Base: "E", Quote: "F"}
+	prices := map[provider.Pair]*provider.Price{
+		ab: {Type: "median", Pair: ab, Price: 1},
+		cd: {Type: "median", Pair: cd, Price: 2},
+		ef: {Type: "median", Pair: ef, Price: 3},
+	}
+	gof := &mocks.Provider{}
+	gof.On("Prices").Return(prices, nil)
+	gof.On("Prices", ef, ab, cd).Return(prices, nil)
+
+	srv := httptest.NewServer(rpc.NewJSONRPCHandler(gof, null.New()))
+	defer srv.Close()
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{args: nil, want: "A/B 1.00000000\nC/D 2.00000000\nE/F 3.00000000\n"},
+		{args: []string{"E/F", "A/B", "C/D"}, want: "E/F 3.00000000\nA/B 1.00000000\nC/D 2.00000000\n"},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, ","), func(t *testing.T) {
+			// Prices are returned as a map, so the order must not depend on
+			// the map iteration order:
+			for i := 0; i < 10; i++ {
+				opts := &options{
+					Format:         formatTypeValue{format: marshal.Plain},
+					Precision:      marshal.DefaultPrecision,
+					ConfigFilePath: "nonexistent.json",
+				}
+				out := &bytes.Buffer{}
+				cmd := NewPricesCmd(opts)
+				cmd.SetOut(out)
+				cmd.SetArgs(append([]string{"--server", srv.URL}, tt.args...))
+				require.NoError(t, cmd.Execute())
+				assert.Equal(t, tt.want, out.String())
+			}
+		})
+	}
+}
+
+func TestSortPairs(t *testing.T) {
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	cd := provider.Pair{Base: "C", Quote: "D"}
+	ef := provider.Pair{Base: "E", Quote: "F"}
+	m := map[provider.Pair]int{ab: 1, cd: 2, ef: 3}
+
+	assert.Equal(t, []provider.Pair{ab, cd, ef}, sortPairs(m, nil))
+	assert.Equal(t, []provider.Pair{cd, ab, ef}, sortPairs(m, []provider.Pair{cd, ab}))
+	assert.Equal(t, []provider.Pair{ef, ab, cd}, sortPairs(m, []provider.Pair{ef, ef, {Base: "X", Quote: "Y"}}))
+}
+
+func TestParsePairs_Aliases(t *testing.T) {
+	btcusd := provider.Pair{Base: "BTC", Quote: "USD"}
+	gof := &mocks.Provider{}
+	gof.On("Pairs").Return([]provider.Pair{btcusd}, nil)
+
+	opts := &options{}
+	opts.Config.Gofer.Aliases = map[string]string{"XBT": "BTC"}
+	pairs, err := parsePairs(opts, gof, []string{"XBTUSD", "btcusd"})
+	require.NoError(t, err)
+	assert.Equal(t, []provider.Pair{{Base: "XBT", Quote: "USD"}, btcusd}, pairs)
+
+	opts.Config.Gofer.Aliases = map[string]string{"XBT": ""}
+	_, err = parsePairs(opts, gof, []string{"XBTUSD"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,54 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+)
+
+func NewValidateCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:     "validate-config",
+		Aliases: []string{"validate"},
+		Args:    cobra.ExactArgs(0),
+		Short:   "Validate the config file",
+		Long: `Validate the config file without making any network calls.
+
+All problems found in price models and origins are printed. The command
+exits with a non-zero exit code if any of them is an error.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if err := config.ParseFile(&opts.Config, opts.ConfigFilePath); err != nil {
+				return fmt.Errorf(`config error: %w`, err)
+			}
+			problems := opts.Config.Gofer.Validate()
+			for _, p := range problems {
+				if p.Fatal {
+					exitCode = 1
+				}
+				fmt.Fprintln(os.Stdout, p.String())
+			}
+			if len(problems) == 0 {
+				fmt.Fprintln(os.Stdout, "config is valid")
+			}
+			return nil
+		},
+	}
+}
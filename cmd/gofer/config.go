@@ -17,11 +17,20 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/marshal"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/reload"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/rpc"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/config"
 	ethereumConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/ethereum"
@@ -31,6 +40,9 @@ import (
 	"github.com/chronicleprotocol/oracle-suite/pkg/sysmon"
 )
 
+// remoteClientTimeout is the timeout for requests sent to a remote agent.
+const remoteClientTimeout = time.Minute
+
 type Config struct {
 	Ethereum ethereumConfig.Ethereum `json:"ethereum"`
 	Gofer    goferConfig.Gofer       `json:"gofer"`
@@ -57,7 +69,7 @@ func PrepareClientServices(
 	if err != nil {
 		return nil, nil, nil, nil, fmt.Errorf(`ethereum config error: %w`, err)
 	}
-	gof, err := opts.Config.Gofer.ConfigureGofer(cli, log, opts.NoRPC)
+	gof, err := opts.Config.Gofer.ConfigureGofer(ctx, cli, log, opts.NoRPC)
 	if err != nil {
 		return nil, nil, nil, nil, fmt.Errorf(`gofer config error: %w`, err)
 	}
@@ -65,9 +77,9 @@ func PrepareClientServices(
 	if err != nil {
 		return nil, nil, nil, nil, fmt.Errorf(`price hook config error: %w`, err)
 	}
-	mar, err := marshal.NewMarshal(opts.Format.format)
+	mar, err := prepareMarshaller(opts)
 	if err != nil {
-		return nil, nil, nil, nil, fmt.Errorf(`invalid format option: %w`, err)
+		return nil, nil, nil, nil, err
 	}
 	sup := supervisor.New(log)
 	if g, ok := gof.(supervisor.Service); ok {
@@ -79,6 +91,57 @@ func PrepareClientServices(
 	return sup, gof, mar, hook, nil
 }
 
+// PrepareRemoteClientServices returns a provider that fetches prices from
+// a remote gofer agent using its JSON-RPC endpoint. The configuration file
+// is not used.
+func PrepareRemoteClientServices(opts *options, server string) (provider.Provider, marshal.Marshaller, error) {
+	gof, err := rpc.NewJSONRPCClient(server, &http.Client{Timeout: remoteClientTimeout})
+	if err != nil {
+		return nil, nil, fmt.Errorf(`invalid server option: %w`, err)
+	}
+	mar, err := prepareMarshaller(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return gof, mar, nil
+}
+
+func prepareMarshaller(opts *options) (marshal.Marshaller, error) {
+	mar, err := marshal.NewMarshal(opts.Format.format)
+	if err != nil {
+		return nil, fmt.Errorf(`invalid format option: %w`, err)
+	}
+	mar.SetPrecision(opts.Precision)
+	if err := mar.SetFields(opts.Fields); err != nil {
+		return nil, fmt.Errorf(`invalid fields option: %w`, err)
+	}
+	return mar, nil
+}
+
+func PrepareOracleServices(opts *options) (ethereum.Client, marshal.Marshaller, error) {
+	err := config.ParseFile(&opts.Config, opts.ConfigFilePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf(`config error: %w`, err)
+	}
+	log, err := opts.Config.Logger.Configure(loggerConfig.Dependencies{
+		AppName:    "gofer",
+		BaseLogger: opts.Logger(),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf(`logger config error: %w`, err)
+	}
+	cli, err := opts.Config.Ethereum.ConfigureEthereumClient(nil, log)
+	if err != nil {
+		return nil, nil, fmt.Errorf(`ethereum config error: %w`, err)
+	}
+	mar, err := marshal.NewMarshal(opts.Format.format)
+	if err != nil {
+		return nil, nil, fmt.Errorf(`invalid format option: %w`, err)
+	}
+	mar.SetPrecision(opts.Precision)
+	return cli, mar, nil
+}
+
 func PrepareAgentServices(ctx context.Context, opts *options) (*supervisor.Supervisor, error) {
 	err := config.ParseFile(&opts.Config, opts.ConfigFilePath)
 	if err != nil {
@@ -95,7 +158,17 @@ func PrepareAgentServices(ctx context.Context, opts *options) (*supervisor.Super
 	if err != nil {
 		return nil, fmt.Errorf(`ethereum config error: %w`, err)
 	}
-	gof, err := opts.Config.Gofer.ConfigureAsyncGofer(cli, log)
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	go func() {
+		<-ctx.Done()
+		signal.Stop(sigCh)
+	}()
+	gof, err := reload.New(reload.Config{
+		Load:    agentProviderLoader(opts, cli, log),
+		Signals: sigCh,
+		Logger:  log,
+	})
 	if err != nil {
 		return nil, fmt.Errorf(`gofer config error: %w`, err)
 	}
@@ -104,9 +177,41 @@ func PrepareAgentServices(ctx context.Context, opts *options) (*supervisor.Super
 		return nil, fmt.Errorf(`gofer config error: %w`, err)
 	}
 	sup := supervisor.New(log)
-	sup.Watch(gof.(supervisor.Service), age, sysmon.New(time.Minute, log))
+	sup.Watch(gof, age, sysmon.New(time.Minute, log))
+	if srv := opts.Config.Gofer.ConfigurePprofServer(); srv != nil {
+		sup.Watch(srv)
+	}
 	if l, ok := log.(supervisor.Service); ok {
 		sup.Watch(l)
 	}
 	return sup, nil
 }
+
+// agentProviderLoader returns a function that creates the async gofer used
+// by the agent. The first call uses the already parsed config, subsequent
+// calls parse the config file again, so price models can be updated without
+// restarting the agent. Only the gofer price models and origins are
+// reloaded, other options, like the RPC listen address, require a restart.
+// A config read from the standard input cannot be reloaded.
+func agentProviderLoader(opts *options, cli ethereum.Client, logger log.Logger) reload.LoadFunc {
+	loaded := false
+	return func(ctx context.Context) (provider.Provider, error) {
+		gofCfg := opts.Config.Gofer
+		if loaded {
+			if opts.ConfigFilePath == config.StdinPath {
+				return nil, errors.New(`config read from the standard input cannot be reloaded`)
+			}
+			var cfg Config
+			if err := config.ParseFile(&cfg, opts.ConfigFilePath); err != nil {
+				return nil, fmt.Errorf(`config error: %w`, err)
+			}
+			gofCfg = cfg.Gofer
+		}
+		gof, err := gofCfg.ConfigureAsyncGofer(ctx, cli, logger)
+		if err != nil {
+			return nil, fmt.Errorf(`gofer config error: %w`, err)
+		}
+		loaded = true
+		return gof, nil
+	}
+}
@@ -16,6 +16,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -37,9 +38,12 @@ func main() {
 		NewPairsCmd(&opts),
 		NewPricesCmd(&opts),
 		NewAgentCmd(&opts),
+		NewOracleCmd(&opts),
+		NewValidateCmd(&opts),
+		NewOriginsCmd(&opts),
 	)
 
-	if err := rootCmd.Execute(); err != nil {
+	if err := rootCmd.ExecuteContext(context.Background()); err != nil {
 		fmt.Printf("Error: %s\n", err)
 		if exitCode == 0 {
 			os.Exit(1)
@@ -28,16 +28,20 @@ type options struct {
 	flag.LoggerFlag
 	ConfigFilePath string
 	Format         formatTypeValue
+	Precision      int
+	Fields         []string
 	Config         Config
 	NoRPC          bool
 	Version        string
 }
 
 var formatMap = map[marshal.FormatType]string{
-	marshal.Plain:  "plain",
-	marshal.Trace:  "trace",
-	marshal.JSON:   "json",
-	marshal.NDJSON: "ndjson",
+	marshal.Plain:      "plain",
+	marshal.PlainTable: "plain:table",
+	marshal.Trace:      "trace",
+	marshal.JSON:       "json",
+	marshal.NDJSON:     "ndjson",
+	marshal.YAML:       "yaml",
 }
 
 // formatTypeValue is a wrapper for the FormatType to allow implement
@@ -69,5 +73,5 @@ func (v *formatTypeValue) Set(s string) error {
 }
 
 func (v *formatTypeValue) Type() string {
-	return "plain|trace|json|ndjson"
+	return "plain|plain:table|trace|json|ndjson|yaml"
 }
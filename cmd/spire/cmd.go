@@ -52,6 +52,7 @@ func NewRootCommand(opts *options) *cobra.Command {
 		NewAgentCmd(opts),
 		NewPullCmd(opts),
 		NewPushCmd(opts),
+		NewExportCmd(opts),
 	)
 
 	return rootCmd
@@ -0,0 +1,79 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+)
+
+type exportOptions struct {
+	Output string
+}
+
+func NewExportCmd(opts *options) *cobra.Command {
+	var exportOpts exportOptions
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Args:  cobra.ExactArgs(0),
+		Short: "Export all prices held by the agent as JSON",
+		Long:  ``,
+		RunE: func(_ *cobra.Command, args []string) (err error) {
+			ctx, ctxCancel := signal.NotifyContext(context.Background(), os.Interrupt)
+			sup, cli, err := PrepareClientServices(ctx, opts)
+			if err != nil {
+				return err
+			}
+			if err = sup.Start(ctx); err != nil {
+				return err
+			}
+			defer func() {
+				ctxCancel()
+				if sErr := <-sup.Wait(); err == nil { // Ignore sErr if another error has already occurred.
+					err = sErr
+				}
+			}()
+			s, err := cli.ExportPrices()
+			if err != nil {
+				return err
+			}
+			out := os.Stdout
+			if exportOpts.Output != "" {
+				out, err = os.Create(exportOpts.Output)
+				if err != nil {
+					return err
+				}
+				defer out.Close()
+			}
+			return json.NewEncoder(out).Encode(s)
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&exportOpts.Output,
+		"output",
+		"o",
+		"",
+		"file to write the snapshot to (default: stdout)",
+	)
+
+	return cmd
+}
@@ -16,18 +16,43 @@
 package config
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/interpolate"
 )
 
+// StdinPath is the config path that makes ParseFile read the config from
+// the standard input.
+const StdinPath = "-"
+
+// RemoteConfigTimeout is the time limit for fetching a config file over
+// HTTP(S).
+const RemoteConfigTimeout = 30 * time.Second
+
+// RemoteConfigMaxSize is the maximum size of a config file fetched over
+// HTTP(S).
+const RemoteConfigMaxSize = 10 * 1024 * 1024
+
 var getEnv = os.LookupEnv
 
+var stdin io.Reader = os.Stdin
+
+var httpClient = &http.Client{Timeout: RemoteConfigTimeout}
+
+var remoteConfigMaxSize int64 = RemoteConfigMaxSize
+
 func LoadFile(fileName string) (b []byte, err error) {
 	f, err := os.Open(fileName)
 	if err != nil {
@@ -47,35 +72,38 @@ func LoadFile(fileName string) (b []byte, err error) {
 
 // ParseFile parses the given YAML config file from the byte slice and assigns
 // decoded values into the out value.
+//
+// The config file may include other files using the "include" key, which
+// contains a list of paths or glob patterns. Relative paths are resolved
+// from the directory of the file that includes them. Included files are
+// deep-merged in the order in which they are listed, with later files
+// overriding keys from earlier ones, and the including file overriding
+// keys from all included files.
+//
+// If the path is equal to StdinPath, the config is read from the standard
+// input. If the path is an HTTP(S) URL, the config is fetched from it, the
+// request must complete within RemoteConfigTimeout and the response must not
+// be larger than RemoteConfigMaxSize.
 func ParseFile(out interface{}, path string) error {
-	p, err := filepath.Abs(path)
+	n, err := yamlLoadFile(path, nil)
 	if err != nil {
 		return err
 	}
-	f, err := os.Open(p)
-	if err != nil {
-		return fmt.Errorf("failed to load JSON config file: %w", err)
-	}
-	defer func() {
-		if errClose := f.Close(); err == nil && errClose != nil {
-			err = errClose
-		}
-	}()
-	b, err := ioutil.ReadAll(f)
-	if err != nil {
-		return fmt.Errorf("failed to load JSON config file: %w", err)
-	}
-	return Parse(out, b)
+	return parseNode(out, n)
 }
 
 // Parse parses the given YAML config from the byte slice and assigns decoded
 // values into the out value.
 func Parse(out interface{}, config []byte) error {
-	n := yaml.Node{}
-	if err := yaml.Unmarshal(config, &n); err != nil {
+	n := &yaml.Node{}
+	if err := yaml.Unmarshal(config, n); err != nil {
 		return fmt.Errorf("failed to parse YAML config: %w", err)
 	}
-	if err := yamlReplaceEnvVars(&n); err != nil {
+	return parseNode(out, n)
+}
+
+func parseNode(out interface{}, n *yaml.Node) error {
+	if err := yamlReplaceEnvVars(n); err != nil {
 		return fmt.Errorf("failed to parse YAML config: %w", err)
 	}
 	if err := n.Decode(out); err != nil {
@@ -84,6 +112,203 @@ func Parse(out interface{}, config []byte) error {
 	return nil
 }
 
+// yamlLoadFile loads the YAML file and all files included by it. The parents
+// argument contains paths of files that include the loaded file, and is used
+// to detect cyclic includes.
+//
+// The path may be a local file path, the StdinPath or an HTTP(S) URL.
+func yamlLoadFile(path string, parents []string) (*yaml.Node, error) {
+	p, err := configSourceID(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, parent := range parents {
+		if parent == p {
+			return nil, fmt.Errorf("cyclic include of the %s config file", path)
+		}
+	}
+	b, err := loadConfigSource(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load JSON config file: %w", err)
+	}
+	n := &yaml.Node{}
+	if err := yaml.Unmarshal(b, n); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML config file %s: %w", path, err)
+	}
+	includes, err := yamlTakeIncludes(n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse YAML config file %s: %w", path, err)
+	}
+	if len(includes) == 0 {
+		return n, nil
+	}
+	var merged *yaml.Node
+	for _, pattern := range includes {
+		files, err := resolveInclude(p, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include pattern %s in config file %s: %w", pattern, path, err)
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("included config file %s does not exist", pattern)
+		}
+		for _, file := range files {
+			in, err := yamlLoadFile(file, append(parents, p))
+			if err != nil {
+				return nil, err
+			}
+			merged = yamlMerge(merged, in)
+		}
+	}
+	return yamlMerge(merged, n), nil
+}
+
+// configSourceID returns a normalized form of the config path that is used
+// to detect cyclic includes. Local paths are converted to absolute paths.
+func configSourceID(path string) (string, error) {
+	if path == StdinPath || isRemotePath(path) {
+		return path, nil
+	}
+	return filepath.Abs(path)
+}
+
+// loadConfigSource reads the config from the local file, the standard input
+// or the HTTP(S) URL.
+func loadConfigSource(path string) ([]byte, error) {
+	switch {
+	case path == StdinPath:
+		b, err := ioutil.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("could not read config from stdin: %w", err)
+		}
+		return b, nil
+	case isRemotePath(path):
+		return loadRemoteFile(path)
+	default:
+		return LoadFile(path)
+	}
+}
+
+// loadRemoteFile fetches the config file from the HTTP(S) URL. The response
+// must not be larger than remoteConfigMaxSize.
+func loadRemoteFile(u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config URL %s: %w", u, err)
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch config from %s: %w", u, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not fetch config from %s: unexpected status code %d", u, res.StatusCode)
+	}
+	if res.ContentLength > remoteConfigMaxSize {
+		return nil, fmt.Errorf("config from %s exceeds the size limit of %d bytes", u, remoteConfigMaxSize)
+	}
+	b, err := ioutil.ReadAll(io.LimitReader(res.Body, remoteConfigMaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch config from %s: %w", u, err)
+	}
+	if int64(len(b)) > remoteConfigMaxSize {
+		return nil, fmt.Errorf("config from %s exceeds the size limit of %d bytes", u, remoteConfigMaxSize)
+	}
+	return b, nil
+}
+
+// resolveInclude returns the list of config paths for the include pattern
+// found in the parent config. For local files, relative patterns are resolved
+// from the directory of the parent file, or from the working directory if
+// the config was read from the standard input. For remote configs, relative
+// paths are resolved against the parent URL; glob patterns are not supported
+// there.
+func resolveInclude(parent, pattern string) ([]string, error) {
+	if isRemotePath(pattern) {
+		return []string{pattern}, nil
+	}
+	if isRemotePath(parent) {
+		base, err := url.Parse(parent)
+		if err != nil {
+			return nil, err
+		}
+		ref, err := url.Parse(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return []string{base.ResolveReference(ref).String()}, nil
+	}
+	if parent == StdinPath {
+		if pattern == StdinPath {
+			return nil, errors.New("the standard input cannot be included")
+		}
+		return filepath.Glob(pattern)
+	}
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(filepath.Dir(parent), pattern)
+	}
+	return filepath.Glob(pattern)
+}
+
+func isRemotePath(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// yamlTakeIncludes removes the "include" key from the top-level mapping of
+// the given YAML document and returns its values.
+func yamlTakeIncludes(n *yaml.Node) ([]string, error) {
+	m := n
+	if m.Kind == yaml.DocumentNode && len(m.Content) > 0 {
+		m = m.Content[0]
+	}
+	if m.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value != "include" {
+			continue
+		}
+		var includes []string
+		if err := m.Content[i+1].Decode(&includes); err != nil {
+			return nil, fmt.Errorf("the include key must contain a list of paths: %w", err)
+		}
+		m.Content = append(m.Content[:i], m.Content[i+2:]...)
+		return includes, nil
+	}
+	return nil, nil
+}
+
+// yamlMerge deep-merges the src node into the dst node. Mappings are merged
+// key by key, all other values from src replace values in dst.
+func yamlMerge(dst, src *yaml.Node) *yaml.Node {
+	if dst == nil || dst.Kind == 0 {
+		return src
+	}
+	if src.Kind == 0 {
+		return dst
+	}
+	if dst.Kind == yaml.DocumentNode && src.Kind == yaml.DocumentNode && len(dst.Content) > 0 && len(src.Content) > 0 {
+		dst.Content[0] = yamlMerge(dst.Content[0], src.Content[0])
+		return dst
+	}
+	if dst.Kind != yaml.MappingNode || src.Kind != yaml.MappingNode {
+		return src
+	}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		merged := false
+		for j := 0; j+1 < len(dst.Content); j += 2 {
+			if dst.Content[j].Value == src.Content[i].Value {
+				dst.Content[j+1] = yamlMerge(dst.Content[j+1], src.Content[i+1])
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			dst.Content = append(dst.Content, src.Content[i], src.Content[i+1])
+		}
+	}
+	return dst
+}
+
 // yamlReplaceEnvVars replaces recursively all environment variables in the
 // given YAML node.
 func yamlReplaceEnvVars(n *yaml.Node) error {
@@ -93,7 +318,7 @@ func yamlReplaceEnvVars(n *yaml.Node) error {
 		if parsed.HasVars() {
 			n.Value = parsed.Interpolate(func(v interpolate.Variable) string {
 				env, ok := getEnv(v.Name)
-				if !ok {
+				if !ok || (env == "" && v.DefaultIfEmpty) {
 					if v.HasDefault {
 						return v.Default
 					}
@@ -17,10 +17,14 @@ package config
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParse(t *testing.T) {
@@ -31,6 +35,9 @@ func TestParse(t *testing.T) {
 		if v == "num" {
 			return "1", true
 		}
+		if v == "empty" {
+			return "", true
+		}
 		return "env:" + v, true
 	}
 	defer func() { getEnv = os.LookupEnv }()
@@ -80,6 +87,21 @@ func TestParse(t *testing.T) {
 			out:    &struct{ Foo string }{},
 			want:   &struct{ Foo string }{Foo: "bar_baz"},
 		},
+		{
+			config: `{"foo": "bar_${nil:-baz}"}`,
+			out:    &struct{ Foo string }{},
+			want:   &struct{ Foo string }{Foo: "bar_baz"},
+		},
+		{
+			config: `{"foo": "bar_${empty-baz}"}`,
+			out:    &struct{ Foo string }{},
+			want:   &struct{ Foo string }{Foo: "bar_"},
+		},
+		{
+			config: `{"foo": "bar_${empty:-baz}"}`,
+			out:    &struct{ Foo string }{},
+			want:   &struct{ Foo string }{Foo: "bar_baz"},
+		},
 		{
 			config:  `{"foo": ["bar_${nil}"]}`,
 			out:     &struct{ Foo []string }{},
@@ -97,3 +119,87 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestParseFile_Include(t *testing.T) {
+	var out struct {
+		Foo    string
+		Nested map[string]string
+		List   []string
+	}
+	require.NoError(t, ParseFile(&out, "./testdata/include/config.json"))
+
+	assert.Equal(t, "config", out.Foo)
+	assert.Equal(t, map[string]string{"a": "config", "b": "pairs/2", "c": "override"}, out.Nested)
+	assert.Equal(t, []string{"pairs/2"}, out.List)
+}
+
+func TestParseFile_IncludeErrors(t *testing.T) {
+	var out map[string]interface{}
+	err := ParseFile(&out, "./testdata/include/missing.json")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does-not-exist.json does not exist")
+
+	err = ParseFile(&out, "./testdata/include/cycle.json")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cyclic include")
+}
+
+func TestParseFile_Stdin(t *testing.T) {
+	stdin = strings.NewReader(`{"foo": "bar", "include": ["./testdata/include/pairs/*.json"]}`)
+	defer func() { stdin = os.Stdin }()
+
+	var out struct {
+		Foo  string
+		List []string
+	}
+	require.NoError(t, ParseFile(&out, StdinPath))
+
+	assert.Equal(t, "bar", out.Foo)
+	assert.Equal(t, []string{"pairs/2"}, out.List)
+}
+
+func TestParseFile_Remote(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/config.json":
+			_, _ = w.Write([]byte(`{"foo": "bar", "include": ["nested/included.json"]}`))
+		case "/nested/included.json":
+			_, _ = w.Write([]byte(`{"foo": "included", "list": ["a"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	var out struct {
+		Foo  string
+		List []string
+	}
+	require.NoError(t, ParseFile(&out, srv.URL+"/config.json"))
+
+	assert.Equal(t, "bar", out.Foo)
+	assert.Equal(t, []string{"a"}, out.List)
+
+	err := ParseFile(&out, srv.URL+"/missing.json")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected status code 404")
+}
+
+func TestParseFile_RemoteSizeLimit(t *testing.T) {
+	remoteConfigMaxSize = 16
+	defer func() { remoteConfigMaxSize = RemoteConfigMaxSize }()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Flushing before writing the whole body makes the server use the
+		// chunked encoding, so the size is not known in advance.
+		_, _ = w.Write([]byte(`{"foo": `))
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte(`"a value longer than the limit"}`))
+	}))
+	defer srv.Close()
+
+	var out map[string]interface{}
+	err := ParseFile(&out, srv.URL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds the size limit")
+}
@@ -74,16 +74,29 @@ var ethClientFactory = func(
 }
 
 type Ethereum struct {
-	From            string      `yaml:"from"`
-	Keystore        string      `yaml:"keystore"`
-	Password        string      `yaml:"password"`
-	RPC             interface{} `yaml:"rpc"`
-	Timeout         int         `yaml:"timeout"`
-	GracefulTimeout int         `yaml:"gracefulTimeout"`
-	MaxBlocksBehind int         `yaml:"maxBlocksBehind"`
+	From            string       `yaml:"from"`
+	Keystore        string       `yaml:"keystore"`
+	Password        string       `yaml:"password"`
+	RPC             interface{}  `yaml:"rpc"`
+	Timeout         int          `yaml:"timeout"`
+	GracefulTimeout int          `yaml:"gracefulTimeout"`
+	MaxBlocksBehind int          `yaml:"maxBlocksBehind"`
+	RemoteSigner    RemoteSigner `yaml:"remoteSigner"`
+}
+
+// RemoteSigner is the configuration of a remote signer, e.g. a service in
+// front of AWS KMS or an HSM. If the URL is set, the keystore is not used
+// and the From address must be the address of the remote key.
+type RemoteSigner struct {
+	URL    string `yaml:"url"`
+	KeyID  string `yaml:"keyID"`
+	Region string `yaml:"region"`
 }
 
 func (c *Ethereum) ConfigureSigner() (ethereum.Signer, error) {
+	if c.RemoteSigner.URL != "" {
+		return c.configureRemoteSigner()
+	}
 	account, err := c.configureAccount()
 	if err != nil {
 		return nil, err
@@ -156,6 +169,20 @@ func (c *Ethereum) configureAccount() (*geth.Account, error) {
 	return account, nil
 }
 
+func (c *Ethereum) configureRemoteSigner() (ethereum.Signer, error) {
+	if !ethereum.IsHexAddress(c.From) {
+		return nil, errors.New("ethereum config: from address must be provided when the remote signer is used")
+	}
+	if c.RemoteSigner.KeyID == "" {
+		return nil, errors.New("ethereum config: keyID must be provided when the remote signer is used")
+	}
+	return geth.NewRemoteSigner(
+		geth.NewHTTPKeyService(c.RemoteSigner.URL, c.RemoteSigner.Region, nil),
+		c.RemoteSigner.KeyID,
+		ethereum.HexToAddress(c.From),
+	), nil
+}
+
 func (c *Ethereum) readAccountPassphrase(path string) (string, error) {
 	if path == "" {
 		return "", nil
@@ -24,6 +24,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/geth"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
 )
@@ -70,6 +72,27 @@ func TestEthereum_ConfigureSigner_WithPassword(t *testing.T) {
 	)
 }
 
+func TestEthereum_ConfigureSigner_RemoteSigner(t *testing.T) {
+	config := Ethereum{
+		From: "0x07a35a1d4b751a818d93aa38e615c0df23064881",
+		RemoteSigner: RemoteSigner{
+			URL:    "https://example.com/sign",
+			KeyID:  "test-key",
+			Region: "eu-west-1",
+		},
+	}
+
+	signer, err := config.ConfigureSigner()
+	require.NoError(t, err)
+	assert.IsType(t, &geth.RemoteSigner{}, signer)
+	assert.Equal(t, ethereum.HexToAddress(config.From), signer.Address())
+
+	// The key ID is required:
+	config.RemoteSigner.KeyID = ""
+	_, err = config.ConfigureSigner()
+	assert.Error(t, err)
+}
+
 func TestEthereum_ConfigureEthereumClient(t *testing.T) {
 	prevEthClientFactory := ethClientFactory
 	defer func() { ethClientFactory = prevEthClientFactory }()
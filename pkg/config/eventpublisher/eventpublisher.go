@@ -28,6 +28,7 @@ import (
 	ethereumConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher"
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher/httpsink"
 	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher/replayer"
 	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher/teleportevm"
 	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher/teleportstarknet"
@@ -43,6 +44,18 @@ var eventPublisherFactory = func(cfg publisher.Config) (*publisher.EventPublishe
 
 type EventPublisher struct {
 	Listeners listeners `yaml:"listeners"`
+	Sinks     sinks     `yaml:"sinks"`
+}
+
+type sinks struct {
+	HTTP []httpSink `yaml:"http"`
+}
+
+type httpSink struct {
+	URL        string `yaml:"url"`
+	Attempts   int    `yaml:"attempts"`
+	RetryDelay int64  `yaml:"retryDelay"`
+	BufferSize int    `yaml:"bufferSize"`
 }
 
 type listeners struct {
@@ -54,8 +67,12 @@ type teleportEVMListener struct {
 	Ethereum           ethereumConfig.Ethereum `yaml:"ethereum"`
 	Interval           int64                   `yaml:"interval"`
 	PrefetchPeriod     int64                   `yaml:"prefetchPeriod"`
+	MinTimestamp       int64                   `yaml:"minTimestamp"`
+	BlockTime          float64                 `yaml:"blockTime"`
+	EstimateBlockTime  bool                    `yaml:"estimateBlockTime"`
 	BlockConfirmations int64                   `yaml:"blockConfirmations"`
 	BlockLimit         int                     `yaml:"blockLimit"`
+	LagThreshold       int                     `yaml:"lagThreshold"`
 	ReplayAfter        []int64                 `yaml:"replayAfter"`
 	Addresses          []types.Address         `yaml:"addresses"`
 }
@@ -91,6 +108,10 @@ func (c *EventPublisher) Configure(d Dependencies) (*publisher.EventPublisher, e
 	if err := c.configureTeleportStarknet(&eps, d.Logger); err != nil {
 		return nil, fmt.Errorf("eventpublisher config: teleport Starknet: %w", err)
 	}
+	var ess []publisher.EventSink
+	if err := c.configureHTTPSinks(&ess, d.Logger); err != nil {
+		return nil, fmt.Errorf("eventpublisher config: HTTP sink: %w", err)
+	}
 	signer := []publisher.EventSigner{teleportevm.NewSigner(d.Signer, []string{
 		teleportevm.TeleportEventType,
 		teleportstarknet.TeleportEventType,
@@ -99,6 +120,7 @@ func (c *EventPublisher) Configure(d Dependencies) (*publisher.EventPublisher, e
 		Providers: eps,
 		Signers:   signer,
 		Transport: d.Transport,
+		Sinks:     ess,
 		Logger:    d.Logger,
 	}
 	ep, err := eventPublisherFactory(cfg)
@@ -126,14 +148,22 @@ func (c *EventPublisher) configureTeleportEVM(lis *[]publisher.EventProvider, lo
 		for i, r := range cfg.ReplayAfter {
 			replayAfter[i] = time.Duration(r) * time.Second
 		}
+		var minTimestamp time.Time
+		if cfg.MinTimestamp > 0 {
+			minTimestamp = time.Unix(cfg.MinTimestamp, 0)
+		}
 		var ep publisher.EventProvider
 		ep, err = teleportevm.New(teleportevm.Config{
 			Client:             client,
 			Addresses:          cfg.Addresses,
 			Interval:           time.Second * time.Duration(interval),
 			PrefetchPeriod:     time.Duration(cfg.PrefetchPeriod) * time.Second,
+			MinTimestamp:       minTimestamp,
+			BlockTime:          time.Duration(cfg.BlockTime * float64(time.Second)),
+			EstimateBlockTime:  cfg.EstimateBlockTime,
 			BlockLimit:         uint64(cfg.BlockLimit),
 			BlockConfirmations: uint64(cfg.BlockConfirmations),
+			LagThreshold:       uint64(cfg.LagThreshold),
 			Logger:             logger,
 		})
 		if err != nil {
@@ -194,6 +224,26 @@ func (c *EventPublisher) configureTeleportStarknet(lis *[]publisher.EventProvide
 	return nil
 }
 
+func (c *EventPublisher) configureHTTPSinks(sinks *[]publisher.EventSink, logger log.Logger) error {
+	for _, cfg := range c.Sinks.HTTP {
+		if _, err := url.Parse(cfg.URL); err != nil {
+			return fmt.Errorf("url is invalid: %w", err)
+		}
+		s, err := httpsink.New(httpsink.Config{
+			URL:        cfg.URL,
+			Attempts:   cfg.Attempts,
+			RetryDelay: time.Duration(cfg.RetryDelay) * time.Second,
+			BufferSize: cfg.BufferSize,
+			Logger:     logger,
+		})
+		if err != nil {
+			return err
+		}
+		*sinks = append(*sinks, s)
+	}
+	return nil
+}
+
 type ethClients map[string]*rpcclient.Client
 
 // configure returns an Ethereum client for given configuration.
@@ -43,9 +43,13 @@ func TestEventPublisher_Configure_Teleport(t *testing.T) {
 		Ethereum:       ethereumConfig.Ethereum{RPC: "https://example.com/"},
 		Interval:       1,
 		PrefetchPeriod: 1,
+		MinTimestamp:   1,
+		BlockTime:      0.25,
 		BlockLimit:     1,
 		ReplayAfter:    []int64{1},
 		Addresses:      []types.Address{types.HexToAddress("0x07a35a1d4b751a818d93aa38e615c0df23064881")},
+	}}}, Sinks: sinks{HTTP: []httpSink{{
+		URL: "https://example.com/webhook",
 	}}}}
 
 	eventPublisherFactory = func(cfg publisher.Config) (*publisher.EventPublisher, error) {
@@ -54,6 +58,7 @@ func TestEventPublisher_Configure_Teleport(t *testing.T) {
 		assert.Equal(t, log, cfg.Logger)
 		assert.Len(t, cfg.Providers, 1)
 		assert.Len(t, cfg.Signers, 1)
+		assert.Len(t, cfg.Sinks, 1)
 		return &publisher.EventPublisher{}, nil
 	}
 
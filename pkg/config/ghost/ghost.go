@@ -16,8 +16,10 @@
 package ghost
 
 import (
+	"fmt"
 	"time"
 
+	ethereumConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ghost"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
@@ -33,6 +35,27 @@ var ghostFactory = func(cfg ghost.Config) (*ghost.Ghost, error) {
 type Ghost struct {
 	Interval int      `yaml:"interval"`
 	Pairs    []string `yaml:"pairs"`
+
+	// Decimals is an optional number of decimals used to encode prices of
+	// specific pairs, if Oracles expect a different scaling than 18 decimals.
+	Decimals map[string]int `yaml:"decimals"`
+
+	// Signers is an optional list of additional accounts used to sign
+	// prices, next to the account from the ethereum section.
+	Signers []Account `yaml:"signers"`
+	// SignerPolicy describes how an account is chosen to sign a price if
+	// multiple accounts are configured: "roundRobin" (default) or "pair".
+	SignerPolicy string `yaml:"signerPolicy"`
+
+	// Domain is an optional network identifier, e.g. "mainnet", for which
+	// prices are signed. It must match the domain configured in relayers.
+	Domain string `yaml:"domain"`
+}
+
+type Account struct {
+	From     string `yaml:"from"`
+	Keystore string `yaml:"keystore"`
+	Password string `yaml:"password"`
 }
 
 type Dependencies struct {
@@ -43,13 +66,38 @@ type Dependencies struct {
 }
 
 func (c *Ghost) Configure(d Dependencies) (*ghost.Ghost, error) {
+	var policy ghost.SignerPolicy
+	switch c.SignerPolicy {
+	case "", "roundRobin":
+		policy = ghost.RoundRobin
+	case "pair":
+		policy = ghost.ByPair
+	default:
+		return nil, fmt.Errorf("ghost config: unknown signer policy: %s", c.SignerPolicy)
+	}
+	var signers []ethereum.Signer
+	for _, a := range c.Signers {
+		acc := ethereumConfig.Ethereum{From: a.From, Keystore: a.Keystore, Password: a.Password}
+		sig, err := acc.ConfigureSigner()
+		if err != nil {
+			return nil, fmt.Errorf("ghost config: unable to load the %s signer: %w", a.From, err)
+		}
+		if sig.Address() == ethereum.EmptyAddress {
+			return nil, fmt.Errorf("ghost config: the from field of a signer must not be empty")
+		}
+		signers = append(signers, sig)
+	}
 	cfg := ghost.Config{
 		PriceProvider: d.Gofer,
 		Signer:        d.Signer,
+		Signers:       signers,
+		SignerPolicy:  policy,
 		Transport:     d.Transport,
 		Logger:        d.Logger,
 		Interval:      time.Second * time.Duration(c.Interval),
 		Pairs:         c.Pairs,
+		Decimals:      c.Decimals,
+		Domain:        c.Domain,
 	}
 	return ghostFactory(cfg)
 }
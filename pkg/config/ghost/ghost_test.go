@@ -43,11 +43,13 @@ func TestGhost_Configure(t *testing.T) {
 	config := Ghost{
 		Interval: interval,
 		Pairs:    pairs,
+		Domain:   "mainnet",
 	}
 
 	ghostFactory = func(cfg ghost.Config) (*ghost.Ghost, error) {
 		assert.Equal(t, time.Duration(interval)*time.Second, cfg.Interval)
 		assert.Equal(t, pairs, cfg.Pairs)
+		assert.Equal(t, "mainnet", cfg.Domain)
 		assert.Equal(t, signer, cfg.Signer)
 		assert.Equal(t, transport, cfg.Transport)
 		assert.Equal(t, logger, cfg.Logger)
@@ -64,3 +66,32 @@ func TestGhost_Configure(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotNil(t, g)
 }
+
+func TestGhost_Configure_Signers(t *testing.T) {
+	prevGhostFactory := ghostFactory
+	defer func() { ghostFactory = prevGhostFactory }()
+
+	config := Ghost{
+		Signers: []Account{{
+			From:     "2d800d93b065ce011af83f316cef9f0d005b0aa4",
+			Keystore: "../ethereum/testdata/keystore",
+			Password: "../ethereum/testdata/2.pass",
+		}},
+		SignerPolicy: "pair",
+	}
+
+	ghostFactory = func(cfg ghost.Config) (*ghost.Ghost, error) {
+		require.Len(t, cfg.Signers, 1)
+		assert.Equal(t, "0x2D800d93B065CE011Af83f316ceF9F0d005B0AA4", cfg.Signers[0].Address().String())
+		assert.Equal(t, ghost.ByPair, cfg.SignerPolicy)
+		return &ghost.Ghost{}, nil
+	}
+
+	_, err := config.Configure(Dependencies{Signer: &ethereumMocks.Signer{}})
+	require.NoError(t, err)
+
+	// Unknown policy:
+	config.SignerPolicy = "foo"
+	_, err = config.Configure(Dependencies{Signer: &ethereumMocks.Signer{}})
+	assert.Error(t, err)
+}
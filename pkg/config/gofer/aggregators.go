@@ -0,0 +1,92 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gofer
+
+import (
+	"fmt"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/graph/nodes"
+)
+
+// AggregatorFactory creates the root node of a price model for the given
+// pair. The params argument is the params section of the price model. The
+// returned node must implement the nodes.Parent interface, sources of the
+// price model are added to it as children.
+type AggregatorFactory func(pair provider.Pair, params yaml.Node) (nodes.Aggregator, error)
+
+var (
+	aggregatorsMu sync.RWMutex
+	aggregators   = map[string]AggregatorFactory{
+		"median":   medianAggregator,
+		"indirect": indirectAggregator,
+		"fallback": fallbackAggregator,
+	}
+)
+
+// RegisterAggregator registers a factory for the given price model method,
+// so it can be used in the priceModels section of the configuration. It
+// returns an error if the method is already registered.
+func RegisterAggregator(method string, factory AggregatorFactory) error {
+	aggregatorsMu.Lock()
+	defer aggregatorsMu.Unlock()
+	if _, ok := aggregators[method]; ok {
+		return fmt.Errorf("aggregator method %s is already registered", method)
+	}
+	aggregators[method] = factory
+	return nil
+}
+
+// aggregatorFactory returns the factory for the given method.
+func aggregatorFactory(method string) (AggregatorFactory, bool) {
+	aggregatorsMu.RLock()
+	defer aggregatorsMu.RUnlock()
+	f, ok := aggregators[method]
+	return f, ok
+}
+
+func medianAggregator(pair provider.Pair, params yaml.Node) (nodes.Aggregator, error) {
+	var p MedianPriceModel
+	if err := params.Decode(&p); err != nil {
+		return nil, err
+	}
+	if p.MinSourceSuccess < 0 || p.MaxSourceSuccess < 0 {
+		return nil, fmt.Errorf("the number of sources for the %s pair must not be negative", pair)
+	}
+	if p.MaxSourceSuccess > 0 && p.MaxSourceSuccess < p.MinSourceSuccess {
+		return nil, fmt.Errorf(
+			"the maximumSuccessfulSources parameter for the %s pair must not be less than minimumSuccessfulSources",
+			pair,
+		)
+	}
+	if p.OutlierThreshold < 0 {
+		return nil, fmt.Errorf("the outlierThreshold parameter for the %s pair must not be negative", pair)
+	}
+	node := nodes.NewMedianAggregatorNode(pair, p.MinSourceSuccess, p.MaxSourceSuccess)
+	node.SetOutlierThreshold(p.OutlierThreshold)
+	return node, nil
+}
+
+func indirectAggregator(pair provider.Pair, _ yaml.Node) (nodes.Aggregator, error) {
+	return nodes.NewIndirectAggregatorNode(pair), nil
+}
+
+func fallbackAggregator(pair provider.Pair, _ yaml.Node) (nodes.Aggregator, error) {
+	return nodes.NewFallbackAggregatorNode(pair), nil
+}
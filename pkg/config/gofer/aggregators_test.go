@@ -0,0 +1,122 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gofer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/graph/nodes"
+)
+
+// firstAggregatorNode is a custom aggregator that returns the price of the
+// first child multiplied by a factor.
+type firstAggregatorNode struct {
+	pair     provider.Pair
+	factor   float64
+	children []nodes.Node
+}
+
+func (n *firstAggregatorNode) Children() []nodes.Node   { return n.children }
+func (n *firstAggregatorNode) AddChild(node nodes.Node) { n.children = append(n.children, node) }
+func (n *firstAggregatorNode) Pair() provider.Pair      { return n.pair }
+
+func (n *firstAggregatorNode) Price() nodes.AggregatorPrice {
+	p := n.children[0].(nodes.Origin).Price()
+	p.Price *= n.factor
+	return nodes.AggregatorPrice{
+		PairPrice:    p.PairPrice,
+		OriginPrices: []nodes.OriginPrice{p},
+		Parameters:   map[string]string{"method": "first"},
+	}
+}
+
+func TestRegisterAggregator(t *testing.T) {
+	require.NoError(t, RegisterAggregator("first", func(pair provider.Pair, params yaml.Node) (nodes.Aggregator, error) {
+		var p struct {
+			Factor float64 `yaml:"factor"`
+		}
+		if err := params.Decode(&p); err != nil {
+			return nil, err
+		}
+		return &firstAggregatorNode{pair: pair, factor: p.Factor}, nil
+	}))
+	t.Cleanup(func() {
+		aggregatorsMu.Lock()
+		delete(aggregators, "first")
+		aggregatorsMu.Unlock()
+	})
+
+	// Methods cannot be registered twice:
+	assert.Error(t, RegisterAggregator("first", nil))
+	assert.Error(t, RegisterAggregator("median", nil))
+
+	config := Gofer{
+		PriceModels: map[string]PriceModel{
+			"A/B": {
+				Method:  "first",
+				Sources: [][]Source{{{Origin: "a", Pair: "A/B"}}},
+				Params:  yamlNode(t, `{"factor": 2}`),
+			},
+		},
+	}
+	graphs, err := config.buildGraphs()
+	require.NoError(t, err)
+
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	node, ok := graphs[ab].(*firstAggregatorNode)
+	require.True(t, ok)
+	require.Len(t, node.Children(), 1)
+
+	origin := node.Children()[0].(*nodes.OriginNode)
+	require.NoError(t, origin.Ingest(nodes.OriginPrice{
+		PairPrice: nodes.PairPrice{Pair: ab, Price: 10, Time: time.Now()},
+		Origin:    "a",
+	}))
+	assert.Equal(t, float64(20), graphs[ab].Price().Price)
+}
+
+func TestBuildGraphs_UnknownMethod(t *testing.T) {
+	config := Gofer{
+		PriceModels: map[string]PriceModel{
+			"A/B": {Method: "unknown", Sources: [][]Source{{{Origin: "a", Pair: "A/B"}}}},
+		},
+	}
+	_, err := config.buildGraphs()
+	assert.Error(t, err)
+}
+
+func TestBuildGraphs_FallbackMethod(t *testing.T) {
+	config := Gofer{
+		PriceModels: map[string]PriceModel{
+			"A/B": {
+				Method:  "fallback",
+				Sources: [][]Source{{{Origin: "a", Pair: "A/B"}}, {{Origin: "b", Pair: "A/B"}}},
+			},
+		},
+	}
+	graphs, err := config.buildGraphs()
+	require.NoError(t, err)
+
+	node, ok := graphs[provider.Pair{Base: "A", Quote: "B"}].(*nodes.FallbackAggregatorNode)
+	require.True(t, ok)
+	assert.Len(t, node.Children(), 2)
+}
@@ -0,0 +1,232 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gofer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/graph/nodes"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/origins"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/query"
+)
+
+// ErrCheckTimeout is returned for pairs of an origin that did not respond
+// within the time limit given to CheckOrigins.
+var ErrCheckTimeout = errors.New("origin did not respond in time")
+
+// ErrNoResult is returned for pairs for which an origin responded without
+// a price.
+var ErrNoResult = errors.New("origin did not return a price for the pair")
+
+// CheckOrigins queries every origin used by price models for all pairs they
+// are used for. At most concurrency origins are queried at the same time and
+// each of them must respond within the given timeout. Results are sorted by
+// origin and pair names.
+func (c *Gofer) CheckOrigins(
+	ctx context.Context,
+	cli ethereum.Client,
+	concurrency int,
+	timeout time.Duration,
+) ([]origins.Check, error) {
+	pairs, err := c.OriginPairs()
+	if err != nil {
+		return nil, err
+	}
+	originSet, err := c.buildOrigins(ctx, cli, null.New())
+	if err != nil {
+		return nil, err
+	}
+	return checkOrigins(originSet, pairs, concurrency, timeout), nil
+}
+
+// TimeOrigins works like CheckOrigins, but instead of prices, it returns
+// response times of every origin. Results are sorted by origin names.
+// Responses are never cached, so every latency is measured for a request
+// that was actually sent to the origin.
+func (c *Gofer) TimeOrigins(
+	ctx context.Context,
+	cli ethereum.Client,
+	concurrency int,
+	timeout time.Duration,
+) ([]origins.Timing, error) {
+	pairs, err := c.OriginPairs()
+	if err != nil {
+		return nil, err
+	}
+	uncached := *c
+	uncached.CacheTTL = 0
+	pools := map[string]*query.TimingWorkerPool{}
+	wrap := func(origin string, wp query.WorkerPool) query.WorkerPool {
+		pools[origin] = query.NewTimingWorkerPool(wp)
+		return pools[origin]
+	}
+	originSet, err := uncached.buildOriginsWithPools(ctx, cli, null.New(), wrap)
+	if err != nil {
+		return nil, err
+	}
+	return timeOrigins(originSet, pools, pairs, concurrency, timeout), nil
+}
+
+// OriginPairs returns pairs used by price models grouped by origin names.
+func (c *Gofer) OriginPairs() (map[string][]origins.Pair, error) {
+	graphs, err := c.buildGraphs()
+	if err != nil {
+		return nil, err
+	}
+	var roots []nodes.Node
+	for _, pair := range sortGraphs(graphs) {
+		roots = append(roots, graphs[pair])
+	}
+	return originPairs(roots), nil
+}
+
+// originPairs returns unique pairs used by origin nodes in the given graphs
+// grouped by origin names. Pairs are sorted by their names.
+func originPairs(roots []nodes.Node) map[string][]origins.Pair {
+	seen := map[nodes.OriginPair]bool{}
+	pairs := map[string][]origins.Pair{}
+	nodes.Walk(func(n nodes.Node) {
+		o, ok := n.(nodes.Origin)
+		if !ok || seen[o.OriginPair()] {
+			return
+		}
+		seen[o.OriginPair()] = true
+		op := o.OriginPair()
+		pairs[op.Origin] = append(pairs[op.Origin], origins.Pair{Base: op.Pair.Base, Quote: op.Pair.Quote})
+	}, roots...)
+	for _, p := range pairs {
+		sort.Slice(p, func(i, j int) bool { return p[i].String() < p[j].String() })
+	}
+	return pairs
+}
+
+func checkOrigins(
+	originSet *origins.Set,
+	originPairs map[string][]origins.Pair,
+	concurrency int,
+	timeout time.Duration,
+) []origins.Check {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var checks []origins.Check
+	sem := make(chan struct{}, concurrency)
+	for origin, pairs := range originPairs {
+		origin, pairs := origin, pairs
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			res := checkOrigin(originSet, origin, pairs, timeout)
+			mu.Lock()
+			checks = append(checks, res...)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(checks, func(i, j int) bool {
+		if checks[i].Origin != checks[j].Origin {
+			return checks[i].Origin < checks[j].Origin
+		}
+		return checks[i].Pair.String() < checks[j].Pair.String()
+	})
+	return checks
+}
+
+// checkOrigin fetches all pairs from a single origin. Handlers do not
+// support cancellation, so if the timeout is reached, the fetch is left
+// running in the background and its results are discarded.
+func checkOrigin(originSet *origins.Set, origin string, pairs []origins.Pair, timeout time.Duration) []origins.Check {
+	ch := make(chan map[string][]origins.FetchResult, 1)
+	go func() {
+		ch <- originSet.Fetch(map[string][]origins.Pair{origin: pairs})
+	}()
+
+	var results []origins.FetchResult
+	var err error
+	if timeout > 0 {
+		select {
+		case res := <-ch:
+			results = res[origin]
+		case <-time.After(timeout):
+			err = ErrCheckTimeout
+		}
+	} else {
+		results = (<-ch)[origin]
+	}
+	if err == nil {
+		err = ErrNoResult
+	}
+
+	var checks []origins.Check
+	for _, pair := range pairs {
+		check := origins.Check{Origin: origin, Pair: pair, Err: err}
+		for _, fr := range results {
+			if !fr.Price.Pair.Equal(pair) {
+				continue
+			}
+			check.Price = fr.Price.Price
+			switch {
+			case fr.Error != nil:
+				check.Err = fr.Error
+			case fr.Price.Price <= 0:
+				check.Err = fmt.Errorf("invalid price: %g", fr.Price.Price)
+			default:
+				check.Err = nil
+			}
+			break
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// timeOrigins checks all origins and returns the request latencies recorded
+// by their worker pools.
+func timeOrigins(
+	originSet *origins.Set,
+	pools map[string]*query.TimingWorkerPool,
+	originPairs map[string][]origins.Pair,
+	concurrency int,
+	timeout time.Duration,
+) []origins.Timing {
+	checks := checkOrigins(originSet, originPairs, concurrency, timeout)
+	var timings []origins.Timing
+	for _, check := range checks {
+		if len(timings) == 0 || timings[len(timings)-1].Origin != check.Origin {
+			t := origins.Timing{Origin: check.Origin}
+			if p, ok := pools[check.Origin]; ok {
+				t.Latencies = p.Timings()
+			}
+			timings = append(timings, t)
+		}
+		if t := &timings[len(timings)-1]; t.Err == nil {
+			t.Err = check.Err
+		}
+	}
+	return timings
+}
@@ -0,0 +1,241 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gofer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+	ethereumMocks "github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/graph/nodes"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/origins"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/query"
+)
+
+type slowHandler struct {
+	delay time.Duration
+}
+
+func (h slowHandler) Fetch(pairs []origins.Pair) []origins.FetchResult {
+	time.Sleep(h.delay)
+	return nil
+}
+
+func TestCheckOrigins(t *testing.T) {
+	okPool := query.NewMockWorkerPool()
+	okPool.MockBody(`[{"symbol":"AB","lastPrice":"1.5","bidPrice":"1","askPrice":"2","volume":"1","closeTime":1}]`)
+	failPool := query.NewMockWorkerPool()
+	failPool.MockResp(&query.HTTPResponse{Error: errors.New("connection refused")})
+
+	set := origins.NewSet(map[string]origins.Handler{
+		"ok":   origins.NewBaseExchangeHandler(origins.Binance{WorkerPool: okPool}, nil),
+		"fail": origins.NewBaseExchangeHandler(origins.Binance{WorkerPool: failPool}, nil),
+		"slow": slowHandler{delay: time.Second},
+	})
+	ab := origins.Pair{Base: "A", Quote: "B"}
+	cd := origins.Pair{Base: "C", Quote: "D"}
+
+	checks := checkOrigins(set, map[string][]origins.Pair{
+		"ok":      {ab, cd},
+		"fail":    {ab},
+		"slow":    {ab},
+		"unknown": {ab},
+	}, 2, 100*time.Millisecond)
+
+	require.Len(t, checks, 5)
+	assert.Equal(t, "fail", checks[0].Origin)
+	assert.EqualError(t, checks[0].Err, "connection refused")
+	assert.Equal(t, "ok", checks[1].Origin)
+	assert.Equal(t, ab, checks[1].Pair)
+	assert.NoError(t, checks[1].Err)
+	assert.Equal(t, 1.5, checks[1].Price)
+	assert.Equal(t, "ok", checks[2].Origin)
+	assert.Equal(t, cd, checks[2].Pair)
+	assert.Error(t, checks[2].Err)
+	assert.Equal(t, "slow", checks[3].Origin)
+	assert.ErrorIs(t, checks[3].Err, ErrCheckTimeout)
+	assert.Equal(t, "unknown", checks[4].Origin)
+	assert.ErrorIs(t, checks[4].Err, origins.ErrUnknownOrigin)
+}
+
+// delayedWorkerPool is a mock worker pool that responds after a delay.
+type delayedWorkerPool struct {
+	*query.MockWorkerPool
+	delay time.Duration
+}
+
+func (d delayedWorkerPool) Query(req *query.HTTPRequest) *query.HTTPResponse {
+	time.Sleep(d.delay)
+	return d.MockWorkerPool.Query(req)
+}
+
+func TestTimeOrigins(t *testing.T) {
+	okPool := query.NewMockWorkerPool()
+	okPool.MockBody(`[{"symbol":"AB","lastPrice":"1.5","bidPrice":"1","askPrice":"2","volume":"1","closeTime":1}]`)
+	failPool := query.NewMockWorkerPool()
+	failPool.MockResp(&query.HTTPResponse{Error: errors.New("connection refused")})
+
+	pools := map[string]*query.TimingWorkerPool{
+		"fast": query.NewTimingWorkerPool(delayedWorkerPool{MockWorkerPool: okPool, delay: 10 * time.Millisecond}),
+		"slow": query.NewTimingWorkerPool(delayedWorkerPool{MockWorkerPool: okPool, delay: 100 * time.Millisecond}),
+		"fail": query.NewTimingWorkerPool(delayedWorkerPool{MockWorkerPool: failPool, delay: 10 * time.Millisecond}),
+	}
+	set := origins.NewSet(map[string]origins.Handler{
+		"fast": origins.NewBaseExchangeHandler(origins.Binance{WorkerPool: pools["fast"]}, nil),
+		"slow": origins.NewBaseExchangeHandler(origins.Binance{WorkerPool: pools["slow"]}, nil),
+		"fail": origins.NewBaseExchangeHandler(origins.Binance{WorkerPool: pools["fail"]}, nil),
+	})
+	ab := origins.Pair{Base: "A", Quote: "B"}
+
+	timings := timeOrigins(set, pools, map[string][]origins.Pair{
+		"fast": {ab},
+		"slow": {ab},
+		"fail": {ab},
+	}, 3, time.Second)
+
+	require.Len(t, timings, 3)
+	assert.Equal(t, "fail", timings[0].Origin)
+	assert.EqualError(t, timings[0].Err, "connection refused")
+	assert.Equal(t, "fast", timings[1].Origin)
+	assert.NoError(t, timings[1].Err)
+	assert.Equal(t, "slow", timings[2].Origin)
+	assert.NoError(t, timings[2].Err)
+	for _, timing := range timings {
+		require.Len(t, timing.Latencies, 1)
+	}
+	assert.GreaterOrEqual(t, timings[1].Average(), 10*time.Millisecond)
+	assert.Less(t, timings[1].Average(), 100*time.Millisecond)
+	assert.GreaterOrEqual(t, timings[2].Average(), 100*time.Millisecond)
+	assert.Equal(t, timings[2].Average(), timings[2].Max())
+}
+
+func TestConfig_TimeOrigins_NoCache(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte(`[{"symbol":"AB","lastPrice":"1.5","bidPrice":"1","askPrice":"2","volume":"1","closeTime":1}]`))
+	}))
+	defer srv.Close()
+
+	// Both origins send the same request, so the second one would be served
+	// from the cache if it was used:
+	var cfg Gofer
+	require.NoError(t, config.Parse(&cfg, []byte(`
+cacheTTL: 60
+origins:
+  x: {type: binance, url: "`+srv.URL+`", params: {}}
+  y: {type: binance, url: "`+srv.URL+`", params: {}}
+priceModels:
+  A/B:
+    method: median
+    sources: [[{origin: x, pair: A/B}], [{origin: y, pair: A/B}]]
+    params: {minimumSuccessfulSources: 1}
+`)))
+
+	timings, err := cfg.TimeOrigins(context.Background(), &ethereumMocks.Client{}, 1, time.Second)
+	require.NoError(t, err)
+	require.Len(t, timings, 2)
+	for _, timing := range timings {
+		assert.NoError(t, timing.Err)
+		assert.Len(t, timing.Latencies, 1)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	assert.Equal(t, 60, cfg.CacheTTL)
+}
+
+func TestConfig_originPairs(t *testing.T) {
+	var cfg Gofer
+	require.NoError(t, config.Parse(&cfg, []byte(`
+priceModels:
+  A/B:
+    method: median
+    sources: [[{origin: binance, pair: A/B}], [{origin: kraken, pair: A/B}]]
+    params: {minimumSuccessfulSources: 1}
+  A/C:
+    method: median
+    sources: [[{origin: binance, pair: A/B}, {origin: binance, pair: B/C}]]
+    params: {minimumSuccessfulSources: 1}
+`)))
+	graphs, err := cfg.buildGraphs()
+	require.NoError(t, err)
+	var roots []nodes.Node
+	for _, g := range graphs {
+		roots = append(roots, g)
+	}
+
+	pairs := originPairs(roots)
+	assert.ElementsMatch(t, []origins.Pair{{Base: "A", Quote: "B"}, {Base: "B", Quote: "C"}}, pairs["binance"])
+	assert.ElementsMatch(t, []origins.Pair{{Base: "A", Quote: "B"}}, pairs["kraken"])
+}
+
+func TestConfig_OriginPairs_Order(t *testing.T) {
+	var cfg Gofer
+	require.NoError(t, config.Parse(&cfg, []byte(`
+priceModels:
+  ZRX/USD:
+    method: median
+    sources: [[{origin: binance, pair: ZRX/USD}]]
+    params: {minimumSuccessfulSources: 1}
+  AAVE/ETH:
+    method: median
+    sources: [[{origin: binance, pair: AAVE/ETH}]]
+    params: {minimumSuccessfulSources: 1}
+  AAVE/BTC:
+    method: median
+    sources: [[{origin: binance, pair: AAVE/BTC}], [{origin: kraken, pair: AAVE/BTC}]]
+    params: {minimumSuccessfulSources: 1}
+  ETH/BTC:
+    method: median
+    sources: [[{origin: kraken, pair: ETH/BTC}], [{origin: binance, pair: ETH/BTC}]]
+    params: {minimumSuccessfulSources: 1}
+  A/BC:
+    method: median
+    sources: [[{origin: binance, pair: A/BC}]]
+    params: {minimumSuccessfulSources: 1}
+  AB/C:
+    method: median
+    sources: [[{origin: binance, pair: AB/C}]]
+    params: {minimumSuccessfulSources: 1}
+`)))
+
+	// Pairs must be sorted lexically by their names regardless of the order
+	// in which price models are built:
+	for i := 0; i < 10; i++ {
+		pairs, err := cfg.OriginPairs()
+		require.NoError(t, err)
+		assert.Equal(t, []origins.Pair{
+			{Base: "A", Quote: "BC"},
+			{Base: "AAVE", Quote: "BTC"},
+			{Base: "AAVE", Quote: "ETH"},
+			{Base: "AB", Quote: "C"},
+			{Base: "ETH", Quote: "BTC"},
+			{Base: "ZRX", Quote: "USD"},
+		}, pairs["binance"])
+		assert.Equal(t, []origins.Pair{
+			{Base: "AAVE", Quote: "BTC"},
+			{Base: "ETH", Quote: "BTC"},
+		}, pairs["kraken"])
+	}
+}
@@ -18,6 +18,7 @@ package gofer
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"reflect"
 	"sort"
 	"strings"
@@ -35,12 +36,18 @@ import (
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/rpc"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/httpserver"
+	"github.com/chronicleprotocol/oracle-suite/pkg/httpserver/middleware"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 )
 
 const defaultTTL = 60 * time.Second
 const maxTTL = 240 * time.Second
 
+// pprofReadHeaderTimeout is the ReadHeaderTimeout of the pprof server. Other
+// timeouts are not set because collecting a profile may take a long time.
+const pprofReadHeaderTimeout = 10 * time.Second
+
 type ErrCyclicReference struct {
 	Pair provider.Pair
 	Path []nodes.Node
@@ -48,27 +55,126 @@ type ErrCyclicReference struct {
 
 func (e ErrCyclicReference) Error() string {
 	s := strings.Builder{}
-	s.WriteString(fmt.Sprintf("a cyclic reference was detected for the %s pair: ", e.Path))
+	s.WriteString(fmt.Sprintf("a cyclic reference was detected for the %s pair: ", e.Pair))
 	for i, n := range e.Path {
-		t := reflect.TypeOf(n).String()
-		switch typedNode := n.(type) {
-		case nodes.Aggregator:
-			s.WriteString(fmt.Sprintf("%s(%s)", t, typedNode.Pair()))
-		default:
-			s.WriteString(t)
-		}
+		s.WriteString(nodeName(n))
 		if i != len(e.Path)-1 {
 			s.WriteString(" -> ")
 		}
 	}
+	// The last node in the path refers to one of the previous nodes, which
+	// closes the cycle:
+	if len(e.Path) > 0 {
+		for _, c := range e.Path[len(e.Path)-1].Children() {
+			for _, n := range e.Path {
+				if c == n {
+					s.WriteString(" -> ")
+					s.WriteString(nodeName(n))
+					return s.String()
+				}
+			}
+		}
+	}
 	return s.String()
 }
 
+func nodeName(n nodes.Node) string {
+	t := reflect.TypeOf(n).String()
+	if typedNode, ok := n.(nodes.Aggregator); ok {
+		return fmt.Sprintf("%s(%s)", t, typedNode.Pair())
+	}
+	return t
+}
+
 type Gofer struct {
 	RPC           RPC                   `yaml:"rpc"` // Old configuration format, to remove in the future.
 	RPCListenAddr string                `yaml:"rpcListenAddr"`
 	Origins       map[string]Origin     `yaml:"origins"`
 	PriceModels   map[string]PriceModel `yaml:"priceModels"`
+	CacheTTL      int                   `yaml:"cacheTTL"`
+
+	// Proxy is an URL of a proxy used for HTTP requests to all origins.
+	// It may be overridden for each origin.
+	Proxy string `yaml:"proxy"`
+
+	// QuoteNormalization maps a source quote asset to a target quote asset,
+	// e.g. USDT to USD. Prices from origins quoted in the source asset are
+	// converted using the price model for the source/target pair.
+	QuoteNormalization map[string]string `yaml:"quoteNormalization"`
+
+	// Aliases maps alternative asset symbols to canonical symbols used in
+	// price models, e.g. XBT to BTC. Aliases are resolved for both assets of
+	// requested pairs before a price model is looked up.
+	Aliases map[string]string `yaml:"aliases"`
+
+	// OriginHealth configures temporary exclusion of origins that
+	// repeatedly fail to return prices.
+	OriginHealth OriginHealth `yaml:"originHealth"`
+
+	// ExactArithmetic enables aggregation of prices using arbitrary
+	// precision arithmetic instead of float64.
+	ExactArithmetic bool `yaml:"exactArithmetic"`
+
+	// CORS configures CORS headers for HTTP endpoints served by the agent.
+	// If no origins are configured, CORS headers are not sent, so only
+	// same-origin requests are allowed by browsers.
+	CORS CORS `yaml:"cors"`
+
+	// RateLimit configures per-client rate limiting of HTTP endpoints served
+	// by the agent.
+	RateLimit RateLimit `yaml:"rateLimit"`
+
+	// Pprof configures the HTTP server that exposes runtime profiling data.
+	Pprof Pprof `yaml:"pprof"`
+}
+
+type Pprof struct {
+	// Address is the listen address of the pprof server. It is separate
+	// from the agent's address, so profiling data is not exposed publicly.
+	// If empty, the pprof server is disabled.
+	Address string `yaml:"address"`
+}
+
+type RateLimit struct {
+	// Limit is the maximum number of requests a client may send in a window.
+	// If zero, requests are not limited.
+	Limit int `yaml:"limit"`
+	// Window is the duration of a window in seconds.
+	Window int `yaml:"window"`
+	// Paths defines limits for specific path prefixes, e.g. "/jsonrpc".
+	Paths map[string]RateLimitRule `yaml:"paths"`
+	// APIKeyHeader is the name of the header used to identify clients by
+	// an API key instead of the IP address.
+	APIKeyHeader string `yaml:"apiKeyHeader"`
+	// APIKeys is the list of API keys that identify clients. Clients with
+	// other keys are identified by the IP address.
+	APIKeys []string `yaml:"apiKeys"`
+}
+
+type RateLimitRule struct {
+	Limit  int `yaml:"limit"`
+	Window int `yaml:"window"`
+}
+
+type CORS struct {
+	// Origins is a list of allowed origins, "*" allows all origins.
+	Origins []string `yaml:"origins"`
+	// Methods is a list of allowed methods (default: GET, POST).
+	Methods []string `yaml:"methods"`
+	// Headers is a list of allowed headers (default: Content-Type).
+	Headers []string `yaml:"headers"`
+}
+
+type OriginHealth struct {
+	// FailureThreshold is the number of consecutive failures after which an
+	// origin is skipped. If zero, origins are never skipped.
+	FailureThreshold int `yaml:"failureThreshold"`
+	// Cooldown is the initial time in seconds for which an origin is skipped.
+	// If zero, the default of 60 seconds is used.
+	Cooldown int `yaml:"cooldown"`
+	// MaxCooldown is the maximum time in seconds for which an origin is
+	// skipped.
+	MaxCooldown int `yaml:"maxCooldown"`
 }
 
 type RPC struct {
@@ -76,9 +182,15 @@ type RPC struct {
 }
 
 type Origin struct {
-	Type   string    `yaml:"type"`
-	URL    string    `yaml:"url"` // TODO: Move it to the params field.
-	Params yaml.Node `yaml:"params"`
+	Type    string    `yaml:"type"`
+	URL     string    `yaml:"url"` // TODO: Move it to the params field.
+	Params  yaml.Node `yaml:"params"`
+	Timeout int       `yaml:"timeout"`
+	Proxy   string    `yaml:"proxy"`
+
+	// Headers are added to every HTTP request made to the origin, e.g. to
+	// provide an API key.
+	Headers map[string]string `yaml:"headers"`
 }
 
 type PriceModel struct {
@@ -86,17 +198,52 @@ type PriceModel struct {
 	Sources [][]Source `yaml:"sources"`
 	Params  yaml.Node  `yaml:"params"`
 	TTL     int        `yaml:"ttl"`
+	MaxTTL  int        `yaml:"maxTTL"`
+
+	// ErrorPolicy defines whether errors of sources fail the price. One of
+	// "lenient" (default), "strict" or "all-or-nothing".
+	ErrorPolicy string `yaml:"errorPolicy"`
+
+	// CircuitBreaker is optional. If set, prices that move too much within
+	// a short time are returned with an error and will not be relayed.
+	CircuitBreaker *CircuitBreaker `yaml:"circuitBreaker"`
+}
+
+type CircuitBreaker struct {
+	MaxDeviation float64 `yaml:"maxDeviation"` // in percent
+	Window       int     `yaml:"window"`       // in seconds
 }
 
 type MedianPriceModel struct {
 	MinSourceSuccess int                    `yaml:"minimumSuccessfulSources"`
+	MaxSourceSuccess int                    `yaml:"maximumSuccessfulSources"`
 	PostPriceHook    map[string]interface{} `yaml:"postPriceHook"`
+
+	// OutlierThreshold is the deviation from the median price, in percent,
+	// above which sources are reported as outliers. If zero, outliers are
+	// not reported.
+	OutlierThreshold float64 `yaml:"outlierThreshold"`
 }
 
 type Source struct {
 	Origin string `yaml:"origin"`
 	Pair   string `yaml:"pair"`
 	TTL    int    `yaml:"ttl"`
+	MaxTTL int    `yaml:"maxTTL"`
+
+	// Model is optional. If set, the source is a nested price model, e.g.
+	// a regional median used by a global median. The Origin field must be
+	// empty and the Pair field defaults to the pair of the parent model.
+	Model *PriceModel `yaml:"model"`
+}
+
+// pair returns the pair of the source. For nested price models without
+// the pair field, the pair of the parent model is returned.
+func (s Source) pair(modelPair provider.Pair) (provider.Pair, error) {
+	if s.Model != nil && s.Pair == "" {
+		return modelPair, nil
+	}
+	return provider.NewPair(s.Pair)
 }
 
 // ConfigureRPCAgent returns a new rpc.Agent instance.
@@ -105,11 +252,20 @@ func (c *Gofer) ConfigureRPCAgent(cli ethereum.Client, gof provider.Provider, lo
 	if len(c.RPCListenAddr) != 0 {
 		listenAddr = c.RPCListenAddr
 	}
+	var mws []httpserver.Middleware
+	if len(c.CORS.Origins) > 0 {
+		mws = append(mws, c.CORS.middleware())
+	}
+	if c.RateLimit.Limit > 0 || len(c.RateLimit.Paths) > 0 {
+		mws = append(mws, c.RateLimit.middleware())
+	}
 	srv, err := rpc.NewAgent(rpc.AgentConfig{
-		Provider: gof,
-		Network:  "tcp",
-		Address:  listenAddr,
-		Logger:   logger,
+		Provider:       gof,
+		Network:        "tcp",
+		Address:        listenAddr,
+		Middlewares:    mws,
+		AllowedOrigins: c.CORS.Origins,
+		Logger:         logger,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("unable to initialize RPC agent: %w", err)
@@ -117,8 +273,61 @@ func (c *Gofer) ConfigureRPCAgent(cli ethereum.Client, gof provider.Provider, lo
 	return srv, nil
 }
 
+// ConfigurePprofServer returns a new HTTP server that serves runtime
+// profiling data on the httpserver.PprofPath. If the server is disabled in
+// the config, nil is returned.
+func (c *Gofer) ConfigurePprofServer() *httpserver.HTTPServer {
+	if c.Pprof.Address == "" {
+		return nil
+	}
+	return httpserver.New(&http.Server{
+		Addr:              c.Pprof.Address,
+		Handler:           httpserver.NewPprofHandler(),
+		ReadHeaderTimeout: pprofReadHeaderTimeout,
+	})
+}
+
+// middleware returns the CORS middleware for the configuration.
+func (c CORS) middleware() *middleware.CORS {
+	methods := strings.Join(c.Methods, ", ")
+	if methods == "" {
+		methods = "GET, POST"
+	}
+	headers := strings.Join(c.Headers, ", ")
+	if headers == "" {
+		headers = "Content-Type"
+	}
+	return &middleware.CORS{
+		Origin:  middleware.AllowedOrigins(c.Origins),
+		Methods: func(*http.Request) string { return methods },
+		Headers: func(*http.Request) string { return headers },
+	}
+}
+
+// middleware returns the rate limiting middleware for the configuration.
+func (c RateLimit) middleware() *middleware.RateLimit {
+	paths := make(map[string]middleware.RateLimitRule, len(c.Paths))
+	for p, r := range c.Paths {
+		paths[p] = middleware.RateLimitRule{Limit: r.Limit, Window: time.Duration(r.Window) * time.Second}
+	}
+	return &middleware.RateLimit{
+		Rule:         middleware.RateLimitRule{Limit: c.Limit, Window: time.Duration(c.Window) * time.Second},
+		Paths:        paths,
+		APIKeyHeader: c.APIKeyHeader,
+		APIKeys:      c.APIKeys,
+	}
+}
+
 // ConfigureAsyncGofer returns a new async gofer instance.
-func (c *Gofer) ConfigureAsyncGofer(cli ethereum.Client, logger log.Logger) (provider.Provider, error) {
+func (c *Gofer) ConfigureAsyncGofer(
+	ctx context.Context,
+	cli ethereum.Client,
+	logger log.Logger,
+) (provider.Provider, error) {
+	aliases, err := provider.NewAliases(c.Aliases)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load aliases: %w", err)
+	}
 	gra, err := c.buildGraphs()
 	if err != nil {
 		return nil, fmt.Errorf("unable to load price models: %w", err)
@@ -127,15 +336,17 @@ func (c *Gofer) ConfigureAsyncGofer(cli ethereum.Client, logger log.Logger) (pro
 	for _, n := range gra {
 		ns = append(ns, n)
 	}
-	originSet, err := c.buildOrigins(cli)
+	setOutlierHook(gra, logger)
+	originSet, err := c.buildOrigins(ctx, cli, logger)
 	if err != nil {
 		return nil, err
 	}
-	fed := feeder.NewFeeder(originSet, logger)
+	fed := c.buildFeeder(originSet, logger)
 	gof, err := graph.NewAsyncProvider(gra, fed, ns, logger)
 	if err != nil {
 		return nil, fmt.Errorf("unable to initialize RPC agent: %w", err)
 	}
+	gof.SetAliases(aliases)
 	return gof, nil
 }
 
@@ -158,39 +369,105 @@ func (c *Gofer) ConfigurePriceHook(ctx context.Context, cli ethereum.Client) (pr
 	return provider.NewPostPriceHook(ctx, cli, m)
 }
 
-// ConfigureGofer returns a new async gofer instance.
-func (c *Gofer) ConfigureGofer(cli ethereum.Client, logger log.Logger, noRPC bool) (provider.Provider, error) {
+// ConfigureGofer returns a new async gofer instance. Requests made to origins
+// are canceled once the given context is done.
+func (c *Gofer) ConfigureGofer(
+	ctx context.Context,
+	cli ethereum.Client,
+	logger log.Logger,
+	noRPC bool,
+) (provider.Provider, error) {
 	listenAddr := c.RPC.Address
 	if len(c.RPCListenAddr) != 0 {
 		listenAddr = c.RPCListenAddr
 	}
 	if listenAddr == "" || noRPC {
+		aliases, err := provider.NewAliases(c.Aliases)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load aliases: %w", err)
+		}
 		gra, err := c.buildGraphs()
 		if err != nil {
 			return nil, fmt.Errorf("unable to load price models: %w", err)
 		}
-		originSet, err := c.buildOrigins(cli)
+		setOutlierHook(gra, logger)
+		originSet, err := c.buildOrigins(ctx, cli, logger)
 		if err != nil {
 			return nil, err
 		}
-		fed := feeder.NewFeeder(originSet, logger)
+		fed := c.buildFeeder(originSet, logger)
 		gof := graph.NewProvider(gra, fed)
+		gof.SetAliases(aliases)
 		return gof, nil
 	}
 	return c.configureRPCClient(listenAddr)
 }
 
+// buildFeeder returns a new feeder.Feeder instance.
+func (c *Gofer) buildFeeder(originSet *origins.Set, logger log.Logger) *feeder.Feeder {
+	fed := feeder.NewFeeder(originSet, logger)
+	fed.SetHealthConfig(feeder.HealthConfig{
+		FailureThreshold: c.OriginHealth.FailureThreshold,
+		Cooldown:         time.Duration(c.OriginHealth.Cooldown) * time.Second,
+		MaxCooldown:      time.Duration(c.OriginHealth.MaxCooldown) * time.Second,
+	})
+	return fed
+}
+
 // configureRPCClient returns a new rpc.RPC instance.
 func (c *Gofer) configureRPCClient(listenAddr string) (*rpc.Provider, error) {
 	return rpc.NewProvider("tcp", listenAddr)
 }
 
-func (c *Gofer) buildOrigins(cli ethereum.Client) (*origins.Set, error) {
-	const defaultWorkerCount = 10
-	wp := query.NewHTTPWorkerPool(defaultWorkerCount)
+// buildOrigins returns a new origin set. Streaming origins are connected
+// until the given context is done.
+func (c *Gofer) buildOrigins(ctx context.Context, cli ethereum.Client, logger log.Logger) (*origins.Set, error) {
+	originSet, err := c.buildOriginsWithPools(ctx, cli, logger, nil)
+	if err != nil {
+		return nil, err
+	}
+	originSet.Start(ctx)
+	return originSet, nil
+}
+
+// poolWrapper returns a worker pool used by the given origin, e.g. to
+// measure its response times.
+type poolWrapper func(origin string, wp query.WorkerPool) query.WorkerPool
+
+// buildOriginsWithPools works like buildOrigins, but if wrap is not nil,
+// every origin uses a separate worker pool returned by it.
+func (c *Gofer) buildOriginsWithPools(
+	ctx context.Context,
+	cli ethereum.Client,
+	logger log.Logger,
+	wrap poolWrapper,
+) (*origins.Set, error) {
+	wp, err := c.workerPool(ctx, c.Proxy)
+	if err != nil {
+		return nil, err
+	}
+	pools := map[string]query.WorkerPool{c.Proxy: wp}
 	originSet := origins.DefaultOriginSet(wp)
+	if wrap != nil {
+		for name := range originSet.Handlers() {
+			originSet.SetHandler(name, origins.DefaultOriginSet(wrap(name, wp)).Handlers()[name])
+		}
+	}
 	for name, origin := range c.Origins {
-		handler, err := NewHandler(origin.Type, wp, cli, origin.URL, origin.Params)
+		owp := wp
+		if origin.Proxy != "" {
+			if owp = pools[origin.Proxy]; owp == nil {
+				if owp, err = c.workerPool(ctx, origin.Proxy); err != nil {
+					return nil, fmt.Errorf("failed to initiate origin with name %s due to error: %w", name, err)
+				}
+				pools[origin.Proxy] = owp
+			}
+		}
+		owp = originWorkerPool(owp, origin)
+		if wrap != nil {
+			owp = wrap(name, owp)
+		}
+		handler, err := NewHandler(origin.Type, owp, cli, origin.URL, origin.Params, logger)
 		if err != nil || handler == nil {
 			return nil, fmt.Errorf(
 				"failed to initiate %s origin with name %s due to error: %w", origin.Type, name, err,
@@ -201,6 +478,39 @@ func (c *Gofer) buildOrigins(cli ethereum.Client) (*origins.Set, error) {
 	return originSet, nil
 }
 
+// workerPool returns a new worker pool that sends requests through the given
+// proxy. If the proxy is empty, requests are sent directly. Requests are
+// canceled once the given context is done.
+func (c *Gofer) workerPool(ctx context.Context, proxy string) (query.WorkerPool, error) {
+	const defaultWorkerCount = 10
+	var wp query.WorkerPool
+	if proxy == "" {
+		wp = query.NewHTTPWorkerPool(defaultWorkerCount)
+	} else {
+		pwp, err := query.NewHTTPWorkerPoolWithProxy(defaultWorkerCount, proxy)
+		if err != nil {
+			return nil, err
+		}
+		wp = pwp
+	}
+	if c.CacheTTL > 0 {
+		wp = query.NewCachedWorkerPool(wp, time.Duration(c.CacheTTL)*time.Second)
+	}
+	return query.NewContextWorkerPool(ctx, wp), nil
+}
+
+// originWorkerPool returns a worker pool for the given origin. If the origin
+// has its own HTTP options, the shared worker pool is wrapped to apply them.
+func originWorkerPool(wp query.WorkerPool, origin Origin) query.WorkerPool {
+	if len(origin.Headers) > 0 {
+		wp = query.NewHeaderWorkerPool(wp, origin.Headers)
+	}
+	if origin.Timeout > 0 {
+		wp = query.NewTimeoutWorkerPool(wp, time.Duration(origin.Timeout)*time.Second)
+	}
+	return wp
+}
+
 func (c *Gofer) buildGraphs() (map[provider.Pair]nodes.Aggregator, error) {
 	var err error
 
@@ -218,14 +528,46 @@ func (c *Gofer) buildGraphs() (map[provider.Pair]nodes.Aggregator, error) {
 		return nil, err
 	}
 
+	err = c.buildCircuitBreakers(graphs)
+	if err != nil {
+		return nil, err
+	}
+
 	err = c.detectCycle(graphs)
 	if err != nil {
 		return nil, err
 	}
 
+	if c.ExactArithmetic {
+		var roots []nodes.Node
+		for _, n := range graphs {
+			roots = append(roots, n)
+		}
+		nodes.Walk(func(n nodes.Node) {
+			if e, ok := n.(nodes.ExactArithmetic); ok {
+				e.SetExactArithmetic(true)
+			}
+		}, roots...)
+	}
+
 	return graphs, nil
 }
 
+// setOutlierHook sets the hook which logs and reports as metrics outliers
+// found by aggregators.
+func setOutlierHook(graphs map[provider.Pair]nodes.Aggregator, logger log.Logger) {
+	hook := nodes.NewLoggerOutlierHook(logger)
+	var roots []nodes.Node
+	for _, n := range graphs {
+		roots = append(roots, n)
+	}
+	nodes.Walk(func(n nodes.Node) {
+		if o, ok := n.(nodes.OutlierDetection); ok {
+			o.SetOutlierHook(hook)
+		}
+	}, roots...)
+}
+
 func (c *Gofer) buildRoots(graphs map[provider.Pair]nodes.Aggregator) error {
 	for name, model := range c.PriceModels {
 		modelPair, err := provider.NewPair(name)
@@ -233,18 +575,59 @@ func (c *Gofer) buildRoots(graphs map[provider.Pair]nodes.Aggregator) error {
 			return err
 		}
 
-		switch model.Method {
-		case "median":
-			var params MedianPriceModel
-			if err := model.Params.Decode(&params); err != nil {
-				return err
-			}
-			graphs[modelPair] = nodes.NewMedianAggregatorNode(modelPair, params.MinSourceSuccess)
-		default:
-			return fmt.Errorf("unknown method %s for pair %s", model.Method, name)
+		node, err := aggregatorNode(modelPair, model)
+		if err != nil {
+			return err
 		}
+		graphs[modelPair] = node
+	}
+
+	return nil
+}
+
+// aggregatorNode creates the root node of the given price model. Sources
+// are added by the buildBranch method.
+func aggregatorNode(modelPair provider.Pair, model PriceModel) (nodes.Aggregator, error) {
+	factory, ok := aggregatorFactory(model.Method)
+	if !ok {
+		return nil, fmt.Errorf("unknown method %s for pair %s", model.Method, modelPair)
+	}
+	node, err := factory(modelPair, model.Params)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := nodes.ParseErrorPolicy(model.ErrorPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid errorPolicy for pair %s: %w", modelPair, err)
 	}
+	if e, ok := node.(nodes.ErrorPropagation); ok {
+		e.SetErrorPolicy(policy)
+	} else if policy != nodes.ErrorPolicyLenient {
+		return nil, fmt.Errorf("the %s method for pair %s does not support error policies", model.Method, modelPair)
+	}
+	return node, nil
+}
 
+// buildCircuitBreakers wraps root nodes of price models with the circuit
+// breaker configured. Other price models that refer to these models use
+// the unwrapped nodes.
+func (c *Gofer) buildCircuitBreakers(graphs map[provider.Pair]nodes.Aggregator) error {
+	for name, model := range c.PriceModels {
+		if model.CircuitBreaker == nil {
+			continue
+		}
+		if model.CircuitBreaker.MaxDeviation <= 0 || model.CircuitBreaker.Window <= 0 {
+			return fmt.Errorf("the circuitBreaker parameters for the %s pair must be greater than zero", name)
+		}
+		modelPair, _ := provider.NewPair(name)
+		cb := nodes.NewCircuitBreakerNode(
+			modelPair,
+			model.CircuitBreaker.MaxDeviation,
+			time.Duration(model.CircuitBreaker.Window)*time.Second,
+		)
+		cb.AddChild(graphs[modelPair])
+		graphs[modelPair] = cb
+	}
 	return nil
 }
 
@@ -254,59 +637,228 @@ func (c *Gofer) buildBranches(graphs map[provider.Pair]nodes.Aggregator) error {
 		// in buildRoots method.
 		modelPair, _ := provider.NewPair(name)
 
-		var parent nodes.Parent
-		if typedNode, ok := graphs[modelPair].(nodes.Parent); ok {
-			parent = typedNode
-		} else {
+		if err := c.buildBranch(graphs, modelPair, model, graphs[modelPair]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// buildBranch adds nodes for sources of the given price model to its
+// root node.
+func (c *Gofer) buildBranch(
+	graphs map[provider.Pair]nodes.Aggregator,
+	modelPair provider.Pair,
+	model PriceModel,
+	root nodes.Aggregator,
+) error {
+
+	var parent nodes.Parent
+	if typedNode, ok := root.(nodes.Parent); ok {
+		parent = typedNode
+	} else {
+		return fmt.Errorf(
+			"%s must implement the nodes.Parent interface",
+			reflect.TypeOf(root).Elem().String(),
+		)
+	}
+
+	// The indirect method calculates the cross rate for a single, ordered
+	// list of sources, so they are added directly to the root node.
+	if model.Method == "indirect" {
+		if len(model.Sources) != 1 {
 			return fmt.Errorf(
-				"%s must implement the nodes.Parent interface",
-				reflect.TypeOf(graphs[modelPair]).Elem().String(),
+				"the indirect method for the %s pair requires exactly one list of sources, %d given",
+				modelPair,
+				len(model.Sources),
 			)
 		}
+		if err := c.validateIndirectPath(modelPair, model.Sources[0]); err != nil {
+			return err
+		}
+		children, err := c.sourceNodes(graphs, modelPair, model, model.Sources[0])
+		if err != nil {
+			return err
+		}
+		for _, n := range children {
+			parent.AddChild(n)
+		}
+		return nil
+	}
 
-		for _, sources := range model.Sources {
-			var children []nodes.Node
-			for _, source := range sources {
-				var err error
-				var node nodes.Node
-
-				if source.Origin == "." {
-					node, err = c.reference(graphs, source)
-					if err != nil {
-						return err
-					}
-				} else {
-					node, err = c.originNode(model, source)
-					if err != nil {
-						return err
-					}
-				}
+	for _, sources := range model.Sources {
+		children, err := c.sourceNodes(graphs, modelPair, model, sources)
+		if err != nil {
+			return err
+		}
 
-				children = append(children, node)
+		// If there are provided multiple sources it means, that the price
+		// have to be calculated by using the nodes.IndirectAggregatorNode.
+		// Otherwise, we can pass that nodes.OriginNode directly to
+		// the parent node.
+		var node nodes.Node
+		if len(children) == 1 {
+			node, err = c.normalizeQuote(graphs, modelPair, sources[0], children[0])
+			if err != nil {
+				return err
 			}
-
-			// If there are provided multiple sources it means, that the price
-			// have to be calculated by using the nodes.IndirectAggregatorNode.
-			// Otherwise, we can pass that nodes.OriginNode directly to
-			// the parent node.
-			var node nodes.Node
-			if len(children) == 1 {
-				node = children[0]
-			} else {
-				indirectAggregator := nodes.NewIndirectAggregatorNode(modelPair)
-				for _, c := range children {
-					indirectAggregator.AddChild(c)
-				}
-				node = indirectAggregator
+		} else {
+			indirectAggregator := nodes.NewIndirectAggregatorNode(modelPair)
+			for _, c := range children {
+				indirectAggregator.AddChild(c)
 			}
+			node = indirectAggregator
+		}
+
+		parent.AddChild(node)
+	}
 
-			parent.AddChild(node)
+	return nil
+}
+
+// sourceNodes returns nodes for the given list of sources. Sources referring
+// to another price model are resolved to the root node of that model, and
+// nested price models are built as separate branches.
+func (c *Gofer) sourceNodes(
+	graphs map[provider.Pair]nodes.Aggregator,
+	modelPair provider.Pair,
+	model PriceModel,
+	sources []Source,
+) ([]nodes.Node, error) {
+
+	var children []nodes.Node
+	for _, source := range sources {
+		var err error
+		var node nodes.Node
+
+		if source.Model != nil {
+			node, err = c.nestedModel(graphs, modelPair, model, source)
+			if err != nil {
+				return nil, err
+			}
+		} else if source.Origin == "." {
+			node, err = c.reference(graphs, source)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			node, err = c.originNode(model, source)
+			if err != nil {
+				return nil, err
+			}
 		}
+
+		children = append(children, node)
 	}
+	return children, nil
+}
 
+// normalizeQuote wraps the given source node in a node that converts its
+// price to the quote asset of the model pair, if the source is quoted in
+// a different asset and a normalization is configured for it. Otherwise,
+// the node is returned unchanged.
+func (c *Gofer) normalizeQuote(
+	graphs map[provider.Pair]nodes.Aggregator,
+	modelPair provider.Pair,
+	source Source,
+	node nodes.Node,
+) (nodes.Node, error) {
+
+	sourcePair, err := source.pair(modelPair)
+	if err != nil {
+		return nil, err
+	}
+	if sourcePair.Base != modelPair.Base || sourcePair.Quote == modelPair.Quote {
+		return node, nil
+	}
+	if c.QuoteNormalization[sourcePair.Quote] != modelPair.Quote {
+		return node, nil
+	}
+	convPair := provider.Pair{Base: sourcePair.Quote, Quote: modelPair.Quote}
+	conv, ok := graphs[convPair]
+	if !ok {
+		return nil, fmt.Errorf(
+			"unable to find price model for the %s pair required to normalize the %s source of the %s pair",
+			convPair,
+			sourcePair,
+			modelPair,
+		)
+	}
+	return nodes.NewQuoteNormalizerNode(modelPair, node, conv), nil
+}
+
+// validateIndirectPath checks if the cross rate calculated for the given list
+// of sources resolves to the model pair.
+func (c *Gofer) validateIndirectPath(modelPair provider.Pair, sources []Source) error {
+	var pairs []provider.Pair
+	for _, source := range sources {
+		sourcePair, err := source.pair(modelPair)
+		if err != nil {
+			return err
+		}
+		pairs = append(pairs, sourcePair)
+	}
+	resolvedPair, err := nodes.IndirectPair(pairs...)
+	if err != nil {
+		return fmt.Errorf("invalid indirect path for the %s pair: %w", modelPair, err)
+	}
+	if !resolvedPair.Equal(modelPair) {
+		return fmt.Errorf(
+			"invalid indirect path for the %s pair: %w",
+			modelPair,
+			nodes.ErrResolve{ExpectedPair: modelPair, ResolvedPair: resolvedPair},
+		)
+	}
 	return nil
 }
 
+// nestedModel builds the price model defined in the given source. The nested
+// model inherits the TTL settings of its parent model, unless it defines its
+// own ones.
+func (c *Gofer) nestedModel(
+	graphs map[provider.Pair]nodes.Aggregator,
+	modelPair provider.Pair,
+	parent PriceModel,
+	source Source,
+) (nodes.Node, error) {
+
+	if source.Origin != "" {
+		return nil, fmt.Errorf(
+			"the nested price model for the %s pair must not define an origin",
+			modelPair,
+		)
+	}
+	model := *source.Model
+	if model.CircuitBreaker != nil {
+		return nil, fmt.Errorf(
+			"the nested price model for the %s pair must not define a circuit breaker",
+			modelPair,
+		)
+	}
+	if len(model.Sources) == 0 {
+		return nil, fmt.Errorf("the nested price model for the %s pair has no sources", modelPair)
+	}
+	if model.TTL == 0 {
+		model.TTL = parent.TTL
+	}
+	if model.MaxTTL == 0 {
+		model.MaxTTL = parent.MaxTTL
+	}
+	pair, err := source.pair(modelPair)
+	if err != nil {
+		return nil, err
+	}
+	node, err := aggregatorNode(pair, model)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.buildBranch(graphs, pair, model, node); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
 func (c *Gofer) reference(graphs map[provider.Pair]nodes.Aggregator, source Source) (nodes.Node, error) {
 	sourcePair, err := provider.NewPair(source.Pair)
 	if err != nil {
@@ -342,7 +894,24 @@ func (c *Gofer) originNode(model PriceModel, source Source) (nodes.Node, error)
 		ttl = time.Second * time.Duration(source.TTL)
 	}
 
-	return nodes.NewOriginNode(originPair, ttl, ttl+maxTTL), nil
+	// After the maxTTL, the price is considered stale and will not be used
+	// by parent nodes:
+	staleTTL := ttl + maxTTL
+	if model.MaxTTL > 0 {
+		staleTTL = time.Second * time.Duration(model.MaxTTL)
+	}
+	if source.MaxTTL > 0 {
+		staleTTL = time.Second * time.Duration(source.MaxTTL)
+	}
+	if staleTTL < ttl {
+		return nil, fmt.Errorf(
+			"the maxTTL for the %s source from the %s origin must not be less than the ttl",
+			sourcePair,
+			source.Origin,
+		)
+	}
+
+	return nodes.NewOriginNode(originPair, ttl, staleTTL), nil
 }
 
 func (c *Gofer) detectCycle(graphs map[provider.Pair]nodes.Aggregator) error {
@@ -16,14 +16,25 @@
 package gofer
 
 import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"testing"
 	"time"
 
 	"gopkg.in/yaml.v3"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/callback"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/graph/nodes"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/origins"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/query"
 
 	ethereumMocks "github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
 
@@ -113,6 +124,123 @@ func TestConfig_buildGraphs_ValidConfig(t *testing.T) {
 	assert.Same(t, c[bc], c[ac].Children()[1].(*nodes.IndirectAggregatorNode).Children()[1])
 }
 
+func TestConfig_buildGraphs_IndirectMethod(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     []Source
+		expected float64
+	}{
+		{
+			// ETH/USD / GBP/USD, the GBP/USD price must be inverted:
+			name:     "inverted",
+			path:     []Source{{Origin: ".", Pair: "ETH/USD"}, {Origin: ".", Pair: "GBP/USD"}},
+			expected: 1500,
+		},
+		{
+			// ETH/USD * USD/GBP:
+			name:     "direct",
+			path:     []Source{{Origin: ".", Pair: "ETH/USD"}, {Origin: ".", Pair: "USD/GBP"}},
+			expected: 1500,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Gofer{
+				PriceModels: map[string]PriceModel{
+					"ETH/USD": {
+						Method:  "median",
+						Sources: [][]Source{{{Origin: "a", Pair: "ETH/USD"}}},
+						Params:  yamlNode(t, `{"minimumSuccessfulSources": 1}`),
+					},
+					"GBP/USD": {
+						Method:  "median",
+						Sources: [][]Source{{{Origin: "b", Pair: "GBP/USD"}}},
+						Params:  yamlNode(t, `{"minimumSuccessfulSources": 1}`),
+					},
+					"USD/GBP": {
+						Method:  "median",
+						Sources: [][]Source{{{Origin: "c", Pair: "USD/GBP"}}},
+						Params:  yamlNode(t, `{"minimumSuccessfulSources": 1}`),
+					},
+					"ETH/GBP": {
+						Method:  "indirect",
+						Sources: [][]Source{tt.path},
+					},
+				},
+			}
+
+			g, err := config.buildGraphs()
+			require.NoError(t, err)
+
+			ethgbp := provider.Pair{Base: "ETH", Quote: "GBP"}
+			require.IsType(t, &nodes.IndirectAggregatorNode{}, g[ethgbp])
+			require.Len(t, g[ethgbp].Children(), 2)
+
+			// Child nodes must be the root nodes of referenced models, in
+			// the same order as defined in the config:
+			for i, s := range tt.path {
+				p, _ := provider.NewPair(s.Pair)
+				assert.Same(t, g[p], g[ethgbp].Children()[i])
+			}
+
+			ingest := func(pair provider.Pair, origin string, price float64) {
+				on := g[pair].Children()[0].(*nodes.OriginNode)
+				require.NoError(t, on.Ingest(nodes.OriginPrice{
+					PairPrice: nodes.PairPrice{Pair: pair, Price: price, Bid: price, Ask: price, Time: time.Now()},
+					Origin:    origin,
+				}))
+			}
+			ingest(provider.Pair{Base: "ETH", Quote: "USD"}, "a", 1800)
+			ingest(provider.Pair{Base: "GBP", Quote: "USD"}, "b", 1.2)
+			ingest(provider.Pair{Base: "USD", Quote: "GBP"}, "c", 1/1.2)
+
+			price := g[ethgbp].Price()
+			assert.NoError(t, price.Error)
+			assert.Equal(t, ethgbp, price.Pair)
+			assert.InDelta(t, tt.expected, price.Price, 0.0000001)
+			assert.Equal(t, "indirect", price.Parameters["method"])
+		})
+	}
+}
+
+func TestConfig_buildGraphs_IndirectMethodInvalidPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources [][]Source
+	}{
+		{
+			name:    "no-common-part",
+			sources: [][]Source{{{Origin: "a", Pair: "ETH/USD"}, {Origin: "b", Pair: "EUR/GBP"}}},
+		},
+		{
+			name:    "wrong-pair",
+			sources: [][]Source{{{Origin: "a", Pair: "ETH/USD"}, {Origin: "b", Pair: "USD/EUR"}}},
+		},
+		{
+			name: "multiple-paths",
+			sources: [][]Source{
+				{{Origin: "a", Pair: "ETH/USD"}, {Origin: "b", Pair: "USD/GBP"}},
+				{{Origin: "a", Pair: "ETH/USD"}, {Origin: "b", Pair: "USD/GBP"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Gofer{
+				PriceModels: map[string]PriceModel{
+					"ETH/GBP": {
+						Method:  "indirect",
+						Sources: tt.sources,
+					},
+				},
+			}
+
+			_, err := config.buildGraphs()
+			assert.Error(t, err)
+		})
+	}
+}
+
 func TestConfig_buildGraphs_CyclicConfig(t *testing.T) {
 	config := Gofer{
 		Origins: nil,
@@ -160,6 +288,373 @@ func TestConfig_buildGraphs_CyclicConfig(t *testing.T) {
 	assert.Error(t, err2)
 }
 
+func TestConfig_buildGraphs_CyclicJSONConfig(t *testing.T) {
+	// The A/C pair refers to the B/C pair, which refers back to the A/C pair:
+	var cfg Gofer
+	err := config.Parse(&cfg, []byte(`
+{
+  "priceModels": {
+    "A/C": {
+      "method": "indirect",
+      "sources": [[{"origin": "ab", "pair": "A/B"}, {"origin": ".", "pair": "B/C"}]]
+    },
+    "B/C": {
+      "method": "indirect",
+      "sources": [[{"origin": "ba", "pair": "B/A"}, {"origin": ".", "pair": "A/C"}]]
+    }
+  }
+}`))
+	require.NoError(t, err)
+
+	_, err = cfg.buildGraphs()
+	require.Error(t, err)
+
+	var cycleErr ErrCyclicReference
+	require.True(t, errors.As(err, &cycleErr))
+	assert.Equal(t, provider.Pair{Base: "A", Quote: "C"}, cycleErr.Pair)
+	assert.Equal(
+		t,
+		"a cyclic reference was detected for the A/C pair: "+
+			"*nodes.IndirectAggregatorNode(A/C) -> "+
+			"*nodes.IndirectAggregatorNode(B/C) -> "+
+			"*nodes.IndirectAggregatorNode(A/C)",
+		err.Error(),
+	)
+}
+
+func TestConfig_buildGraphs_NestedMedian(t *testing.T) {
+	// The global median uses two regional medians:
+	var cfg Gofer
+	err := config.Parse(&cfg, []byte(`
+{
+  "priceModels": {
+    "A/B": {
+      "method": "median",
+      "params": {"minimumSuccessfulSources": 2},
+      "sources": [
+        [{"model": {
+          "method": "median",
+          "params": {"minimumSuccessfulSources": 2},
+          "sources": [[{"origin": "a", "pair": "A/B"}], [{"origin": "b", "pair": "A/B"}], [{"origin": "c", "pair": "A/B"}]]
+        }}],
+        [{"model": {
+          "method": "median",
+          "params": {"minimumSuccessfulSources": 2},
+          "sources": [[{"origin": "d", "pair": "A/B"}], [{"origin": "e", "pair": "A/B"}]]
+        }}]
+      ]
+    }
+  }
+}`))
+	require.NoError(t, err)
+
+	graphs, err := cfg.buildGraphs()
+	require.NoError(t, err)
+
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	root := graphs[ab].(*nodes.MedianAggregatorNode)
+	require.Len(t, root.Children(), 2)
+	require.IsType(t, &nodes.MedianAggregatorNode{}, root.Children()[0])
+	require.IsType(t, &nodes.MedianAggregatorNode{}, root.Children()[1])
+	assert.Len(t, root.Children()[0].Children(), 3)
+	assert.Len(t, root.Children()[1].Children(), 2)
+
+	ingest := func(prices map[string]float64) {
+		nodes.Walk(func(n nodes.Node) {
+			o, ok := n.(*nodes.OriginNode)
+			if !ok {
+				return
+			}
+			price := nodes.OriginPrice{
+				PairPrice: nodes.PairPrice{Pair: ab, Price: prices[o.OriginPair().Origin], Time: time.Now()},
+				Origin:    o.OriginPair().Origin,
+			}
+			if price.Price == 0 {
+				price.Error = errors.New("exchange is down")
+			}
+			require.NoError(t, o.Ingest(price))
+		}, root)
+	}
+
+	// One of the first region's sources fails, but the regional median
+	// still has enough sources:
+	ingest(map[string]float64{"a": 1, "b": 3, "d": 5, "e": 7})
+	price := root.Price()
+	require.NoError(t, price.Error)
+	assert.Equal(t, float64(4), price.Price)
+	require.Len(t, price.AggregatorPrices, 2)
+	assert.Equal(t, "a, b", price.AggregatorPrices[0].Parameters["includedSources"])
+	assert.Equal(t, "d, e", price.AggregatorPrices[1].Parameters["includedSources"])
+
+	// The second region has only one source, so its median fails and
+	// the global median does not have enough sources:
+	ingest(map[string]float64{"a": 1, "b": 3, "c": 5, "d": 5})
+	price = root.Price()
+	var notEnough nodes.ErrNotEnoughSources
+	require.True(t, errors.As(price.Error, &notEnough))
+	assert.Equal(t, nodes.ErrNotEnoughSources{Given: 1, Min: 2}, notEnough)
+	assert.NoError(t, price.AggregatorPrices[0].Error)
+	require.True(t, errors.As(price.AggregatorPrices[1].Error, &notEnough))
+	assert.Equal(t, nodes.ErrNotEnoughSources{Given: 1, Min: 2}, notEnough)
+	assert.Equal(t, "a, b, c", price.AggregatorPrices[0].Parameters["includedSources"])
+	assert.Equal(t, "d", price.AggregatorPrices[1].Parameters["includedSources"])
+}
+
+func TestConfig_buildGraphs_NestedModelWithOrigin(t *testing.T) {
+	config := Gofer{
+		PriceModels: map[string]PriceModel{
+			"A/B": {
+				Method: "median",
+				Sources: [][]Source{{{
+					Origin: "a",
+					Model: &PriceModel{
+						Method:  "median",
+						Sources: [][]Source{{{Origin: "a", Pair: "A/B"}}},
+						Params:  yamlNode(t, `{"minimumSuccessfulSources": 1}`),
+					},
+				}}},
+				Params: yamlNode(t, `{"minimumSuccessfulSources": 1}`),
+			},
+		},
+	}
+	_, err := config.buildGraphs()
+	assert.Error(t, err)
+}
+
+func TestConfig_buildGraphs_MedianSourcesLimits(t *testing.T) {
+	tests := []struct {
+		params  string
+		wantErr bool
+	}{
+		{params: `{"minimumSuccessfulSources": 2}`},
+		{params: `{"minimumSuccessfulSources": 2, "maximumSuccessfulSources": 2}`},
+		{params: `{"minimumSuccessfulSources": 2, "maximumSuccessfulSources": 3}`},
+		{params: `{"minimumSuccessfulSources": 3, "maximumSuccessfulSources": 2}`, wantErr: true},
+		{params: `{"minimumSuccessfulSources": -1}`, wantErr: true},
+		{params: `{"maximumSuccessfulSources": -1}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.params, func(t *testing.T) {
+			config := Gofer{
+				PriceModels: map[string]PriceModel{
+					"A/B": {
+						Method: "median",
+						Sources: [][]Source{
+							{{Origin: "a", Pair: "A/B"}},
+							{{Origin: "b", Pair: "A/B"}},
+							{{Origin: "c", Pair: "A/B"}},
+						},
+						Params: yamlNode(t, tt.params),
+					},
+				},
+			}
+
+			_, err := config.buildGraphs()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestConfig_buildGraphs_ErrorPolicy(t *testing.T) {
+	tests := []struct {
+		policy  string
+		wantErr bool
+	}{
+		{policy: ""},
+		{policy: "lenient"},
+		{policy: "strict"},
+		{policy: "all-or-nothing"},
+		{policy: "foo", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			config := Gofer{
+				PriceModels: map[string]PriceModel{
+					"A/B": {
+						Method: "median",
+						Sources: [][]Source{
+							{{Origin: "a", Pair: "A/B"}},
+							{{Origin: "b", Pair: "A/B"}},
+						},
+						Params:      yamlNode(t, `{"minimumSuccessfulSources": 1}`),
+						ErrorPolicy: tt.policy,
+					},
+				},
+			}
+
+			graphs, err := config.buildGraphs()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			ab := provider.Pair{Base: "A", Quote: "B"}
+			nodes.Walk(func(n nodes.Node) {
+				if o, ok := n.(*nodes.OriginNode); ok && o.OriginPair().Origin == "a" {
+					require.NoError(t, o.Ingest(nodes.OriginPrice{
+						PairPrice: nodes.PairPrice{Pair: ab, Price: 10, Time: time.Now()},
+						Origin:    "a",
+					}))
+				}
+			}, graphs[ab])
+
+			// The "b" origin has no price, so it fails:
+			price := graphs[ab].Price()
+			assert.Equal(t, float64(10), price.Price)
+			if tt.policy == "strict" || tt.policy == "all-or-nothing" {
+				assert.Error(t, price.Error)
+			} else {
+				assert.NoError(t, price.Error)
+			}
+		})
+	}
+}
+
+func TestConfig_buildGraphs_ExactArithmetic(t *testing.T) {
+	config := Gofer{
+		PriceModels: map[string]PriceModel{
+			"A/C": {
+				Method: "median",
+				Sources: [][]Source{
+					{{Origin: "a", Pair: "A/B"}, {Origin: "b", Pair: "B/C"}},
+				},
+				Params: yamlNode(t, `{"minimumSuccessfulSources": 1}`),
+			},
+		},
+		ExactArithmetic: true,
+	}
+
+	graphs, err := config.buildGraphs()
+	require.NoError(t, err)
+
+	ac := provider.Pair{Base: "A", Quote: "C"}
+	prices := map[string]float64{"a": 0.1, "b": 3}
+	nodes.Walk(func(n nodes.Node) {
+		if o, ok := n.(*nodes.OriginNode); ok {
+			require.NoError(t, o.Ingest(nodes.OriginPrice{
+				PairPrice: nodes.PairPrice{
+					Pair:  o.OriginPair().Pair,
+					Price: prices[o.OriginPair().Origin],
+					Time:  time.Now(),
+				},
+				Origin: o.OriginPair().Origin,
+			}))
+		}
+	}, graphs[ac])
+
+	price := graphs[ac].Price()
+	require.NoError(t, price.Error)
+	assert.Equal(t, big.NewRat(3, 10), price.ExactPrice)
+	assert.Equal(t, 0.3, price.Price)
+}
+
+func TestConfig_buildGraphs_QuoteNormalization(t *testing.T) {
+	config := Gofer{
+		QuoteNormalization: map[string]string{"USDT": "USD"},
+		PriceModels: map[string]PriceModel{
+			"USDT/USD": {
+				Method:  "median",
+				Sources: [][]Source{{{Origin: "a", Pair: "USDT/USD"}}},
+				Params:  yamlNode(t, `{"minimumSuccessfulSources": 1}`),
+			},
+			"BTC/USD": {
+				Method: "median",
+				Sources: [][]Source{
+					{{Origin: "a", Pair: "BTC/USD"}},
+					{{Origin: "b", Pair: "BTC/USDT"}},
+				},
+				Params: yamlNode(t, `{"minimumSuccessfulSources": 2}`),
+			},
+		},
+	}
+
+	g, err := config.buildGraphs()
+	require.NoError(t, err)
+
+	btcusd := provider.Pair{Base: "BTC", Quote: "USD"}
+	usdtusd := provider.Pair{Base: "USDT", Quote: "USD"}
+	btcusdt := provider.Pair{Base: "BTC", Quote: "USDT"}
+
+	// The USD quoted source is used directly:
+	assert.IsType(t, &nodes.OriginNode{}, g[btcusd].Children()[0])
+
+	// The USDT quoted source is converted using the USDT/USD price model:
+	norm := g[btcusd].Children()[1].(*nodes.IndirectAggregatorNode)
+	assert.Equal(t, btcusd, norm.Pair())
+	assert.Equal(t, btcusdt, norm.Children()[0].(*nodes.OriginNode).OriginPair().Pair)
+	assert.Same(t, g[usdtusd], norm.Children()[1])
+
+	// Feed a USDT quoted price and the USDT/USD rate to verify the result:
+	n := time.Now()
+	require.NoError(t, g[btcusd].Children()[0].(*nodes.OriginNode).Ingest(nodes.OriginPrice{
+		PairPrice: nodes.PairPrice{Pair: btcusd, Price: 19900, Time: n},
+		Origin:    "a",
+	}))
+	require.NoError(t, norm.Children()[0].(*nodes.OriginNode).Ingest(nodes.OriginPrice{
+		PairPrice: nodes.PairPrice{Pair: btcusdt, Price: 20000, Time: n},
+		Origin:    "b",
+	}))
+	require.NoError(t, g[usdtusd].Children()[0].(*nodes.OriginNode).Ingest(nodes.OriginPrice{
+		PairPrice: nodes.PairPrice{Pair: usdtusd, Price: 0.99, Time: n},
+		Origin:    "a",
+	}))
+	assert.InDelta(t, 19800, norm.Price().Price, 1e-9)
+	assert.InDelta(t, 19850, g[btcusd].Price().Price, 1e-9)
+}
+
+func TestConfig_ConfigureGofer_Aliases(t *testing.T) {
+	config := Gofer{
+		Aliases: map[string]string{"XBT": "BTC"},
+		PriceModels: map[string]PriceModel{
+			"BTC/USD": {
+				Method:  "median",
+				Sources: [][]Source{{{Origin: "a", Pair: "BTC/USD"}}},
+				Params:  yamlNode(t, `{"minimumSuccessfulSources": 1}`),
+			},
+		},
+	}
+
+	gof, err := config.ConfigureGofer(context.Background(), &ethereumMocks.Client{}, null.New(), true)
+	require.NoError(t, err)
+
+	xbtusd, err := provider.NewPair("xbt/usd")
+	require.NoError(t, err)
+	models, err := gof.Models(xbtusd)
+	require.NoError(t, err)
+	btcusd := provider.Pair{Base: "BTC", Quote: "USD"}
+	require.Contains(t, models, btcusd)
+	assert.Equal(t, btcusd, models[btcusd].Pair)
+}
+
+func TestConfig_ConfigureGofer_InvalidAliases(t *testing.T) {
+	config := Gofer{Aliases: map[string]string{"XBT": "BTC", "BTC": "XBT"}}
+
+	_, err := config.ConfigureGofer(context.Background(), &ethereumMocks.Client{}, null.New(), true)
+	assert.Error(t, err)
+	assert.NotEmpty(t, config.Validate())
+}
+
+func TestConfig_buildGraphs_QuoteNormalizationMissingModel(t *testing.T) {
+	config := Gofer{
+		QuoteNormalization: map[string]string{"USDT": "USD"},
+		PriceModels: map[string]PriceModel{
+			"BTC/USD": {
+				Method:  "median",
+				Sources: [][]Source{{{Origin: "b", Pair: "BTC/USDT"}}},
+				Params:  yamlNode(t, `{"minimumSuccessfulSources": 1}`),
+			},
+		},
+	}
+
+	_, err := config.buildGraphs()
+	assert.Error(t, err)
+}
+
 func TestConfig_buildGraphs_NoSources(t *testing.T) {
 	config := Gofer{
 		Origins: nil,
@@ -296,6 +791,53 @@ func TestConfig_buildGraphs_MedianTTL(t *testing.T) {
 	assert.Equal(t, 120*time.Second, g[p].Children()[0].(*nodes.OriginNode).MinTTL())
 }
 
+func TestConfig_buildGraphs_MaxTTL(t *testing.T) {
+	config := Gofer{
+		Origins: nil,
+		PriceModels: map[string]PriceModel{
+			"A/B": {
+				Method: "median",
+				TTL:    60,
+				MaxTTL: 120,
+				Sources: [][]Source{
+					{
+						{Origin: "ab1", Pair: "A/B"},
+					},
+					{
+						{Origin: "ab2", Pair: "A/B", MaxTTL: 90},
+					},
+				},
+			},
+		},
+	}
+
+	p, _ := provider.NewPair("A/B")
+	g, err := config.buildGraphs()
+	require.NoError(t, err)
+
+	assert.Equal(t, 120*time.Second, g[p].Children()[0].(*nodes.OriginNode).MaxTTL())
+	assert.Equal(t, 90*time.Second, g[p].Children()[1].(*nodes.OriginNode).MaxTTL())
+}
+
+func TestConfig_buildGraphs_MaxTTLLowerThanTTL(t *testing.T) {
+	config := Gofer{
+		Origins: nil,
+		PriceModels: map[string]PriceModel{
+			"A/B": {
+				Method: "median",
+				Sources: [][]Source{
+					{
+						{Origin: "ab", Pair: "A/B", TTL: 60, MaxTTL: 30},
+					},
+				},
+			},
+		},
+	}
+
+	_, err := config.buildGraphs()
+	assert.Error(t, err)
+}
+
 func TestConfig_buildGraphs_UpdatedOriginURL(t *testing.T) {
 	url := "http://localhost:8080"
 
@@ -320,7 +862,7 @@ func TestConfig_buildGraphs_UpdatedOriginURL(t *testing.T) {
 		},
 	}
 
-	o, err := config.buildOrigins(&ethereumMocks.Client{})
+	o, err := config.buildOrigins(context.Background(), &ethereumMocks.Client{}, null.New())
 	require.NoError(t, err)
 	require.NotNil(t, o)
 
@@ -335,3 +877,211 @@ func TestConfig_buildGraphs_UpdatedOriginURL(t *testing.T) {
 	require.NotNil(t, bin)
 	require.Equal(t, url, bin.BaseURL)
 }
+
+func TestConfig_originWorkerPool(t *testing.T) {
+	wp := query.NewMockWorkerPool()
+
+	// Without options, the shared worker pool is used:
+	assert.Same(t, wp, originWorkerPool(wp, Origin{Type: "binance"}))
+
+	// With a timeout, requests are limited by the TimeoutWorkerPool:
+	assert.IsType(t, &query.TimeoutWorkerPool{}, originWorkerPool(wp, Origin{Type: "binance", Timeout: 2}))
+}
+
+func TestConfig_originWorkerPool_Headers(t *testing.T) {
+	require.NoError(t, os.Setenv("GOFER_TEST_API_KEY", "secret"))
+	defer os.Unsetenv("GOFER_TEST_API_KEY")
+
+	var cfg Gofer
+	require.NoError(t, config.Parse(&cfg, []byte(`
+origins:
+  binance:
+    type: binance
+    headers:
+      X-MBX-APIKEY: ${GOFER_TEST_API_KEY}
+`)))
+
+	var headers map[string]string
+	wp := query.NewMockWorkerPool()
+	wp.SetRequestAssertions(func(req *query.HTTPRequest) {
+		headers = req.Headers
+	})
+	originWorkerPool(wp, cfg.Origins["binance"]).Query(&query.HTTPRequest{URL: "http://example.com/ticker"})
+
+	assert.Equal(t, map[string]string{"X-MBX-APIKEY": "secret"}, headers)
+}
+
+func TestConfig_buildOrigins_Proxy(t *testing.T) {
+	config := Gofer{
+		Proxy: "http://127.0.0.1:3128",
+		Origins: map[string]Origin{
+			"a": {Type: "binance", Params: yamlNode(t, `{}`)},
+			"b": {Type: "binance", Params: yamlNode(t, `{}`), Proxy: "socks5://127.0.0.1:1080"},
+		},
+	}
+	o, err := config.buildOrigins(context.Background(), &ethereumMocks.Client{}, null.New())
+	require.NoError(t, err)
+	assert.Len(t, o.Handlers(), len(origins.DefaultOriginSet(nil).Handlers())+2)
+
+	// Invalid proxy URL:
+	config.Origins["b"] = Origin{Type: "binance", Params: yamlNode(t, `{}`), Proxy: "ftp://127.0.0.1"}
+	_, err = config.buildOrigins(context.Background(), &ethereumMocks.Client{}, null.New())
+	assert.ErrorAs(t, err, &query.ErrInvalidProxy{})
+}
+
+func TestConfig_buildOriginsWithPools(t *testing.T) {
+	config := Gofer{
+		Origins: map[string]Origin{
+			"a": {Type: "binance", Params: yamlNode(t, `{}`)},
+		},
+	}
+	wrapped := map[string]bool{}
+	o, err := config.buildOriginsWithPools(
+		context.Background(),
+		&ethereumMocks.Client{},
+		null.New(),
+		func(origin string, wp query.WorkerPool) query.WorkerPool {
+			wrapped[origin] = true
+			return wp
+		},
+	)
+	require.NoError(t, err)
+
+	// Every origin, including the default ones, must use its own pool:
+	for name := range o.Handlers() {
+		assert.True(t, wrapped[name], name)
+	}
+	assert.Len(t, wrapped, len(o.Handlers()))
+}
+
+func TestConfig_buildGraphs_CircuitBreaker(t *testing.T) {
+	config := Gofer{
+		PriceModels: map[string]PriceModel{
+			"A/B": {
+				Method:         "median",
+				Sources:        [][]Source{{{Origin: "a", Pair: "A/B"}}},
+				Params:         yamlNode(t, `{}`),
+				CircuitBreaker: &CircuitBreaker{MaxDeviation: 50, Window: 600},
+			},
+		},
+	}
+
+	g, err := config.buildGraphs()
+	require.NoError(t, err)
+	require.IsType(t, &nodes.CircuitBreakerNode{}, g[provider.Pair{Base: "A", Quote: "B"}])
+	require.Len(t, g[provider.Pair{Base: "A", Quote: "B"}].Children(), 1)
+	assert.IsType(t, &nodes.MedianAggregatorNode{}, g[provider.Pair{Base: "A", Quote: "B"}].Children()[0])
+
+	// Invalid parameters:
+	model := config.PriceModels["A/B"]
+	model.CircuitBreaker = &CircuitBreaker{MaxDeviation: 50}
+	config.PriceModels["A/B"] = model
+	_, err = config.buildGraphs()
+	assert.Error(t, err)
+}
+
+func TestConfig_ConfigureGofer_Cancel(t *testing.T) {
+	done := make(chan struct{})
+	defer close(done)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+
+	config := Gofer{
+		Origins: map[string]Origin{
+			"ab": {Type: "binance", URL: srv.URL, Params: yamlNode(t, `{}`)},
+		},
+		PriceModels: map[string]PriceModel{
+			"A/B": {
+				Method:  "median",
+				Sources: [][]Source{{{Origin: "ab", Pair: "A/B"}}},
+				Params:  yamlNode(t, `{minimumSuccessfulSources: 1}`),
+			},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	gof, err := config.ConfigureGofer(ctx, &ethereumMocks.Client{}, null.New(), true)
+	require.NoError(t, err)
+
+	// Cancel the context while the origin is being queried:
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	price, err := gof.Price(provider.Pair{Base: "A", Quote: "B"})
+	require.NoError(t, err)
+	require.Len(t, price.Prices, 1)
+	assert.Contains(t, price.Prices[0].Error, context.Canceled.Error())
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestConfig_buildGraphs_OutlierThreshold(t *testing.T) {
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	config := Gofer{
+		PriceModels: map[string]PriceModel{
+			"A/B": {
+				Method: "median",
+				Sources: [][]Source{
+					{{Origin: "a", Pair: "A/B"}},
+					{{Origin: "b", Pair: "A/B"}},
+					{{Origin: "c", Pair: "A/B"}},
+				},
+				Params: yamlNode(t, `{"minimumSuccessfulSources": 1, "outlierThreshold": 10}`),
+			},
+		},
+	}
+
+	graphs, err := config.buildGraphs()
+	require.NoError(t, err)
+
+	var sources []interface{}
+	setOutlierHook(graphs, callback.New(log.Debug, func(_ log.Level, fields log.Fields, msg string) {
+		if msg == nodes.OutlierMetricMessage {
+			sources = append(sources, fields["source"])
+		}
+	}))
+
+	prices := map[string]float64{"a": 10, "b": 10, "c": 20}
+	nodes.Walk(func(n nodes.Node) {
+		if o, ok := n.(*nodes.OriginNode); ok {
+			require.NoError(t, o.Ingest(nodes.OriginPrice{
+				PairPrice: nodes.PairPrice{Pair: ab, Price: prices[o.OriginPair().Origin], Time: time.Now()},
+				Origin:    o.OriginPair().Origin,
+			}))
+		}
+	}, graphs[ab])
+
+	price := graphs[ab].Price()
+	require.NoError(t, price.Error)
+	assert.Equal(t, []interface{}{"c"}, sources)
+
+	// The threshold must not be negative:
+	config.PriceModels["A/B"] = PriceModel{
+		Method:  "median",
+		Sources: [][]Source{{{Origin: "a", Pair: "A/B"}}},
+		Params:  yamlNode(t, `{"minimumSuccessfulSources": 1, "outlierThreshold": -1}`),
+	}
+	_, err = config.buildGraphs()
+	assert.Error(t, err)
+}
+
+func TestConfig_ConfigurePprofServer(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	// Disabled by default:
+	assert.Nil(t, (&Gofer{}).ConfigurePprofServer())
+
+	srv := (&Gofer{Pprof: Pprof{Address: "127.0.0.1:0"}}).ConfigurePprofServer()
+	require.NotNil(t, srv)
+	require.NoError(t, srv.Start(ctx))
+
+	res, err := http.Get("http://" + srv.Addr().String() + "/debug/pprof/")
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
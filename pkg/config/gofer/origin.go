@@ -17,9 +17,11 @@ package gofer
 
 import (
 	"fmt"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/query"
 
 	pkgEthereum "github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
@@ -63,6 +65,30 @@ func parseParamsContracts(params yaml.Node) (origins.ContractAddresses, error) {
 	return res.Contracts, nil
 }
 
+func parseParamsCurveCoins(params yaml.Node) (origins.CurvePoolCoins, error) {
+	var res struct {
+		Coins map[string]struct {
+			Indices  [2]int `yaml:"indices"`
+			Decimals [2]int `yaml:"decimals"`
+		} `yaml:"coins"`
+	}
+	err := params.Decode(&res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal origin coins from params: %w", err)
+	}
+	coins := origins.CurvePoolCoins{}
+	for pair, c := range res.Coins {
+		if c.Indices[0] < 0 || c.Indices[1] < 0 || c.Indices[0] == c.Indices[1] {
+			return nil, fmt.Errorf("invalid coin indices for the %s pair", pair)
+		}
+		if c.Decimals[0] < 0 || c.Decimals[1] < 0 {
+			return nil, fmt.Errorf("invalid coin decimals for the %s pair", pair)
+		}
+		coins[pair] = origins.CurveCoins{Indices: c.Indices, Decimals: c.Decimals}
+	}
+	return coins, nil
+}
+
 //nolint:funlen,gocyclo,whitespace
 func NewHandler(
 	origin string,
@@ -70,6 +96,7 @@ func NewHandler(
 	cli pkgEthereum.Client,
 	baseURL string,
 	params yaml.Node,
+	logger log.Logger,
 ) (origins.Handler, error) {
 	aliases, err := parseParamsSymbolAliases(params)
 	if err != nil {
@@ -88,6 +115,30 @@ func NewHandler(
 		}, aliases), nil
 	case "binance":
 		return origins.NewBaseExchangeHandler(origins.Binance{WorkerPool: wp, BaseURL: baseURL}, aliases), nil
+	case "binanceStream":
+		var res struct {
+			StreamURL         string `yaml:"streamURL"`
+			MaxAge            int    `yaml:"maxAge"`
+			ReconnectMinDelay int    `yaml:"reconnectMinDelay"`
+			ReconnectMaxDelay int    `yaml:"reconnectMaxDelay"`
+			MaxRetries        int    `yaml:"maxRetries"`
+		}
+		if err := params.Decode(&res); err != nil {
+			return nil, fmt.Errorf("failed to marshal origin stream params: %w", err)
+		}
+		h := origins.NewBinanceStream(
+			res.StreamURL,
+			origins.Binance{WorkerPool: wp, BaseURL: baseURL},
+			time.Duration(res.MaxAge)*time.Second,
+		)
+		if res.ReconnectMinDelay > 0 {
+			h.Backoff.Min = time.Duration(res.ReconnectMinDelay) * time.Second
+		}
+		if res.ReconnectMaxDelay > 0 {
+			h.Backoff.Max = time.Duration(res.ReconnectMaxDelay) * time.Second
+		}
+		h.Backoff.MaxRetries = res.MaxRetries
+		return origins.NewBaseExchangeHandler(h, aliases), nil
 	case "bitfinex":
 		return origins.NewBaseExchangeHandler(origins.Bitfinex{WorkerPool: wp, BaseURL: baseURL}, aliases), nil
 	case "bitstamp":
@@ -100,6 +151,8 @@ func NewHandler(
 		return origins.NewBaseExchangeHandler(origins.CoinbasePro{WorkerPool: wp, BaseURL: baseURL}, aliases), nil
 	case "cryptocompare":
 		return origins.NewBaseExchangeHandler(origins.CryptoCompare{WorkerPool: wp, BaseURL: baseURL}, aliases), nil
+	case "cryptocom":
+		return origins.NewBaseExchangeHandler(origins.CryptoCom{WorkerPool: wp, BaseURL: baseURL}, aliases), nil
 	case "coinmarketcap":
 		apiKey, err := parseParamsAPIKey(params)
 		if err != nil {
@@ -135,7 +188,7 @@ func NewHandler(
 	case "kraken":
 		return origins.NewBaseExchangeHandler(origins.Kraken{WorkerPool: wp, BaseURL: baseURL}, aliases), nil
 	case "kucoin":
-		return origins.NewBaseExchangeHandler(origins.Kucoin{WorkerPool: wp, BaseURL: baseURL}, aliases), nil
+		return origins.NewBaseExchangeHandler(origins.Kucoin{WorkerPool: wp, BaseURL: baseURL, Logger: logger}, aliases), nil
 	case "loopring":
 		return origins.NewBaseExchangeHandler(origins.Loopring{WorkerPool: wp, BaseURL: baseURL}, aliases), nil
 	case "okex":
@@ -168,7 +221,11 @@ func NewHandler(
 		if err != nil {
 			return nil, err
 		}
-		h, err := origins.NewCurveFinance(cli, contracts, averageFromBlocks)
+		coins, err := parseParamsCurveCoins(params)
+		if err != nil {
+			return nil, err
+		}
+		h, err := origins.NewCurveFinance(cli, contracts, coins, averageFromBlocks)
 		if err != nil {
 			return nil, err
 		}
@@ -183,6 +240,16 @@ func NewHandler(
 			return nil, err
 		}
 		return origins.NewBaseExchangeHandler(*h, aliases), nil
+	case "chainlink":
+		contracts, err := parseParamsContracts(params)
+		if err != nil {
+			return nil, err
+		}
+		h, err := origins.NewChainlink(cli, contracts)
+		if err != nil {
+			return nil, err
+		}
+		return origins.NewBaseExchangeHandler(*h, aliases), nil
 	case "wsteth":
 		contracts, err := parseParamsContracts(params)
 		if err != nil {
@@ -19,6 +19,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/origins"
 )
 
 func TestParsingOriginParamsAliases(t *testing.T) {
@@ -44,3 +46,23 @@ func TestParsingOriginParamsAliases(t *testing.T) {
 	assert.NotNil(t, aliases)
 	assert.Equal(t, "WETH", aliases["ETH"])
 }
+
+func TestParsingOriginParamsCurveCoins(t *testing.T) {
+	// Parsing empty coins
+	coins, err := parseParamsCurveCoins(yamlNode(t, `{}`))
+	assert.NoError(t, err)
+	assert.Empty(t, coins)
+
+	// Parsing coins
+	coins, err = parseParamsCurveCoins(yamlNode(t, `{"coins":{"USDC/USDT":{"indices":[1,2],"decimals":[6,6]}}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, origins.CurveCoins{Indices: [2]int{1, 2}, Decimals: [2]int{6, 6}}, coins["USDC/USDT"])
+
+	// Same indices
+	_, err = parseParamsCurveCoins(yamlNode(t, `{"coins":{"USDC/USDT":{"indices":[1,1]}}}`))
+	assert.Error(t, err)
+
+	// Negative decimals
+	_, err = parseParamsCurveCoins(yamlNode(t, `{"coins":{"USDC/USDT":{"indices":[1,2],"decimals":[-1,6]}}}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,184 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gofer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/graph/nodes"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/origins"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/maputil"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/query"
+)
+
+// Problem describes an issue found in the configuration.
+type Problem struct {
+	// Pair is the name of the price model to which the problem relates.
+	// It is empty for problems that do not relate to any price model.
+	Pair string
+	// Message describes the problem.
+	Message string
+	// Fatal is true if Gofer cannot work correctly with the configuration.
+	// Otherwise, the problem is only a warning.
+	Fatal bool
+}
+
+func (p Problem) String() string {
+	level := "warning"
+	if p.Fatal {
+		level = "error"
+	}
+	if p.Pair == "" {
+		return fmt.Sprintf("%s: %s", level, p.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", level, p.Pair, p.Message)
+}
+
+// Validate checks the configuration and returns a list of problems found.
+// Unlike ConfigureGofer, it does not stop on the first problem and it does
+// not make any network calls.
+func (c *Gofer) Validate() []Problem {
+	var problems []Problem
+	fatal := func(pair, format string, args ...interface{}) {
+		problems = append(problems, Problem{Pair: pair, Message: fmt.Sprintf(format, args...), Fatal: true})
+	}
+	warning := func(pair, format string, args ...interface{}) {
+		problems = append(problems, Problem{Pair: pair, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Check custom origins. Handlers are created using a mock worker pool,
+	// so no requests are made.
+	known := map[string]bool{}
+	for name := range origins.DefaultOriginSet(nil).Handlers() {
+		known[name] = true
+	}
+	for _, name := range sortedKeys(c.Origins) {
+		origin := c.Origins[name]
+		handler, err := NewHandler(origin.Type, query.NewMockWorkerPool(), nil, origin.URL, origin.Params, null.New())
+		if err != nil || handler == nil {
+			fatal("", "invalid %s origin %s: %v", origin.Type, name, err)
+			continue
+		}
+		known[name] = true
+	}
+
+	if _, err := provider.NewAliases(c.Aliases); err != nil {
+		fatal("", "invalid aliases: %v", err)
+	}
+
+	// Check sources of price models.
+	used := map[string]bool{}
+	for _, name := range sortedKeys(c.PriceModels) {
+		if _, err := provider.NewPair(name); err != nil {
+			fatal(name, "invalid pair name: %v", err)
+			continue
+		}
+		c.validateModel(name, c.PriceModels[name], known, used, fatal)
+	}
+	for _, name := range sortedKeys(c.Origins) {
+		if !used[name] {
+			warning("", "origin %s is not used by any price model", name)
+		}
+	}
+
+	// Build graphs to find problems that are detected only while building
+	// them, like cyclic references.
+	graphs, err := c.buildGraphs()
+	if err != nil {
+		fatal("", "%v", err)
+		return problems
+	}
+	for _, pair := range sortGraphs(graphs) {
+		reachable := false
+		nodes.Walk(func(n nodes.Node) {
+			if o, ok := n.(nodes.Origin); ok && known[o.OriginPair().Origin] {
+				reachable = true
+			}
+		}, graphs[pair])
+		if !reachable {
+			fatal(pair.String(), "no reachable origins")
+		}
+	}
+
+	return problems
+}
+
+// validateModel checks sources of the given price model. Nested price models
+// are checked recursively and their problems are reported for the parent
+// model.
+func (c *Gofer) validateModel(
+	name string,
+	model PriceModel,
+	known map[string]bool,
+	used map[string]bool,
+	fatal func(pair, format string, args ...interface{}),
+) {
+
+	if len(model.Sources) == 0 {
+		fatal(name, "no sources defined")
+	}
+	for _, sources := range model.Sources {
+		for _, source := range sources {
+			if source.Model != nil {
+				c.validateModel(name, *source.Model, known, used, fatal)
+				continue
+			}
+			used[source.Origin] = true
+			switch {
+			case source.Origin == ".":
+				if !c.hasPriceModel(source.Pair) {
+					fatal(name, "reference to an undefined price model %s", source.Pair)
+				}
+			case !known[source.Origin]:
+				fatal(name, "unknown origin %s", source.Origin)
+			}
+		}
+	}
+	if model.Method == "median" {
+		var params MedianPriceModel
+		if err := model.Params.Decode(&params); err == nil && params.MinSourceSuccess > len(model.Sources) {
+			fatal(
+				name,
+				"minimumSuccessfulSources is %d but only %d sources are defined",
+				params.MinSourceSuccess,
+				len(model.Sources),
+			)
+		}
+	}
+}
+
+// hasPriceModel returns true if there is a price model for the given pair.
+func (c *Gofer) hasPriceModel(pair string) bool {
+	p, err := provider.NewPair(pair)
+	if err != nil {
+		return false
+	}
+	for name := range c.PriceModels {
+		if mp, err := provider.NewPair(name); err == nil && mp.Equal(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := maputil.Keys(m)
+	sort.Strings(keys)
+	return keys
+}
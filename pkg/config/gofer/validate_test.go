@@ -0,0 +1,181 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gofer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		problems []Problem
+	}{
+		{
+			name: "valid",
+			config: `
+priceModels:
+  A/B:
+    method: median
+    sources: [[{origin: binance, pair: A/B}], [{origin: kraken, pair: A/B}]]
+    params: {minimumSuccessfulSources: 2}
+  A/C:
+    method: median
+    sources: [[{origin: ., pair: A/B}, {origin: kraken, pair: B/C}]]
+    params: {minimumSuccessfulSources: 1}
+`,
+		},
+		{
+			name: "unknown-origin",
+			config: `
+priceModels:
+  A/B:
+    method: median
+    sources: [[{origin: binance, pair: A/B}], [{origin: foo, pair: A/B}]]
+    params: {minimumSuccessfulSources: 1}
+`,
+			problems: []Problem{
+				{Pair: "A/B", Message: "unknown origin foo", Fatal: true},
+			},
+		},
+		{
+			name: "nested-unknown-origin",
+			config: `
+priceModels:
+  A/B:
+    method: median
+    sources:
+      - [{model: {method: median, sources: [[{origin: binance, pair: A/B}], [{origin: foo, pair: A/B}]]}}]
+      - [{origin: kraken, pair: A/B}]
+    params: {minimumSuccessfulSources: 1}
+`,
+			problems: []Problem{
+				{Pair: "A/B", Message: "unknown origin foo", Fatal: true},
+			},
+		},
+		{
+			name: "no-reachable-origins",
+			config: `
+priceModels:
+  A/B:
+    method: median
+    sources: [[{origin: foo, pair: A/B}]]
+    params: {minimumSuccessfulSources: 1}
+`,
+			problems: []Problem{
+				{Pair: "A/B", Message: "unknown origin foo", Fatal: true},
+				{Pair: "A/B", Message: "no reachable origins", Fatal: true},
+			},
+		},
+		{
+			name: "undefined-reference",
+			config: `
+priceModels:
+  A/C:
+    method: median
+    sources: [[{origin: ., pair: A/B}, {origin: kraken, pair: B/C}]]
+    params: {minimumSuccessfulSources: 1}
+`,
+			problems: []Problem{
+				{Pair: "A/C", Message: "reference to an undefined price model A/B", Fatal: true},
+				{Message: "unable to find price model for the A/B pair", Fatal: true},
+			},
+		},
+		{
+			name: "not-enough-sources",
+			config: `
+priceModels:
+  A/B:
+    method: median
+    sources: [[{origin: binance, pair: A/B}]]
+    params: {minimumSuccessfulSources: 2}
+`,
+			problems: []Problem{
+				{Pair: "A/B", Message: "minimumSuccessfulSources is 2 but only 1 sources are defined", Fatal: true},
+			},
+		},
+		{
+			name: "cycle",
+			config: `
+priceModels:
+  A/B:
+    method: median
+    sources: [[{origin: ., pair: A/C}, {origin: kraken, pair: C/B}]]
+    params: {minimumSuccessfulSources: 1}
+  A/C:
+    method: median
+    sources: [[{origin: ., pair: A/B}, {origin: kraken, pair: B/C}]]
+    params: {minimumSuccessfulSources: 1}
+`,
+			problems: []Problem{
+				{
+					Message: "a cyclic reference was detected for the A/B pair: " +
+						"*nodes.MedianAggregatorNode(A/B) -> *nodes.IndirectAggregatorNode(A/B) -> " +
+						"*nodes.MedianAggregatorNode(A/C) -> *nodes.IndirectAggregatorNode(A/C) -> " +
+						"*nodes.MedianAggregatorNode(A/B)",
+					Fatal: true,
+				},
+			},
+		},
+		{
+			name: "unused-origin",
+			config: `
+origins:
+  bar:
+    type: binance
+priceModels:
+  A/B:
+    method: median
+    sources: [[{origin: binance, pair: A/B}]]
+    params: {minimumSuccessfulSources: 1}
+`,
+			problems: []Problem{
+				{Message: "origin bar is not used by any price model", Fatal: false},
+			},
+		},
+		{
+			name: "invalid-origin",
+			config: `
+origins:
+  bar:
+    type: foo
+priceModels:
+  A/B:
+    method: median
+    sources: [[{origin: bar, pair: A/B}]]
+    params: {minimumSuccessfulSources: 1}
+`,
+			problems: []Problem{
+				{Message: "invalid foo origin bar: unknown origin", Fatal: true},
+				{Pair: "A/B", Message: "unknown origin bar", Fatal: true},
+				{Pair: "A/B", Message: "no reachable origins", Fatal: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg Gofer
+			require.NoError(t, config.Parse(&cfg, []byte(tt.config)))
+			assert.Equal(t, tt.problems, cfg.Validate())
+		})
+	}
+}
@@ -16,6 +16,9 @@
 package spectre
 
 import (
+	"errors"
+	"fmt"
+	"math/big"
 	"time"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/store"
@@ -28,6 +31,10 @@ import (
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
 )
 
+// defaultCallTimeout is the default time limit in seconds for a single call
+// to an Oracle contract.
+const defaultCallTimeout = 30
+
 //nolint
 var spectreFactory = func(cfg spectre.Config) (*spectre.Spectre, error) {
 	return spectre.NewSpectre(cfg)
@@ -39,8 +46,30 @@ var priceStoreFactory = func(cfg store.Config) (*store.PriceStore, error) {
 }
 
 type Spectre struct {
-	Interval    int64                 `yaml:"interval"`
-	Medianizers map[string]Medianizer `yaml:"medianizers"`
+	Interval int64 `yaml:"interval"`
+	// IntervalJitter is the maximum fraction of the interval by which the
+	// first update is randomly delayed, e.g. 0.5 for half of the interval.
+	IntervalJitter float64               `yaml:"intervalJitter"`
+	Medianizers    map[string]Medianizer `yaml:"medianizers"`
+	// CallTimeout is the time limit in seconds for a single call to an
+	// Oracle contract. If zero, the default of 30 seconds is used.
+	CallTimeout int64 `yaml:"callTimeout"`
+	// BatchPoke enables updating all Oracles in a single transaction using
+	// the Multicall2 or Multicall3 contract, whose address is given in
+	// the Multicall field.
+	BatchPoke bool   `yaml:"batchPoke"`
+	Multicall string `yaml:"multicall"`
+	// FeederGroups maps feeder addresses to operator groups. It is used
+	// together with MinFeederGroups to require that prices sent to an
+	// Oracle come from feeders operated by different parties.
+	FeederGroups map[string]string `yaml:"feederGroups"`
+	// MinFeederGroups is the minimum number of distinct feeder groups
+	// required to update an Oracle. Feeders without a group are counted as
+	// separate groups.
+	MinFeederGroups int `yaml:"minFeederGroups"`
+	// Domain is an optional network identifier. If set, only prices
+	// signed for this domain are accepted.
+	Domain string `yaml:"domain"`
 }
 
 type Medianizer struct {
@@ -48,6 +77,17 @@ type Medianizer struct {
 	OracleSpread     float64 `yaml:"oracleSpread"`
 	OracleExpiration int64   `yaml:"oracleExpiration"`
 	MsgExpiration    int64   `yaml:"msgExpiration"`
+	// MinQuorum is the minimum number of prices required to update the
+	// Oracle. If it is greater than the quorum of the Oracle contract, it
+	// is used instead. Optional, e.g. to require a higher quorum during
+	// a migration. Only as many prices as the quorum of the Oracle contract
+	// are sent, because the contract rejects updates with any other number
+	// of prices.
+	MinQuorum int64 `yaml:"minQuorum"`
+	// MaxPokeCost is the maximum estimated cost, in ether, of an update
+	// caused by the spread. Expired Oracles are updated regardless of the
+	// cost. Optional, if zero, the cost is not checked.
+	MaxPokeCost float64 `yaml:"maxPokeCost"`
 }
 
 type Dependencies struct {
@@ -66,30 +106,74 @@ type PriceStoreDependencies struct {
 }
 
 func (c *Spectre) ConfigureSpectre(d Dependencies) (*spectre.Spectre, error) {
+	if c.CallTimeout < 0 {
+		return nil, errors.New("callTimeout must not be negative")
+	}
+	callTimeout := c.CallTimeout
+	if callTimeout == 0 {
+		callTimeout = defaultCallTimeout
+	}
 	cfg := spectre.Config{
-		Signer:     d.Signer,
-		Interval:   time.Second * time.Duration(c.Interval),
-		PriceStore: d.PriceStore,
-		Logger:     d.Logger,
+		Signer:         d.Signer,
+		Interval:       time.Second * time.Duration(c.Interval),
+		IntervalJitter: c.IntervalJitter,
+		CallTimeout:    time.Second * time.Duration(callTimeout),
+		PriceStore:     d.PriceStore,
+		Logger:         d.Logger,
+	}
+	if len(c.FeederGroups) > 0 {
+		cfg.FeederGroups = make(map[ethereum.Address]string, len(c.FeederGroups))
+		for addr, group := range c.FeederGroups {
+			if !ethereum.IsHexAddress(addr) {
+				return nil, fmt.Errorf("invalid feeder address in feederGroups: %s", addr)
+			}
+			cfg.FeederGroups[ethereum.HexToAddress(addr)] = group
+		}
+	}
+	cfg.MinFeederGroups = c.MinFeederGroups
+	if c.BatchPoke {
+		if !ethereum.IsHexAddress(c.Multicall) {
+			return nil, errors.New("multicall contract address must be provided when batchPoke is enabled")
+		}
+		cfg.BatchPoker = oracleGeth.NewMulticall(d.EthereumClient, ethereum.HexToAddress(c.Multicall))
 	}
 	for name, pair := range c.Medianizers {
+		if pair.MinQuorum < 0 {
+			return nil, fmt.Errorf("minQuorum for the %s medianizer must not be negative", name)
+		}
+		if pair.MaxPokeCost < 0 {
+			return nil, fmt.Errorf("maxPokeCost for the %s medianizer must not be negative", name)
+		}
 		cfg.Pairs = append(cfg.Pairs, &spectre.Pair{
 			AssetPair:        name,
 			OracleSpread:     pair.OracleSpread,
 			OracleExpiration: time.Second * time.Duration(pair.OracleExpiration),
 			PriceExpiration:  time.Second * time.Duration(pair.MsgExpiration),
 			Median:           oracleGeth.NewMedian(d.EthereumClient, ethereum.HexToAddress(pair.Contract)),
+			MinQuorum:        pair.MinQuorum,
+			MaxPokeCost:      etherToWei(pair.MaxPokeCost),
 		})
 	}
 	return spectreFactory(cfg)
 }
 
+// etherToWei converts the amount in ether to wei. It returns nil for zero.
+func etherToWei(ether float64) *big.Int {
+	if ether == 0 {
+		return nil
+	}
+	wei, _ := new(big.Float).Mul(big.NewFloat(ether), big.NewFloat(1e18)).Int(nil)
+	return wei
+}
+
 func (c *Spectre) ConfigurePriceStore(d PriceStoreDependencies) (*store.PriceStore, error) {
 	cfg := store.Config{
 		Storage:   store.NewMemoryStorage(),
 		Signer:    d.Signer,
 		Transport: d.Transport,
+		Feeds:     d.Feeds,
 		Pairs:     maputil.Keys(c.Medianizers),
+		Domain:    c.Domain,
 		Logger:    d.Logger,
 	}
 
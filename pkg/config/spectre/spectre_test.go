@@ -16,6 +16,7 @@
 package spectre
 
 import (
+	"math/big"
 	"testing"
 	"time"
 
@@ -25,6 +26,7 @@ import (
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	ethereumMocks "github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	oracleGeth "github.com/chronicleprotocol/oracle-suite/pkg/price/oracle/geth"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/store"
 	"github.com/chronicleprotocol/oracle-suite/pkg/spectre"
 )
@@ -45,13 +47,17 @@ func TestSpectre_Configure(t *testing.T) {
 	logger := null.New()
 
 	config := Spectre{
-		Interval: interval,
+		Interval:       interval,
+		IntervalJitter: 0.5,
+		CallTimeout:    5,
 		Medianizers: map[string]Medianizer{
 			"AAABBB": {
 				Contract:         "0xe0F30cb149fAADC7247E953746Be9BbBB6B5751f",
 				OracleSpread:     0.1,
 				OracleExpiration: 15500,
 				MsgExpiration:    1800,
+				MinQuorum:        5,
+				MaxPokeCost:      0.01,
 			},
 		},
 	}
@@ -60,11 +66,15 @@ func TestSpectre_Configure(t *testing.T) {
 		assert.Equal(t, signer, cfg.Signer)
 		assert.Equal(t, ps, cfg.PriceStore)
 		assert.Equal(t, secToDuration(interval), cfg.Interval)
+		assert.Equal(t, 0.5, cfg.IntervalJitter)
+		assert.Equal(t, 5*time.Second, cfg.CallTimeout)
 		assert.Equal(t, logger, cfg.Logger)
 		assert.Equal(t, "AAABBB", cfg.Pairs[0].AssetPair)
 		assert.Equal(t, secToDuration(config.Medianizers["AAABBB"].OracleExpiration), cfg.Pairs[0].OracleExpiration)
 		assert.Equal(t, secToDuration(config.Medianizers["AAABBB"].MsgExpiration), cfg.Pairs[0].PriceExpiration)
 		assert.Equal(t, config.Medianizers["AAABBB"].OracleSpread, cfg.Pairs[0].OracleSpread)
+		assert.Equal(t, int64(5), cfg.Pairs[0].MinQuorum)
+		assert.Equal(t, big.NewInt(1e16), cfg.Pairs[0].MaxPokeCost)
 		assert.Equal(t, ethereum.HexToAddress(config.Medianizers["AAABBB"].Contract), cfg.Pairs[0].Median.Address())
 		return &spectre.Spectre{}, nil
 	}
@@ -80,6 +90,124 @@ func TestSpectre_Configure(t *testing.T) {
 	require.NotNil(t, s)
 }
 
+func TestSpectre_Configure_BatchPoke(t *testing.T) {
+	prevSpectreFactory := spectreFactory
+	defer func() {
+		spectreFactory = prevSpectreFactory
+	}()
+
+	ethClient := &ethereumMocks.Client{}
+	multicall := "0xeefba1e63905ef1d7acba5a8513c70307c1ce441"
+
+	config := Spectre{
+		Interval:  10,
+		BatchPoke: true,
+		Multicall: multicall,
+	}
+
+	spectreFactory = func(cfg spectre.Config) (*spectre.Spectre, error) {
+		assert.Equal(t, oracleGeth.NewMulticall(ethClient, ethereum.HexToAddress(multicall)), cfg.BatchPoker)
+		return &spectre.Spectre{}, nil
+	}
+
+	_, err := config.ConfigureSpectre(Dependencies{
+		Signer:         &ethereumMocks.Signer{},
+		PriceStore:     &store.PriceStore{},
+		EthereumClient: ethClient,
+	})
+	require.NoError(t, err)
+
+	// Multicall address is required:
+	config.Multicall = ""
+	_, err = config.ConfigureSpectre(Dependencies{
+		Signer:         &ethereumMocks.Signer{},
+		PriceStore:     &store.PriceStore{},
+		EthereumClient: ethClient,
+	})
+	require.Error(t, err)
+}
+
+func TestSpectre_Configure_FeederGroups(t *testing.T) {
+	prevSpectreFactory := spectreFactory
+	defer func() {
+		spectreFactory = prevSpectreFactory
+	}()
+
+	feeder := "0x07a35a1d4b751a818d93aa38e615c0df23064881"
+	config := Spectre{
+		Interval:        10,
+		FeederGroups:    map[string]string{feeder: "operatorA"},
+		MinFeederGroups: 2,
+	}
+
+	spectreFactory = func(cfg spectre.Config) (*spectre.Spectre, error) {
+		assert.Equal(t, map[ethereum.Address]string{ethereum.HexToAddress(feeder): "operatorA"}, cfg.FeederGroups)
+		assert.Equal(t, 2, cfg.MinFeederGroups)
+		return &spectre.Spectre{}, nil
+	}
+
+	_, err := config.ConfigureSpectre(Dependencies{
+		Signer:         &ethereumMocks.Signer{},
+		PriceStore:     &store.PriceStore{},
+		EthereumClient: &ethereumMocks.Client{},
+	})
+	require.NoError(t, err)
+
+	// Feeder addresses must be valid:
+	config.FeederGroups = map[string]string{"invalid": "operatorA"}
+	_, err = config.ConfigureSpectre(Dependencies{
+		Signer:         &ethereumMocks.Signer{},
+		PriceStore:     &store.PriceStore{},
+		EthereumClient: &ethereumMocks.Client{},
+	})
+	require.Error(t, err)
+}
+
 func secToDuration(s int64) time.Duration {
 	return time.Duration(s) * time.Second
 }
+
+func TestSpectre_Configure_NegativeMinQuorum(t *testing.T) {
+	config := Spectre{
+		Interval: 10,
+		Medianizers: map[string]Medianizer{
+			"AAABBB": {Contract: "0xe0F30cb149fAADC7247E953746Be9BbBB6B5751f", MinQuorum: -1},
+		},
+	}
+
+	_, err := config.ConfigureSpectre(Dependencies{
+		Signer:         &ethereumMocks.Signer{},
+		PriceStore:     &store.PriceStore{},
+		EthereumClient: &ethereumMocks.Client{},
+	})
+	require.Error(t, err)
+}
+
+func TestSpectre_Configure_CallTimeout(t *testing.T) {
+	prevSpectreFactory := spectreFactory
+	defer func() {
+		spectreFactory = prevSpectreFactory
+	}()
+
+	var callTimeout time.Duration
+	spectreFactory = func(cfg spectre.Config) (*spectre.Spectre, error) {
+		callTimeout = cfg.CallTimeout
+		return &spectre.Spectre{}, nil
+	}
+	deps := Dependencies{
+		Signer:         &ethereumMocks.Signer{},
+		PriceStore:     &store.PriceStore{},
+		EthereumClient: &ethereumMocks.Client{},
+	}
+
+	// The default timeout is used if not set:
+	config := Spectre{Interval: 10}
+	_, err := config.ConfigureSpectre(deps)
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, callTimeout)
+
+	// The timeout must not be negative:
+	config.CallTimeout = -1
+	_, err = config.ConfigureSpectre(deps)
+	require.Error(t, err)
+}
@@ -42,6 +42,9 @@ type Spire struct {
 	RPC           RPC      `yaml:"rpc"` // Old configuration format, to remove in the future.
 	RPCListenAddr string   `yaml:"rpcListenAddr"`
 	Pairs         []string `yaml:"pairs"`
+	// Domain is an optional network identifier. If set, only prices
+	// signed for this domain are accepted.
+	Domain string `yaml:"domain"`
 }
 
 type RPC struct {
@@ -101,7 +104,9 @@ func (c *Spire) ConfigurePriceStore(d PriceStoreDependencies) (*store.PriceStore
 		Storage:   store.NewMemoryStorage(),
 		Signer:    d.Signer,
 		Transport: d.Transport,
+		Feeds:     d.Feeds,
 		Pairs:     c.Pairs,
+		Domain:    c.Domain,
 		Logger:    d.Logger,
 	}
 	return priceStoreFactory(cfg)
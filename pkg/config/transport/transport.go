@@ -23,6 +23,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/libp2p/go-libp2p-core/crypto"
 
@@ -30,12 +31,15 @@ import (
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/file"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/libp2p"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/libp2p/crypto/ethkey"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/multi"
 )
 
 const LibP2P = "libp2p"
 const LibSSB = "ssb"
+const File = "file"
 const DefaultTransport = LibP2P
 
 var p2pTransportFactory = func(cfg libp2p.Config) (transport.Transport, error) {
@@ -46,21 +50,33 @@ type Transport struct {
 	Transport string      `yaml:"transport"`
 	P2P       P2P         `yaml:"libp2p"`
 	SSB       Scuttlebutt `yaml:"ssb"`
+	File      FileReplay  `yaml:"file"`
 }
 
 type P2P struct {
-	PrivKeySeed      string   `yaml:"privKeySeed"`
-	ListenAddrs      []string `yaml:"listenAddrs"`
-	BootstrapAddrs   []string `yaml:"bootstrapAddrs"`
-	DirectPeersAddrs []string `yaml:"directPeersAddrs"`
-	BlockedAddrs     []string `yaml:"blockedAddrs"`
-	DisableDiscovery bool     `yaml:"disableDiscovery"`
+	PrivKeySeed      string         `yaml:"privKeySeed"`
+	ListenAddrs      []string       `yaml:"listenAddrs"`
+	BootstrapAddrs   []string       `yaml:"bootstrapAddrs"`
+	DirectPeersAddrs []string       `yaml:"directPeersAddrs"`
+	BlockedAddrs     []string       `yaml:"blockedAddrs"`
+	DisableDiscovery bool           `yaml:"disableDiscovery"`
+	Compression      bool           `yaml:"compression"`
+	MaxMessageSize   map[string]int `yaml:"maxMessageSize"`
+	DedupCacheSize   int            `yaml:"dedupCacheSize"`
+	DedupTTL         int            `yaml:"dedupTTL"`
+	MessageTTL       int            `yaml:"messageTTL"`
 }
 
 type Scuttlebutt struct {
 	Caps string `yaml:"caps"`
 }
 
+type FileReplay struct {
+	ReadPath  string `yaml:"readPath"`
+	WritePath string `yaml:"writePath"`
+	Timing    bool   `yaml:"timing"`
+}
+
 type Caps struct {
 	Shs    string `yaml:"shs"`
 	Sign   string `yaml:"sign"`
@@ -77,10 +93,52 @@ type BootstrapDependencies struct {
 	Logger log.Logger
 }
 
+// Configure returns the configured transport. Multiple transports may be
+// given as a comma-separated list, e.g. "libp2p,file", in which case they are
+// used simultaneously.
 func (c *Transport) Configure(d Dependencies, t map[string]transport.Message) (transport.Transport, error) {
-	switch strings.ToLower(c.Transport) {
+	names := strings.Split(c.Transport, ",")
+	if len(names) == 1 {
+		return c.configureTransport(names[0], d, t)
+	}
+	var ts []transport.Transport
+	for _, name := range names {
+		tr, err := c.configureTransport(strings.TrimSpace(name), d, t)
+		if err != nil {
+			return nil, err
+		}
+		ts = append(ts, tr)
+	}
+	return multi.New(multi.Config{
+		Transports:     ts,
+		Topics:         t,
+		DedupCacheSize: c.P2P.DedupCacheSize,
+		DedupTTL:       time.Duration(c.P2P.DedupTTL) * time.Second,
+		Logger:         d.Logger,
+	})
+}
+
+func (c *Transport) configureTransport(
+	name string,
+	d Dependencies,
+	t map[string]transport.Message,
+) (transport.Transport, error) {
+	switch strings.ToLower(name) {
 	case LibSSB:
 		return nil, errors.New("ssb not yet implemented")
+	case File:
+		var id []byte
+		if d.Signer != nil {
+			id = d.Signer.Address().Bytes()
+		}
+		return file.New(file.Config{
+			ID:        id,
+			Topics:    t,
+			ReadPath:  c.File.ReadPath,
+			WritePath: c.File.WritePath,
+			Timing:    c.File.Timing,
+			Logger:    d.Logger,
+		})
 	case LibP2P:
 		fallthrough
 	default:
@@ -103,6 +161,11 @@ func (c *Transport) Configure(d Dependencies, t map[string]transport.Message) (t
 			BlockedAddrs:     c.P2P.BlockedAddrs,
 			FeedersAddrs:     d.Feeds,
 			Discovery:        !c.P2P.DisableDiscovery,
+			Compression:      c.P2P.Compression,
+			MaxMessageSize:   c.P2P.MaxMessageSize,
+			DedupCacheSize:   c.P2P.DedupCacheSize,
+			DedupTTL:         time.Duration(c.P2P.DedupTTL) * time.Second,
+			MessageTTL:       time.Duration(c.P2P.MessageTTL) * time.Second,
 			Signer:           d.Signer,
 			Logger:           d.Logger,
 			AppName:          "spire",
@@ -16,7 +16,9 @@
 package transport
 
 import (
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -25,9 +27,11 @@ import (
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/file"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/libp2p"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/local"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/multi"
 )
 
 func TestTransport_P2P_EmptyConfig(t *testing.T) {
@@ -102,6 +106,9 @@ func TestTransport_P2P_CustomValues(t *testing.T) {
 			DirectPeersAddrs: directPeersAddrs,
 			BlockedAddrs:     blockedAddrs,
 			DisableDiscovery: true,
+			DedupCacheSize:   100,
+			DedupTTL:         60,
+			MessageTTL:       30,
 		},
 	}
 
@@ -114,6 +121,9 @@ func TestTransport_P2P_CustomValues(t *testing.T) {
 		assert.Equal(t, blockedAddrs, cfg.BlockedAddrs)
 		assert.Equal(t, map[string]transport.Message{messages.PriceV0MessageName: (*messages.Price)(nil)}, cfg.Topics)
 		assert.Equal(t, false, cfg.Discovery)
+		assert.Equal(t, 100, cfg.DedupCacheSize)
+		assert.Equal(t, time.Minute, cfg.DedupTTL)
+		assert.Equal(t, 30*time.Second, cfg.MessageTTL)
 		assert.Equal(t, "spire", cfg.AppName)
 		assert.Equal(t, feeds, cfg.FeedersAddrs)
 		assert.Same(t, signer, cfg.Signer)
@@ -163,3 +173,53 @@ func TestTransport_P2P_InvalidSeed(t *testing.T) {
 	}, nil)
 	require.Error(t, err)
 }
+
+func TestTransport_File(t *testing.T) {
+	signer := &mocks.Signer{}
+	signer.On("Address").Return(ethereum.HexToAddress("0x07a35a1d4b751a818d93aa38e615c0df23064881"))
+
+	config := Transport{
+		Transport: File,
+		File: FileReplay{
+			WritePath: filepath.Join(t.TempDir(), "messages.ndjson"),
+		},
+	}
+
+	tra, err := config.Configure(Dependencies{
+		Signer: signer,
+		Logger: null.New(),
+	},
+		map[string]transport.Message{messages.PriceV0MessageName: (*messages.Price)(nil)},
+	)
+	require.NoError(t, err)
+	assert.IsType(t, &file.File{}, tra)
+	assert.Equal(t, signer.Address().Bytes(), tra.ID())
+}
+
+func TestTransport_Multi(t *testing.T) {
+	prevP2PTransportFactory := p2pTransportFactory
+	defer func() { p2pTransportFactory = prevP2PTransportFactory }()
+
+	signer := &mocks.Signer{}
+	signer.On("Address").Return(ethereum.HexToAddress("0x07a35a1d4b751a818d93aa38e615c0df23064881"))
+	p2pTransportFactory = func(cfg libp2p.Config) (transport.Transport, error) {
+		return local.New([]byte("test"), 0, nil), nil
+	}
+
+	config := Transport{
+		Transport: "libp2p, file",
+		File: FileReplay{
+			WritePath: filepath.Join(t.TempDir(), "messages.ndjson"),
+		},
+	}
+
+	tra, err := config.Configure(Dependencies{
+		Signer: signer,
+		Logger: null.New(),
+	},
+		map[string]transport.Message{messages.PriceV0MessageName: (*messages.Price)(nil)},
+	)
+	require.NoError(t, err)
+	assert.IsType(t, &multi.Multi{}, tra)
+	assert.Equal(t, []byte("test"), tra.ID())
+}
@@ -84,6 +84,11 @@ type Client interface {
 	// SendTransaction injects a signed transaction into the pending pool
 	// for execution.
 	SendTransaction(ctx context.Context, transaction *Transaction) (*Hash, error)
+	// EstimateGas returns the amount of gas required to execute the call
+	// as a transaction.
+	EstimateGas(ctx context.Context, call Call) (uint64, error)
+	// GasPrice returns the currently suggested gas price.
+	GasPrice(ctx context.Context) (*big.Int, error)
 	// FilterLogs executes a filter query.
 	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
 }
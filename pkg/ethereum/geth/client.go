@@ -80,6 +80,7 @@ type EthClient interface {
 	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
 	SuggestGasPrice(ctx context.Context) (*big.Int, error)
 	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error)
 	NetworkID(ctx context.Context) (*big.Int, error)
 	BlockNumber(ctx context.Context) (uint64, error)
 	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
@@ -267,6 +268,29 @@ func (e *Client) SendTransaction(ctx context.Context, transaction *pkgEthereum.T
 	return nil, ErrInvalidSignedTxType
 }
 
+// EstimateGas implements the ethereum.Client interface.
+func (e *Client) EstimateGas(ctx context.Context, call pkgEthereum.Call) (uint64, error) {
+	addr := common.Address{}
+	if e.signer != nil {
+		addr = e.signer.Address()
+	}
+
+	gas, err := e.ethClient.EstimateGas(ctx, ethereum.CallMsg{
+		From: addr,
+		To:   &call.Address,
+		Data: call.Data,
+	})
+	if err := isRevertErr(err); err != nil {
+		return 0, err
+	}
+	return gas, err
+}
+
+// GasPrice implements the ethereum.Client interface.
+func (e *Client) GasPrice(ctx context.Context) (*big.Int, error) {
+	return e.ethClient.SuggestGasPrice(ctx)
+}
+
 // FilterLogs implements the ethereum.Client interface.
 func (e *Client) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
 	return e.ethClient.FilterLogs(ctx, query)
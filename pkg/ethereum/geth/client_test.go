@@ -179,6 +179,27 @@ func TestClient_Storage(t *testing.T) {
 	assert.Equal(t, data, resp)
 }
 
+func TestClient_EstimateGas(t *testing.T) {
+	account, _ := NewAccount("./testdata/keystore", "test123", clientAddress)
+	ethClient := &mocks.EthClient{}
+	client := NewClient(ethClient, NewSigner(account))
+
+	ethClient.On("EstimateGas", mock.Anything, mock.Anything).Return(uint64(21000), nil)
+
+	gas, err := client.EstimateGas(
+		context.Background(),
+		pkgEthereum.Call{Address: clientContractAddress, Data: clientCallData},
+	)
+
+	cm := ethClient.Calls()[0].Arguments.Get(1).(ethereum.CallMsg)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(21000), gas)
+	assert.Equal(t, clientCallData, cm.Data)
+	assert.Equal(t, clientAddress, cm.From)
+	assert.Equal(t, clientContractAddress, *cm.To)
+}
+
 func TestClient_SendTransaction(t *testing.T) {
 	account, _ := NewAccount("./testdata/keystore", "test123", clientAddress)
 	ethClient := &mocks.EthClient{}
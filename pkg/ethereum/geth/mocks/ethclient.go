@@ -80,6 +80,13 @@ func (e *EthClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
 	return args.Get(0).(*big.Int), args.Error(1)
 }
 
+func (e *EthClient) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	args := e.Called(ctx, call)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
 func (e *EthClient) NetworkID(ctx context.Context) (*big.Int, error) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
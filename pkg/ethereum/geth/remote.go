@@ -0,0 +1,226 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package geth
+
+import (
+	"bytes"
+	"context"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+)
+
+const defaultRemoteSignerTimeout = 10 * time.Second
+
+var ErrRemoteSignature = errors.New("remote signature does not match the signer address")
+
+// secp256k1N is the order of the secp256k1 curve.
+var secp256k1N = crypto.S256().Params().N
+var secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+
+// KeyService signs digests using a key that is stored remotely, e.g. in
+// AWS KMS or in an HSM. The private key never leaves the service.
+type KeyService interface {
+	// Sign signs the 32-byte digest with the secp256k1 key identified by
+	// keyID. The signature must be returned in the ASN.1 DER format, as
+	// defined in RFC 3279, which is the format used by AWS KMS.
+	Sign(ctx context.Context, keyID string, digest []byte) ([]byte, error)
+}
+
+// RemoteSigner implements the ethereum.Signer interface using a KeyService.
+//
+// Because the KeyService returns signatures without the recovery ID, the
+// address of the key must be known upfront. It is used to find the correct
+// recovery ID for every signature.
+type RemoteSigner struct {
+	service KeyService
+	keyID   string
+	address ethereum.Address
+	timeout time.Duration
+}
+
+// NewRemoteSigner returns a new RemoteSigner instance. The address must be
+// the Ethereum address of the key identified by keyID.
+func NewRemoteSigner(service KeyService, keyID string, address ethereum.Address) *RemoteSigner {
+	return &RemoteSigner{
+		service: service,
+		keyID:   keyID,
+		address: address,
+		timeout: defaultRemoteSignerTimeout,
+	}
+}
+
+// Address implements the ethereum.Signer interface.
+func (s *RemoteSigner) Address() ethereum.Address {
+	return s.address
+}
+
+// SignTransaction implements the ethereum.Signer interface.
+func (s *RemoteSigner) SignTransaction(transaction *ethereum.Transaction) error {
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   transaction.ChainID,
+		Nonce:     transaction.Nonce,
+		GasTipCap: transaction.PriorityFee,
+		GasFeeCap: transaction.MaxFee,
+		Gas:       transaction.GasLimit.Uint64(),
+		To:        &transaction.Address,
+		Data:      transaction.Data,
+	})
+	signer := types.LatestSignerForChainID(transaction.ChainID)
+	sig, err := s.sign(signer.Hash(tx).Bytes())
+	if err != nil {
+		return err
+	}
+	signedTx, err := tx.WithSignature(signer, sig)
+	if err != nil {
+		return err
+	}
+	transaction.SignedTx = signedTx
+	return nil
+}
+
+// Signature implements the ethereum.Signer interface.
+func (s *RemoteSigner) Signature(data []byte) (ethereum.Signature, error) {
+	msg := []byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(data), data))
+	sig, err := s.sign(crypto.Keccak256(msg))
+	if err != nil {
+		return ethereum.Signature{}, err
+	}
+
+	// Transform V from 0/1 to 27/28 according to the yellow paper:
+	sig[64] += 27
+
+	return ethereum.SignatureFromBytes(sig), nil
+}
+
+// Recover implements the ethereum.Signer interface.
+func (s *RemoteSigner) Recover(signature ethereum.Signature, data []byte) (*ethereum.Address, error) {
+	return Recover(signature, data)
+}
+
+// sign signs the digest using the KeyService and returns the signature in
+// the 65-byte [R || S || V] format, where V is 0 or 1.
+func (s *RemoteSigner) sign(digest []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	der, err := s.service.Sign(ctx, s.keyID, digest)
+	if err != nil {
+		return nil, err
+	}
+	var rs struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(der, &rs); err != nil {
+		return nil, fmt.Errorf("unable to decode remote signature: %w", err)
+	}
+	if rs.R == nil || rs.S == nil || rs.R.BitLen() > 256 || rs.S.BitLen() > 256 {
+		return nil, errors.New("unable to decode remote signature: invalid R or S value")
+	}
+	// Ethereum accepts only signatures with the S value in the lower half
+	// of the curve order (EIP-2), but a remote service may return either:
+	if rs.S.Cmp(secp256k1HalfN) > 0 {
+		rs.S = new(big.Int).Sub(secp256k1N, rs.S)
+	}
+	sig := make([]byte, 65)
+	rs.R.FillBytes(sig[0:32])
+	rs.S.FillBytes(sig[32:64])
+	// Find the recovery ID for which the signature recovers to the signer
+	// address:
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+		pub, err := crypto.SigToPub(digest, sig)
+		if err != nil {
+			continue
+		}
+		if crypto.PubkeyToAddress(*pub) == s.address {
+			return sig, nil
+		}
+	}
+	return nil, ErrRemoteSignature
+}
+
+// HTTPKeyService is a KeyService that delegates signing to a remote signer
+// over HTTP. The digest is sent in a POST request as the following JSON
+// object:
+//
+//	{"keyId": "...", "region": "...", "digest": "0x..."}
+//
+// The service must respond with the DER encoded signature:
+//
+//	{"signature": "0x..."}
+type HTTPKeyService struct {
+	url    string
+	region string
+	client *http.Client
+}
+
+// NewHTTPKeyService returns a new HTTPKeyService instance. The region is
+// passed to the remote signer as is, and it may be empty.
+func NewHTTPKeyService(url, region string, client *http.Client) *HTTPKeyService {
+	if client == nil {
+		client = &http.Client{Timeout: defaultRemoteSignerTimeout}
+	}
+	return &HTTPKeyService{url: url, region: region, client: client}
+}
+
+type httpKeyServiceRequest struct {
+	KeyID  string `json:"keyId"`
+	Region string `json:"region,omitempty"`
+	Digest string `json:"digest"`
+}
+
+type httpKeyServiceResponse struct {
+	Signature string `json:"signature"`
+}
+
+// Sign implements the KeyService interface.
+func (s *HTTPKeyService) Sign(ctx context.Context, keyID string, digest []byte) ([]byte, error) {
+	body, err := json.Marshal(httpKeyServiceRequest{
+		KeyID:  keyID,
+		Region: s.region,
+		Digest: "0x" + hex.EncodeToString(digest),
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("remote signer returned unexpected status code: %d", res.StatusCode)
+	}
+	var r httpKeyServiceResponse
+	if err := json.NewDecoder(res.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("unable to decode remote signer response: %w", err)
+	}
+	return hex.DecodeString(strings.TrimPrefix(r.Signature, "0x"))
+}
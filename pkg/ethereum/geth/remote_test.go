@@ -0,0 +1,124 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package geth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+)
+
+// testKeyService signs digests with a local key and returns DER encoded
+// signatures, like AWS KMS does.
+type testKeyService struct {
+	key   *ecdsa.PrivateKey
+	highS bool
+}
+
+func (k *testKeyService) Sign(_ context.Context, keyID string, digest []byte) ([]byte, error) {
+	if keyID != "test-key" {
+		return nil, assert.AnError
+	}
+	sig, err := crypto.Sign(digest, k.key)
+	if err != nil {
+		return nil, err
+	}
+	r := new(big.Int).SetBytes(sig[0:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+	if k.highS {
+		s = new(big.Int).Sub(secp256k1N, s)
+	}
+	return asn1.Marshal(struct{ R, S *big.Int }{r, s})
+}
+
+func TestRemoteSigner_Signature(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	for _, highS := range []bool{false, true} {
+		signer := NewRemoteSigner(&testKeyService{key: key, highS: highS}, "test-key", address)
+		for _, data := range [][]byte{[]byte("foo"), []byte("bar"), []byte("baz")} {
+			sig, err := signer.Signature(data)
+			require.NoError(t, err)
+
+			recovered, err := signer.Recover(sig, data)
+			require.NoError(t, err)
+			assert.Equal(t, address, *recovered)
+		}
+	}
+}
+
+func TestRemoteSigner_Signature_WrongAddress(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	signer := NewRemoteSigner(&testKeyService{key: key}, "test-key", ethereum.Address{0x01})
+	_, err = signer.Signature([]byte("foo"))
+	assert.ErrorIs(t, err, ErrRemoteSignature)
+}
+
+func TestRemoteSigner_SignTransaction(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	signer := NewRemoteSigner(&testKeyService{key: key}, "test-key", address)
+	tx := &ethereum.Transaction{
+		Address:     ethereum.Address{0x02},
+		GasLimit:    big.NewInt(21000),
+		MaxFee:      big.NewInt(2),
+		PriorityFee: big.NewInt(1),
+		Nonce:       1,
+		ChainID:     big.NewInt(1),
+	}
+	require.NoError(t, signer.SignTransaction(tx))
+
+	signedTx := tx.SignedTx.(*types.Transaction)
+	from, err := types.Sender(types.LatestSignerForChainID(big.NewInt(1)), signedTx)
+	require.NoError(t, err)
+	assert.Equal(t, address, from)
+}
+
+func TestHTTPKeyService(t *testing.T) {
+	digest := crypto.Keccak256([]byte("foo"))
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		var r httpKeyServiceRequest
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&r))
+		assert.Equal(t, "test-key", r.KeyID)
+		assert.Equal(t, "eu-west-1", r.Region)
+		assert.Equal(t, "0x"+hex.EncodeToString(digest), r.Digest)
+		_ = json.NewEncoder(res).Encode(httpKeyServiceResponse{Signature: "0x3006020101020102"})
+	}))
+	defer srv.Close()
+
+	sig, err := NewHTTPKeyService(srv.URL, "eu-west-1", nil).Sign(context.Background(), "test-key", digest)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x30, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x02}, sig)
+}
@@ -65,6 +65,16 @@ func (e *Client) SendTransaction(ctx context.Context, transaction *ethereum.Tran
 	return args.Get(0).(*ethereum.Hash), args.Error(1)
 }
 
+func (e *Client) EstimateGas(ctx context.Context, call ethereum.Call) (uint64, error) {
+	args := e.Called(ctx, call)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (e *Client) GasPrice(ctx context.Context) (*big.Int, error) {
+	args := e.Called(ctx)
+	return args.Get(0).(*big.Int), args.Error(1)
+}
+
 func (e *Client) FilterLogs(ctx context.Context, query geth.FilterQuery) ([]types.Log, error) {
 	args := e.Called(ctx, query)
 	return args.Get(0).([]types.Log), args.Error(1)
@@ -0,0 +1,242 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package httpsink
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/retry"
+)
+
+const LoggerTag = "HTTP_EVENT_SINK"
+
+const defaultAttempts = 3
+const defaultRetryDelay = time.Second
+const defaultBufferSize = 1000
+const defaultTimeout = 10 * time.Second
+
+// Config is the configuration for the EventSink.
+type Config struct {
+	// URL is the webhook URL to which events are sent.
+	URL string
+	// Client is the HTTP client used to send requests. If nil, a client with
+	// a default timeout is used.
+	Client *http.Client
+	// Attempts is the number of attempts to deliver a single event.
+	// If zero, the default value of 3 is used.
+	Attempts int
+	// RetryDelay is the delay between delivery attempts. If zero, the default
+	// value of one second is used.
+	RetryDelay time.Duration
+	// BufferSize is the number of events that can wait for delivery. If the
+	// buffer is full, new events are dropped. If zero, the default value of
+	// 1000 is used.
+	BufferSize int
+	// Logger is a current logger interface used by the EventSink.
+	Logger log.Logger
+}
+
+// EventSink sends events as JSON to a webhook URL using POST requests.
+//
+// Events are delivered asynchronously, so the Send method never blocks.
+// If an event cannot be delivered after the configured number of attempts, or
+// if the buffer is full, the event is written to the log as a dead letter.
+type EventSink struct {
+	ctx        context.Context
+	waitCh     chan error
+	eventCh    chan *messages.Event
+	url        string
+	client     *http.Client
+	attempts   int
+	retryDelay time.Duration
+	log        log.Logger
+}
+
+// jsonEvent is the JSON representation of an event sent to the webhook.
+type jsonEvent struct {
+	Type        string                   `json:"type"`
+	ID          string                   `json:"id"`
+	Index       string                   `json:"index"`
+	EventDate   int64                    `json:"eventDate"`
+	MessageDate int64                    `json:"messageDate"`
+	Data        map[string]string        `json:"data"`
+	Signatures  map[string]jsonSignature `json:"signatures"`
+}
+
+type jsonSignature struct {
+	Signer    string `json:"signer"`
+	Signature string `json:"signature"`
+}
+
+// New returns a new instance of the EventSink struct.
+func New(cfg Config) (*EventSink, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("url must not be empty")
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: defaultTimeout}
+	}
+	if cfg.Attempts <= 0 {
+		cfg.Attempts = defaultAttempts
+	}
+	if cfg.RetryDelay == 0 {
+		cfg.RetryDelay = defaultRetryDelay
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = defaultBufferSize
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = null.New()
+	}
+	return &EventSink{
+		waitCh:     make(chan error),
+		eventCh:    make(chan *messages.Event, cfg.BufferSize),
+		url:        cfg.URL,
+		client:     cfg.Client,
+		attempts:   cfg.Attempts,
+		retryDelay: cfg.RetryDelay,
+		log:        cfg.Logger.WithField("tag", LoggerTag),
+	}, nil
+}
+
+// Start implements the supervisor.Service interface.
+func (s *EventSink) Start(ctx context.Context) error {
+	if s.ctx != nil {
+		return errors.New("service can be started only once")
+	}
+	if ctx == nil {
+		return errors.New("context must not be nil")
+	}
+	s.log.Infof("Starting")
+	s.ctx = ctx
+	go s.sendRoutine()
+	return nil
+}
+
+// Wait implements the supervisor.Service interface.
+func (s *EventSink) Wait() chan error {
+	return s.waitCh
+}
+
+// Send implements the publisher.EventSink interface.
+//
+// The event is queued for delivery. If the queue is full, the event is
+// dropped and written to the log as a dead letter.
+func (s *EventSink) Send(evt *messages.Event) {
+	select {
+	case s.eventCh <- evt.Copy():
+	default:
+		s.deadLetter(evt, errors.New("buffer is full"))
+	}
+}
+
+func (s *EventSink) sendRoutine() {
+	defer func() { close(s.waitCh) }()
+	defer s.log.Info("Stopped")
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case evt := <-s.eventCh:
+			s.deliver(evt)
+		}
+	}
+}
+
+// deliver sends the event to the webhook. If all attempts fail, the event is
+// written to the log as a dead letter.
+func (s *EventSink) deliver(evt *messages.Event) {
+	body, err := json.Marshal(mapEvent(evt))
+	if err != nil {
+		s.deadLetter(evt, err)
+		return
+	}
+	err = retry.Try(s.ctx, func() error {
+		err := s.post(body)
+		if err != nil {
+			s.log.
+				WithError(err).
+				WithFields(log.Fields{"id": evt.ID, "type": evt.Type}).
+				Warn("Unable to send the event")
+		}
+		return err
+	}, s.attempts, s.retryDelay)
+	if err != nil && s.ctx.Err() == nil {
+		s.deadLetter(evt, err)
+	}
+}
+
+func (s *EventSink) post(body []byte) error {
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+	return nil
+}
+
+func (s *EventSink) deadLetter(evt *messages.Event, err error) {
+	body, _ := json.Marshal(mapEvent(evt))
+	s.log.
+		WithError(err).
+		WithFields(log.Fields{
+			"id":    evt.ID,
+			"type":  evt.Type,
+			"event": string(body),
+		}).
+		Error("Unable to deliver the event, dead letter")
+}
+
+// mapEvent converts the event to its JSON representation.
+func mapEvent(e *messages.Event) *jsonEvent {
+	j := &jsonEvent{
+		Type:        e.Type,
+		ID:          hex.EncodeToString(e.ID),
+		Index:       hex.EncodeToString(e.Index),
+		EventDate:   e.EventDate.Unix(),
+		MessageDate: e.MessageDate.Unix(),
+		Data:        map[string]string{},
+		Signatures:  map[string]jsonSignature{},
+	}
+	for k, v := range e.Data {
+		j.Data[k] = hex.EncodeToString(v)
+	}
+	for k, v := range e.Signatures {
+		j.Signatures[k] = jsonSignature{
+			Signer:    hex.EncodeToString(v.Signer),
+			Signature: hex.EncodeToString(v.Signature),
+		}
+	}
+	return j
+}
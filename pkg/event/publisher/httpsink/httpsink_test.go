@@ -0,0 +1,120 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package httpsink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/callback"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+)
+
+var testEvent = &messages.Event{
+	Type:        "test",
+	ID:          []byte{0x01},
+	Index:       []byte{0x02},
+	EventDate:   time.Unix(1, 0),
+	MessageDate: time.Unix(2, 0),
+	Data:        map[string][]byte{"key": {0x03}},
+	Signatures:  map[string]messages.EventSignature{"sig": {Signer: []byte{0x04}, Signature: []byte{0x05}}},
+}
+
+func TestEventSink(t *testing.T) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFunc()
+
+	var calls int32
+	bodyCh := make(chan *jsonEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		// Fail the first two requests to test retries.
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			res.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		evt := &jsonEvent{}
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(evt))
+		bodyCh <- evt
+	}))
+	defer srv.Close()
+
+	s, err := New(Config{URL: srv.URL, RetryDelay: time.Millisecond})
+	require.NoError(t, err)
+	require.NoError(t, s.Start(ctx))
+
+	s.Send(testEvent)
+
+	select {
+	case evt := <-bodyCh:
+		assert.Equal(t, &jsonEvent{
+			Type:        "test",
+			ID:          "01",
+			Index:       "02",
+			EventDate:   1,
+			MessageDate: 2,
+			Data:        map[string]string{"key": "03"},
+			Signatures:  map[string]jsonSignature{"sig": {Signer: "04", Signature: "05"}},
+		}, evt)
+	case <-ctx.Done():
+		require.Fail(t, "event was not delivered")
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestEventSink_DeadLetter(t *testing.T) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFunc()
+
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		res.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	deadLetterCh := make(chan string, 1)
+	logger := callback.New(log.Debug, func(level log.Level, fields log.Fields, msg string) {
+		if strings.Contains(msg, "dead letter") {
+			deadLetterCh <- fields["event"].(string)
+		}
+	})
+
+	s, err := New(Config{URL: srv.URL, Attempts: 2, RetryDelay: time.Millisecond, Logger: logger})
+	require.NoError(t, err)
+	require.NoError(t, s.Start(ctx))
+
+	s.Send(testEvent)
+
+	select {
+	case evt := <-deadLetterCh:
+		assert.Contains(t, evt, `"type":"test"`)
+	case <-ctx.Done():
+		require.Fail(t, "dead letter was not logged")
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,568 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package logfetcher
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/retry"
+)
+
+// retryInterval is the interval between retry attempts in case of an error
+// while communicating with a node.
+const retryInterval = 5 * time.Second
+
+// filterLogsAttempts is the number of attempts to fetch logs from a single
+// block range before the range is given up until the next fetch cycle.
+const filterLogsAttempts = 3
+
+// filterLogsRetryDelay is the delay before the first retry of a failed
+// FilterLogs call. Every next delay is doubled.
+const filterLogsRetryDelay = time.Second
+
+// blockTimeSampleSize is the number of recent blocks used to estimate the
+// block time.
+const blockTimeSampleSize = 1000
+
+// DecodeFunc converts a log to an event message.
+type DecodeFunc func(types.Log) (*messages.Event, error)
+
+// Config contains a configuration options for LogFetcher.
+type Config struct {
+	// Client is an instance of Ethereum RPC client.
+	Client ethereumv2.Client
+	// Addresses is a list of contracts from which logs will be fetched.
+	Addresses []types.Address
+	// Topics is a topic filter used to fetch logs, as defined for the
+	// eth_getLogs method.
+	Topics []types.Hashes
+	// Decode converts fetched logs to event messages. If it returns an error,
+	// the log is skipped.
+	Decode DecodeFunc
+	// Interval specifies how often fetcher should check for new logs.
+	Interval time.Duration
+	// PrefetchPeriod specifies how far back in time fetcher should prefetch
+	// logs. It is used only during the initial start of the fetcher.
+	PrefetchPeriod time.Duration
+	// MinTimestamp, if set, stops the prefetch at blocks older than the given
+	// time, even if the prefetch period is not yet reached. If the prefetch
+	// period is zero, only MinTimestamp limits the prefetch.
+	MinTimestamp time.Time
+	// BlockTime is the expected time between blocks. If set, the block from
+	// which the prefetch starts is calculated from the prefetch period and
+	// timestamps of blocks are not checked for every fetched range, which
+	// significantly reduces the number of requests on chains with short
+	// block times, like L2 rollups.
+	BlockTime time.Duration
+	// EstimateBlockTime, if true and BlockTime is not set, estimates the
+	// block time from timestamps of recent blocks.
+	EstimateBlockTime bool
+	// BlockLimit specifies how from many blocks logs can be fetched at once.
+	BlockLimit uint64
+	// BlockConfirmations specifies how many blocks should be confirmed before
+	// fetching logs.
+	BlockConfirmations uint64
+	// LagThreshold is the number of blocks by which the fetcher may fall
+	// behind the chain tip before a warning is logged. If zero, no warnings
+	// are logged.
+	LagThreshold uint64
+	// Logger is a current logger interface used by the LogFetcher. It is
+	// used as is, so callers should add their own tag to it.
+	Logger log.Logger
+}
+
+// LogFetcher listens to events on Ethereum compatible blockchains.
+//
+// It periodically fetches new logs that match the topic filter from the
+// blockchain, converts them into messages.Event using the decode function
+// and sends them to the channel provided by Events method.
+//
+// During the initial start of the fetcher it also fetches older blocks
+// until it reaches the block that is older than the prefetch period. This is
+// done to fetch events that were emitted before the fetcher was started.
+//
+// In the event of an error in communication with a node, whether related to
+// network errors or the node itself, the fetcher will try to repeat requests
+// to the node indefinitely. The only exception are requests for logs from
+// new blocks, which are retried a few times with a backoff. If they still
+// fail, the block range is fetched again in the next cycle, so no events are
+// skipped.
+type LogFetcher struct {
+	eventCh chan *messages.Event
+
+	// Configuration parameters copied from Config:
+	client         ethereumv2.Client
+	addresses      []types.Address
+	topics         []types.Hashes
+	decode         DecodeFunc
+	interval       time.Duration
+	prefetchPeriod time.Duration
+	minTimestamp   time.Time
+	blockTime      time.Duration
+	estimateBlock  bool
+	blockLimit     uint64
+	blockConfirms  uint64
+	lagThreshold   uint64
+	log            log.Logger
+
+	// lag is the number of blocks between the chain tip and the last scanned
+	// block, measured in the last fetch cycle.
+	lag uint64
+
+	// Used in tests only:
+	disablePrefetchEventsRoutine bool
+	disableFetchEventsRoutine    bool
+	filterLogsRetryDelay         time.Duration
+}
+
+// New returns a new instance of the LogFetcher struct.
+func New(cfg Config) (*LogFetcher, error) {
+	if cfg.Interval == 0 {
+		return nil, errors.New("interval is not set")
+	}
+	if len(cfg.Addresses) == 0 {
+		return nil, errors.New("no addresses provided")
+	}
+	if cfg.Decode == nil {
+		return nil, errors.New("decode function is not set")
+	}
+	if cfg.BlockLimit <= 0 {
+		return nil, errors.New("block limit must be greater than 0")
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = null.New()
+	}
+	return &LogFetcher{
+		eventCh:        make(chan *messages.Event),
+		client:         cfg.Client,
+		topics:         cfg.Topics,
+		decode:         cfg.Decode,
+		interval:       cfg.Interval,
+		addresses:      cfg.Addresses,
+		prefetchPeriod: cfg.PrefetchPeriod,
+		minTimestamp:   cfg.MinTimestamp,
+		blockTime:      cfg.BlockTime,
+		estimateBlock:  cfg.EstimateBlockTime,
+		blockLimit:     cfg.BlockLimit,
+		blockConfirms:  cfg.BlockConfirmations,
+		lagThreshold:   cfg.LagThreshold,
+		log:            cfg.Logger,
+	}, nil
+}
+
+// Events implements the publisher.EventProvider interface.
+func (lf *LogFetcher) Events() chan *messages.Event {
+	return lf.eventCh
+}
+
+// Start implements the publisher.EventProvider interface.
+func (lf *LogFetcher) Start(ctx context.Context) error {
+	if !lf.disablePrefetchEventsRoutine {
+		go lf.prefetchEventsRoutine(ctx)
+	}
+	if !lf.disableFetchEventsRoutine {
+		go lf.fetchEventsRoutine(ctx)
+	}
+	return nil
+}
+
+// prefetchEventsRoutine fetches events from older blocks until it reaches the
+// block that is older than the prefetch period or the minimum timestamp. This
+// is done to fetch events that were emitted before the fetcher was started.
+func (lf *LogFetcher) prefetchEventsRoutine(ctx context.Context) {
+	if lf.prefetchPeriod == 0 && lf.minTimestamp.IsZero() {
+		return
+	}
+	latestBlock, ok := lf.getBlockNumber(ctx)
+	if !ok {
+		return // Context was canceled.
+	}
+	if latestBlock < lf.blockConfirms {
+		return // There are no confirmed blocks yet.
+	}
+	blockTime := lf.blockTime
+	if blockTime == 0 && lf.estimateBlock {
+		if blockTime, ok = lf.estimateBlockTime(ctx, latestBlock-lf.blockConfirms); !ok {
+			return // Context was canceled.
+		}
+	}
+	if blockTime > 0 {
+		lf.prefetchEventsFromBlock(ctx, latestBlock-lf.blockConfirms, blockTime)
+		return
+	}
+	for d := lf.blockConfirms; ctx.Err() == nil; d += lf.blockLimit {
+		var from, to uint64
+		// Because all integer are unsigned, we need to check against
+		// underflow.
+		if d+lf.blockLimit-1 > latestBlock {
+			from = 0
+		} else {
+			from = latestBlock - (d + lf.blockLimit - 1)
+		}
+		to = latestBlock - d
+		ts, ok := lf.getBlockTimestamp(ctx, to)
+		if !ok {
+			return // Context was canceled.
+		}
+		if !lf.minTimestamp.IsZero() && ts.Before(lf.minTimestamp) {
+			return // All blocks in the range are older than the minimum timestamp.
+		}
+		lf.handleEventsForever(ctx, from, to)
+		if from == 0 || (lf.prefetchPeriod > 0 && time.Since(ts) > lf.prefetchPeriod) {
+			return // End of the prefetch period reached.
+		}
+	}
+}
+
+// prefetchEventsFromBlock fetches events from the block range calculated
+// using the given block time, starting from the newest blocks.
+func (lf *LogFetcher) prefetchEventsFromBlock(ctx context.Context, latestBlock uint64, blockTime time.Duration) {
+	from, ok := lf.prefetchStartBlock(ctx, latestBlock, blockTime)
+	if !ok {
+		return // Context was canceled.
+	}
+	ranges := splitBlockRanges(from, latestBlock, lf.blockLimit)
+	for i := len(ranges) - 1; i >= 0 && ctx.Err() == nil; i-- {
+		lf.handleEventsForever(ctx, ranges[i][0], ranges[i][1])
+	}
+}
+
+// prefetchStartBlock returns the oldest block that should be prefetched,
+// assuming that blocks are produced every blockTime. If all blocks are older
+// than the minimum timestamp, a block newer than latestBlock is returned.
+func (lf *LogFetcher) prefetchStartBlock(
+	ctx context.Context,
+	latestBlock uint64,
+	blockTime time.Duration,
+) (uint64, bool) {
+	blocks := uint64(math.MaxUint64)
+	if lf.prefetchPeriod > 0 {
+		blocks = uint64((lf.prefetchPeriod + blockTime - 1) / blockTime)
+	}
+	if !lf.minTimestamp.IsZero() {
+		ts, ok := lf.getBlockTimestamp(ctx, latestBlock)
+		if !ok {
+			return 0, false
+		}
+		if ts.Before(lf.minTimestamp) {
+			return latestBlock + 1, true
+		}
+		if n := uint64(ts.Sub(lf.minTimestamp) / blockTime); n < blocks {
+			blocks = n
+		}
+	}
+	if blocks >= latestBlock {
+		return 0, true
+	}
+	return latestBlock - blocks, true
+}
+
+// estimateBlockTime estimates the average block time using timestamps of
+// recent blocks. If the estimation is not possible, e.g. because timestamps
+// are equal, zero is returned.
+func (lf *LogFetcher) estimateBlockTime(ctx context.Context, latestBlock uint64) (time.Duration, bool) {
+	n := uint64(blockTimeSampleSize)
+	if n > latestBlock {
+		n = latestBlock
+	}
+	if n == 0 {
+		return 0, true
+	}
+	t1, ok := lf.getBlockTimestamp(ctx, latestBlock)
+	if !ok {
+		return 0, false
+	}
+	t0, ok := lf.getBlockTimestamp(ctx, latestBlock-n)
+	if !ok {
+		return 0, false
+	}
+	if !t1.After(t0) {
+		return 0, true
+	}
+	blockTime := t1.Sub(t0) / time.Duration(n)
+	lf.log.WithField("blockTime", blockTime.String()).Info("Estimated block time")
+	return blockTime, true
+}
+
+// fetchEventsRoutine periodically fetches new logs from the blockchain.
+func (lf *LogFetcher) fetchEventsRoutine(ctx context.Context) {
+	latestBlock, ok := lf.getBlockNumber(ctx)
+	if !ok {
+		return // Context was canceled.
+	}
+	t := time.NewTicker(lf.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			currentBlock, ok := lf.getBlockNumber(ctx)
+			if !ok {
+				return // Context was canceled.
+			}
+			lf.updateLag(currentBlock, latestBlock)
+			if currentBlock <= latestBlock {
+				continue // There is no new blocks.
+			}
+			// The latestBlock is advanced only past ranges that were
+			// successfully fetched. Failed ranges are fetched again in the
+			// next cycle.
+			for _, b := range splitBlockRanges(latestBlock+1, currentBlock, lf.blockLimit) {
+				from := b[0] - lf.blockConfirms
+				to := b[1] - lf.blockConfirms
+				if !lf.handleEvents(ctx, from, to) {
+					if ctx.Err() == nil {
+						lf.log.
+							WithFields(log.Fields{"from": from, "to": to}).
+							Warn("Unable to fetch logs, the range will be fetched again in the next cycle")
+					}
+					break
+				}
+				latestBlock = b[1]
+			}
+		}
+	}
+}
+
+// Lag returns the number of blocks between the chain tip and the last
+// scanned block, measured in the last fetch cycle.
+func (lf *LogFetcher) Lag() uint64 {
+	return atomic.LoadUint64(&lf.lag)
+}
+
+// updateLag updates the lag between the chain tip and the last scanned
+// block. If the lag exceeds the threshold, a warning is logged.
+func (lf *LogFetcher) updateLag(currentBlock, lastScannedBlock uint64) {
+	var lag uint64
+	if currentBlock > lastScannedBlock {
+		lag = currentBlock - lastScannedBlock
+	}
+	atomic.StoreUint64(&lf.lag, lag)
+	fields := log.Fields{
+		"currentBlock":     currentBlock,
+		"lastScannedBlock": lastScannedBlock,
+		"lag":              lag,
+	}
+	if lf.lagThreshold > 0 && lag > lf.lagThreshold {
+		lf.log.WithFields(fields).Warn("Fetcher is falling behind the chain tip")
+		return
+	}
+	lf.log.WithFields(fields).Debug("Fetching new blocks")
+}
+
+// handleEventsForever calls handleEvents until it succeeds or the context is
+// canceled.
+func (lf *LogFetcher) handleEventsForever(ctx context.Context, from, to uint64) {
+	for !lf.handleEvents(ctx, from, to) && ctx.Err() == nil {
+		t := time.NewTimer(retryInterval)
+		select {
+		case <-ctx.Done():
+		case <-t.C:
+		}
+		t.Stop()
+	}
+}
+
+// handleEvents fetches logs from the given block range, decodes them and
+// sends them to the eventCh channel. It returns false if logs could not be
+// fetched for any of the addresses or if the context was canceled. Because
+// events from other addresses may already be sent, the same events may be
+// sent again when the range is retried.
+func (lf *LogFetcher) handleEvents(ctx context.Context, from, to uint64) bool {
+	for _, address := range lf.addresses {
+		lf.log.
+			WithFields(log.Fields{
+				"from":    from,
+				"to":      to,
+				"address": address.String(),
+			}).
+			Info("Fetching logs")
+		logs, err := lf.filterLogs(ctx, address, from, to)
+		if err != nil {
+			return false
+		}
+		for _, l := range logs {
+			if l.Address != address {
+				// This should never happen. All logs returned by
+				// eth_filterLogs should be emitted by the specified
+				// contract. If it happens, there is a bug somewhere.
+				lf.log.
+					WithFields(log.Fields{
+						"expected": address.String(),
+						"actual":   l.Address.String(),
+					}).
+					Panic("Log emitted by wrong contract")
+			}
+			if l.Removed {
+				// This should never happen. All logs returned by
+				// eth_filterLogs should not be removed.
+				lf.log.
+					WithFields(log.Fields{
+						"address":     l.Address.String(),
+						"blockNumber": l.BlockNumber,
+						"blockHash":   l.BlockHash.String(),
+						"txHash":      l.TxHash.String(),
+					}).
+					Warn("Received removed log")
+				continue
+			}
+			evt, err := lf.decode(l)
+			if err != nil {
+				lf.log.
+					WithError(err).
+					Error("Unable to convert log to event")
+				continue
+			}
+			lf.eventCh <- evt
+		}
+	}
+	return true
+}
+
+// getBlockNumber returns the latest block number on the blockchain.
+//
+// The method will try to fetch blocks indefinitely in case of an error.
+// The only way to stop this method from trying again is to cancel the
+// context. In that case, the method will return false as a second return
+// value.
+func (lf *LogFetcher) getBlockNumber(ctx context.Context) (uint64, bool) {
+	var err error
+	var res uint64
+	retry.TryForever(
+		ctx,
+		func() error {
+			res, err = lf.client.BlockNumber(ctx)
+			if err != nil {
+				lf.log.WithError(err).Error("Unable to get block number")
+			}
+			return err
+		},
+		retryInterval,
+	)
+	if ctx.Err() != nil {
+		return 0, false
+	}
+	return res, true
+}
+
+// getBlockNumber returns the latest block number on the blockchain.
+//
+// The method will try to fetch blocks indefinitely in case of an error.
+// The only way to stop this method from trying again is to cancel the
+// context. In that case, the method will return false as a second return
+// value.
+func (lf *LogFetcher) getBlockTimestamp(ctx context.Context, block uint64) (time.Time, bool) {
+	var err error
+	var res any
+	retry.TryForever(
+		ctx,
+		func() error {
+			res, err = lf.client.BlockByNumber(ctx, types.Uint64ToBlockNumber(block))
+			if err != nil {
+				lf.log.WithError(err).Error("Unable to get block timestamp")
+			}
+			return err
+		},
+		retryInterval,
+	)
+	if res == nil || ctx.Err() != nil {
+		return time.Time{}, false
+	}
+	if res, ok := res.(*types.BlockTxHashes); ok {
+		return time.Unix(res.Timestamp.Big().Int64(), 0), true
+	}
+	lf.log.Panic("BlockByNumber returned unexpected type")
+	return time.Time{}, true
+}
+
+// filterLogs fetches logs that match the topic filter from the blockchain.
+//
+// The method will try to fetch logs filterLogsAttempts times, doubling the
+// delay between attempts. If all attempts fail or the context is canceled,
+// the last error is returned.
+func (lf *LogFetcher) filterLogs(
+	ctx context.Context,
+	addr types.Address,
+	from, to uint64,
+) ([]types.Log, error) {
+
+	delay := lf.filterLogsRetryDelay
+	if delay == 0 {
+		delay = filterLogsRetryDelay
+	}
+	for i := 0; ; i++ {
+		fromBlockNumber := types.Uint64ToBlockNumber(from)
+		toBlockNumber := types.Uint64ToBlockNumber(to)
+		res, err := lf.client.FilterLogs(ctx, types.FilterLogsQuery{
+			FromBlock: &fromBlockNumber,
+			ToBlock:   &toBlockNumber,
+			Address:   types.Addresses{addr},
+			Topics:    lf.topics,
+		})
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err == nil {
+			return res, nil
+		}
+		lf.log.WithError(err).Error("Unable to filter logs")
+		if i+1 >= filterLogsAttempts {
+			return nil, err
+		}
+		t := time.NewTimer(delay << i)
+		select {
+		case <-ctx.Done():
+		case <-t.C:
+		}
+		t.Stop()
+	}
+}
+
+// splitBlockRanges splits a block range into smaller ranges of at most
+// "limit" blocks. Some RPC providers have a limit on the number of blocks
+// that can be fetched in a single request and this method is used to
+// keep the number of blocks in each request below that limit.
+func splitBlockRanges(from, to, limit uint64) [][2]uint64 {
+	if from > to {
+		return nil
+	}
+	if to-from <= limit {
+		return [][2]uint64{{from, to}}
+	}
+	var ranges [][2]uint64
+	rangeFrom := from
+	rangeTo := from
+	for rangeTo < to {
+		rangeTo = rangeFrom + limit - 1
+		if rangeTo > to {
+			rangeTo = to
+		}
+		ranges = append(ranges, [2]uint64{rangeFrom, rangeTo})
+		rangeFrom = rangeTo + 1
+	}
+	return ranges
+}
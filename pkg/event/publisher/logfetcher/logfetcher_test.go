@@ -0,0 +1,492 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package logfetcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/rpcclient/mocks"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/callback"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+)
+
+var testAddress = types.HexToAddress("0x2d800d93b065ce011af83f316cef9f0d005b0aa4")
+var testTopic0 = types.HexToHash("0x1111111111111111111111111111111111111111111111111111111111111111")
+var testData = types.HexToBytes("0x0000000000000000000000000000000000000000000000000000000000000042")
+
+// testDecode is a custom decode function that copies log data to an event.
+func testDecode(l types.Log) (*messages.Event, error) {
+	if len(l.Data) != 32 {
+		return nil, errors.New("invalid data length")
+	}
+	return &messages.Event{
+		Type:  "test",
+		Index: l.TxHash.Bytes(),
+		Data:  map[string][]byte{"value": l.Data},
+	}, nil
+}
+
+func TestLogFetcher_FetchEventsRoutine(t *testing.T) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFunc()
+
+	cli := &mocks.Client{}
+	lf, err := New(Config{
+		Client:             cli,
+		Addresses:          types.Addresses{testAddress},
+		Topics:             []types.Hashes{{testTopic0}},
+		Decode:             testDecode,
+		Interval:           100 * time.Millisecond,
+		PrefetchPeriod:     100 * time.Second,
+		BlockLimit:         10,
+		BlockConfirmations: 1,
+		Logger:             null.New(),
+	})
+	require.NoError(t, err)
+	lf.disablePrefetchEventsRoutine = true
+	lf.disableFetchEventsRoutine = false
+
+	txHash := types.HexToHash("0x66e8ab5a41d4b109c7f6ea5303e3c292771e57fb0b93a8474ca6f72e53eac0e8")
+	logs := []types.Log{
+		{TxIndex: types.Uint64ToNumber(1), Data: testData, TxHash: txHash, Address: testAddress},
+		{TxIndex: types.Uint64ToNumber(2), Data: testData, TxHash: txHash, Address: testAddress},
+	}
+
+	cli.On("BlockNumber", ctx).Return(uint64(100), nil).Once()
+	cli.On("BlockNumber", ctx).Return(uint64(119), nil).Once()
+	cli.On("BlockNumber", ctx).Return(uint64(125), nil).Once()
+
+	// First two ranges must be split into two FilterLogs calls to avoid exceeding the block limit.
+	cli.On("FilterLogs", ctx, mock.Anything).Return(logs, nil).Once().Run(func(args mock.Arguments) {
+		fq := args.Get(1).(types.FilterLogsQuery)
+		assert.Equal(t, uint64(100), fq.FromBlock.Big().Uint64()) // latest block minus block confirmations
+		assert.Equal(t, uint64(109), fq.ToBlock.Big().Uint64())   // latest block minus block confirmations minus block limit
+		assert.Equal(t, types.Addresses{testAddress}, fq.Address)
+		assert.Equal(t, []types.Hashes{{testTopic0}}, fq.Topics)
+	})
+	cli.On("FilterLogs", ctx, mock.Anything).Return(logs, nil).Once().Run(func(args mock.Arguments) {
+		fq := args.Get(1).(types.FilterLogsQuery)
+		assert.Equal(t, uint64(110), fq.FromBlock.Big().Uint64())
+		assert.Equal(t, uint64(118), fq.ToBlock.Big().Uint64())
+		assert.Equal(t, types.Addresses{testAddress}, fq.Address)
+		assert.Equal(t, []types.Hashes{{testTopic0}}, fq.Topics)
+	})
+	cli.On("FilterLogs", ctx, mock.Anything).Return(logs, nil).Once().Run(func(args mock.Arguments) {
+		fq := args.Get(1).(types.FilterLogsQuery)
+		assert.Equal(t, uint64(119), fq.FromBlock.Big().Uint64())
+		assert.Equal(t, uint64(124), fq.ToBlock.Big().Uint64())
+		assert.Equal(t, types.Addresses{testAddress}, fq.Address)
+		assert.Equal(t, []types.Hashes{{testTopic0}}, fq.Topics)
+	})
+
+	require.NoError(t, lf.Start(ctx))
+
+	waitForEvents(ctx, t, lf, 6)
+}
+
+func TestLogFetcher_FetchEventsRoutine_Retry(t *testing.T) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFunc()
+
+	cli := &mocks.Client{}
+	lf, err := New(Config{
+		Client:             cli,
+		Addresses:          types.Addresses{testAddress},
+		Topics:             []types.Hashes{{testTopic0}},
+		Decode:             testDecode,
+		Interval:           100 * time.Millisecond,
+		PrefetchPeriod:     100 * time.Second,
+		BlockLimit:         10,
+		BlockConfirmations: 1,
+		Logger:             null.New(),
+	})
+	require.NoError(t, err)
+	lf.disablePrefetchEventsRoutine = true
+	lf.disableFetchEventsRoutine = false
+	lf.filterLogsRetryDelay = time.Millisecond
+
+	txHash := types.HexToHash("0x66e8ab5a41d4b109c7f6ea5303e3c292771e57fb0b93a8474ca6f72e53eac0e8")
+	logs := []types.Log{
+		{TxIndex: types.Uint64ToNumber(1), Data: testData, TxHash: txHash, Address: testAddress},
+		{TxIndex: types.Uint64ToNumber(2), Data: testData, TxHash: txHash, Address: testAddress},
+	}
+
+	cli.On("BlockNumber", ctx).Return(uint64(100), nil).Once()
+	cli.On("BlockNumber", ctx).Return(uint64(110), nil)
+
+	// The range fails twice and then succeeds.
+	cli.On("FilterLogs", ctx, mock.Anything).Return([]types.Log(nil), errors.New("err")).Twice()
+	cli.On("FilterLogs", ctx, mock.Anything).Return(logs, nil).Once().Run(func(args mock.Arguments) {
+		fq := args.Get(1).(types.FilterLogsQuery)
+		assert.Equal(t, uint64(100), fq.FromBlock.Big().Uint64())
+		assert.Equal(t, uint64(109), fq.ToBlock.Big().Uint64())
+	})
+
+	require.NoError(t, lf.Start(ctx))
+
+	waitForEvents(ctx, t, lf, 2)
+	cli.AssertNumberOfCalls(t, "FilterLogs", 3)
+}
+
+func TestLogFetcher_FetchEventsRoutine_RetryNextCycle(t *testing.T) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFunc()
+
+	cli := &mocks.Client{}
+	lf, err := New(Config{
+		Client:             cli,
+		Addresses:          types.Addresses{testAddress},
+		Topics:             []types.Hashes{{testTopic0}},
+		Decode:             testDecode,
+		Interval:           100 * time.Millisecond,
+		PrefetchPeriod:     100 * time.Second,
+		BlockLimit:         10,
+		BlockConfirmations: 1,
+		Logger:             null.New(),
+	})
+	require.NoError(t, err)
+	lf.disablePrefetchEventsRoutine = true
+	lf.disableFetchEventsRoutine = false
+	lf.filterLogsRetryDelay = time.Millisecond
+
+	txHash := types.HexToHash("0x66e8ab5a41d4b109c7f6ea5303e3c292771e57fb0b93a8474ca6f72e53eac0e8")
+	logs := []types.Log{
+		{TxIndex: types.Uint64ToNumber(1), Data: testData, TxHash: txHash, Address: testAddress},
+	}
+
+	cli.On("BlockNumber", ctx).Return(uint64(100), nil).Once()
+	cli.On("BlockNumber", ctx).Return(uint64(110), nil)
+
+	// All attempts in the first cycle fail, so the same range must be
+	// fetched again in the next cycle.
+	cli.On("FilterLogs", ctx, mock.Anything).Return([]types.Log(nil), errors.New("err")).Times(filterLogsAttempts)
+	cli.On("FilterLogs", ctx, mock.Anything).Return(logs, nil).Once().Run(func(args mock.Arguments) {
+		fq := args.Get(1).(types.FilterLogsQuery)
+		assert.Equal(t, uint64(100), fq.FromBlock.Big().Uint64())
+		assert.Equal(t, uint64(109), fq.ToBlock.Big().Uint64())
+	})
+
+	require.NoError(t, lf.Start(ctx))
+
+	waitForEvents(ctx, t, lf, 1)
+	cli.AssertNumberOfCalls(t, "FilterLogs", filterLogsAttempts+1)
+}
+
+func TestLogFetcher_PrefetchEventsRoutine(t *testing.T) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second)
+	defer cancelFunc()
+
+	cli := &mocks.Client{}
+	lf, err := New(Config{
+		Client:             cli,
+		Addresses:          types.Addresses{testAddress},
+		Topics:             []types.Hashes{{testTopic0}},
+		Decode:             testDecode,
+		Interval:           100 * time.Millisecond,
+		PrefetchPeriod:     100 * time.Second,
+		BlockLimit:         15,
+		BlockConfirmations: 1,
+		Logger:             null.New(),
+	})
+	lf.disablePrefetchEventsRoutine = false
+	lf.disableFetchEventsRoutine = true
+	require.NoError(t, err)
+
+	txHash := types.HexToHash("0x66e8ab5a41d4b109c7f6ea5303e3c292771e57fb0b93a8474ca6f72e53eac0e8")
+	logs := []types.Log{
+		{TxIndex: types.Uint64ToNumber(1), Data: testData, TxHash: txHash, Address: testAddress},
+		{TxIndex: types.Uint64ToNumber(2), Data: testData, TxHash: txHash, Address: testAddress},
+	}
+
+	now := time.Now().Unix()
+	cli.On("BlockByNumber", mock.Anything, types.Uint64ToBlockNumber(99)).Return(dummyBlock(99, now), nil)
+	cli.On("BlockByNumber", mock.Anything, types.Uint64ToBlockNumber(84)).Return(dummyBlock(84, now-80), nil)
+	cli.On("BlockByNumber", mock.Anything, types.Uint64ToBlockNumber(69)).Return(dummyBlock(69, now-160), nil)
+	cli.On("BlockNumber", ctx).Return(uint64(100), nil).Once()
+	cli.On("FilterLogs", ctx, mock.Anything).Return([]types.Log{}, nil).Once().Run(func(args mock.Arguments) {
+		fq := args.Get(1).(types.FilterLogsQuery)
+		assert.Equal(t, uint64(85), fq.FromBlock.Big().Uint64()) // latest block minus block confirmations minus block limit
+		assert.Equal(t, uint64(99), fq.ToBlock.Big().Uint64())   // latest block minus block confirmations
+		assert.Equal(t, types.Addresses{testAddress}, fq.Address)
+		assert.Equal(t, []types.Hashes{{testTopic0}}, fq.Topics)
+	})
+	cli.On("FilterLogs", ctx, mock.Anything).Return([]types.Log{}, nil).Once().Run(func(args mock.Arguments) {
+		fq := args.Get(1).(types.FilterLogsQuery)
+		assert.Equal(t, uint64(70), fq.FromBlock.Big().Uint64())
+		assert.Equal(t, uint64(84), fq.ToBlock.Big().Uint64())
+		assert.Equal(t, types.Addresses{testAddress}, fq.Address)
+		assert.Equal(t, []types.Hashes{{testTopic0}}, fq.Topics)
+	})
+	cli.On("FilterLogs", ctx, mock.Anything).Return(logs, nil).Once().Run(func(args mock.Arguments) {
+		fq := args.Get(1).(types.FilterLogsQuery)
+		assert.Equal(t, uint64(55), fq.FromBlock.Big().Uint64())
+		assert.Equal(t, uint64(69), fq.ToBlock.Big().Uint64())
+		assert.Equal(t, types.Addresses{testAddress}, fq.Address)
+		assert.Equal(t, []types.Hashes{{testTopic0}}, fq.Topics)
+	})
+
+	require.NoError(t, lf.Start(ctx))
+
+	waitForEvents(ctx, t, lf, 2)
+}
+
+func waitForEvents(ctx context.Context, t *testing.T, lf *LogFetcher, expectedEvents int) {
+	events := 0
+loop:
+	for events < expectedEvents {
+		select {
+		case msg := <-lf.Events():
+			events++
+			assert.Equal(t, "test", msg.Type)
+			assert.Equal(t, testData.Bytes(), msg.Data["value"])
+		case <-ctx.Done():
+			break loop
+		}
+	}
+
+	assert.Equal(t, expectedEvents, events)
+}
+
+func dummyBlock(number uint64, timestamp int64) *types.BlockTxHashes {
+	return &types.BlockTxHashes{
+		Block: types.Block{
+			Number:    types.Uint64ToNumber(number),
+			Timestamp: types.Uint64ToNumber(uint64(timestamp)),
+		},
+	}
+}
+
+func TestLogFetcher_PrefetchEventsRoutine_MinTimestamp(t *testing.T) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second)
+	defer cancelFunc()
+
+	now := time.Now().Unix()
+	cli := &mocks.Client{}
+	lf, err := New(Config{
+		Client:             cli,
+		Addresses:          types.Addresses{testAddress},
+		Topics:             []types.Hashes{{testTopic0}},
+		Decode:             testDecode,
+		Interval:           100 * time.Millisecond,
+		MinTimestamp:       time.Unix(now-100, 0),
+		BlockLimit:         15,
+		BlockConfirmations: 1,
+		Logger:             null.New(),
+	})
+	require.NoError(t, err)
+	lf.disablePrefetchEventsRoutine = false
+	lf.disableFetchEventsRoutine = true
+
+	cli.On("BlockNumber", ctx).Return(uint64(100), nil).Once()
+	cli.On("BlockByNumber", mock.Anything, types.Uint64ToBlockNumber(99)).Return(dummyBlock(99, now), nil)
+	cli.On("BlockByNumber", mock.Anything, types.Uint64ToBlockNumber(84)).Return(dummyBlock(84, now-80), nil)
+	cli.On("BlockByNumber", mock.Anything, types.Uint64ToBlockNumber(69)).Return(dummyBlock(69, now-160), nil)
+	cli.On("FilterLogs", ctx, mock.Anything).Return([]types.Log{}, nil).Twice()
+
+	done := make(chan struct{})
+	go func() {
+		lf.prefetchEventsRoutine(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		require.Fail(t, "prefetch did not stop at the minimum timestamp")
+	}
+
+	// Only the 85-99 and 70-84 ranges are newer than the minimum timestamp.
+	// Block 69 is older, so the 55-69 range must not be fetched.
+	cli.AssertNumberOfCalls(t, "FilterLogs", 2)
+	cli.AssertCalled(t, "FilterLogs", ctx, mock.MatchedBy(func(fq types.FilterLogsQuery) bool {
+		return fq.FromBlock.Big().Uint64() == 70 && fq.ToBlock.Big().Uint64() == 84
+	}))
+}
+
+func TestLogFetcher_estimateBlockTime(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Unix()
+	cli := &mocks.Client{}
+	lf, err := New(Config{
+		Client:     cli,
+		Addresses:  types.Addresses{testAddress},
+		Decode:     testDecode,
+		Interval:   time.Second,
+		BlockLimit: 100,
+	})
+	require.NoError(t, err)
+
+	// Blocks are produced every 250ms:
+	cli.On("BlockByNumber", mock.Anything, types.Uint64ToBlockNumber(50000)).Return(dummyBlock(50000, now), nil)
+	cli.On("BlockByNumber", mock.Anything, types.Uint64ToBlockNumber(49000)).Return(dummyBlock(49000, now-250), nil)
+
+	bt, ok := lf.estimateBlockTime(ctx, 50000)
+	require.True(t, ok)
+	assert.Equal(t, 250*time.Millisecond, bt)
+}
+
+func TestLogFetcher_prefetchStartBlock(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Unix()
+	tests := []struct {
+		name           string
+		prefetchPeriod time.Duration
+		minTimestamp   time.Time
+		blockTime      time.Duration
+		latestBlock    uint64
+		want           uint64
+	}{
+		{
+			name:           "l1",
+			prefetchPeriod: time.Hour,
+			blockTime:      12 * time.Second,
+			latestBlock:    50000,
+			want:           49700,
+		},
+		{
+			name:           "l2",
+			prefetchPeriod: time.Hour,
+			blockTime:      250 * time.Millisecond,
+			latestBlock:    50000,
+			want:           35600,
+		},
+		{
+			name:           "min-timestamp",
+			prefetchPeriod: time.Hour,
+			minTimestamp:   time.Unix(now-60, 0),
+			blockTime:      250 * time.Millisecond,
+			latestBlock:    50000,
+			want:           49760,
+		},
+		{
+			name:           "genesis",
+			prefetchPeriod: time.Hour,
+			blockTime:      250 * time.Millisecond,
+			latestBlock:    1000,
+			want:           0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := &mocks.Client{}
+			cli.On("BlockByNumber", mock.Anything, types.Uint64ToBlockNumber(tt.latestBlock)).
+				Return(dummyBlock(tt.latestBlock, now), nil)
+			lf, err := New(Config{
+				Client:         cli,
+				Addresses:      types.Addresses{testAddress},
+				Decode:         testDecode,
+				Interval:       time.Second,
+				PrefetchPeriod: tt.prefetchPeriod,
+				MinTimestamp:   tt.minTimestamp,
+				BlockLimit:     100,
+			})
+			require.NoError(t, err)
+
+			from, ok := lf.prefetchStartBlock(ctx, tt.latestBlock, tt.blockTime)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, from)
+		})
+	}
+}
+
+func TestLogFetcher_PrefetchEventsRoutine_BlockTime(t *testing.T) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second)
+	defer cancelFunc()
+
+	cli := &mocks.Client{}
+	lf, err := New(Config{
+		Client:             cli,
+		Addresses:          types.Addresses{testAddress},
+		Topics:             []types.Hashes{{testTopic0}},
+		Decode:             testDecode,
+		Interval:           100 * time.Millisecond,
+		PrefetchPeriod:     10 * time.Second,
+		BlockTime:          250 * time.Millisecond,
+		BlockLimit:         15,
+		BlockConfirmations: 1,
+		Logger:             null.New(),
+	})
+	require.NoError(t, err)
+
+	// The prefetch period covers 40 blocks, so blocks from 59 to 99 must be
+	// fetched, starting from the newest ones, without checking timestamps.
+	var ranges [][2]uint64
+	cli.On("BlockNumber", ctx).Return(uint64(100), nil).Once()
+	cli.On("FilterLogs", ctx, mock.Anything).Return([]types.Log{}, nil).Run(func(args mock.Arguments) {
+		fq := args.Get(1).(types.FilterLogsQuery)
+		ranges = append(ranges, [2]uint64{fq.FromBlock.Big().Uint64(), fq.ToBlock.Big().Uint64()})
+	})
+
+	lf.prefetchEventsRoutine(ctx)
+
+	assert.Equal(t, [][2]uint64{{89, 99}, {74, 88}, {59, 73}}, ranges)
+	cli.AssertNotCalled(t, "BlockByNumber", mock.Anything, mock.Anything)
+}
+
+func TestLogFetcher_Lag(t *testing.T) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFunc()
+
+	warnCh := make(chan log.Fields, 1)
+	logger := callback.New(log.Warn, func(level log.Level, fields log.Fields, msg string) {
+		if msg == "Fetcher is falling behind the chain tip" {
+			select {
+			case warnCh <- fields:
+			default:
+			}
+		}
+	})
+
+	cli := &mocks.Client{}
+	lf, err := New(Config{
+		Client:       cli,
+		Addresses:    types.Addresses{testAddress},
+		Decode:       testDecode,
+		Interval:     50 * time.Millisecond,
+		BlockLimit:   100,
+		LagThreshold: 50,
+		Logger:       logger,
+	})
+	require.NoError(t, err)
+
+	// The chain tip advances by 100 blocks between cycles:
+	cli.On("BlockNumber", ctx).Return(uint64(100), nil).Once()
+	cli.On("BlockNumber", ctx).Return(uint64(200), nil).Once()
+	cli.On("BlockNumber", ctx).Return(uint64(210), nil)
+	cli.On("FilterLogs", ctx, mock.Anything).Return([]types.Log{}, nil)
+
+	go lf.fetchEventsRoutine(ctx)
+
+	select {
+	case fields := <-warnCh:
+		assert.Equal(t, uint64(100), fields["lag"])
+		assert.Equal(t, uint64(200), fields["currentBlock"])
+		assert.Equal(t, uint64(100), fields["lastScannedBlock"])
+	case <-ctx.Done():
+		require.Fail(t, "lag warning was not logged")
+	}
+	assert.Eventually(t, func() bool { return lf.Lag() == 10 }, time.Second, 10*time.Millisecond)
+}
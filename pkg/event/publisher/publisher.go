@@ -35,6 +35,7 @@ type EventPublisher struct {
 
 	signers   []EventSigner
 	listeners []EventProvider
+	sinks     []EventSink
 	transport transport.Transport
 	log       log.Logger
 }
@@ -45,6 +46,14 @@ type EventProvider interface {
 	Events() chan *messages.Event
 }
 
+// EventSink receives published events, e.g. to forward them to an external
+// service. The Send method must not block.
+type EventSink interface {
+	Start(ctx context.Context) error
+	Wait() chan error
+	Send(evt *messages.Event)
+}
+
 // EventSigner signs events.
 type EventSigner interface {
 	Sign(event *messages.Event) (bool, error)
@@ -58,6 +67,9 @@ type Config struct {
 	Signers []EventSigner
 	// Transport is used to send events to the Oracle network.
 	Transport transport.Transport
+	// Sinks is an optional list of sinks to which signed events are sent
+	// in addition to the transport.
+	Sinks []EventSink
 	// Logger is a current logger interface used by the EventPublisher.
 	Logger log.Logger
 }
@@ -74,6 +86,7 @@ func New(cfg Config) (*EventPublisher, error) {
 		waitCh:    make(chan error),
 		transport: cfg.Transport,
 		listeners: cfg.Providers,
+		sinks:     cfg.Sinks,
 		signers:   cfg.Signers,
 		log:       cfg.Logger.WithField("tag", LoggerTag),
 	}, nil
@@ -89,6 +102,11 @@ func (l *EventPublisher) Start(ctx context.Context) error {
 	}
 	l.log.Infof("Starting")
 	l.ctx = ctx
+	for _, s := range l.sinks {
+		if err := s.Start(l.ctx); err != nil {
+			return err
+		}
+	}
 	l.listenerLoop()
 	for _, lis := range l.listeners {
 		err := lis.Start(l.ctx)
@@ -148,6 +166,9 @@ func (l *EventPublisher) broadcast(evt *messages.Event) {
 			}).
 			Error("Unable to publish the event")
 	}
+	for _, s := range l.sinks {
+		s.Send(evt)
+	}
 }
 
 func (l *EventPublisher) sign(evt *messages.Event) bool {
@@ -186,4 +207,7 @@ func (l *EventPublisher) contextCancelHandler() {
 	defer func() { close(l.waitCh) }()
 	defer l.log.Info("Stopped")
 	<-l.ctx.Done()
+	for _, s := range l.sinks {
+		<-s.Wait()
+	}
 }
@@ -18,7 +18,10 @@ package ghost
 import (
 	"context"
 	"errors"
+	"fmt"
+	"hash/fnv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
@@ -27,6 +30,7 @@ import (
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/correlation"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
@@ -34,15 +38,30 @@ import (
 
 const LoggerTag = "GHOST"
 
+// SignerPolicy describes how a signer is chosen if multiple signers are
+// configured.
+type SignerPolicy int
+
+const (
+	// RoundRobin uses signers in turn for consecutive prices.
+	RoundRobin SignerPolicy = iota
+	// ByPair always uses the same signer for the same asset pair.
+	ByPair
+)
+
 type Ghost struct {
 	ctx    context.Context
 	waitCh chan error
 
 	priceProvider provider.Provider
-	signer        ethereum.Signer
+	signers       []ethereum.Signer
+	signerPolicy  SignerPolicy
+	signerCounter uint64
 	transport     transport.Transport
 	interval      time.Duration
 	pairs         []provider.Pair
+	decimals      map[provider.Pair]int
+	domain        string
 	log           log.Logger
 }
 
@@ -50,16 +69,31 @@ type Ghost struct {
 type Config struct {
 	// Pairs is a list supported pairs.
 	Pairs []string
+	// Decimals is an optional number of decimals used to encode prices of
+	// the given pairs. It must match the scaling expected by the Oracle
+	// contract. Prices of other pairs are encoded using the
+	// oracle.PriceMultiplier.
+	Decimals map[string]int
 	// PriceProvider is an instance of the provider.Provider.
 	PriceProvider provider.Provider
 	// Signer is an instance of the ethereum.Signer which will be used to
 	// sign prices.
 	Signer ethereum.Signer
+	// Signers is an optional list of additional signers. If provided, prices
+	// are signed by Signer and Signers, chosen according to SignerPolicy.
+	// All of them must be on the list of feeders accepted by relayers.
+	Signers []ethereum.Signer
+	// SignerPolicy describes how a signer is chosen for each price.
+	SignerPolicy SignerPolicy
 	// Transport is an implementation of transport used to send prices to
 	// relayers.
 	Transport transport.Transport
 	// Interval describes how often we should send prices to the network.
 	Interval time.Duration
+	// Domain is an optional network identifier. If set, prices are
+	// additionally signed for this domain, so relayers on other networks
+	// can reject them.
+	Domain string
 	// Logger is a current logger interface used by the Ghost. The Logger
 	// helps to monitor asynchronous processes.
 	Logger log.Logger
@@ -82,13 +116,27 @@ func New(cfg Config) (*Ghost, error) {
 	if err != nil {
 		return nil, err
 	}
+	decimals := make(map[provider.Pair]int, len(cfg.Decimals))
+	for name, d := range cfg.Decimals {
+		pair, err := provider.NewPair(name)
+		if err != nil {
+			return nil, err
+		}
+		if d < 0 {
+			return nil, fmt.Errorf("number of decimals for the %s pair must not be negative", name)
+		}
+		decimals[pair] = d
+	}
 	g := &Ghost{
 		waitCh:        make(chan error),
 		priceProvider: cfg.PriceProvider,
-		signer:        cfg.Signer,
+		signers:       append([]ethereum.Signer{cfg.Signer}, cfg.Signers...),
+		signerPolicy:  cfg.SignerPolicy,
 		transport:     cfg.Transport,
 		interval:      cfg.Interval,
 		pairs:         pairs,
+		decimals:      decimals,
+		domain:        cfg.Domain,
 		log:           cfg.Logger.WithField("tag", LoggerTag),
 	}
 	return g, nil
@@ -114,8 +162,9 @@ func (g *Ghost) Wait() chan error {
 }
 
 // broadcast sends price for single pair to the network. This method uses
-// current price from the Provider, so it must be updated beforehand.
-func (g *Ghost) broadcast(pair provider.Pair) error {
+// current price from the Provider, so it must be updated beforehand. The
+// correlation ID carried by the context is attached to the message.
+func (g *Ghost) broadcast(ctx context.Context, pair provider.Pair) error {
 	var err error
 
 	tick, err := g.priceProvider.Price(pair)
@@ -127,11 +176,24 @@ func (g *Ghost) broadcast(pair provider.Pair) error {
 	}
 
 	// Create price:
-	price := &oracle.Price{Wat: pair.Base + pair.Quote, Age: tick.Time}
-	price.SetFloat64Price(tick.Price)
+	price := &oracle.Price{Wat: pair.Base + pair.Quote, Age: tick.Time, Domain: g.domain}
+	if d, ok := g.decimals[pair]; ok {
+		if tick.ExactPrice != nil {
+			err = price.SetRatPriceDecimals(tick.ExactPrice, d)
+		} else {
+			err = price.SetFloat64PriceDecimals(tick.Price, d)
+		}
+		if err != nil {
+			return err
+		}
+	} else if tick.ExactPrice != nil {
+		price.SetRatPrice(tick.ExactPrice)
+	} else {
+		price.SetFloat64Price(tick.Price)
+	}
 
 	// Sign price:
-	err = price.Sign(g.signer)
+	err = price.Sign(g.signer(pair))
 	if err != nil {
 		return err
 	}
@@ -141,6 +203,7 @@ func (g *Ghost) broadcast(pair provider.Pair) error {
 	if err != nil {
 		return err
 	}
+	msg.CorrelationID = correlation.FromContext(ctx)
 	if err := g.transport.Broadcast(messages.PriceV0MessageName, msg.AsV0()); err != nil {
 		return err
 	}
@@ -150,6 +213,23 @@ func (g *Ghost) broadcast(pair provider.Pair) error {
 	return err
 }
 
+// signer returns the signer that should be used to sign a price for the
+// given pair.
+func (g *Ghost) signer(pair provider.Pair) ethereum.Signer {
+	if len(g.signers) == 1 {
+		return g.signers[0]
+	}
+	switch g.signerPolicy {
+	case ByPair:
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(pair.String()))
+		return g.signers[h.Sum32()%uint32(len(g.signers))]
+	default:
+		n := atomic.AddUint64(&g.signerCounter, 1) - 1
+		return g.signers[n%uint64(len(g.signers))]
+	}
+}
+
 // broadcasterRoutine creates an asynchronous loop which fetches prices from exchanges and then
 // sends them to the network at a specified interval.
 func (g *Ghost) broadcasterRoutine() {
@@ -169,19 +249,7 @@ func (g *Ghost) broadcasterRoutine() {
 			// we are using goroutines here.
 			wg.Add(1)
 			go func() {
-				for _, pair := range g.pairs {
-					err := g.broadcast(pair)
-					if err != nil {
-						g.log.
-							WithFields(log.Fields{"assetPair": pair}).
-							WithError(err).
-							Warn("Unable to broadcast price")
-					} else {
-						g.log.
-							WithFields(log.Fields{"assetPair": pair}).
-							Info("Price broadcast")
-					}
-				}
+				g.broadcastAll(g.ctx)
 				wg.Done()
 			}()
 		}
@@ -189,6 +257,27 @@ func (g *Ghost) broadcasterRoutine() {
 	}
 }
 
+// broadcastAll sends prices for all pairs to the network. Every call is
+// a separate feed cycle with its own correlation ID, which is added to
+// log entries and to broadcast messages.
+func (g *Ghost) broadcastAll(ctx context.Context) {
+	ctx = correlation.WithNewID(ctx)
+	logger := correlation.Logger(ctx, g.log)
+	for _, pair := range g.pairs {
+		err := g.broadcast(ctx, pair)
+		if err != nil {
+			logger.
+				WithFields(log.Fields{"assetPair": pair}).
+				WithError(err).
+				Warn("Unable to broadcast price")
+		} else {
+			logger.
+				WithFields(log.Fields{"assetPair": pair}).
+				Info("Price broadcast")
+		}
+	}
+}
+
 func (g *Ghost) contextCancelHandler() {
 	defer func() { close(g.waitCh) }()
 	defer g.log.Info("Stopped")
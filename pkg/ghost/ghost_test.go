@@ -27,13 +27,19 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	ethereumMocks "github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/callback"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/correlation"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
 	priceMocks "github.com/chronicleprotocol/oracle-suite/pkg/price/provider/mocks"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/store"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/store/testutil"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/local"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
@@ -305,3 +311,217 @@ func assertPrice(t *testing.T, expected *provider.Price, actual *messages.Price)
 	assert.Equal(t, actual.Price.R, [32]byte(common.HexToHash("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")))
 	assert.Equal(t, actual.Price.S, [32]byte(common.HexToHash("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")))
 }
+
+func TestGhost_Signers(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy SignerPolicy
+		pairs  []provider.Pair
+		want   []byte // V values of signatures, one for each pair
+	}{
+		{
+			name:   "round-robin",
+			policy: RoundRobin,
+			pairs: []provider.Pair{
+				{Base: "AAA", Quote: "BBB"},
+				{Base: "AAA", Quote: "BBB"},
+				{Base: "AAA", Quote: "BBB"},
+				{Base: "XXX", Quote: "YYY"},
+			},
+			want: []byte{1, 2, 1, 2},
+		},
+		{
+			name:   "by-pair",
+			policy: ByPair,
+			pairs: []provider.Pair{
+				{Base: "AAA", Quote: "BBB"},
+				{Base: "AAA", Quote: "CCC"},
+				{Base: "AAA", Quote: "BBB"},
+				{Base: "AAA", Quote: "CCC"},
+			},
+			want: []byte{2, 1, 2, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, ctxCancel := context.WithCancel(context.Background())
+			defer ctxCancel()
+
+			pro := &priceMocks.Provider{}
+			pro.On("Price", provider.Pair{Base: "AAA", Quote: "BBB"}).Return(PriceAAABBB, nil)
+			pro.On("Price", provider.Pair{Base: "AAA", Quote: "CCC"}).Return(PriceAAABBB, nil)
+			pro.On("Price", provider.Pair{Base: "XXX", Quote: "YYY"}).Return(PriceXXXYYY, nil)
+
+			// Each signer creates a signature with a different V value:
+			sig1 := &ethereumMocks.Signer{}
+			sig2 := &ethereumMocks.Signer{}
+			sig1.On("Signature", mock.Anything).Return(ethereum.SignatureFromVRS(1, [32]byte{1}, [32]byte{1}), nil)
+			sig2.On("Signature", mock.Anything).Return(ethereum.SignatureFromVRS(2, [32]byte{2}, [32]byte{2}), nil)
+
+			tra := local.New([]byte("test"), len(tt.pairs), map[string]transport.Message{
+				messages.PriceV0MessageName: (*messages.Price)(nil),
+				messages.PriceV1MessageName: (*messages.Price)(nil),
+			})
+			require.NoError(t, tra.Start(ctx))
+
+			gho, err := New(Config{
+				PriceProvider: pro,
+				Signer:        sig1,
+				Signers:       []ethereum.Signer{sig2},
+				SignerPolicy:  tt.policy,
+				Transport:     tra,
+			})
+			require.NoError(t, err)
+
+			// Both signers are allowed feeders, so the relayer's price store
+			// must accept prices signed by any of them:
+			rec := &ethereumMocks.Signer{}
+			rec.On("Recover", ethereum.SignatureFromVRS(1, [32]byte{1}, [32]byte{1}), mock.Anything).Return(&testutil.Address1, nil)
+			rec.On("Recover", ethereum.SignatureFromVRS(2, [32]byte{2}, [32]byte{2}), mock.Anything).Return(&testutil.Address2, nil)
+			ps, err := store.New(store.Config{
+				Storage:   store.NewMemoryStorage(),
+				Signer:    rec,
+				Transport: tra,
+				Pairs:     []string{"AAABBB", "AAACCC", "XXXYYY"},
+				Feeds:     []ethereum.Address{testutil.Address1, testutil.Address2},
+			})
+			require.NoError(t, err)
+
+			for i, pair := range tt.pairs {
+				require.NoError(t, gho.broadcast(context.Background(), pair))
+				msg := <-tra.Messages(messages.PriceV1MessageName)
+				price := msg.Message.(*messages.Price)
+				assert.Equal(t, tt.want[i], price.Price.V, "price %d", i)
+
+				from, err := price.Price.From(rec)
+				require.NoError(t, err)
+				assert.NoError(t, ps.Add(ctx, *from, price))
+			}
+		})
+	}
+}
+
+func TestGhost_Decimals(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	pro := &priceMocks.Provider{}
+	pro.On("Price", provider.Pair{Base: "AAA", Quote: "BBB"}).Return(PriceAAABBB, nil)
+	pro.On("Price", provider.Pair{Base: "XXX", Quote: "YYY"}).Return(PriceXXXYYY, nil)
+	sig := &ethereumMocks.Signer{}
+	sig.On("Signature", mock.Anything).Return(ethereum.SignatureFromBytes(bytes.Repeat([]byte{0xAA}, 65)), nil)
+
+	tra := local.New([]byte("test"), 2, map[string]transport.Message{
+		messages.PriceV0MessageName: (*messages.Price)(nil),
+		messages.PriceV1MessageName: (*messages.Price)(nil),
+	})
+	require.NoError(t, tra.Start(ctx))
+
+	gho, err := New(Config{
+		PriceProvider: pro,
+		Signer:        sig,
+		Transport:     tra,
+		Decimals:      map[string]int{"AAA/BBB": 8},
+	})
+	require.NoError(t, err)
+
+	// The AAA/BBB price is encoded using 8 decimals:
+	require.NoError(t, gho.broadcast(context.Background(), provider.Pair{Base: "AAA", Quote: "BBB"}))
+	msg := <-tra.Messages(messages.PriceV1MessageName)
+	assert.Equal(t, "11000000000", msg.Message.(*messages.Price).Price.Val.String())
+
+	// Other prices use 18 decimals:
+	require.NoError(t, gho.broadcast(context.Background(), provider.Pair{Base: "XXX", Quote: "YYY"}))
+	msg = <-tra.Messages(messages.PriceV1MessageName)
+	assert.Equal(t, "210000000000000000000", msg.Message.(*messages.Price).Price.Val.String())
+
+	// Negative decimals are invalid:
+	_, err = New(Config{
+		PriceProvider: pro,
+		Signer:        sig,
+		Transport:     tra,
+		Decimals:      map[string]int{"AAA/BBB": -1},
+	})
+	assert.Error(t, err)
+}
+
+func TestGhost_Domain(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	pro := &priceMocks.Provider{}
+	pro.On("Price", provider.Pair{Base: "AAA", Quote: "BBB"}).Return(PriceAAABBB, nil)
+	sig := &ethereumMocks.Signer{}
+	sig.On("Signature", mock.Anything).Return(ethereum.SignatureFromBytes(bytes.Repeat([]byte{0xAA}, 65)), nil)
+
+	tra := local.New([]byte("test"), 2, map[string]transport.Message{
+		messages.PriceV0MessageName: (*messages.Price)(nil),
+		messages.PriceV1MessageName: (*messages.Price)(nil),
+	})
+	require.NoError(t, tra.Start(ctx))
+
+	gho, err := New(Config{
+		PriceProvider: pro,
+		Signer:        sig,
+		Transport:     tra,
+		Domain:        "mainnet",
+	})
+	require.NoError(t, err)
+
+	// Both the price and the domain must be signed:
+	require.NoError(t, gho.broadcast(context.Background(), provider.Pair{Base: "AAA", Quote: "BBB"}))
+	msg := <-tra.Messages(messages.PriceV1MessageName)
+	price := msg.Message.(*messages.Price).Price
+	assert.Equal(t, "mainnet", price.Domain)
+	assert.Equal(t, ethereum.SignatureFromBytes(bytes.Repeat([]byte{0xAA}, 65)), price.DomainSig)
+	sig.AssertNumberOfCalls(t, "Signature", 2)
+}
+
+func TestGhost_CorrelationID(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	pro := &priceMocks.Provider{}
+	pro.On("Price", provider.Pair{Base: "AAA", Quote: "BBB"}).Return(PriceAAABBB, nil)
+	pro.On("Price", provider.Pair{Base: "XXX", Quote: "YYY"}).Return(PriceXXXYYY, nil)
+	sig := &ethereumMocks.Signer{}
+	sig.On("Signature", mock.Anything).Return(ethereum.SignatureFromBytes(bytes.Repeat([]byte{0xAA}, 65)), nil)
+
+	tra := local.New([]byte("test"), 4, map[string]transport.Message{
+		messages.PriceV0MessageName: (*messages.Price)(nil),
+		messages.PriceV1MessageName: (*messages.Price)(nil),
+	})
+	require.NoError(t, tra.Start(ctx))
+
+	var ids []interface{}
+	gho, err := New(Config{
+		Pairs:         []string{"AAA/BBB", "XXX/YYY"},
+		PriceProvider: pro,
+		Signer:        sig,
+		Transport:     tra,
+		Logger: callback.New(log.Debug, func(_ log.Level, fields log.Fields, _ string) {
+			ids = append(ids, fields[correlation.LogField])
+		}),
+	})
+	require.NoError(t, err)
+
+	// All log entries and messages of a single cycle must have the same ID:
+	gho.broadcastAll(ctx)
+	require.Len(t, ids, 2)
+	id, ok := ids[0].(string)
+	require.True(t, ok)
+	assert.NotEmpty(t, id)
+	assert.Equal(t, id, ids[1])
+	for i := 0; i < 2; i++ {
+		msg := <-tra.Messages(messages.PriceV0MessageName)
+		assert.Equal(t, id, msg.Message.(*messages.Price).CorrelationID)
+		msg = <-tra.Messages(messages.PriceV1MessageName)
+		assert.Equal(t, id, msg.Message.(*messages.Price).CorrelationID)
+	}
+
+	// The next cycle must use a different ID:
+	gho.broadcastAll(ctx)
+	require.Len(t, ids, 4)
+	assert.NotEqual(t, id, ids[2])
+	assert.Equal(t, ids[2], ids[3])
+}
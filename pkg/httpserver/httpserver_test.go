@@ -78,3 +78,12 @@ func TestServer_UseMustPanicAfterServerHTTP(t *testing.T) {
 
 	assert.NotNil(t, panicVal)
 }
+
+func TestPprofHandler(t *testing.T) {
+	r := httptest.NewRequest("GET", PprofPath+"cmdline", nil)
+	rw := httptest.NewRecorder()
+
+	NewPprofHandler().ServeHTTP(rw, r)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.NotEmpty(t, rw.Body.String())
+}
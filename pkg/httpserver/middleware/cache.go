@@ -0,0 +1,127 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Cache is a middleware that adds caching headers to successful responses
+// to GET and HEAD requests and handles conditional requests.
+//
+// The ETag header is calculated from the response body, so the whole
+// response is buffered before it is sent. If the request contains the
+// If-None-Match header that matches the ETag, or the If-Modified-Since header
+// that is not older than the time returned by LastModified, the 304 response
+// is returned without a body.
+type Cache struct {
+	// MaxAge is used in the Cache-Control header. If zero, clients must
+	// revalidate the response every time.
+	MaxAge time.Duration
+	// LastModified is an optional function that returns the time of the last
+	// modification of the requested resource. If it returns a zero time,
+	// the Last-Modified header is not set. If it is nil, the Last-Modified
+	// header set by the wrapped handler, if any, is used, so handlers can
+	// determine the time from the same data they use for the response.
+	LastModified func(r *http.Request) time.Time
+}
+
+// Handle implements the httpserver.Middleware interface.
+func (c *Cache) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(rw, r)
+			return
+		}
+		buf := &bufferedWriter{header: rw.Header(), code: http.StatusOK}
+		next.ServeHTTP(buf, r)
+		if buf.code != http.StatusOK {
+			rw.WriteHeader(buf.code)
+			_, _ = rw.Write(buf.body.Bytes())
+			return
+		}
+
+		sum := sha256.Sum256(buf.body.Bytes())
+		etag := fmt.Sprintf(`"%s"`, hex.EncodeToString(sum[:16]))
+		rw.Header().Set("ETag", etag)
+		if c.MaxAge > 0 {
+			rw.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(c.MaxAge.Seconds())))
+		} else {
+			rw.Header().Set("Cache-Control", "no-cache")
+		}
+		var modified time.Time
+		if c.LastModified != nil {
+			modified = c.LastModified(r).UTC().Truncate(time.Second)
+		} else if t, err := http.ParseTime(buf.header.Get("Last-Modified")); err == nil {
+			modified = t.UTC()
+		}
+		if !modified.IsZero() {
+			rw.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+		}
+		if notModified(r, etag, modified) {
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write(buf.body.Bytes())
+	})
+}
+
+// notModified returns true if conditional headers of the request match the
+// given ETag or modification time. If-None-Match takes precedence over
+// If-Modified-Since, as required by RFC 7232.
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, t := range strings.Split(inm, ",") {
+			t = strings.TrimPrefix(strings.TrimSpace(t), "W/")
+			if t == etag || t == "*" {
+				return true
+			}
+		}
+		return false
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !modified.IsZero() {
+		t, err := http.ParseTime(ims)
+		return err == nil && !modified.After(t)
+	}
+	return false
+}
+
+// bufferedWriter implements the http.ResponseWriter interface. It buffers
+// the response body and the status code.
+type bufferedWriter struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func (b *bufferedWriter) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedWriter) Write(buf []byte) (int, error) {
+	return b.body.Write(buf)
+}
+
+func (b *bufferedWriter) WriteHeader(code int) {
+	b.code = code
+}
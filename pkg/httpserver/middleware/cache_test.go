@@ -0,0 +1,117 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	modified := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	h := (&Cache{
+		MaxAge:       5 * time.Second,
+		LastModified: func(r *http.Request) time.Time { return modified },
+	}).Handle(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		_, _ = rw.Write([]byte(`["BTC/USD"]`))
+	}))
+
+	// The first request returns the body with caching headers:
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest("GET", "/pairs", nil))
+	require.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, `["BTC/USD"]`, rw.Body.String())
+	assert.Equal(t, "public, max-age=5", rw.Header().Get("Cache-Control"))
+	assert.Equal(t, "Sun, 02 Jan 2022 03:04:05 GMT", rw.Header().Get("Last-Modified"))
+	etag := rw.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		code    int
+	}{
+		{name: "matching-etag", headers: map[string]string{"If-None-Match": etag}, code: http.StatusNotModified},
+		{name: "weak-etag", headers: map[string]string{"If-None-Match": `"a", W/` + etag}, code: http.StatusNotModified},
+		{name: "other-etag", headers: map[string]string{"If-None-Match": `"a"`}, code: http.StatusOK},
+		{
+			name:    "not-modified-since",
+			headers: map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)},
+			code:    http.StatusNotModified,
+		},
+		{
+			name:    "modified-since",
+			headers: map[string]string{"If-Modified-Since": modified.Add(-time.Second).Format(http.TimeFormat)},
+			code:    http.StatusOK,
+		},
+		{
+			name: "etag-precedence",
+			headers: map[string]string{
+				"If-None-Match":     `"a"`,
+				"If-Modified-Since": modified.Format(http.TimeFormat),
+			},
+			code: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/pairs", nil)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			rw := httptest.NewRecorder()
+			h.ServeHTTP(rw, r)
+			assert.Equal(t, tt.code, rw.Code)
+			assert.Equal(t, etag, rw.Header().Get("ETag"))
+			if tt.code == http.StatusNotModified {
+				assert.Empty(t, rw.Body.String())
+			}
+		})
+	}
+}
+
+func TestCache_Errors(t *testing.T) {
+	h := (&Cache{}).Handle(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusInternalServerError)
+	}))
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest("GET", "/prices", nil))
+	assert.Equal(t, http.StatusInternalServerError, rw.Code)
+	assert.Empty(t, rw.Header().Get("ETag"))
+}
+
+func TestCache_HandlerLastModified(t *testing.T) {
+	modified := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	h := (&Cache{}).Handle(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+		_, _ = rw.Write([]byte(`["BTC/USD"]`))
+	}))
+
+	// Without the LastModified function, the header set by the handler
+	// should be used for conditional requests:
+	r := httptest.NewRequest("GET", "/pairs", nil)
+	r.Header.Set("If-Modified-Since", modified.Format(http.TimeFormat))
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, r)
+	assert.Equal(t, http.StatusNotModified, rw.Code)
+	assert.Equal(t, "no-cache", rw.Header().Get("Cache-Control"))
+	assert.Equal(t, "Sun, 02 Jan 2022 03:04:05 GMT", rw.Header().Get("Last-Modified"))
+}
@@ -17,9 +17,14 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
 )
 
 // CORS adds a basic support for CORS preflight requests.
+//
+// If the Origin function returns an empty string, the origin is not allowed:
+// the Access-Control-Allow-Origin header is not set and preflight requests
+// are rejected with the 403 status code.
 type CORS struct {
 	// Origin is a function that returns a value of
 	// an Access-Control-Allow-Origin header. It cannot be nil.
@@ -36,9 +41,21 @@ type CORS struct {
 func (c *CORS) Handle(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 		headers := rw.Header()
-		headers.Set("Access-Control-Allow-Origin", c.Origin(r))
+		origin := c.Origin(r)
+		if origin != "" {
+			headers.Set("Access-Control-Allow-Origin", origin)
+		}
+		if origin != "*" {
+			// The response depends on the Origin header, so caches must
+			// not reuse it for requests from other origins.
+			headers.Add("Vary", "Origin")
+		}
 		switch r.Method {
 		case "OPTIONS":
+			if origin == "" {
+				rw.WriteHeader(http.StatusForbidden)
+				return
+			}
 			headers.Set("Access-Control-Allow-Headers", c.Headers(r))
 			headers.Set("Access-Control-Allow-Methods", c.Methods(r))
 			headers.Set("Access-Control-Max-Age", "86400")
@@ -48,3 +65,20 @@ func (c *CORS) Handle(next http.Handler) http.Handler {
 		}
 	})
 }
+
+// AllowedOrigins returns a function for the CORS.Origin field that allows
+// only the given origins. The "*" origin allows all origins.
+func AllowedOrigins(origins []string) func(r *http.Request) string {
+	return func(r *http.Request) string {
+		origin := r.Header.Get("Origin")
+		for _, o := range origins {
+			if o == "*" {
+				return "*"
+			}
+			if origin != "" && strings.EqualFold(o, origin) {
+				return origin
+			}
+		}
+		return ""
+	}
+}
@@ -58,3 +58,43 @@ func TestCORS_Get(t *testing.T) {
 	assert.Empty(t, rw.Header().Get("Access-Control-Allow-Methods"))
 	assert.Empty(t, rw.Header().Get("Access-Control-Allow-Max-Age"))
 }
+
+func TestCORS_OptionsDisallowedOrigin(t *testing.T) {
+	c := &CORS{
+		Origin:  AllowedOrigins([]string{"https://example.com"}),
+		Headers: func(r *http.Request) string { return "header" },
+		Methods: func(r *http.Request) string { return "GET" },
+	}
+	h := c.Handle(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {}))
+	r := httptest.NewRequest("OPTIONS", "/", nil)
+	r.Header.Set("Origin", "https://evil.com")
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, r)
+
+	assert.Equal(t, http.StatusForbidden, rw.Code)
+	assert.Empty(t, rw.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, rw.Header().Get("Access-Control-Allow-Headers"))
+	assert.Empty(t, rw.Header().Get("Access-Control-Allow-Methods"))
+}
+
+func TestAllowedOrigins(t *testing.T) {
+	tests := []struct {
+		origins []string
+		origin  string
+		want    string
+	}{
+		{origins: nil, origin: "https://example.com", want: ""},
+		{origins: []string{"https://example.com"}, origin: "https://example.com", want: "https://example.com"},
+		{origins: []string{"https://example.com"}, origin: "https://EXAMPLE.com", want: "https://EXAMPLE.com"},
+		{origins: []string{"https://example.com"}, origin: "https://evil.com", want: ""},
+		{origins: []string{"https://example.com"}, origin: "", want: ""},
+		{origins: []string{"*"}, origin: "https://evil.com", want: "*"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/", nil)
+		if tt.origin != "" {
+			r.Header.Set("Origin", tt.origin)
+		}
+		assert.Equal(t, tt.want, AllowedOrigins(tt.origins)(r))
+	}
+}
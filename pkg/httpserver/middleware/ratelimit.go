@@ -0,0 +1,172 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package middleware
+
+import (
+	"crypto/subtle"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitRule defines the maximum number of requests a single client may
+// send during a time window.
+type RateLimitRule struct {
+	// Limit is the maximum number of requests in a window. If zero, requests
+	// are not limited.
+	Limit int
+	// Window is the duration of a window.
+	Window time.Duration
+}
+
+// RateLimit limits the number of requests sent by a single client using
+// fixed time windows. Clients are identified by an API key, if it is one of
+// the allowed keys, or by the IP address.
+//
+// If the limit is exceeded, the 429 status code is returned together with
+// the Retry-After header.
+type RateLimit struct {
+	// Rule is the default rule used for all paths.
+	Rule RateLimitRule
+	// Paths contains rules for specific path prefixes. If a request path
+	// matches multiple prefixes, the longest one is used. Each path has its
+	// own counters.
+	Paths map[string]RateLimitRule
+	// APIKeyHeader is the name of the header with an API key used to
+	// identify clients. If empty, clients are identified only by the IP
+	// address.
+	APIKeyHeader string
+	// APIKeys is the list of allowed API keys. Clients that send other keys
+	// are identified by the IP address, so they cannot avoid the limit by
+	// sending a different key with every request.
+	APIKeys []string
+
+	mu       sync.Mutex
+	counters map[rateLimitKey]*rateLimitCounter
+	pruned   time.Time
+	now      func() time.Time
+}
+
+type rateLimitKey struct {
+	path   string
+	client string
+}
+
+type rateLimitCounter struct {
+	start time.Time
+	count int
+}
+
+// Handle implements the httpserver.Middleware interface.
+func (l *RateLimit) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		path, rule := l.rule(r.URL.Path)
+		if rule.Limit <= 0 || rule.Window <= 0 {
+			next.ServeHTTP(rw, r)
+			return
+		}
+		if wait := l.take(rateLimitKey{path: path, client: l.client(r)}, rule); wait > 0 {
+			rw.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			rw.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// rule returns the rule and the path prefix for the given path.
+func (l *RateLimit) rule(path string) (string, RateLimitRule) {
+	prefix, rule := "", l.Rule
+	for p, r := range l.Paths {
+		if strings.HasPrefix(path, p) && len(p) > len(prefix) {
+			prefix, rule = p, r
+		}
+	}
+	return prefix, rule
+}
+
+// client returns the identifier of the client that sent the request.
+func (l *RateLimit) client(r *http.Request) string {
+	if l.APIKeyHeader != "" {
+		if key := r.Header.Get(l.APIKeyHeader); key != "" && l.allowedKey(key) {
+			return "key:" + key
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// allowedKey returns true if the API key is on the list of allowed keys.
+func (l *RateLimit) allowedKey(key string) bool {
+	for _, k := range l.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// take counts the request and returns the time the client has to wait
+// before sending another request, or zero if the request is allowed.
+func (l *RateLimit) take(key rateLimitKey, rule RateLimitRule) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if l.now != nil {
+		now = l.now()
+	}
+	if l.counters == nil {
+		l.counters = make(map[rateLimitKey]*rateLimitCounter)
+	}
+	l.prune(now)
+	c, ok := l.counters[key]
+	if !ok || now.Sub(c.start) >= rule.Window {
+		c = &rateLimitCounter{start: now}
+		l.counters[key] = c
+	}
+	if c.count >= rule.Limit {
+		return c.start.Add(rule.Window).Sub(now)
+	}
+	c.count++
+	return 0
+}
+
+// prune removes counters of expired windows, so the memory usage does not
+// grow with the number of clients seen over time.
+func (l *RateLimit) prune(now time.Time) {
+	window := l.Rule.Window
+	for _, r := range l.Paths {
+		if r.Window > window {
+			window = r.Window
+		}
+	}
+	if now.Sub(l.pruned) < window {
+		return
+	}
+	for k, c := range l.counters {
+		if now.Sub(c.start) >= window {
+			delete(l.counters, k)
+		}
+	}
+	l.pruned = now
+}
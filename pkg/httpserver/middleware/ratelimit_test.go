@@ -0,0 +1,88 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := &RateLimit{
+		Rule:         RateLimitRule{Limit: 2, Window: 10 * time.Second},
+		Paths:        map[string]RateLimitRule{"/jsonrpc": {Limit: 1, Window: 5 * time.Second}},
+		APIKeyHeader: "X-API-Key",
+		APIKeys:      []string{"key"},
+		now:          func() time.Time { return now },
+	}
+	h := l.Handle(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+	call := func(path, ip, key string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", path, nil)
+		r.RemoteAddr = ip + ":1234"
+		if key != "" {
+			r.Header.Set("X-API-Key", key)
+		}
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, r)
+		return rw
+	}
+
+	// Default rule:
+	assert.Equal(t, http.StatusOK, call("/v1/prices/", "1.1.1.1", "").Code)
+	assert.Equal(t, http.StatusOK, call("/v1/prices/", "1.1.1.1", "").Code)
+	rw := call("/v1/prices/", "1.1.1.1", "")
+	assert.Equal(t, http.StatusTooManyRequests, rw.Code)
+	assert.Equal(t, "10", rw.Header().Get("Retry-After"))
+
+	// Other clients are not affected:
+	assert.Equal(t, http.StatusOK, call("/v1/prices/", "2.2.2.2", "").Code)
+	assert.Equal(t, http.StatusOK, call("/v1/prices/", "1.1.1.1", "key").Code)
+
+	// Unknown API keys are ignored, the client is identified by the IP:
+	assert.Equal(t, http.StatusTooManyRequests, call("/v1/prices/", "1.1.1.1", "other").Code)
+	assert.Equal(t, http.StatusOK, call("/v1/prices/", "3.3.3.3", "other").Code)
+	assert.Equal(t, http.StatusOK, call("/v1/prices/", "3.3.3.3", "another").Code)
+	assert.Equal(t, http.StatusTooManyRequests, call("/v1/prices/", "3.3.3.3", "yet-another").Code)
+
+	// Path rule:
+	assert.Equal(t, http.StatusOK, call("/jsonrpc", "1.1.1.1", "").Code)
+	rw = call("/jsonrpc", "1.1.1.1", "")
+	assert.Equal(t, http.StatusTooManyRequests, rw.Code)
+	assert.Equal(t, "5", rw.Header().Get("Retry-After"))
+
+	// Counters are reset after the window:
+	now = now.Add(6 * time.Second)
+	assert.Equal(t, http.StatusOK, call("/jsonrpc", "1.1.1.1", "").Code)
+	rw = call("/v1/prices/", "1.1.1.1", "")
+	assert.Equal(t, http.StatusTooManyRequests, rw.Code)
+	assert.Equal(t, "4", rw.Header().Get("Retry-After"))
+	now = now.Add(4 * time.Second)
+	assert.Equal(t, http.StatusOK, call("/v1/prices/", "1.1.1.1", "").Code)
+}
+
+func TestRateLimit_NoLimit(t *testing.T) {
+	h := (&RateLimit{}).Handle(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+	for i := 0; i < 100; i++ {
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, httptest.NewRequest("GET", "/", nil))
+		assert.Equal(t, http.StatusOK, rw.Code)
+	}
+}
@@ -0,0 +1,93 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package correlation provides correlation IDs that make it possible to
+// find all log entries related to a single feed or relay cycle, even if
+// they are produced by different services.
+package correlation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+)
+
+// LogField is the name of the log field that contains the correlation ID.
+const LogField = "correlationID"
+
+// idSize is the size of a correlation ID in bytes.
+const idSize = 8
+
+type ctxKey struct{}
+
+// NewID returns a new random correlation ID.
+func NewID() string {
+	b := make([]byte, idSize)
+	if _, err := rand.Read(b); err != nil {
+		// The crypto/rand reader does not fail on supported platforms,
+		// and the ID is used only for debugging.
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// IsValidID returns true if the given string is a correlation ID in the
+// format returned by NewID, that is, idSize bytes encoded as hex.
+//
+// Correlation IDs received from other services must be checked before they
+// are used because they are not covered by a signature.
+func IsValidID(id string) bool {
+	if len(id) != hex.EncodedLen(idSize) {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
+
+// WithID returns a copy of the context that carries the given correlation
+// ID. If the ID is empty, the context is returned unchanged.
+func WithID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// WithNewID returns a copy of the context that carries a new correlation ID.
+func WithNewID(ctx context.Context) context.Context {
+	return WithID(ctx, NewID())
+}
+
+// FromContext returns the correlation ID carried by the context or an empty
+// string if there is none.
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// Logger returns the logger with the LogField field set to the correlation
+// ID carried by the context. If there is no ID, the logger is returned
+// unchanged.
+func Logger(ctx context.Context, logger log.Logger) log.Logger {
+	if id := FromContext(ctx); id != "" {
+		return logger.WithField(LogField, id)
+	}
+	return logger
+}
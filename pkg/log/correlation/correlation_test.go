@@ -0,0 +1,60 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package correlation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/callback"
+)
+
+func TestCorrelationID(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, FromContext(ctx))
+	assert.Equal(t, ctx, WithID(ctx, ""))
+
+	ctx = WithNewID(ctx)
+	id := FromContext(ctx)
+	assert.Len(t, id, idSize*2)
+	assert.NotEqual(t, id, FromContext(WithNewID(ctx)))
+}
+
+func TestIsValidID(t *testing.T) {
+	assert.True(t, IsValidID(NewID()))
+	assert.True(t, IsValidID("0123456789abcdef"))
+	assert.False(t, IsValidID(""))
+	assert.False(t, IsValidID("foo"))
+	assert.False(t, IsValidID("0123456789abcdef0"))
+	assert.False(t, IsValidID("0123456789abcdeg"))
+	assert.False(t, IsValidID("0123456789\nabcde"))
+}
+
+func TestLogger(t *testing.T) {
+	var fields []log.Fields
+	l := callback.New(log.Debug, func(_ log.Level, f log.Fields, _ string) {
+		fields = append(fields, f)
+	})
+
+	Logger(context.Background(), l).Info("without ID")
+	Logger(WithID(context.Background(), "foo"), l).Info("with ID")
+
+	assert.NotContains(t, fields[0], LogField)
+	assert.Equal(t, "foo", fields[1][LogField])
+}
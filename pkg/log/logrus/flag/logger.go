@@ -17,6 +17,7 @@ package flag
 
 import (
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/sirupsen/logrus"
@@ -30,6 +31,10 @@ import (
 type LoggerFlag struct {
 	verbosityFlag
 	formatterFlag
+
+	// output is the writer to which logs are written, if nil, logs are
+	// written to the standard error. Used in tests.
+	output io.Writer
 }
 
 func NewLoggerFlagSet(logger *LoggerFlag) *pflag.FlagSet {
@@ -45,6 +50,18 @@ func NewLoggerFlagSet(logger *LoggerFlag) *pflag.FlagSet {
 		"log.format",
 		"log format",
 	)
+	// The --log-level and --log-format flags are aliases for the flags
+	// above, they set the same values.
+	fs.Var(
+		&logger.verbosityFlag,
+		"log-level",
+		"log level, same as --log.verbosity",
+	)
+	fs.Var(
+		&logger.formatterFlag,
+		"log-format",
+		"log format, same as --log.format",
+	)
 	return fs
 }
 
@@ -52,6 +69,9 @@ func (logger *LoggerFlag) Logger() log.Logger {
 	l := logrus.New()
 	l.SetLevel(logger.Verbosity())
 	l.SetFormatter(logger.Formatter())
+	if logger.output != nil {
+		l.SetOutput(logger.output)
+	}
 	return logrus2.New(l)
 }
 
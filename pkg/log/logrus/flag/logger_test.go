@@ -0,0 +1,78 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package flag
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+)
+
+func TestLoggerFlag_Level(t *testing.T) {
+	tests := []struct {
+		args      []string
+		level     log.Level
+		wantDebug bool
+		wantInfo  bool
+	}{
+		{args: nil, level: log.Warn},
+		{args: []string{"--log-level", "info"}, level: log.Info, wantInfo: true},
+		{args: []string{"--log-level", "debug"}, level: log.Debug, wantDebug: true, wantInfo: true},
+		{args: []string{"--log.verbosity", "debug"}, level: log.Debug, wantDebug: true, wantInfo: true},
+		{args: []string{"-v", "error"}, level: log.Error},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.args), func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			lf := &LoggerFlag{output: buf}
+			require.NoError(t, NewLoggerFlagSet(lf).Parse(tt.args))
+
+			l := lf.Logger()
+			l.Debug("debug message")
+			l.Info("info message")
+
+			assert.Equal(t, tt.level, l.Level())
+			assert.Equal(t, tt.wantDebug, bytes.Contains(buf.Bytes(), []byte("debug message")))
+			assert.Equal(t, tt.wantInfo, bytes.Contains(buf.Bytes(), []byte("info message")))
+		})
+	}
+}
+
+func TestLoggerFlag_Format(t *testing.T) {
+	buf := &bytes.Buffer{}
+	lf := &LoggerFlag{output: buf}
+	require.NoError(t, NewLoggerFlagSet(lf).Parse([]string{"--log-format", "json", "--log-level", "info"}))
+
+	lf.Logger().WithField("foo", "bar").Info("message")
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "message", entry["msg"])
+	assert.Equal(t, "bar", entry["foo"])
+}
+
+func TestLoggerFlag_InvalidValues(t *testing.T) {
+	lf := &LoggerFlag{}
+	fs := NewLoggerFlagSet(lf)
+	assert.Error(t, fs.Set("log-level", "foo"))
+	assert.Error(t, fs.Set("log-format", "foo"))
+}
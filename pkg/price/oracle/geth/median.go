@@ -35,7 +35,8 @@ const gasLimit = 200000
 const maxReadRetries = 3
 const delayBetweenReadRetries = 5 * time.Second
 
-// Median implements the oracle.Median interface using go-ethereum packages.
+// Median implements the oracle.Median and oracle.PokeEstimator interfaces
+// using go-ethereum packages.
 type Median struct {
 	ethereum ethereum.Client
 	address  ethereum.Address
@@ -147,27 +148,7 @@ func (m *Median) Feeds(ctx context.Context) ([]ethereum.Address, error) {
 
 // Poke implements the oracle.Median interface.
 func (m *Median) Poke(ctx context.Context, prices []*oracle.Price, simulateBeforeRun bool) (*ethereum.Hash, error) {
-	// It's important to send prices in correct order, otherwise contract will fail:
-	sort.Slice(prices, func(i, j int) bool {
-		return prices[i].Val.Cmp(prices[j].Val) < 0
-	})
-
-	var (
-		val []*big.Int
-		age []*big.Int
-		v   []uint8
-		r   [][32]byte
-		s   [][32]byte
-	)
-
-	for _, arg := range prices {
-		val = append(val, arg.Val)
-		age = append(age, big.NewInt(arg.Age.Unix()))
-		v = append(v, arg.V)
-		r = append(r, arg.R)
-		s = append(s, arg.S)
-	}
-
+	val, age, v, r, s := pokeArgs(prices)
 	if simulateBeforeRun {
 		if _, err := m.read(ctx, "poke", val, age, v, r, s); err != nil {
 			return nil, err
@@ -177,6 +158,24 @@ func (m *Median) Poke(ctx context.Context, prices []*oracle.Price, simulateBefor
 	return m.write(ctx, "poke", val, age, v, r, s)
 }
 
+// EstimatePoke implements the oracle.PokeEstimator interface.
+func (m *Median) EstimatePoke(ctx context.Context, prices []*oracle.Price) (*big.Int, error) {
+	val, age, v, r, s := pokeArgs(prices)
+	cd, err := medianABI.Pack("poke", val, age, v, r, s)
+	if err != nil {
+		return nil, err
+	}
+	gas, err := m.ethereum.EstimateGas(ctx, ethereum.Call{Address: m.address, Data: cd})
+	if err != nil {
+		return nil, err
+	}
+	gasPrice, err := m.ethereum.GasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Mul(new(big.Int).SetUint64(gas), gasPrice), nil
+}
+
 // Lift implements the oracle.Median interface.
 func (m *Median) Lift(ctx context.Context, addresses []common.Address, simulateBeforeRun bool) (*ethereum.Hash, error) {
 	if simulateBeforeRun {
@@ -241,6 +240,24 @@ func (m *Median) write(ctx context.Context, method string, args ...interface{})
 	})
 }
 
+// pokeArgs returns arguments for the poke method.
+func pokeArgs(prices []*oracle.Price) (val, age []*big.Int, v []uint8, r, s [][32]byte) {
+	// It's important to send prices in correct order, otherwise contract will fail:
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].Val.Cmp(prices[j].Val) < 0
+	})
+
+	for _, arg := range prices {
+		val = append(val, arg.Val)
+		age = append(age, big.NewInt(arg.Age.Unix()))
+		v = append(v, arg.V)
+		r = append(r, arg.R)
+		s = append(s, arg.S)
+	}
+
+	return val, age, v, r, s
+}
+
 func retry(maxRetries int, delay time.Duration, f func() error) error {
 	for i := 0; ; i++ {
 		err := f()
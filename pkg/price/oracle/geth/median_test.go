@@ -166,3 +166,26 @@ func TestMedian_Poke(t *testing.T) {
 	assert.Equal(t, uint64(0), tx.Nonce)
 	assert.Equal(t, cd, hex.EncodeToString(tx.Data))
 }
+
+func TestMedian_EstimatePoke(t *testing.T) {
+	// Prepare test data:
+	c := &mocks.Client{}
+	a := ethereum.HexToAddress("0x1f8fbe73820765677e68eb6e933dcb3c94c9b708")
+	m := NewMedian(c, a)
+
+	p1 := &oracle.Price{Wat: "AAABBB", Age: time.Unix(100, 0)}
+	p1.SetFloat64Price(10)
+
+	c.On("EstimateGas", mock.Anything, mock.Anything).Return(uint64(100000), nil)
+	c.On("GasPrice", mock.Anything).Return(big.NewInt(50e9), nil)
+
+	// Call EstimatePoke function:
+	cost, err := m.EstimatePoke(context.Background(), []*oracle.Price{p1})
+
+	// Verify:
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(5e15), cost)
+	call := c.Calls[0].Arguments.Get(1).(ethereum.Call)
+	assert.Equal(t, a, call.Address)
+	assert.Equal(t, "89bbb8b2", hex.EncodeToString(call.Data[:4]))
+}
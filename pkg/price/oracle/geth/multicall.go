@@ -0,0 +1,82 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package geth
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
+)
+
+// multicallCall is the Call structure used by the Multicall contract.
+type multicallCall struct {
+	Target   common.Address
+	CallData []byte
+}
+
+// Multicall implements the oracle.BatchPoker interface using the Multicall2
+// contract: https://github.com/makerdao/multicall, or any other contract
+// with the same tryAggregate method, like Multicall3.
+//
+// Pokes are sent using the tryAggregate method, which does not require all
+// calls to succeed, so a single reverted poke, e.g. because another relayer
+// has already updated that Oracle, does not prevent updating the others.
+type Multicall struct {
+	ethereum ethereum.Client
+	address  ethereum.Address
+}
+
+// NewMulticall creates the new Multicall instance.
+func NewMulticall(ethereum ethereum.Client, address ethereum.Address) *Multicall {
+	return &Multicall{
+		ethereum: ethereum,
+		address:  address,
+	}
+}
+
+// BatchPoke implements the oracle.BatchPoker interface.
+func (m *Multicall) BatchPoke(ctx context.Context, pokes []oracle.Poke, simulateBeforeRun bool) (*ethereum.Hash, error) {
+	var calls []multicallCall
+	for _, p := range pokes {
+		val, age, v, r, s := pokeArgs(p.Prices)
+		cd, err := medianABI.Pack("poke", val, age, v, r, s)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, multicallCall{Target: p.Address, CallData: cd})
+	}
+
+	cd, err := multicallABI.Pack("tryAggregate", false, calls)
+	if err != nil {
+		return nil, err
+	}
+
+	if simulateBeforeRun {
+		if _, err := m.ethereum.Call(ctx, ethereum.Call{Address: m.address, Data: cd}); err != nil {
+			return nil, err
+		}
+	}
+
+	return m.ethereum.SendTransaction(ctx, &ethereum.Transaction{
+		Address:  m.address,
+		GasLimit: new(big.Int).SetUint64(gasLimit * uint64(len(pokes))),
+		Data:     cd,
+	})
+}
@@ -0,0 +1,98 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package geth
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
+)
+
+func TestMulticall_BatchPoke(t *testing.T) {
+	// Prepare test data:
+	c := &mocks.Client{}
+	a := ethereum.HexToAddress("0x1111111111111111111111111111111111111111")
+	m := NewMulticall(c, a)
+
+	newPrice := func(wat string, val float64, age int64) *oracle.Price {
+		p := &oracle.Price{Wat: wat}
+		p.SetFloat64Price(val)
+		p.Age = time.Unix(age, 0)
+		p.V = uint8(age)
+		p.R = [32]byte{byte(age)}
+		p.S = [32]byte{byte(age + 1)}
+		return p
+	}
+	pokes := []oracle.Poke{
+		{
+			Address: ethereum.HexToAddress("0x2222222222222222222222222222222222222222"),
+			Prices:  []*oracle.Price{newPrice("AAABBB", 20, 1), newPrice("AAABBB", 10, 2)},
+		},
+		{
+			Address: ethereum.HexToAddress("0x3333333333333333333333333333333333333333"),
+			Prices:  []*oracle.Price{newPrice("XXXYYY", 30, 3)},
+		},
+	}
+
+	c.On("SendTransaction", mock.Anything, mock.Anything).Return(&ethereum.Hash{}, nil)
+
+	// Call BatchPoke function:
+	_, err := m.BatchPoke(context.Background(), pokes, false)
+	require.NoError(t, err)
+
+	// Verify generated transaction:
+	tx := c.Calls[0].Arguments.Get(1).(*ethereum.Transaction)
+	assert.Equal(t, a, tx.Address)
+	assert.Equal(t, big.NewInt(gasLimit*2), tx.GasLimit)
+	assert.Equal(t, multicallABI.Methods["tryAggregate"].ID, tx.Data[:4])
+
+	args, err := multicallABI.Methods["tryAggregate"].Inputs.Unpack(tx.Data[4:])
+	require.NoError(t, err)
+
+	// A failed poke must not revert the whole transaction:
+	assert.Equal(t, false, args[0])
+	calls := args[1].([]struct {
+		Target   common.Address `json:"target"`
+		CallData []byte         `json:"callData"`
+	})
+	require.Len(t, calls, 2)
+	for i, call := range calls {
+		assert.Equal(t, pokes[i].Address, call.Target)
+		assert.Equal(t, medianABI.Methods["poke"].ID, call.CallData[:4])
+
+		poke, err := medianABI.Methods["poke"].Inputs.Unpack(call.CallData[4:])
+		require.NoError(t, err)
+		val := poke[0].([]*big.Int)
+		require.Len(t, val, len(pokes[i].Prices))
+		for j, p := range pokes[i].Prices {
+			// Prices must be sorted:
+			assert.Equal(t, p.Val, val[j])
+			if j > 0 {
+				assert.True(t, val[j-1].Cmp(val[j]) <= 0)
+			}
+		}
+	}
+}
@@ -67,3 +67,30 @@ type Median interface {
 	// transaction will be send.
 	SetBar(ctx context.Context, bar *big.Int, simulateBeforeRun bool) (*ethereum.Hash, error)
 }
+
+// PokeEstimator is implemented by Median contracts which can estimate the
+// cost of the poke transaction.
+type PokeEstimator interface {
+	// EstimatePoke returns the estimated cost, in wei, of the poke
+	// transaction with the given prices, that is, the estimated gas
+	// multiplied by the current gas price.
+	EstimatePoke(ctx context.Context, prices []*Price) (*big.Int, error)
+}
+
+// Poke contains arguments for a single poke call made by BatchPoker.
+type Poke struct {
+	// Address is the address of the medianizer contract.
+	Address common.Address
+	// Prices is the list of prices which will be sent to the contract.
+	Prices []*Price
+}
+
+// BatchPoker is an interface for a multicall contract which can be used to
+// update multiple Oracles in a single transaction.
+type BatchPoker interface {
+	// BatchPoke sends a single transaction which invokes the poke method
+	// on all given medianizer contracts. If simulateBeforeRun is set to true,
+	// then transaction will be simulated on the EVM before actual transaction
+	// will be send.
+	BatchPoke(ctx context.Context, pokes []Poke, simulateBeforeRun bool) (*ethereum.Hash, error)
+}
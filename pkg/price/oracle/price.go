@@ -21,7 +21,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
+	"strconv"
 	"strings"
 	"time"
 
@@ -31,8 +33,49 @@ import (
 
 const PriceMultiplier = 1e18
 
+// DefaultDecimals is the number of decimals of the fixed-point price
+// representation defined by PriceMultiplier.
+const DefaultDecimals = 18
+
+// maxScalingError is the maximum relative error caused by truncating a price
+// to the given number of decimals.
+const maxScalingError = 1e-6
+
+// maxVal is the maximum value that can be stored by the Median contract,
+// which uses the uint128 type for prices.
+var maxVal = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+// PriceVersion is the version of the JSON representation of the Price
+// structure. Prices encoded by older versions of the software do not contain
+// the version field and are treated as version 1.
+const PriceVersion = 2
+
 var ErrPriceNotSet = errors.New("unable to sign a price because the price is not set")
 var ErrUnmarshallingFailure = errors.New("unable to unmarshal given JSON")
+var ErrDomainNotSet = errors.New("price is not signed for any domain")
+
+// ErrPriceScaling is returned when a price cannot be represented as
+// a fixed-point number with the given number of decimals.
+type ErrPriceScaling struct {
+	Price    string
+	Decimals int
+	Reason   string
+}
+
+func (e ErrPriceScaling) Error() string {
+	return fmt.Sprintf("unable to scale the price %s to %d decimals: %s", e.Price, e.Decimals, e.Reason)
+}
+
+// ErrUnsupportedVersion is returned when a price was encoded using an unknown
+// version of the JSON representation, probably by a newer version of the
+// software.
+type ErrUnsupportedVersion struct {
+	Version int
+}
+
+func (e ErrUnsupportedVersion) Error() string {
+	return fmt.Sprintf("unsupported price version %d, the latest supported version is %d", e.Version, PriceVersion)
+}
 
 func errUnmarshalling(s string, err error) error {
 	return fmt.Errorf("%w: %s: %s", ErrUnmarshallingFailure, s, err)
@@ -52,19 +95,26 @@ type Price struct {
 	StarkR  []byte
 	StarkS  []byte
 	StarkPK []byte
+
+	// Domain separation:
+	Domain    string             // Domain is the network identifier, e.g. "mainnet".
+	DomainSig ethereum.Signature // DomainSig is the signature over the Domain and the price hash.
 }
 
 // jsonPrice is the JSON representation of the Price structure.
 type jsonPrice struct {
-	Wat     string `json:"wat"`
-	Val     string `json:"val"`
-	Age     int64  `json:"age"`
-	V       string `json:"v"`
-	R       string `json:"r"`
-	S       string `json:"s"`
-	StarkR  string `json:"stark_r,omitempty"`
-	StarkS  string `json:"stark_s,omitempty"`
-	StarkPK string `json:"stark_pk,omitempty"`
+	Version   int    `json:"version,omitempty"`
+	Wat       string `json:"wat"`
+	Val       string `json:"val"`
+	Age       int64  `json:"age"`
+	V         string `json:"v"`
+	R         string `json:"r"`
+	S         string `json:"s"`
+	StarkR    string `json:"stark_r,omitempty"`
+	StarkS    string `json:"stark_s,omitempty"`
+	StarkPK   string `json:"stark_pk,omitempty"`
+	Domain    string `json:"domain,omitempty"`
+	DomainSig string `json:"domain_sig,omitempty"`
 }
 
 func (p *Price) SetFloat64Price(price float64) {
@@ -75,6 +125,59 @@ func (p *Price) SetFloat64Price(price float64) {
 	p.Val = pi
 }
 
+// SetRatPrice sets the price from a rational number. Unlike SetFloat64Price,
+// it does not introduce rounding errors, the value is truncated only after
+// multiplying by PriceMultiplier.
+func (p *Price) SetRatPrice(price *big.Rat) {
+	x := new(big.Rat).Mul(price, new(big.Rat).SetInt64(PriceMultiplier))
+	p.Val = new(big.Int).Quo(x.Num(), x.Denom())
+}
+
+// SetFloat64PriceDecimals sets the price as a fixed-point number with the
+// given number of decimals, for Oracles that use a different scaling than
+// PriceMultiplier. The shortest decimal representation of the price is used.
+//
+// An error is returned if the price is negative, the value does not fit in
+// the uint128 type, or if truncation to the given number of decimals would
+// change the price by more than one part per million.
+func (p *Price) SetFloat64PriceDecimals(price float64, decimals int) error {
+	if math.IsNaN(price) || math.IsInf(price, 0) {
+		return ErrPriceScaling{Price: fmt.Sprint(price), Decimals: decimals, Reason: "invalid price"}
+	}
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(price, 'g', -1, 64))
+	return p.SetRatPriceDecimals(r, decimals)
+}
+
+// SetRatPriceDecimals works like SetFloat64PriceDecimals, but it takes the
+// price as a rational number.
+func (p *Price) SetRatPriceDecimals(price *big.Rat, decimals int) error {
+	errScaling := func(reason string) error {
+		str := strings.TrimRight(strings.TrimRight(price.FloatString(DefaultDecimals), "0"), ".")
+		return ErrPriceScaling{Price: str, Decimals: decimals, Reason: reason}
+	}
+	if decimals < 0 {
+		return errScaling("number of decimals must not be negative")
+	}
+	if price.Sign() < 0 {
+		return errScaling("price must not be negative")
+	}
+	m := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	x := new(big.Rat).Mul(price, new(big.Rat).SetInt(m))
+	val := new(big.Int).Quo(x.Num(), x.Denom())
+	if val.Cmp(maxVal) > 0 {
+		return errScaling("value overflows uint128")
+	}
+	if price.Sign() > 0 {
+		// Relative error caused by the truncation:
+		diff := new(big.Rat).Sub(x, new(big.Rat).SetInt(val))
+		if f, _ := diff.Quo(diff, x).Float64(); f > maxScalingError {
+			return errScaling("too much precision would be lost")
+		}
+	}
+	p.Val = val
+	return nil
+}
+
 func (p *Price) Float64Price() float64 {
 	x := new(big.Float).SetInt(p.Val)
 	x = new(big.Float).Quo(x, new(big.Float).SetFloat64(PriceMultiplier))
@@ -104,9 +207,34 @@ func (p *Price) Sign(signer ethereum.Signer) error {
 
 	p.V, p.R, p.S = signature.VRS()
 
+	if p.Domain != "" {
+		domainSig, err := signer.Signature(p.domainHash())
+		if err != nil {
+			return err
+		}
+		p.DomainSig = domainSig
+	}
+
 	return nil
 }
 
+// DomainFrom returns the address of the feeder that signed the price for
+// the network given in the Domain field.
+//
+// The domain signature is separate from the VRS signature because the latter
+// must remain compatible with the Median contract.
+func (p *Price) DomainFrom(signer ethereum.Signer) (*ethereum.Address, error) {
+	if p.Domain == "" {
+		return nil, ErrDomainNotSet
+	}
+	from, err := signer.Recover(p.DomainSig, p.domainHash())
+	if err != nil {
+		return nil, err
+	}
+
+	return from, nil
+}
+
 func (p *Price) Signature() ethereum.Signature {
 	return ethereum.SignatureFromVRS(p.V, p.R, p.S)
 }
@@ -129,11 +257,13 @@ func (p *Price) Fields(signer ethereum.Signer) log.Fields {
 		"starkR":  encodeHexNumber(p.StarkR),
 		"starkS":  encodeHexNumber(p.StarkS),
 		"starkPK": encodeHexNumber(p.StarkPK),
+		"domain":  p.Domain,
 	}
 }
 
 func (p *Price) MarshalJSON() ([]byte, error) {
-	return json.Marshal(jsonPrice{
+	j := jsonPrice{
+		Version: PriceVersion,
 		Wat:     p.Wat,
 		Val:     p.Val.String(),
 		Age:     p.Age.Unix(),
@@ -143,7 +273,12 @@ func (p *Price) MarshalJSON() ([]byte, error) {
 		StarkR:  encodeHexNumber(p.StarkR),
 		StarkS:  encodeHexNumber(p.StarkS),
 		StarkPK: encodeHexNumber(p.StarkPK),
-	})
+		Domain:  p.Domain,
+	}
+	if p.Domain != "" {
+		j.DomainSig = hex.EncodeToString(p.DomainSig.Bytes())
+	}
+	return json.Marshal(j)
 }
 
 func (p *Price) UnmarshalJSON(bytes []byte) error {
@@ -153,6 +288,15 @@ func (p *Price) UnmarshalJSON(bytes []byte) error {
 		return errUnmarshalling("price fields errors", err)
 	}
 
+	switch j.Version {
+	case 0, 1:
+		// Version 1 did not have the version field, otherwise it is
+		// identical to version 2.
+	case PriceVersion:
+	default:
+		return ErrUnsupportedVersion{Version: j.Version}
+	}
+
 	j.V = strings.TrimPrefix(j.V, "0x")
 	j.R = strings.TrimPrefix(j.R, "0x")
 	j.S = strings.TrimPrefix(j.S, "0x")
@@ -203,6 +347,19 @@ func (p *Price) UnmarshalJSON(bytes []byte) error {
 		return errUnmarshalling("unable to decode StarkPK param", err)
 	}
 
+	p.Domain = j.Domain
+	if len(j.DomainSig) != 0 {
+		var b []byte
+		b, err = hex.DecodeString(strings.TrimPrefix(j.DomainSig, "0x"))
+		if err != nil {
+			return errUnmarshalling("unable to decode domain signature", err)
+		}
+		if len(b) != ethereum.SignatureLength {
+			return errUnmarshalling("unable to decode domain signature", errors.New("invalid length"))
+		}
+		p.DomainSig = ethereum.SignatureFromBytes(b)
+	}
+
 	return nil
 }
 
@@ -247,3 +404,13 @@ func (p *Price) hash() []byte {
 
 	return ethereum.SHA3Hash(hash)
 }
+
+// domainHash is the hash signed by the DomainSig signature. It binds the price
+// hash to the network identifier: keccak256(keccak256(domain), hash).
+func (p *Price) domainHash() []byte {
+	hash := make([]byte, 64)
+	copy(hash[0:32], ethereum.SHA3Hash([]byte(p.Domain)))
+	copy(hash[32:64], p.hash())
+
+	return ethereum.SHA3Hash(hash)
+}
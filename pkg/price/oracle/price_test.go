@@ -18,11 +18,16 @@ package oracle
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"math"
+	"math/big"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
@@ -63,6 +68,59 @@ func TestPrice_SetFloat64Price(t *testing.T) {
 	}
 }
 
+func TestPrice_SetRatPrice(t *testing.T) {
+	// 0.1 * 3 = 0.30000000000000004 in float64 arithmetic:
+	a, b := 0.1, 3.0
+	pf := &Price{Wat: "AAABBB"}
+	pf.SetFloat64Price(a * b)
+	assert.NotEqual(t, "300000000000000000", pf.Val.String())
+
+	pr := &Price{Wat: "AAABBB"}
+	pr.SetRatPrice(new(big.Rat).Mul(big.NewRat(1, 10), big.NewRat(3, 1)))
+	assert.Equal(t, "300000000000000000", pr.Val.String())
+
+	// Values are truncated after multiplying by PriceMultiplier:
+	pr.SetRatPrice(big.NewRat(1, 3))
+	assert.Equal(t, "333333333333333333", pr.Val.String())
+}
+
+func TestPrice_SetFloat64PriceDecimals(t *testing.T) {
+	tests := []struct {
+		price    float64
+		decimals int
+		want     string
+		wantErr  bool
+	}{
+		{price: 1234.5678, decimals: 18, want: "1234567800000000000000"},
+		{price: 1234.5678, decimals: 8, want: "123456780000"},
+		{price: 0.1, decimals: 8, want: "10000000"},
+		{price: 0, decimals: 8, want: "0"},
+		{price: 12345678.9, decimals: 0, want: "12345678"},
+		{price: 1234.5678, decimals: 0, wantErr: true},     // Only 4 significant digits are left.
+		{price: 0.000000001, decimals: 8, wantErr: true},   // Truncated to zero.
+		{price: 0.00012345678, decimals: 8, wantErr: true}, // Only 5 significant digits are left.
+		{price: 1e21, decimals: 18, wantErr: true},         // Overflows uint128.
+		{price: -1, decimals: 18, wantErr: true},           // Negative price.
+		{price: 1, decimals: -1, wantErr: true},            // Negative decimals.
+		{price: math.Inf(1), decimals: 18, wantErr: true},  // Invalid price.
+		{price: math.NaN(), decimals: 18, wantErr: true},   // Invalid price.
+		{price: 1e20, decimals: 18, want: "100000000000000000000000000000000000000"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v/%d", tt.price, tt.decimals), func(t *testing.T) {
+			p := &Price{Wat: "AAABBB"}
+			err := p.SetFloat64PriceDecimals(tt.price, tt.decimals)
+			if tt.wantErr {
+				assert.True(t, errors.As(err, &ErrPriceScaling{}))
+				assert.Nil(t, p.Val)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, p.Val.String())
+		})
+	}
+}
+
 func TestPrice_Sign(t *testing.T) {
 	s := &mocks.Signer{}
 	p := &Price{Wat: "AAABBB"}
@@ -94,6 +152,38 @@ func TestPrice_Sign(t *testing.T) {
 	assert.Equal(t, addr, *retAddr)
 }
 
+func TestPrice_Sign_Domain(t *testing.T) {
+	s := &mocks.Signer{}
+	p := &Price{Wat: "AAABBB", Domain: "mainnet"}
+	p.Age = time.Unix(1605371361, 0)
+	p.SetFloat64Price(42)
+
+	sig := ethereum.Signature{0x01}
+	domainSig := ethereum.Signature{0x02}
+	var addr ethereum.Address
+	rand.Read(addr[:])
+
+	// The VRS signature must not depend on the domain, otherwise it would not
+	// be accepted by the Median contract:
+	hash, _ := hex.DecodeString(priceHash)
+	domainHash := ethereum.SHA3Hash(append(ethereum.SHA3Hash([]byte("mainnet")), hash...))
+	s.On("Signature", hash).Return(sig, nil)
+	s.On("Signature", domainHash).Return(domainSig, nil)
+	require.NoError(t, p.Sign(s))
+	assert.Equal(t, sig, p.Signature())
+	assert.Equal(t, domainSig, p.DomainSig)
+
+	s.On("Recover", domainSig, domainHash).Return(&addr, nil)
+	retAddr, err := p.DomainFrom(s)
+	require.NoError(t, err)
+	assert.Equal(t, addr, *retAddr)
+
+	// Without the domain:
+	p.Domain = ""
+	_, err = p.DomainFrom(s)
+	assert.ErrorIs(t, err, ErrDomainNotSet)
+}
+
 func TestPrice_Sign_NoPrice(t *testing.T) {
 	s := &mocks.Signer{}
 	p := &Price{Wat: "AAABBB"}
@@ -118,6 +208,7 @@ func TestPrice_Marshall(t *testing.T) {
 	assert.NoError(t, err)
 	assert.JSONEq(t, `
 		{
+		   "version":2,
 		   "wat":"AAABBB",
 		   "val":"42000000000000000000",
 		   "age":1605371361,
@@ -159,6 +250,7 @@ func TestPrice_Marshall_WithoutStarknet(t *testing.T) {
 	assert.NoError(t, err)
 	assert.JSONEq(t, `
 		{
+		   "version":2,
 		   "wat":"AAABBB",
 		   "val":"42000000000000000000",
 		   "age":1605371361,
@@ -186,3 +278,65 @@ func TestPrice_Marshall_WithoutStarknet(t *testing.T) {
 	assert.Len(t, p2.StarkS, 0)
 	assert.Len(t, p2.StarkPK, 0)
 }
+
+func TestPrice_Marshall_Domain(t *testing.T) {
+	p := &Price{Wat: "AAABBB", Domain: "mainnet"}
+	p.Age = time.Unix(1605371361, 0)
+	p.SetFloat64Price(42)
+	p.DomainSig = ethereum.Signature{0x03}
+
+	j, err := p.MarshalJSON()
+	require.NoError(t, err)
+	assert.Contains(t, string(j), `"domain":"mainnet"`)
+	assert.Contains(t, string(j), `"domain_sig":"03`)
+
+	var p2 Price
+	require.NoError(t, p2.UnmarshalJSON(j))
+	assert.Equal(t, p.Domain, p2.Domain)
+	assert.Equal(t, p.DomainSig, p2.DomainSig)
+
+	// Invalid signature length:
+	err = p2.UnmarshalJSON([]byte(`{"wat":"AAABBB","val":"1","age":1,"domain":"mainnet","domain_sig":"0102"}`))
+	assert.ErrorIs(t, err, ErrUnmarshallingFailure)
+}
+
+func TestPrice_Unmarshall_Versions(t *testing.T) {
+	tests := []struct {
+		json    string
+		wantErr error
+	}{
+		{ // Version 1 did not have the version field.
+			json: `{"wat":"AAABBB","val":"42000000000000000000","age":1605371361,"v":"aa","r":"` +
+				`0100000000000000000000000000000000000000000000000000000000000000","s":"` +
+				`0200000000000000000000000000000000000000000000000000000000000000"}`,
+		},
+		{
+			json: `{"version":2,"wat":"AAABBB","val":"42000000000000000000","age":1605371361,"v":"aa","r":"` +
+				`0100000000000000000000000000000000000000000000000000000000000000","s":"` +
+				`0200000000000000000000000000000000000000000000000000000000000000"}`,
+		},
+		{
+			json: `{"version":99,"wat":"AAABBB","val":"42000000000000000000","age":1605371361,"v":"aa","r":"` +
+				`0100000000000000000000000000000000000000000000000000000000000000","s":"` +
+				`0200000000000000000000000000000000000000000000000000000000000000"}`,
+			wantErr: ErrUnsupportedVersion{Version: 99},
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var p Price
+			err := json.Unmarshal([]byte(tt.json), &p)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "AAABBB", p.Wat)
+			assert.Equal(t, float64(42), p.Float64Price())
+			assert.Equal(t, time.Unix(1605371361, 0), p.Age)
+			assert.Equal(t, byte(0xAA), p.V)
+			assert.Equal(t, [32]byte{0x01}, p.R)
+			assert.Equal(t, [32]byte{0x02}, p.S)
+		})
+	}
+}
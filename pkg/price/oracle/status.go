@@ -0,0 +1,71 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+)
+
+// Status contains the current state of the Oracle contract.
+type Status struct {
+	// Address is the address of the Oracle contract.
+	Address ethereum.Address
+	// Wat is the asset name.
+	Wat string
+	// Bar is the minimum number of prices necessary to accept a new median
+	// value.
+	Bar int64
+	// Age is the time of the last price update.
+	Age time.Time
+	// Val is the current asset price multiplied by PriceMultiplier.
+	Val *big.Int
+	// Feeds is the list of addresses authorized to update the price.
+	Feeds []ethereum.Address
+}
+
+// Float64Price returns the current asset price as a float.
+func (s *Status) Float64Price() float64 {
+	return (&Price{Val: s.Val}).Float64Price()
+}
+
+// ReadStatus reads the current state of the Oracle contract.
+func ReadStatus(ctx context.Context, m Median) (*Status, error) {
+	var err error
+	s := &Status{Address: m.Address()}
+	if s.Wat, err = m.Wat(ctx); err != nil {
+		return nil, err
+	}
+	if s.Bar, err = m.Bar(ctx); err != nil {
+		return nil, err
+	}
+	if s.Age, err = m.Age(ctx); err != nil {
+		return nil, err
+	}
+	if s.Val, err = m.Val(ctx); err != nil {
+		return nil, err
+	}
+	if s.Feeds, err = m.Feeds(ctx); err != nil {
+		return nil, err
+	}
+	// The asset name is stored as bytes32, so it's padded with zeros:
+	s.Wat = strings.TrimRight(s.Wat, "\x00")
+	return s, nil
+}
@@ -0,0 +1,67 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+)
+
+type staticMedian struct {
+	Median
+	address ethereum.Address
+	wat     string
+	bar     int64
+	age     time.Time
+	val     *big.Int
+	feeds   []ethereum.Address
+}
+
+func (m *staticMedian) Address() common.Address                           { return m.address }
+func (m *staticMedian) Wat(context.Context) (string, error)               { return m.wat, nil }
+func (m *staticMedian) Bar(context.Context) (int64, error)                { return m.bar, nil }
+func (m *staticMedian) Age(context.Context) (time.Time, error)            { return m.age, nil }
+func (m *staticMedian) Val(context.Context) (*big.Int, error)             { return m.val, nil }
+func (m *staticMedian) Feeds(context.Context) ([]ethereum.Address, error) { return m.feeds, nil }
+
+func TestReadStatus(t *testing.T) {
+	m := &staticMedian{
+		address: ethereum.HexToAddress("0x1111111111111111111111111111111111111111"),
+		wat:     "ETHUSD" + string(make([]byte, 26)),
+		bar:     13,
+		age:     time.Unix(1600000000, 0),
+		val:     new(big.Int).Mul(big.NewInt(1500), big.NewInt(PriceMultiplier)),
+		feeds:   []ethereum.Address{ethereum.HexToAddress("0x2222222222222222222222222222222222222222")},
+	}
+
+	s, err := ReadStatus(context.Background(), m)
+	require.NoError(t, err)
+	assert.Equal(t, m.address, s.Address)
+	assert.Equal(t, "ETHUSD", s.Wat)
+	assert.Equal(t, int64(13), s.Bar)
+	assert.Equal(t, m.age, s.Age)
+	assert.Equal(t, m.val, s.Val)
+	assert.Equal(t, 1500.0, s.Float64Price())
+	assert.Equal(t, m.feeds, s.Feeds)
+}
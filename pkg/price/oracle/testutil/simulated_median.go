@@ -0,0 +1,225 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package testutil
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
+)
+
+// Errors returned by the SimulatedMedian. They correspond to the revert
+// reasons of the Median contract.
+var (
+	ErrBarTooLow     = errors.New("Median/bar-too-low")
+	ErrStaleMessage  = errors.New("Median/stale-message")
+	ErrNotInOrder    = errors.New("Median/messages-not-in-order")
+	ErrInvalidOracle = errors.New("Median/invalid-oracle")
+	ErrAlreadySigned = errors.New("Median/oracle-already-signed")
+	ErrQuorumIsZero  = errors.New("Median/quorum-is-zero")
+	ErrQuorumNotOdd  = errors.New("Median/quorum-not-odd-number")
+)
+
+// SimulatedMedian is an in-memory implementation of the oracle.Median interface
+// which mimics the behavior of the Median contract. It is intended to be
+// used in tests, where it replaces a real contract.
+//
+// If a signer is provided, the SimulatedMedian verifies that prices are
+// signed by the lifted feeders, otherwise signatures are ignored.
+type SimulatedMedian struct {
+	mu sync.Mutex
+
+	address common.Address
+	wat     string
+	signer  ethereum.Signer
+	age     time.Time
+	bar     int64
+	val     *big.Int
+	feeds   []ethereum.Address
+	pokes   [][]*oracle.Price
+	txCount int64
+}
+
+// NewSimulatedMedian returns a new SimulatedMedian instance for the given
+// asset pair. The signer may be nil.
+func NewSimulatedMedian(address common.Address, wat string, bar int64, signer ethereum.Signer) *SimulatedMedian {
+	return &SimulatedMedian{
+		address: address,
+		wat:     wat,
+		signer:  signer,
+		bar:     bar,
+		val:     big.NewInt(0),
+	}
+}
+
+// SetState sets the current price and its age, as if the contract were
+// poked at the given time.
+func (m *SimulatedMedian) SetState(val *big.Int, age time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.val = new(big.Int).Set(val)
+	m.age = age
+}
+
+// Pokes returns the list of prices from all successful Poke calls.
+func (m *SimulatedMedian) Pokes() [][]*oracle.Price {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([][]*oracle.Price(nil), m.pokes...)
+}
+
+// Address implements the oracle.Median interface.
+func (m *SimulatedMedian) Address() common.Address {
+	return m.address
+}
+
+// Age implements the oracle.Median interface.
+func (m *SimulatedMedian) Age(_ context.Context) (time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.age, nil
+}
+
+// Bar implements the oracle.Median interface.
+func (m *SimulatedMedian) Bar(_ context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.bar, nil
+}
+
+// Val implements the oracle.Median interface.
+func (m *SimulatedMedian) Val(_ context.Context) (*big.Int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return new(big.Int).Set(m.val), nil
+}
+
+// Wat implements the oracle.Median interface.
+func (m *SimulatedMedian) Wat(_ context.Context) (string, error) {
+	return m.wat, nil
+}
+
+// Feeds implements the oracle.Median interface.
+func (m *SimulatedMedian) Feeds(_ context.Context) ([]ethereum.Address, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]ethereum.Address(nil), m.feeds...), nil
+}
+
+// Poke implements the oracle.Median interface. The prices are validated the
+// same way as in the Median contract. On success, the median price is stored
+// and the age is set to the current time.
+func (m *SimulatedMedian) Poke(_ context.Context, prices []*oracle.Price, _ bool) (*ethereum.Hash, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if int64(len(prices)) != m.bar {
+		return nil, ErrBarTooLow
+	}
+	signed := map[ethereum.Address]bool{}
+	for i, p := range prices {
+		if !p.Age.After(m.age) {
+			return nil, ErrStaleMessage
+		}
+		if i > 0 && p.Val.Cmp(prices[i-1].Val) < 0 {
+			return nil, ErrNotInOrder
+		}
+		if m.signer == nil {
+			continue
+		}
+		from, err := p.From(m.signer)
+		if err != nil || !m.isFeed(*from) {
+			return nil, ErrInvalidOracle
+		}
+		if signed[*from] {
+			return nil, ErrAlreadySigned
+		}
+		signed[*from] = true
+	}
+	m.val = new(big.Int).Set(prices[len(prices)/2].Val)
+	m.age = time.Now()
+	m.pokes = append(m.pokes, prices)
+	return m.txHash(), nil
+}
+
+// Lift implements the oracle.Median interface.
+func (m *SimulatedMedian) Lift(_ context.Context, addresses []common.Address, _ bool) (*ethereum.Hash, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, a := range addresses {
+		if !m.isFeed(a) {
+			m.feeds = append(m.feeds, a)
+		}
+	}
+	return m.txHash(), nil
+}
+
+// Drop implements the oracle.Median interface.
+func (m *SimulatedMedian) Drop(_ context.Context, addresses []common.Address, _ bool) (*ethereum.Hash, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var feeds []ethereum.Address
+	for _, f := range m.feeds {
+		drop := false
+		for _, a := range addresses {
+			if f == a {
+				drop = true
+				break
+			}
+		}
+		if !drop {
+			feeds = append(feeds, f)
+		}
+	}
+	m.feeds = feeds
+	return m.txHash(), nil
+}
+
+// SetBar implements the oracle.Median interface.
+func (m *SimulatedMedian) SetBar(_ context.Context, bar *big.Int, _ bool) (*ethereum.Hash, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if bar.Sign() <= 0 {
+		return nil, ErrQuorumIsZero
+	}
+	if bar.Bit(0) == 0 {
+		return nil, ErrQuorumNotOdd
+	}
+	m.bar = bar.Int64()
+	return m.txHash(), nil
+}
+
+func (m *SimulatedMedian) isFeed(address ethereum.Address) bool {
+	for _, f := range m.feeds {
+		if f == address {
+			return true
+		}
+	}
+	return false
+}
+
+// txHash returns a unique, fake transaction hash.
+func (m *SimulatedMedian) txHash() *ethereum.Hash {
+	m.txCount++
+	h := common.BigToHash(big.NewInt(m.txCount))
+	return &h
+}
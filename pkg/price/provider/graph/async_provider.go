@@ -18,6 +18,7 @@ package graph
 import (
 	"context"
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
@@ -33,11 +34,15 @@ const LoggerTag = "ASYNC_GOFER"
 // but allows updating prices asynchronously.
 type AsyncProvider struct {
 	*Provider
-	ctx    context.Context
-	waitCh chan error
-	feeder *feeder.Feeder
-	nodes  []nodes.Node
-	log    log.Logger
+	ctx     context.Context
+	waitCh  chan error
+	readyCh chan struct{}
+	feeder  *feeder.Feeder
+	nodes   []nodes.Node
+	log     log.Logger
+
+	mu   sync.Mutex
+	subs map[chan struct{}]struct{}
 }
 
 // NewAsyncProvider returns a new AsyncGofer instance.
@@ -51,9 +56,11 @@ func NewAsyncProvider(
 	return &AsyncProvider{
 		Provider: NewProvider(graph, nil),
 		waitCh:   make(chan error),
+		readyCh:  make(chan struct{}),
 		feeder:   feeder,
 		nodes:    nodes,
 		log:      logger.WithField("tag", LoggerTag),
+		subs:     map[chan struct{}]struct{}{},
 	}, nil
 }
 
@@ -81,6 +88,8 @@ func (a *AsyncProvider) Start(ctx context.Context) error {
 			}
 		}, graph)
 	}
+	var ready sync.WaitGroup
+	ready.Add(len(originNodes))
 	for _, ns := range originNodes {
 		ns := ns
 		ttl := gcdTTL(ns)
@@ -95,10 +104,12 @@ func (a *AsyncProvider) Start(ctx context.Context) error {
 			if len(warns.List) > 0 {
 				a.log.WithError(warns.ToError()).Warn("Unable to feed some nodes")
 			}
+			a.notify()
 		}
 		go func() {
 			ticker := time.NewTicker(ttl)
 			feed()
+			ready.Done()
 			for {
 				select {
 				case <-a.ctx.Done():
@@ -111,19 +122,60 @@ func (a *AsyncProvider) Start(ctx context.Context) error {
 		}()
 	}
 
+	go func() {
+		ready.Wait()
+		close(a.readyCh)
+	}()
 	go a.contextCancelHandler()
 	return nil
 }
 
+// Ready returns a channel that is closed once prices from all origins have
+// been fetched for the first time, whether successfully or not.
+func (a *AsyncProvider) Ready() <-chan struct{} {
+	return a.readyCh
+}
+
 // Wait waits until the context is canceled or until an error occurs.
 func (a *AsyncProvider) Wait() chan error {
 	return a.waitCh
 }
 
+// Subscribe returns a channel that receives a value every time prices are
+// updated. Notifications are coalesced, so a slow receiver gets only one
+// notification for several updates. The returned function must be called to
+// unsubscribe.
+func (a *AsyncProvider) Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	a.mu.Lock()
+	a.subs[ch] = struct{}{}
+	a.mu.Unlock()
+	return ch, func() {
+		a.mu.Lock()
+		delete(a.subs, ch)
+		a.mu.Unlock()
+	}
+}
+
+// notify notifies all subscribers about updated prices.
+func (a *AsyncProvider) notify() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for ch := range a.subs {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
 func (a *AsyncProvider) contextCancelHandler() {
 	defer func() { close(a.waitCh) }()
 	defer a.log.Info("Stopped")
 	<-a.ctx.Done()
+	if err := a.feeder.Close(); err != nil {
+		a.log.WithError(err).Warn("Unable to close origins")
+	}
 }
 
 // gcdTTL returns the greatest common divisor of nodes minTTLs.
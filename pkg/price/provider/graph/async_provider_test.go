@@ -1,18 +1,35 @@
 package graph
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/graph/feeder"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/graph/nodes"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/origins"
 )
 
+type staticHandler struct {
+	price float64
+}
+
+func (h staticHandler) Fetch(pairs []origins.Pair) []origins.FetchResult {
+	var frs []origins.FetchResult
+	for _, p := range pairs {
+		frs = append(frs, origins.FetchResult{Price: origins.Price{Pair: p, Price: h.price, Timestamp: time.Now()}})
+	}
+	return frs
+}
+
 func Test_gcdTTL(t *testing.T) {
 	p := provider.Pair{Base: "A", Quote: "B"}
-	root := nodes.NewMedianAggregatorNode(p, 1)
+	root := nodes.NewMedianAggregatorNode(p, 1, 0)
 	ttl := time.Second * time.Duration(time.Now().Unix()+10)
 	on1 := nodes.NewOriginNode(nodes.OriginPair{Origin: "a", Pair: p}, 12*time.Second, ttl)
 	on2 := nodes.NewOriginNode(nodes.OriginPair{Origin: "b", Pair: p}, 6*time.Second, ttl)
@@ -24,3 +41,52 @@ func Test_gcdTTL(t *testing.T) {
 
 	assert.Equal(t, 2*time.Second, gcdTTL([]nodes.Node{root}))
 }
+
+func TestAsyncProvider_Subscribe(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	p := provider.Pair{Base: "A", Quote: "B"}
+	root := nodes.NewMedianAggregatorNode(p, 1, 0)
+	root.AddChild(nodes.NewOriginNode(nodes.OriginPair{Origin: "a", Pair: p}, time.Minute, time.Hour))
+	fed := feeder.NewFeeder(origins.NewSet(map[string]origins.Handler{"a": staticHandler{price: 10}}), null.New())
+	gof, err := NewAsyncProvider(map[provider.Pair]nodes.Aggregator{p: root}, fed, []nodes.Node{root}, null.New())
+	require.NoError(t, err)
+
+	ch, unsubscribe := gof.Subscribe()
+	defer unsubscribe()
+	require.NoError(t, gof.Start(ctx))
+
+	// The first feed cycle runs immediately after start:
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		require.Fail(t, "no notification after a feed cycle")
+	}
+	price, err := gof.Price(p)
+	require.NoError(t, err)
+	assert.Equal(t, 10.0, price.Price)
+}
+
+func TestAsyncProvider_Ready(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	p := provider.Pair{Base: "A", Quote: "B"}
+	root := nodes.NewMedianAggregatorNode(p, 1, 0)
+	root.AddChild(nodes.NewOriginNode(nodes.OriginPair{Origin: "a", Pair: p}, time.Minute, time.Hour))
+	fed := feeder.NewFeeder(origins.NewSet(map[string]origins.Handler{"a": staticHandler{price: 10}}), null.New())
+	gof, err := NewAsyncProvider(map[provider.Pair]nodes.Aggregator{p: root}, fed, []nodes.Node{root}, null.New())
+	require.NoError(t, err)
+	require.NoError(t, gof.Start(ctx))
+
+	// Once ready, prices from the first feed cycle must be available:
+	select {
+	case <-gof.Ready():
+	case <-time.After(time.Second):
+		require.Fail(t, "provider is not ready after the first feed cycle")
+	}
+	price, err := gof.Price(p)
+	require.NoError(t, err)
+	assert.Equal(t, 10.0, price.Price)
+}
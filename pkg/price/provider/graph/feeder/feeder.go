@@ -70,18 +70,37 @@ type Feedable interface {
 type Feeder struct {
 	waitCh chan error
 	set    *origins.Set
+	health *health
 	log    log.Logger
+	now    func() time.Time
 }
 
 // NewFeeder creates new Feeder instance.
 func NewFeeder(set *origins.Set, log log.Logger) *Feeder {
 	return &Feeder{
 		set:    set,
+		health: newHealth(HealthConfig{}),
 		log:    log.WithField("tag", LoggerTag),
 		waitCh: make(chan error),
+		now:    time.Now,
 	}
 }
 
+// SetHealthConfig enables skipping of origins that repeatedly fail to
+// return prices. It must be called before the Feeder is used.
+func (f *Feeder) SetHealthConfig(cfg HealthConfig) {
+	f.health = newHealth(cfg)
+}
+
+// Close closes origins used by the Feeder, e.g. open connections of
+// streaming origins. The Feeder must not be used after it is closed.
+func (f *Feeder) Close() error {
+	if f.set == nil {
+		return nil
+	}
+	return f.set.Close()
+}
+
 // Feed sets Prices to Feedable nodes. This method takes list of root nodes
 // and sets prices to all of their children that implement the Feedable interface.
 // The t parameter represents the time against which the price expiration is compared.
@@ -138,7 +157,18 @@ func (f *Feeder) feedNodes(ns []Feedable) Warnings {
 		)
 	}
 
+	now := f.now()
+	for origin := range pairsMap {
+		if f.health.skipped(origin, now) {
+			f.log.
+				WithField("origin", origin).
+				Debug("Origin skipped due to previous failures")
+			delete(pairsMap, origin)
+		}
+	}
+
 	for origin, frs := range f.set.Fetch(pairsMap) {
+		f.updateHealth(origin, frs, now)
 		for _, fr := range frs {
 			op := originPair{
 				origin: origin,
@@ -162,6 +192,23 @@ func (f *Feeder) feedNodes(ns []Feedable) Warnings {
 	return warns
 }
 
+// updateHealth updates the health state of the origin. The fetch is
+// considered failed if the origin did not return any valid price.
+func (f *Feeder) updateHealth(origin string, frs []origins.FetchResult, t time.Time) {
+	for _, fr := range frs {
+		if fr.Error == nil {
+			f.health.success(origin)
+			return
+		}
+	}
+	if until := f.health.failure(origin, t); !until.IsZero() {
+		f.log.
+			WithField("origin", origin).
+			WithField("until", until.String()).
+			Warn("Origin temporarily excluded due to repeated failures")
+	}
+}
+
 func appendPairIfUnique(pairs []origins.Pair, pair origins.Pair) []origins.Pair {
 	exists := false
 	for _, p := range pairs {
@@ -16,6 +16,7 @@
 package feeder
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -81,7 +82,7 @@ func TestFeeder_Feed_EmptyGraph(t *testing.T) {
 }
 
 func TestFeeder_Feed_NoFeedableNodes(t *testing.T) {
-	g := nodes.NewMedianAggregatorNode(provider.Pair{Base: "A", Quote: "B"}, 1)
+	g := nodes.NewMedianAggregatorNode(provider.Pair{Base: "A", Quote: "B"}, 1, 0)
 	f := NewFeeder(originsSetMock(nil), null.New())
 
 	// Feed method shouldn't panic
@@ -104,7 +105,7 @@ func TestFeeder_Feed_OneOriginNode(t *testing.T) {
 		},
 	})
 
-	g := nodes.NewMedianAggregatorNode(provider.Pair{Base: "A", Quote: "B"}, 1)
+	g := nodes.NewMedianAggregatorNode(provider.Pair{Base: "A", Quote: "B"}, 1, 0)
 	o := nodes.NewOriginNode(nodes.OriginPair{
 		Origin: "test",
 		Pair:   provider.Pair{Base: "A", Quote: "B"},
@@ -155,7 +156,7 @@ func TestFeeder_Feed_ManyOriginNodes(t *testing.T) {
 		},
 	})
 
-	g := nodes.NewMedianAggregatorNode(provider.Pair{Base: "A", Quote: "B"}, 1)
+	g := nodes.NewMedianAggregatorNode(provider.Pair{Base: "A", Quote: "B"}, 1, 0)
 	o1 := nodes.NewOriginNode(nodes.OriginPair{
 		Origin: "test",
 		Pair:   provider.Pair{Base: "A", Quote: "B"},
@@ -238,7 +239,7 @@ func TestFeeder_Feed_NestedOriginNode(t *testing.T) {
 		},
 	})
 
-	g := nodes.NewMedianAggregatorNode(provider.Pair{Base: "A", Quote: "B"}, 1)
+	g := nodes.NewMedianAggregatorNode(provider.Pair{Base: "A", Quote: "B"}, 1, 0)
 	i := nodes.NewIndirectAggregatorNode(provider.Pair{Base: "A", Quote: "B"})
 	o := nodes.NewOriginNode(nodes.OriginPair{
 		Origin: "test",
@@ -274,7 +275,7 @@ func TestFeeder_Feed_BelowMinTTL(t *testing.T) {
 		},
 	})
 
-	g := nodes.NewMedianAggregatorNode(provider.Pair{Base: "A", Quote: "B"}, 1)
+	g := nodes.NewMedianAggregatorNode(provider.Pair{Base: "A", Quote: "B"}, 1, 0)
 	o := nodes.NewOriginNode(nodes.OriginPair{
 		Origin: "test",
 		Pair:   provider.Pair{Base: "A", Quote: "B"},
@@ -321,7 +322,7 @@ func TestFeeder_Feed_BetweenTTLs(t *testing.T) {
 		},
 	})
 
-	g := nodes.NewMedianAggregatorNode(provider.Pair{Base: "A", Quote: "B"}, 1)
+	g := nodes.NewMedianAggregatorNode(provider.Pair{Base: "A", Quote: "B"}, 1, 0)
 	o := nodes.NewOriginNode(nodes.OriginPair{
 		Origin: "test",
 		Pair:   provider.Pair{Base: "A", Quote: "B"},
@@ -353,3 +354,98 @@ func TestFeeder_Feed_BetweenTTLs(t *testing.T) {
 	assert.Equal(t, 12.0, o.Price().Ask)
 	assert.Equal(t, 11.0, o.Price().Volume24h)
 }
+
+type failingHandler struct {
+	calls int
+	fail  bool
+}
+
+func (h *failingHandler) Fetch(pairs []origins.Pair) []origins.FetchResult {
+	h.calls++
+	var fr []origins.FetchResult
+	for _, pair := range pairs {
+		if h.fail {
+			fr = append(fr, origins.FetchResult{Price: origins.Price{Pair: pair}, Error: errors.New("failed")})
+		} else {
+			fr = append(fr, origins.FetchResult{Price: origins.Price{Pair: pair, Price: 10, Timestamp: time.Unix(10000, 0)}})
+		}
+	}
+	return fr
+}
+
+func TestFeeder_Feed_Health(t *testing.T) {
+	h := &failingHandler{fail: true}
+	o := nodes.NewOriginNode(nodes.OriginPair{
+		Origin: "test",
+		Pair:   provider.Pair{Base: "A", Quote: "B"},
+	}, 0, 0)
+
+	now := time.Unix(10000, 0)
+	f := NewFeeder(origins.NewSet(map[string]origins.Handler{"test": h}), null.New())
+	f.now = func() time.Time { return now }
+	f.SetHealthConfig(HealthConfig{
+		FailureThreshold: 2,
+		Cooldown:         time.Minute,
+		MaxCooldown:      90 * time.Second,
+	})
+
+	feed := func(after time.Duration) {
+		now = now.Add(after)
+		f.Feed([]nodes.Node{o}, now)
+	}
+
+	// Failures below the threshold:
+	feed(0)
+	feed(0)
+	assert.Equal(t, 2, h.calls)
+
+	// The origin should be skipped during the cooldown:
+	feed(30 * time.Second)
+	assert.Equal(t, 2, h.calls)
+
+	// After the cooldown, the origin should be probed again:
+	feed(30 * time.Second)
+	assert.Equal(t, 3, h.calls)
+
+	// The probe failed, so the cooldown should be doubled, but limited to
+	// the MaxCooldown:
+	feed(time.Minute)
+	assert.Equal(t, 3, h.calls)
+	feed(30 * time.Second)
+	assert.Equal(t, 4, h.calls)
+
+	// Successful fetch should restore the origin:
+	h.fail = false
+	feed(90 * time.Second)
+	assert.Equal(t, 5, h.calls)
+	feed(0)
+	assert.Equal(t, 6, h.calls)
+	assert.Equal(t, 10.0, o.Price().Price)
+}
+
+func TestFeeder_Feed_HealthDefaultCooldown(t *testing.T) {
+	h := &failingHandler{fail: true}
+	o := nodes.NewOriginNode(nodes.OriginPair{
+		Origin: "test",
+		Pair:   provider.Pair{Base: "A", Quote: "B"},
+	}, 0, 0)
+
+	now := time.Unix(10000, 0)
+	f := NewFeeder(origins.NewSet(map[string]origins.Handler{"test": h}), null.New())
+	f.now = func() time.Time { return now }
+	f.SetHealthConfig(HealthConfig{FailureThreshold: 1})
+
+	feed := func(after time.Duration) {
+		now = now.Add(after)
+		f.Feed([]nodes.Node{o}, now)
+	}
+
+	// Without the cooldown, the origin should be skipped for the default
+	// time:
+	feed(0)
+	assert.Equal(t, 1, h.calls)
+	feed(defaultHealthCooldown - time.Second)
+	assert.Equal(t, 1, h.calls)
+	feed(time.Second)
+	assert.Equal(t, 2, h.calls)
+}
@@ -0,0 +1,104 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package feeder
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultHealthCooldown is used when the HealthConfig.Cooldown is not set.
+const defaultHealthCooldown = time.Minute
+
+// HealthConfig configures tracking of origin health. An origin that fails
+// to return any price for FailureThreshold consecutive fetches is skipped
+// for the Cooldown time. After the cooldown, the origin is probed again. If
+// it still fails, the cooldown is doubled, up to MaxCooldown.
+type HealthConfig struct {
+	// FailureThreshold is the number of consecutive failures after which
+	// the origin is skipped. If zero, health tracking is disabled.
+	FailureThreshold int
+	// Cooldown is the initial time for which a failing origin is skipped.
+	// If zero, the default of 1 minute is used.
+	Cooldown time.Duration
+	// MaxCooldown is the maximum time for which a failing origin is skipped.
+	// If zero, the cooldown is not limited.
+	MaxCooldown time.Duration
+}
+
+type originState struct {
+	failures  int
+	cooldown  time.Duration
+	skipUntil time.Time
+}
+
+// health tracks the health of origins. It is safe for concurrent use.
+type health struct {
+	mu     sync.Mutex
+	cfg    HealthConfig
+	states map[string]*originState
+}
+
+func newHealth(cfg HealthConfig) *health {
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = defaultHealthCooldown
+	}
+	return &health{
+		cfg:    cfg,
+		states: make(map[string]*originState),
+	}
+}
+
+// skipped returns true if the origin should not be queried at the given time.
+func (h *health) skipped(origin string, t time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.states[origin]
+	return ok && t.Before(s.skipUntil)
+}
+
+// success resets the health state of the origin.
+func (h *health) success(origin string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.states, origin)
+}
+
+// failure records a failed fetch. It returns the time until which the origin
+// will be skipped, or zero time if the failure threshold is not reached yet.
+func (h *health) failure(origin string, t time.Time) time.Time {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.states[origin]
+	if !ok {
+		s = &originState{}
+		h.states[origin] = s
+	}
+	s.failures++
+	if h.cfg.FailureThreshold <= 0 || s.failures < h.cfg.FailureThreshold {
+		return time.Time{}
+	}
+	if s.cooldown == 0 {
+		s.cooldown = h.cfg.Cooldown
+	} else {
+		s.cooldown *= 2
+	}
+	if h.cfg.MaxCooldown > 0 && s.cooldown > h.cfg.MaxCooldown {
+		s.cooldown = h.cfg.MaxCooldown
+	}
+	s.skipUntil = t.Add(s.cooldown)
+	return s.skipUntil
+}
@@ -0,0 +1,135 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package nodes
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+)
+
+type ErrCircuitBreaker struct {
+	Pair      provider.Pair
+	Price     float64
+	Reference float64
+	Deviation float64
+}
+
+func (e ErrCircuitBreaker) Error() string {
+	return fmt.Sprintf(
+		"the price %f for the %s pair deviates by %.2f%% from the last accepted price %f",
+		e.Price,
+		e.Pair,
+		e.Deviation,
+		e.Reference,
+	)
+}
+
+// CircuitBreakerNode protects against extreme price moves, e.g. when one of
+// the origins was compromised and reports an invalid price.
+//
+//  [CircuitBreakerNode] ---- [AggregatorNode A/B] ---- ...
+//
+// The price of the child node is compared with the last accepted price. If
+// the price deviates by more than maxDeviation percent and the last accepted
+// price is not older than the window, the ErrCircuitBreaker error is returned
+// along with the price, so it will not be relayed. The circuit breaker resets
+// automatically when the price moves back within bounds, or when the last
+// accepted price becomes older than the window.
+type CircuitBreakerNode struct {
+	mu sync.Mutex
+
+	pair         provider.Pair
+	maxDeviation float64
+	window       time.Duration
+	children     []Node
+	accepted     *PairPrice
+}
+
+// NewCircuitBreakerNode creates a new CircuitBreakerNode instance. The
+// maxDeviation is the maximum allowed price move in percent.
+func NewCircuitBreakerNode(pair provider.Pair, maxDeviation float64, window time.Duration) *CircuitBreakerNode {
+	return &CircuitBreakerNode{
+		pair:         pair,
+		maxDeviation: maxDeviation,
+		window:       window,
+	}
+}
+
+// Children implements the Node interface.
+func (n *CircuitBreakerNode) Children() []Node {
+	return n.children
+}
+
+// AddChild implements the Parent interface. Only the first Aggregator child
+// is used to calculate the price.
+func (n *CircuitBreakerNode) AddChild(node Node) {
+	n.children = append(n.children, node)
+}
+
+func (n *CircuitBreakerNode) Pair() provider.Pair {
+	return n.pair
+}
+
+func (n *CircuitBreakerNode) Price() AggregatorPrice {
+	var price AggregatorPrice
+	for _, c := range n.children {
+		if a, ok := c.(Aggregator); ok {
+			price = a.Price()
+			break
+		}
+	}
+
+	res := AggregatorPrice{
+		PairPrice:        price.PairPrice,
+		AggregatorPrices: []AggregatorPrice{price},
+		Parameters: map[string]string{
+			"method":       "circuitBreaker",
+			"maxDeviation": strconv.FormatFloat(n.maxDeviation, 'f', -1, 64),
+			"window":       n.window.String(),
+		},
+		Error: price.Error,
+	}
+	if res.Error != nil {
+		return res
+	}
+	if !res.Pair.Equal(n.pair) {
+		res.Error = ErrResolve{ExpectedPair: n.pair, ResolvedPair: res.Pair}
+		return res
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.accepted != nil && n.accepted.Price > 0 && res.Time.Sub(n.accepted.Time) <= n.window {
+		deviation := math.Abs(res.Price-n.accepted.Price) / n.accepted.Price * 100
+		if deviation > n.maxDeviation {
+			res.Error = ErrCircuitBreaker{
+				Pair:      n.pair,
+				Price:     res.Price,
+				Reference: n.accepted.Price,
+				Deviation: deviation,
+			}
+			return res
+		}
+	}
+	accepted := res.PairPrice
+	n.accepted = &accepted
+	return res
+}
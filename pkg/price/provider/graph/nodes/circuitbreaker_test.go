@@ -0,0 +1,85 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package nodes
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+)
+
+func TestCircuitBreakerNode_Price(t *testing.T) {
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	n := time.Now()
+
+	o := NewOriginNode(OriginPair{Pair: ab, Origin: "a"}, time.Hour, time.Hour)
+	m := NewMedianAggregatorNode(ab, 1, 0)
+	m.AddChild(o)
+	cb := NewCircuitBreakerNode(ab, 50, 10*time.Minute)
+	cb.AddChild(m)
+
+	tests := []struct {
+		price float64
+		time  time.Time
+		err   bool
+	}{
+		{price: 100, time: n.Add(-30 * time.Minute)}, // First price is always accepted.
+		{price: 120, time: n.Add(-29 * time.Minute)}, // Within bounds.
+		{price: 1200, time: n.Add(-28 * time.Minute), err: true},
+		{price: 1200, time: n.Add(-27 * time.Minute), err: true},
+		{price: 125, time: n.Add(-26 * time.Minute)}, // Back within bounds.
+		{price: 10, time: n.Add(-25 * time.Minute), err: true},
+		{price: 10, time: n.Add(-10 * time.Minute)}, // The accepted price is older than the window.
+	}
+	for i, tt := range tests {
+		require.NoError(t, o.Ingest(OriginPrice{
+			PairPrice: PairPrice{Pair: ab, Price: tt.price, Bid: tt.price, Ask: tt.price, Time: tt.time},
+			Origin:    "a",
+		}))
+		price := cb.Price()
+		assert.Equal(t, tt.price, price.Price, "test %d", i)
+		assert.Equal(t, "circuitBreaker", price.Parameters["method"], "test %d", i)
+		assert.Len(t, price.AggregatorPrices, 1, "test %d", i)
+		if tt.err {
+			assert.True(t, errors.As(price.Error, &ErrCircuitBreaker{}), "test %d", i)
+		} else {
+			assert.NoError(t, price.Error, "test %d", i)
+		}
+	}
+}
+
+func TestCircuitBreakerNode_Price_ChildError(t *testing.T) {
+	ab := provider.Pair{Base: "A", Quote: "B"}
+
+	o := NewOriginNode(OriginPair{Pair: ab, Origin: "a"}, testTTL, testTTL)
+	m := NewMedianAggregatorNode(ab, 1, 0)
+	m.AddChild(o)
+	cb := NewCircuitBreakerNode(ab, 50, time.Minute)
+	cb.AddChild(m)
+
+	require.NoError(t, o.Ingest(OriginPrice{
+		PairPrice: PairPrice{Pair: ab, Price: 100, Time: time.Now()},
+		Origin:    "a",
+		Error:     errors.New("something"),
+	}))
+
+	assert.Error(t, cb.Price().Error)
+}
@@ -0,0 +1,134 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package nodes
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+)
+
+type ErrNoValidSource struct {
+	Pair  provider.Pair
+	Given int
+}
+
+func (e ErrNoValidSource) Error() string {
+	return fmt.Sprintf(
+		"none of %d sources returned a valid price for the %s pair",
+		e.Given,
+		e.Pair,
+	)
+}
+
+// FallbackAggregatorNode returns the price of the first child node that
+// provides a valid price, in the order in which child nodes were added.
+//
+//                             -- [Origin A/B] (primary)
+//                            /
+//  [FallbackAggregatorNode] ---- [Origin A/B] (first fallback)
+//                            \
+//                             -- [AggregatorNode A/B] (second fallback)
+//
+// A price is valid if it was returned without an error, e.g. its TTL has not
+// expired, and for the same pair as the node. Errors of skipped sources are
+// returned as warnings. The ErrNoValidSource error is returned only if none
+// of the children provides a valid price.
+//
+// With the strict or all-or-nothing error policy, the price fails if any of
+// the preceding sources returned an error.
+type FallbackAggregatorNode struct {
+	pair     provider.Pair
+	policy   ErrorPolicy
+	children []Node
+}
+
+func NewFallbackAggregatorNode(pair provider.Pair) *FallbackAggregatorNode {
+	return &FallbackAggregatorNode{
+		pair: pair,
+	}
+}
+
+// Children implements the Node interface.
+func (n *FallbackAggregatorNode) Children() []Node {
+	return n.children
+}
+
+// AddChild implements the Parent interface.
+func (n *FallbackAggregatorNode) AddChild(node Node) {
+	n.children = append(n.children, node)
+}
+
+// SetErrorPolicy implements the ErrorPropagation interface.
+func (n *FallbackAggregatorNode) SetErrorPolicy(policy ErrorPolicy) {
+	n.policy = policy
+}
+
+func (n *FallbackAggregatorNode) Pair() provider.Pair {
+	return n.pair
+}
+
+func (n *FallbackAggregatorNode) Price() AggregatorPrice {
+	var originPrices []OriginPrice
+	var aggregatorPrices []AggregatorPrice
+	var warns error
+
+	for _, c := range n.children {
+		var name string
+		var price PairPrice
+		var err error
+		switch typedNode := c.(type) {
+		case Origin:
+			originPrice := typedNode.Price()
+			originPrices = append(originPrices, originPrice)
+			name = originPrice.Origin
+			price = originPrice.PairPrice
+			err = originPrice.Error
+		case Aggregator:
+			aggregatorPrice := typedNode.Price()
+			aggregatorPrices = append(aggregatorPrices, aggregatorPrice)
+			name = aggregatorPrice.Parameters["method"] + ":" + aggregatorPrice.Pair.String()
+			price = aggregatorPrice.PairPrice
+			err = aggregatorPrice.Error
+		default:
+			continue
+		}
+		if err == nil && !n.pair.Equal(price.Pair) {
+			err = ErrIncompatiblePairs{Given: price.Pair, Expected: n.pair}
+		}
+		if err != nil {
+			warns = multierror.Append(warns, ErrSourceFailed{Source: name, Err: err})
+			continue
+		}
+		return n.policy.apply(AggregatorPrice{
+			PairPrice:        price,
+			OriginPrices:     originPrices,
+			AggregatorPrices: aggregatorPrices,
+			Parameters:       map[string]string{"method": "fallback", "selectedSource": name},
+			Warnings:         warns,
+		})
+	}
+
+	return AggregatorPrice{
+		PairPrice:        PairPrice{Pair: n.pair},
+		OriginPrices:     originPrices,
+		AggregatorPrices: aggregatorPrices,
+		Parameters:       map[string]string{"method": "fallback"},
+		Error:            multierror.Append(ErrNoValidSource{Pair: n.pair, Given: len(n.children)}, warns),
+	}
+}
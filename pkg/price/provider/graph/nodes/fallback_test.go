@@ -0,0 +1,106 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package nodes
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+)
+
+func TestFallbackAggregatorNode_Price(t *testing.T) {
+	defer func() { timeNow = time.Now }()
+
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	n := time.Now()
+	timeNow = func() time.Time { return n }
+
+	primary := NewOriginNode(OriginPair{Pair: ab, Origin: "a"}, time.Minute, time.Minute)
+	secondary := NewOriginNode(OriginPair{Pair: ab, Origin: "b"}, time.Hour, time.Hour)
+	f := NewFallbackAggregatorNode(ab)
+	f.AddChild(primary)
+	f.AddChild(secondary)
+
+	require.NoError(t, primary.Ingest(OriginPrice{PairPrice: PairPrice{Pair: ab, Price: 10, Time: n}, Origin: "a"}))
+	require.NoError(t, secondary.Ingest(OriginPrice{PairPrice: PairPrice{Pair: ab, Price: 20, Time: n}, Origin: "b"}))
+
+	// The primary source is used:
+	price := f.Price()
+	assert.NoError(t, price.Error)
+	assert.NoError(t, price.Warnings)
+	assert.Equal(t, float64(10), price.Price)
+	assert.Equal(t, "fallback", price.Parameters["method"])
+	assert.Equal(t, "a", price.Parameters["selectedSource"])
+
+	// The primary source is stale, so the secondary one is used:
+	n = n.Add(2 * time.Minute)
+	price = f.Price()
+	assert.NoError(t, price.Error)
+	var merr *multierror.Error
+	require.True(t, errors.As(price.Warnings, &merr))
+	require.Len(t, merr.Errors, 1)
+	assert.Equal(t, "a", merr.Errors[0].(ErrSourceFailed).Source)
+	assert.True(t, errors.As(merr.Errors[0].(ErrSourceFailed).Err, &ErrPriceTTLExpired{}))
+	assert.Equal(t, float64(20), price.Price)
+	assert.Equal(t, "b", price.Parameters["selectedSource"])
+
+	// The primary source is fresh again:
+	require.NoError(t, primary.Ingest(OriginPrice{PairPrice: PairPrice{Pair: ab, Price: 11, Time: n}, Origin: "a"}))
+	price = f.Price()
+	assert.NoError(t, price.Error)
+	assert.Equal(t, float64(11), price.Price)
+	assert.Equal(t, "a", price.Parameters["selectedSource"])
+
+	// All sources are stale:
+	n = n.Add(2 * time.Hour)
+	price = f.Price()
+	require.True(t, errors.As(price.Error, &merr))
+	require.Len(t, merr.Errors, 3)
+	assert.True(t, errors.As(merr.Errors[0], &ErrNoValidSource{}))
+	assert.Equal(t, "a", merr.Errors[1].(ErrSourceFailed).Source)
+	assert.Equal(t, "b", merr.Errors[2].(ErrSourceFailed).Source)
+	assert.Equal(t, float64(0), price.Price)
+	assert.Len(t, price.OriginPrices, 2)
+}
+
+func TestFallbackAggregatorNode_Price_IncompatiblePair(t *testing.T) {
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	cd := provider.Pair{Base: "C", Quote: "D"}
+	n := time.Now()
+
+	o1 := NewOriginNode(OriginPair{Pair: cd, Origin: "a"}, time.Hour, time.Hour)
+	o2 := NewOriginNode(OriginPair{Pair: ab, Origin: "b"}, time.Hour, time.Hour)
+	f := NewFallbackAggregatorNode(ab)
+	f.AddChild(o1)
+	f.AddChild(o2)
+
+	require.NoError(t, o1.Ingest(OriginPrice{PairPrice: PairPrice{Pair: cd, Price: 10, Time: n}, Origin: "a"}))
+	require.NoError(t, o2.Ingest(OriginPrice{PairPrice: PairPrice{Pair: ab, Price: 20, Time: n}, Origin: "b"}))
+
+	price := f.Price()
+	assert.NoError(t, price.Error)
+	var merr *multierror.Error
+	require.True(t, errors.As(price.Warnings, &merr))
+	require.Len(t, merr.Errors, 1)
+	assert.True(t, errors.As(merr.Errors[0].(ErrSourceFailed).Err, &ErrIncompatiblePairs{}))
+	assert.Equal(t, float64(20), price.Price)
+}
@@ -17,6 +17,7 @@ package nodes
 
 import (
 	"fmt"
+	"math/big"
 
 	"github.com/hashicorp/go-multierror"
 
@@ -100,8 +101,16 @@ func (e ErrDivByZero) Error() string {
 // For above node, cross rate for the A/D pair will be calculated. It is important
 // to add child nodes in the correct order, because prices will be calculated from
 // first to last.
+//
+// If exact arithmetic is enabled, the cross rate is also calculated as
+// a rational number and returned in the ExactPrice field.
+//
+// Errors of child prices always fail the cross rate. With the all-or-nothing
+// error policy, warnings of child prices also fail it.
 type IndirectAggregatorNode struct {
 	pair     provider.Pair
+	exact    bool
+	policy   ErrorPolicy
 	children []Node
 }
 
@@ -121,6 +130,16 @@ func (n *IndirectAggregatorNode) AddChild(node Node) {
 	n.children = append(n.children, node)
 }
 
+// SetExactArithmetic implements the ExactArithmetic interface.
+func (n *IndirectAggregatorNode) SetExactArithmetic(enabled bool) {
+	n.exact = enabled
+}
+
+// SetErrorPolicy implements the ErrorPropagation interface.
+func (n *IndirectAggregatorNode) SetErrorPolicy(policy ErrorPolicy) {
+	n.policy = policy
+}
+
 func (n *IndirectAggregatorNode) Pair() provider.Pair {
 	return n.pair
 }
@@ -167,10 +186,14 @@ func (n *IndirectAggregatorNode) Price() AggregatorPrice {
 		}
 	}
 
-	indirectPrice, e := crossRate(prices)
+	indirectPrice, e := crossRate(prices, n.exact)
 	if e != nil {
 		err = multierror.Append(err, e)
 	}
+	if n.exact && indirectPrice.ExactPrice == nil {
+		// A single price is returned as is by the crossRate function.
+		indirectPrice.ExactPrice = indirectPrice.exactPrice()
+	}
 
 	if !indirectPrice.Pair.Equal(n.pair) {
 		err = multierror.Append(
@@ -191,21 +214,41 @@ func (n *IndirectAggregatorNode) Price() AggregatorPrice {
 		)
 	}
 
-	return AggregatorPrice{
+	return n.policy.apply(AggregatorPrice{
 		PairPrice:        indirectPrice,
 		OriginPrices:     originPrices,
 		AggregatorPrices: aggregatorPrices,
 		Parameters:       map[string]string{"method": "indirect"},
 		Error:            err,
+	})
+}
+
+// IndirectPair returns the pair to which the IndirectAggregatorNode will
+// resolve a price for the given list of pairs. Pairs order is important because
+// the cross rate is calculated from first to last. An error is returned if any
+// two adjacent pairs have no common part.
+func IndirectPair(pairs ...provider.Pair) (provider.Pair, error) {
+	if len(pairs) == 0 {
+		return provider.Pair{}, nil
+	}
+	prices := make([]PairPrice, len(pairs))
+	for i, p := range pairs {
+		prices[i] = PairPrice{Pair: p, Price: 1, Bid: 1, Ask: 1}
 	}
+	price, err := crossRate(prices, false)
+	if err != nil {
+		return provider.Pair{}, err
+	}
+	return price.Pair, nil
 }
 
 // crossRate returns a calculated price from the list of prices. Prices order
-// is important because prices are calculated from first to last.
+// is important because prices are calculated from first to last. If exact is
+// true, the price is also calculated as a rational number.
 //
 // TODO: Decide what to do with division by zero during calculating Bid/Ask prices.
 //nolint:gocyclo,funlen
-func crossRate(t []PairPrice) (PairPrice, error) {
+func crossRate(t []PairPrice, exact bool) (PairPrice, error) {
 	var err error
 
 	if len(t) == 0 {
@@ -218,6 +261,7 @@ func crossRate(t []PairPrice) (PairPrice, error) {
 
 		var pair provider.Pair
 		var price, bid, ask float64
+		var exactPrice *big.Rat
 		switch {
 		case a.Pair.Quote == b.Pair.Quote: // A/C, B/C
 			pair.Base = a.Pair.Base
@@ -225,6 +269,9 @@ func crossRate(t []PairPrice) (PairPrice, error) {
 
 			if b.Price > 0 {
 				price = a.Price / b.Price
+				if exact {
+					exactPrice = new(big.Rat).Quo(a.exactPrice(), b.exactPrice())
+				}
 			} else {
 				err = multierror.Append(err, ErrDivByZero{a.Pair, b.Pair})
 				price = 0
@@ -247,6 +294,9 @@ func crossRate(t []PairPrice) (PairPrice, error) {
 
 			if a.Price > 0 {
 				price = b.Price / a.Price
+				if exact {
+					exactPrice = new(big.Rat).Quo(b.exactPrice(), a.exactPrice())
+				}
 			} else {
 				err = multierror.Append(err, ErrDivByZero{a.Pair, b.Pair})
 				price = 0
@@ -267,6 +317,9 @@ func crossRate(t []PairPrice) (PairPrice, error) {
 			pair.Base = a.Pair.Base
 			pair.Quote = b.Pair.Quote
 			price = a.Price * b.Price
+			if exact {
+				exactPrice = new(big.Rat).Mul(a.exactPrice(), b.exactPrice())
+			}
 			bid = a.Bid * b.Bid
 			ask = a.Ask * b.Ask
 		case a.Pair.Base == b.Pair.Quote: // C/A, B/C -> A/B
@@ -275,6 +328,9 @@ func crossRate(t []PairPrice) (PairPrice, error) {
 
 			if a.Price > 0 && b.Price > 0 {
 				price = (float64(1) / b.Price) / a.Price
+				if exact {
+					exactPrice = new(big.Rat).Inv(new(big.Rat).Mul(a.exactPrice(), b.exactPrice()))
+				}
 			} else {
 				err = multierror.Append(err, ErrDivByZero{a.Pair, b.Pair})
 				price = 0
@@ -299,6 +355,11 @@ func crossRate(t []PairPrice) (PairPrice, error) {
 
 		b.Pair = pair
 		b.Price = price
+		b.ExactPrice = nil
+		if exactPrice != nil {
+			b.ExactPrice = exactPrice
+			b.Price, _ = exactPrice.Float64()
+		}
 		b.Bid = bid
 		b.Ask = ask
 		b.Volume24h = 0
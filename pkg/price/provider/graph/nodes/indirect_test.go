@@ -17,6 +17,7 @@ package nodes
 
 import (
 	"errors"
+	"math/big"
 	"testing"
 	"time"
 
@@ -428,6 +429,74 @@ func TestIndirectAggregatorNode_Price_DivByZero(t *testing.T) {
 	assert.True(t, errors.As(m.Price().Error, &ErrDivByZero{}))
 }
 
+func TestIndirectAggregatorNode_Price_ExactArithmetic(t *testing.T) {
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	bc := provider.Pair{Base: "B", Quote: "C"}
+	ac := provider.Pair{Base: "A", Quote: "C"}
+	n := time.Now()
+
+	for _, exact := range []bool{false, true} {
+		m := NewIndirectAggregatorNode(ac)
+		m.SetExactArithmetic(exact)
+
+		c1 := NewOriginNode(OriginPair{Pair: ab, Origin: "a"}, testTTL, testTTL)
+		c2 := NewOriginNode(OriginPair{Pair: bc, Origin: "b"}, testTTL, testTTL)
+		_ = c1.Ingest(OriginPrice{PairPrice: PairPrice{Pair: ab, Price: 0.1, Time: n}, Origin: "a"})
+		_ = c2.Ingest(OriginPrice{PairPrice: PairPrice{Pair: bc, Price: 3, Time: n}, Origin: "b"})
+		m.AddChild(c1)
+		m.AddChild(c2)
+
+		price := m.Price()
+		assert.NoError(t, price.Error)
+		if exact {
+			assert.Equal(t, big.NewRat(3, 10), price.ExactPrice)
+			assert.Equal(t, 0.3, price.Price)
+		} else {
+			// The float64 arithmetic introduces a rounding error:
+			assert.Nil(t, price.ExactPrice)
+			assert.Equal(t, 0.30000000000000004, price.Price)
+		}
+	}
+}
+
+func Test_crossRate_Exact(t *testing.T) {
+	tests := []struct {
+		name   string
+		prices []PairPrice
+		want   *big.Rat
+	}{
+		{
+			name:   "A/C,B/C",
+			prices: []PairPrice{{Pair: provider.Pair{Base: "A", Quote: "C"}, Price: 0.3}, {Pair: provider.Pair{Base: "B", Quote: "C"}, Price: 0.1}},
+			want:   big.NewRat(3, 1),
+		},
+		{
+			name:   "C/A,C/B",
+			prices: []PairPrice{{Pair: provider.Pair{Base: "C", Quote: "A"}, Price: 0.1}, {Pair: provider.Pair{Base: "C", Quote: "B"}, Price: 0.3}},
+			want:   big.NewRat(3, 1),
+		},
+		{
+			name:   "A/C,C/B",
+			prices: []PairPrice{{Pair: provider.Pair{Base: "A", Quote: "C"}, Price: 0.1}, {Pair: provider.Pair{Base: "C", Quote: "B"}, Price: 0.2}},
+			want:   big.NewRat(1, 50),
+		},
+		{
+			name:   "C/A,B/C",
+			prices: []PairPrice{{Pair: provider.Pair{Base: "C", Quote: "A"}, Price: 0.1}, {Pair: provider.Pair{Base: "B", Quote: "C"}, Price: 0.2}},
+			want:   big.NewRat(50, 1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := crossRate(tt.prices, true)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.ExactPrice)
+			f, _ := tt.want.Float64()
+			assert.Equal(t, f, got.Price)
+		})
+	}
+}
+
 func Test_crossRate(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -780,7 +849,7 @@ func Test_crossRate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := crossRate(tt.prices)
+			got, err := crossRate(tt.prices, false)
 
 			if err != nil {
 				if tt.wantErr {
@@ -798,3 +867,55 @@ func Test_crossRate(t *testing.T) {
 		})
 	}
 }
+
+func TestIndirectPair(t *testing.T) {
+	tests := []struct {
+		name    string
+		pairs   []provider.Pair
+		want    provider.Pair
+		wantErr bool
+	}{
+		{
+			name:  "no-pairs",
+			pairs: nil,
+			want:  provider.Pair{},
+		},
+		{
+			name:  "one-pair",
+			pairs: []provider.Pair{{Base: "A", Quote: "B"}},
+			want:  provider.Pair{Base: "A", Quote: "B"},
+		},
+		{
+			name:  "A/C,C/B", // A/C * C/B
+			pairs: []provider.Pair{{Base: "A", Quote: "C"}, {Base: "C", Quote: "B"}},
+			want:  provider.Pair{Base: "A", Quote: "B"},
+		},
+		{
+			name:  "A/C,B/C", // A/C / B/C
+			pairs: []provider.Pair{{Base: "A", Quote: "C"}, {Base: "B", Quote: "C"}},
+			want:  provider.Pair{Base: "A", Quote: "B"},
+		},
+		{
+			name:  "C/A,C/B", // C/B / C/A
+			pairs: []provider.Pair{{Base: "C", Quote: "A"}, {Base: "C", Quote: "B"}},
+			want:  provider.Pair{Base: "A", Quote: "B"},
+		},
+		{
+			name:    "no-common-part",
+			pairs:   []provider.Pair{{Base: "A", Quote: "B"}, {Base: "C", Quote: "D"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IndirectPair(tt.pairs...)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
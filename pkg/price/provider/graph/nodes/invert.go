@@ -0,0 +1,106 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package nodes
+
+import (
+	"math/big"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+)
+
+// InvertAggregatorNode returns the reciprocal of the price of its child
+// node. It is used to provide prices for inverted pairs of price models,
+// e.g. the B/A price from the A/B price model.
+//
+//  [InvertAggregatorNode B/A] ---- [AggregatorNode A/B] ---- ...
+//
+// Bid and ask prices are swapped, because the bid price of the inverted
+// pair is the reciprocal of the ask price of the original pair.
+type InvertAggregatorNode struct {
+	pair     provider.Pair
+	children []Node
+}
+
+// NewInvertAggregatorNode creates a new InvertAggregatorNode instance for
+// the given pair. The child node must provide a price for the inverted pair.
+func NewInvertAggregatorNode(pair provider.Pair) *InvertAggregatorNode {
+	return &InvertAggregatorNode{
+		pair: pair,
+	}
+}
+
+// Children implements the Node interface.
+func (n *InvertAggregatorNode) Children() []Node {
+	return n.children
+}
+
+// AddChild implements the Parent interface. Only the first Aggregator child
+// is used to calculate the price.
+func (n *InvertAggregatorNode) AddChild(node Node) {
+	n.children = append(n.children, node)
+}
+
+func (n *InvertAggregatorNode) Pair() provider.Pair {
+	return n.pair
+}
+
+func (n *InvertAggregatorNode) Price() AggregatorPrice {
+	var price AggregatorPrice
+	for _, c := range n.children {
+		if a, ok := c.(Aggregator); ok {
+			price = a.Price()
+			break
+		}
+	}
+
+	res := AggregatorPrice{
+		PairPrice: PairPrice{
+			Pair:      n.pair,
+			Price:     reciprocal(price.Price),
+			Bid:       reciprocal(price.Ask),
+			Ask:       reciprocal(price.Bid),
+			Volume24h: 0,
+			Time:      price.Time,
+		},
+		AggregatorPrices: []AggregatorPrice{price},
+		Parameters:       map[string]string{"method": "invert"},
+		Error:            price.Error,
+		Warnings:         price.Warnings,
+	}
+	if price.ExactPrice != nil && price.ExactPrice.Sign() > 0 {
+		res.ExactPrice = new(big.Rat).Inv(price.ExactPrice)
+		res.Price, _ = res.ExactPrice.Float64()
+	}
+	if res.Error != nil {
+		return res
+	}
+	if !price.Pair.Equal(n.pair.Inverse()) {
+		res.Error = ErrResolve{ExpectedPair: n.pair.Inverse(), ResolvedPair: price.Pair}
+		return res
+	}
+	if res.Price <= 0 {
+		res.Error = ErrInvalidPrice{Pair: n.pair}
+	}
+	return res
+}
+
+// reciprocal returns 1/x, or zero if x is zero or less.
+func reciprocal(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return 1 / x
+}
@@ -0,0 +1,84 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package nodes
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+)
+
+func TestInvertAggregatorNode_Price(t *testing.T) {
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	n := time.Now()
+
+	o := NewOriginNode(OriginPair{Pair: ab, Origin: "a"}, time.Hour, time.Hour)
+	m := NewMedianAggregatorNode(ab, 1, 0)
+	m.AddChild(o)
+	i := NewInvertAggregatorNode(ab.Inverse())
+	i.AddChild(m)
+
+	require.NoError(t, o.Ingest(OriginPrice{
+		PairPrice: PairPrice{Pair: ab, Price: 4, Bid: 2, Ask: 5, Volume24h: 10, Time: n},
+		Origin:    "a",
+	}))
+
+	price := i.Price()
+	assert.NoError(t, price.Error)
+	assert.Equal(t, ab.Inverse(), price.Pair)
+	assert.Equal(t, 0.25, price.Price)
+	assert.Equal(t, 0.2, price.Bid)
+	assert.Equal(t, 0.5, price.Ask)
+	assert.Equal(t, float64(0), price.Volume24h)
+	assert.Equal(t, n, price.Time)
+	assert.Equal(t, "invert", price.Parameters["method"])
+	assert.Len(t, price.AggregatorPrices, 1)
+	assert.Nil(t, price.ExactPrice)
+
+	// Exact prices are inverted too:
+	m.SetExactArithmetic(true)
+	price = i.Price()
+	assert.Equal(t, big.NewRat(1, 4), price.ExactPrice)
+	assert.Equal(t, 0.25, price.Price)
+}
+
+func TestInvertAggregatorNode_Price_Errors(t *testing.T) {
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	n := time.Now()
+
+	o := NewOriginNode(OriginPair{Pair: ab, Origin: "a"}, time.Hour, time.Hour)
+	m := NewMedianAggregatorNode(ab, 1, 0)
+	m.AddChild(o)
+
+	// Wrong pair:
+	i := NewInvertAggregatorNode(provider.Pair{Base: "C", Quote: "A"})
+	i.AddChild(m)
+	require.NoError(t, o.Ingest(OriginPrice{PairPrice: PairPrice{Pair: ab, Price: 4, Time: n}, Origin: "a"}))
+	assert.True(t, errors.As(i.Price().Error, &ErrResolve{}))
+
+	// Zero price:
+	i = NewInvertAggregatorNode(ab.Inverse())
+	i.AddChild(m)
+	require.NoError(t, o.Ingest(OriginPrice{PairPrice: PairPrice{Pair: ab, Price: 0, Time: n}, Origin: "a"}))
+	assert.Error(t, i.Price().Error)
+	assert.Equal(t, float64(0), i.Price().Price)
+}
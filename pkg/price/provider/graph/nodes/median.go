@@ -17,8 +17,10 @@ package nodes
 
 import (
 	"fmt"
+	"math/big"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -39,6 +41,15 @@ func (e ErrNotEnoughSources) Error() string {
 	)
 }
 
+type ErrSourceFailed struct {
+	Source string
+	Err    error
+}
+
+func (e ErrSourceFailed) Error() string {
+	return fmt.Sprintf("the %s source failed: %s", e.Source, e.Err.Error())
+}
+
 type ErrIncompatiblePairs struct {
 	Given    provider.Pair
 	Expected provider.Pair
@@ -64,16 +75,39 @@ func (e ErrIncompatiblePairs) Error() string {
 //                                                 -- ...
 //
 // All children of this node must return a Price for the same pair.
+//
+// At least minSources child prices must be fetched successfully, otherwise
+// the ErrNotEnoughSources error is returned along with the price. Failures
+// of other sources do not affect the price, they are returned as warnings. If
+// maxSources is greater than zero, then only the first maxSources successful
+// prices, in the order in which child nodes were added, are used to calculate
+// the median.
+//
+// If exact arithmetic is enabled, the median price is also calculated as
+// a rational number and returned in the ExactPrice field.
+//
+// The error policy may be changed to fail the median price if any of the
+// sources fails, even if there are enough remaining prices.
+//
+// If the outlier threshold and the outlier hook are set, the hook is called
+// for every source used to calculate the median whose price deviates from
+// the median by more than the threshold, in percent. Each source price is
+// reported only once, even if the median is read many times.
 type MedianAggregatorNode struct {
 	pair       provider.Pair
 	minSources int
+	maxSources int
+	exact      bool
+	policy     ErrorPolicy
+	outliers   outlierDetector
 	children   []Node
 }
 
-func NewMedianAggregatorNode(pair provider.Pair, minSources int) *MedianAggregatorNode {
+func NewMedianAggregatorNode(pair provider.Pair, minSources, maxSources int) *MedianAggregatorNode {
 	return &MedianAggregatorNode{
 		pair:       pair,
 		minSources: minSources,
+		maxSources: maxSources,
 	}
 }
 
@@ -87,6 +121,27 @@ func (n *MedianAggregatorNode) AddChild(node Node) {
 	n.children = append(n.children, node)
 }
 
+// SetExactArithmetic implements the ExactArithmetic interface.
+func (n *MedianAggregatorNode) SetExactArithmetic(enabled bool) {
+	n.exact = enabled
+}
+
+// SetErrorPolicy implements the ErrorPropagation interface.
+func (n *MedianAggregatorNode) SetErrorPolicy(policy ErrorPolicy) {
+	n.policy = policy
+}
+
+// SetOutlierThreshold sets the deviation from the median price, in percent,
+// above which sources are reported to the outlier hook.
+func (n *MedianAggregatorNode) SetOutlierThreshold(threshold float64) {
+	n.outliers.threshold = threshold
+}
+
+// SetOutlierHook implements the OutlierDetection interface.
+func (n *MedianAggregatorNode) SetOutlierHook(hook OutlierHook) {
+	n.outliers.hook = hook
+}
+
 func (n *MedianAggregatorNode) Pair() provider.Pair {
 	return n.pair
 }
@@ -94,28 +149,38 @@ func (n *MedianAggregatorNode) Pair() provider.Pair {
 func (n *MedianAggregatorNode) Price() AggregatorPrice {
 	var ts time.Time
 	var prices, bids, asks []float64
+	var exactPrices []*big.Rat
+	var sourcePrices []sourcePrice
 	var originPrices []OriginPrice
 	var aggregatorPrices []AggregatorPrice
-	var err error
+	var err, warns error
 
+	var included, excluded []string
 	for i, c := range n.children {
 		// There is no need to copy errors from prices to the MedianAggregatorNode
 		// because there may be enough remaining prices to calculate median price.
 
+		var name string
 		var price PairPrice
 		switch typedNode := c.(type) {
 		case Origin:
 			originPrice := typedNode.Price()
 			originPrices = append(originPrices, originPrice)
+			name = originPrice.Origin
 			price = originPrice.PairPrice
 			if originPrice.Error != nil {
+				warns = multierror.Append(warns, ErrSourceFailed{Source: name, Err: originPrice.Error})
+				excluded = append(excluded, name+" (error)")
 				continue
 			}
 		case Aggregator:
 			aggregatorPrice := typedNode.Price()
 			aggregatorPrices = append(aggregatorPrices, aggregatorPrice)
+			name = aggregatorPrice.Parameters["method"] + ":" + aggregatorPrice.Pair.String()
 			price = aggregatorPrice.PairPrice
 			if aggregatorPrice.Error != nil {
+				warns = multierror.Append(warns, ErrSourceFailed{Source: name, Err: aggregatorPrice.Error})
+				excluded = append(excluded, name+" (error)")
 				continue
 			}
 		}
@@ -125,11 +190,21 @@ func (n *MedianAggregatorNode) Price() AggregatorPrice {
 				err,
 				ErrIncompatiblePairs{Given: price.Pair, Expected: n.pair},
 			)
+			excluded = append(excluded, name+" (incompatible pair)")
+			continue
+		}
+
+		if n.maxSources > 0 && len(prices) >= n.maxSources {
+			excluded = append(excluded, name+" (maximum sources reached)")
 			continue
 		}
 
 		if price.Price > 0 {
 			prices = append(prices, price.Price)
+			sourcePrices = append(sourcePrices, sourcePrice{source: name, price: price.Price, time: price.Time})
+			if n.exact {
+				exactPrices = append(exactPrices, price.exactPrice())
+			}
 		}
 		if price.Bid > 0 {
 			bids = append(bids, price.Bid)
@@ -140,6 +215,7 @@ func (n *MedianAggregatorNode) Price() AggregatorPrice {
 		if i == 0 || price.Time.Before(ts) {
 			ts = price.Time
 		}
+		included = append(included, name)
 	}
 
 	if len(prices) < n.minSources {
@@ -149,20 +225,39 @@ func (n *MedianAggregatorNode) Price() AggregatorPrice {
 		)
 	}
 
-	return AggregatorPrice{
-		PairPrice: PairPrice{
-			Pair:      n.pair,
-			Price:     median(prices),
-			Bid:       median(bids),
-			Ask:       median(asks),
-			Volume24h: 0,
-			Time:      ts,
-		},
+	params := map[string]string{"method": "median", "minimumSuccessfulSources": strconv.Itoa(n.minSources)}
+	if n.maxSources > 0 {
+		params["maximumSuccessfulSources"] = strconv.Itoa(n.maxSources)
+	}
+	if len(included) > 0 {
+		params["includedSources"] = strings.Join(included, ", ")
+	}
+	if len(excluded) > 0 {
+		params["excludedSources"] = strings.Join(excluded, ", ")
+	}
+
+	price := PairPrice{
+		Pair:      n.pair,
+		Price:     median(prices),
+		Bid:       median(bids),
+		Ask:       median(asks),
+		Volume24h: 0,
+		Time:      ts,
+	}
+	if n.exact {
+		price.ExactPrice = medianRat(exactPrices)
+		price.Price, _ = price.ExactPrice.Float64()
+	}
+	n.outliers.detect(n.pair, price.Price, sourcePrices)
+
+	return n.policy.apply(AggregatorPrice{
+		PairPrice:        price,
 		OriginPrices:     originPrices,
 		AggregatorPrices: aggregatorPrices,
-		Parameters:       map[string]string{"method": "median", "minimumSuccessfulSources": strconv.Itoa(n.minSources)},
+		Parameters:       params,
 		Error:            err,
-	}
+		Warnings:         warns,
+	})
 }
 
 func median(xs []float64) float64 {
@@ -181,3 +276,19 @@ func median(xs []float64) float64 {
 
 	return xs[(count-1)/2]
 }
+
+func medianRat(xs []*big.Rat) *big.Rat {
+	count := len(xs)
+	if count == 0 {
+		return new(big.Rat)
+	}
+
+	sort.Slice(xs, func(i, j int) bool { return xs[i].Cmp(xs[j]) < 0 })
+	if count%2 == 0 {
+		m := count / 2
+		x := new(big.Rat).Add(xs[m-1], xs[m])
+		return x.Quo(x, big.NewRat(2, 1))
+	}
+
+	return new(big.Rat).Set(xs[(count-1)/2])
+}
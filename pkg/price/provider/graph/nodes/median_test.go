@@ -17,10 +17,13 @@ package nodes
 
 import (
 	"errors"
+	"math/big"
 	"testing"
 	"time"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
 )
@@ -28,7 +31,7 @@ import (
 const medianTestTTL = 10 * time.Second
 
 func TestMedianAggregatorNode_Children(t *testing.T) {
-	m := NewMedianAggregatorNode(provider.Pair{Base: "A", Quote: "B"}, 3)
+	m := NewMedianAggregatorNode(provider.Pair{Base: "A", Quote: "B"}, 3, 0)
 
 	c1 := NewOriginNode(OriginPair{Pair: provider.Pair{Base: "A", Quote: "B"}, Origin: "a"}, medianTestTTL, medianTestTTL)
 	c2 := NewOriginNode(OriginPair{Pair: provider.Pair{Base: "A", Quote: "B"}, Origin: "b"}, medianTestTTL, medianTestTTL)
@@ -46,7 +49,7 @@ func TestMedianAggregatorNode_Children(t *testing.T) {
 
 func TestMedianAggregatorNode_Pair(t *testing.T) {
 	p := provider.Pair{Base: "A", Quote: "B"}
-	m := NewMedianAggregatorNode(p, 3)
+	m := NewMedianAggregatorNode(p, 3, 0)
 
 	assert.Equal(t, m.Pair(), p)
 }
@@ -54,7 +57,7 @@ func TestMedianAggregatorNode_Pair(t *testing.T) {
 func TestMedianAggregatorNode_Price_ThreeOriginPrices(t *testing.T) {
 	p := provider.Pair{Base: "A", Quote: "B"}
 	n := time.Now()
-	m := NewMedianAggregatorNode(p, 3)
+	m := NewMedianAggregatorNode(p, 3, 0)
 
 	c1 := NewOriginNode(OriginPair{Pair: p, Origin: "a"}, medianTestTTL, medianTestTTL)
 	c2 := NewOriginNode(OriginPair{Pair: p, Origin: "b"}, medianTestTTL, medianTestTTL)
@@ -114,8 +117,12 @@ func TestMedianAggregatorNode_Price_ThreeOriginPrices(t *testing.T) {
 		},
 		OriginPrices:     []OriginPrice{c1.Price(), c2.Price(), c3.Price()},
 		AggregatorPrices: nil,
-		Parameters:       map[string]string{"method": "median", "minimumSuccessfulSources": "3"},
-		Error:            nil,
+		Parameters: map[string]string{
+			"method":                   "median",
+			"minimumSuccessfulSources": "3",
+			"includedSources":          "a, b, c",
+		},
+		Error: nil,
 	}
 
 	assert.Equal(t, expected, m.Price())
@@ -124,19 +131,19 @@ func TestMedianAggregatorNode_Price_ThreeOriginPrices(t *testing.T) {
 func TestMedianAggregatorNode_Price_ThreeAggregatorPrices(t *testing.T) {
 	p := provider.Pair{Base: "A", Quote: "B"}
 	n := time.Now()
-	m := NewMedianAggregatorNode(p, 3)
+	m := NewMedianAggregatorNode(p, 3, 0)
 
 	c1 := NewOriginNode(OriginPair{Pair: p, Origin: "a"}, medianTestTTL, medianTestTTL)
 	c2 := NewOriginNode(OriginPair{Pair: p, Origin: "b"}, medianTestTTL, medianTestTTL)
 	c3 := NewOriginNode(OriginPair{Pair: p, Origin: "c"}, medianTestTTL, medianTestTTL)
 
-	i1 := NewMedianAggregatorNode(p, 1)
+	i1 := NewMedianAggregatorNode(p, 1, 0)
 	i1.AddChild(c1)
 
-	i2 := NewMedianAggregatorNode(p, 1)
+	i2 := NewMedianAggregatorNode(p, 1, 0)
 	i2.AddChild(c2)
 
-	i3 := NewMedianAggregatorNode(p, 1)
+	i3 := NewMedianAggregatorNode(p, 1, 0)
 	i3.AddChild(c3)
 
 	_ = c1.Ingest(OriginPrice{
@@ -204,8 +211,12 @@ func TestMedianAggregatorNode_Price_ThreeAggregatorPrices(t *testing.T) {
 				},
 				OriginPrices:     []OriginPrice{c1.Price()},
 				AggregatorPrices: nil,
-				Parameters:       map[string]string{"method": "median", "minimumSuccessfulSources": "1"},
-				Error:            nil,
+				Parameters: map[string]string{
+					"method":                   "median",
+					"minimumSuccessfulSources": "1",
+					"includedSources":          "a",
+				},
+				Error: nil,
 			},
 			{
 				PairPrice: PairPrice{
@@ -218,8 +229,12 @@ func TestMedianAggregatorNode_Price_ThreeAggregatorPrices(t *testing.T) {
 				},
 				OriginPrices:     []OriginPrice{c2.Price()},
 				AggregatorPrices: nil,
-				Parameters:       map[string]string{"method": "median", "minimumSuccessfulSources": "1"},
-				Error:            nil,
+				Parameters: map[string]string{
+					"method":                   "median",
+					"minimumSuccessfulSources": "1",
+					"includedSources":          "b",
+				},
+				Error: nil,
 			},
 			{
 				PairPrice: PairPrice{
@@ -232,12 +247,20 @@ func TestMedianAggregatorNode_Price_ThreeAggregatorPrices(t *testing.T) {
 				},
 				OriginPrices:     []OriginPrice{c3.Price()},
 				AggregatorPrices: nil,
-				Parameters:       map[string]string{"method": "median", "minimumSuccessfulSources": "1"},
-				Error:            nil,
+				Parameters: map[string]string{
+					"method":                   "median",
+					"minimumSuccessfulSources": "1",
+					"includedSources":          "c",
+				},
+				Error: nil,
 			},
 		},
-		Parameters: map[string]string{"method": "median", "minimumSuccessfulSources": "3"},
-		Error:      nil,
+		Parameters: map[string]string{
+			"method":                   "median",
+			"minimumSuccessfulSources": "3",
+			"includedSources":          "median:A/B, median:A/B, median:A/B",
+		},
+		Error: nil,
 	}
 
 	assert.Equal(t, expected, m.Price())
@@ -246,7 +269,7 @@ func TestMedianAggregatorNode_Price_ThreeAggregatorPrices(t *testing.T) {
 func TestMedianAggregatorNode_Price_NotEnoughSources(t *testing.T) {
 	p := provider.Pair{Base: "A", Quote: "B"}
 	n := time.Now()
-	m := NewMedianAggregatorNode(p, 3)
+	m := NewMedianAggregatorNode(p, 3, 0)
 
 	c1 := NewOriginNode(OriginPair{Pair: p, Origin: "a"}, medianTestTTL, medianTestTTL)
 
@@ -278,7 +301,7 @@ func TestMedianAggregatorNode_Price_NotEnoughSources(t *testing.T) {
 func TestMedianAggregatorNode_Price_ChildPriceWithError(t *testing.T) {
 	p := provider.Pair{Base: "A", Quote: "B"}
 	n := time.Now()
-	m := NewMedianAggregatorNode(p, 2)
+	m := NewMedianAggregatorNode(p, 2, 0)
 
 	c1 := NewOriginNode(OriginPair{Pair: p, Origin: "a"}, medianTestTTL, medianTestTTL)
 	c2 := NewOriginNode(OriginPair{Pair: p, Origin: "b"}, medianTestTTL, medianTestTTL)
@@ -320,13 +343,59 @@ func TestMedianAggregatorNode_Price_ChildPriceWithError(t *testing.T) {
 	assert.Equal(t, float64(10), price.Price)
 	assert.Equal(t, float64(10), price.Bid)
 	assert.Equal(t, float64(10), price.Ask)
+
+	// Included and excluded sources should be listed in parameters:
+	assert.Equal(t, "a", price.Parameters["includedSources"])
+	assert.Equal(t, "b (error)", price.Parameters["excludedSources"])
+}
+
+func TestMedianAggregatorNode_Price_FailedSourcesAsWarnings(t *testing.T) {
+	p := provider.Pair{Base: "A", Quote: "B"}
+	n := time.Now()
+	m := NewMedianAggregatorNode(p, 3, 0)
+
+	// Five sources, two of them fail:
+	for i, name := range []string{"a", "b", "c", "d", "e"} {
+		var err error
+		if name == "b" || name == "d" {
+			err = errors.New("exchange is down")
+		}
+		c := NewOriginNode(OriginPair{Pair: p, Origin: name}, medianTestTTL, medianTestTTL)
+		_ = c.Ingest(OriginPrice{
+			PairPrice: PairPrice{
+				Pair:  p,
+				Price: float64(10 * (i + 1)),
+				Bid:   float64(10 * (i + 1)),
+				Ask:   float64(10 * (i + 1)),
+				Time:  n,
+			},
+			Origin: name,
+			Error:  err,
+		})
+		m.AddChild(c)
+	}
+
+	price := m.Price()
+
+	// The price must be calculated without errors because minSources
+	// prices are available:
+	assert.NoError(t, price.Error)
+	assert.Equal(t, float64(30), price.Price)
+	assert.Equal(t, "a, c, e", price.Parameters["includedSources"])
+
+	// Failed sources must be reported as warnings:
+	var merr *multierror.Error
+	require.True(t, errors.As(price.Warnings, &merr))
+	require.Len(t, merr.Errors, 2)
+	assert.Equal(t, ErrSourceFailed{Source: "b", Err: errors.New("exchange is down")}, merr.Errors[0])
+	assert.Equal(t, ErrSourceFailed{Source: "d", Err: errors.New("exchange is down")}, merr.Errors[1])
 }
 
 func TestMedianAggregatorNode_Price_IncompatiblePairs(t *testing.T) {
 	p1 := provider.Pair{Base: "A", Quote: "B"}
 	p2 := provider.Pair{Base: "C", Quote: "D"}
 	n := time.Now()
-	m := NewMedianAggregatorNode(p1, 2)
+	m := NewMedianAggregatorNode(p1, 2, 0)
 
 	c1 := NewOriginNode(OriginPair{Pair: p1, Origin: "a"}, medianTestTTL, medianTestTTL)
 	c2 := NewOriginNode(OriginPair{Pair: p2, Origin: "b"}, medianTestTTL, medianTestTTL)
@@ -372,7 +441,7 @@ func TestMedianAggregatorNode_Price_IncompatiblePairs(t *testing.T) {
 
 func TestMedianAggregatorNode_Price_NoChildrenNodes(t *testing.T) {
 	p := provider.Pair{Base: "A", Quote: "B"}
-	m := NewMedianAggregatorNode(p, 2)
+	m := NewMedianAggregatorNode(p, 2, 0)
 
 	price := m.Price()
 
@@ -382,7 +451,7 @@ func TestMedianAggregatorNode_Price_NoChildrenNodes(t *testing.T) {
 func TestMedianAggregatorNode_Price_FilterOutPricesLteZero(t *testing.T) {
 	p := provider.Pair{Base: "A", Quote: "B"}
 	n := time.Now()
-	m := NewMedianAggregatorNode(p, 1)
+	m := NewMedianAggregatorNode(p, 1, 0)
 
 	c1 := NewOriginNode(OriginPair{Pair: p, Origin: "a"}, medianTestTTL, medianTestTTL)
 	c2 := NewOriginNode(OriginPair{Pair: p, Origin: "b"}, medianTestTTL, medianTestTTL)
@@ -438,6 +507,95 @@ func TestMedianAggregatorNode_Price_FilterOutPricesLteZero(t *testing.T) {
 	assert.Equal(t, float64(10), price.Ask)
 }
 
+func TestMedianAggregatorNode_Price_SourcesLimits(t *testing.T) {
+	p := provider.Pair{Base: "A", Quote: "B"}
+	n := time.Now()
+
+	tests := []struct {
+		name          string
+		minSources    int
+		maxSources    int
+		errors        []bool // which of the three children fail
+		expectedPrice float64
+		expectedErr   bool
+		excluded      string
+	}{
+		{name: "below-min", minSources: 3, errors: []bool{false, true, false}, expectedPrice: 20, expectedErr: true, excluded: "b (error)"},
+		{name: "at-min", minSources: 2, errors: []bool{false, true, false}, expectedPrice: 20, expectedErr: false, excluded: "b (error)"},
+		{name: "above-min", minSources: 2, errors: []bool{false, false, false}, expectedPrice: 20, expectedErr: false},
+		{name: "max", minSources: 1, maxSources: 2, errors: []bool{false, false, false}, expectedPrice: 15, excluded: "c (maximum sources reached)"},
+		{name: "max-skip-failed", minSources: 1, maxSources: 2, errors: []bool{true, false, false}, expectedPrice: 25, excluded: "a (error)"},
+		{name: "max-below-min", minSources: 2, maxSources: 2, errors: []bool{true, true, false}, expectedPrice: 30, expectedErr: true, excluded: "a (error), b (error)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMedianAggregatorNode(p, tt.minSources, tt.maxSources)
+			for i, origin := range []string{"a", "b", "c"} {
+				var err error
+				if tt.errors[i] {
+					err = errors.New("something")
+				}
+				c := NewOriginNode(OriginPair{Pair: p, Origin: origin}, medianTestTTL, medianTestTTL)
+				_ = c.Ingest(OriginPrice{
+					PairPrice: PairPrice{
+						Pair:  p,
+						Price: float64((i + 1) * 10),
+						Bid:   float64((i + 1) * 10),
+						Ask:   float64((i + 1) * 10),
+						Time:  n,
+					},
+					Origin: origin,
+					Error:  err,
+				})
+				m.AddChild(c)
+			}
+
+			price := m.Price()
+
+			assert.Equal(t, tt.expectedPrice, price.Price)
+			assert.Equal(t, tt.excluded, price.Parameters["excludedSources"])
+			assert.Len(t, price.OriginPrices, 3)
+			if tt.expectedErr {
+				assert.True(t, errors.As(price.Error, &ErrNotEnoughSources{}))
+			} else {
+				assert.NoError(t, price.Error)
+			}
+		})
+	}
+}
+
+func TestMedianAggregatorNode_Price_StalePrice(t *testing.T) {
+	defer func() { timeNow = time.Now }()
+
+	p := provider.Pair{Base: "A", Quote: "B"}
+	n := time.Now()
+	timeNow = func() time.Time { return n }
+
+	m := NewMedianAggregatorNode(p, 2, 0)
+	c1 := NewOriginNode(OriginPair{Pair: p, Origin: "a"}, medianTestTTL, medianTestTTL)
+	c2 := NewOriginNode(OriginPair{Pair: p, Origin: "b"}, medianTestTTL, 2*medianTestTTL)
+	m.AddChild(c1)
+	m.AddChild(c2)
+
+	_ = c1.Ingest(OriginPrice{PairPrice: PairPrice{Pair: p, Price: 10, Time: n}, Origin: "a"})
+	_ = c2.Ingest(OriginPrice{PairPrice: PairPrice{Pair: p, Price: 20, Time: n}, Origin: "b"})
+
+	// Both prices are fresh:
+	price := m.Price()
+	assert.NoError(t, price.Error)
+	assert.Equal(t, float64(15), price.Price)
+
+	// Advance the clock past the maxTTL of the first origin only, its price
+	// is now stale and must not count toward the minimum number of sources:
+	timeNow = func() time.Time { return n.Add(medianTestTTL + time.Second) }
+	price = m.Price()
+	assert.True(t, errors.As(price.Error, &ErrNotEnoughSources{}))
+	assert.True(t, errors.As(price.OriginPrices[0].Error, &ErrPriceTTLExpired{}))
+	assert.NoError(t, price.OriginPrices[1].Error)
+	assert.Equal(t, float64(20), price.Price)
+}
+
 func Test_median(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -473,3 +631,38 @@ func Test_median(t *testing.T) {
 		})
 	}
 }
+
+func TestMedianAggregatorNode_Price_ExactArithmetic(t *testing.T) {
+	p := provider.Pair{Base: "A", Quote: "B"}
+	n := time.Now()
+
+	for _, exact := range []bool{false, true} {
+		m := NewMedianAggregatorNode(p, 2, 0)
+		m.SetExactArithmetic(exact)
+
+		c1 := NewOriginNode(OriginPair{Pair: p, Origin: "a"}, medianTestTTL, medianTestTTL)
+		c2 := NewOriginNode(OriginPair{Pair: p, Origin: "b"}, medianTestTTL, medianTestTTL)
+		_ = c1.Ingest(OriginPrice{PairPrice: PairPrice{Pair: p, Price: 0.1, Time: n}, Origin: "a"})
+		_ = c2.Ingest(OriginPrice{PairPrice: PairPrice{Pair: p, Price: 0.2, Time: n}, Origin: "b"})
+		m.AddChild(c1)
+		m.AddChild(c2)
+
+		price := m.Price()
+		require.NoError(t, price.Error)
+		if exact {
+			// Decimal values reported by origins are used, so the result is exact:
+			assert.Equal(t, big.NewRat(3, 20), price.ExactPrice)
+			assert.Equal(t, 0.15, price.Price)
+		} else {
+			// The float64 arithmetic introduces a rounding error:
+			assert.Nil(t, price.ExactPrice)
+			assert.Equal(t, 0.15000000000000002, price.Price)
+		}
+	}
+}
+
+func Test_medianRat(t *testing.T) {
+	assert.Equal(t, new(big.Rat), medianRat(nil))
+	assert.Equal(t, big.NewRat(2, 1), medianRat([]*big.Rat{big.NewRat(3, 1), big.NewRat(1, 1), big.NewRat(2, 1)}))
+	assert.Equal(t, big.NewRat(5, 2), medianRat([]*big.Rat{big.NewRat(4, 1), big.NewRat(1, 1), big.NewRat(3, 1), big.NewRat(2, 1)}))
+}
@@ -27,10 +27,10 @@ func TestDetectCycles(t *testing.T) {
 	p := provider.Pair{Base: "A", Quote: "B"}
 
 	// Non cyclic graph:
-	nonCyclic := NewMedianAggregatorNode(p, 0)
+	nonCyclic := NewMedianAggregatorNode(p, 0, 0)
 	nonCyclicC1 := NewOriginNode(OriginPair{Origin: "a", Pair: p}, 0, 0)
 	nonCyclicC2 := NewOriginNode(OriginPair{Origin: "b", Pair: p}, 0, 0)
-	nonCyclicC3 := NewMedianAggregatorNode(p, 0)
+	nonCyclicC3 := NewMedianAggregatorNode(p, 0, 0)
 	nonCyclic.AddChild(nonCyclicC1)
 	nonCyclic.AddChild(nonCyclicC2)
 	nonCyclic.AddChild(nonCyclicC3)
@@ -38,10 +38,10 @@ func TestDetectCycles(t *testing.T) {
 	nonCyclicC3.AddChild(nonCyclicC2)
 
 	// Cyclic graph:
-	cyclic := NewMedianAggregatorNode(p, 0)
+	cyclic := NewMedianAggregatorNode(p, 0, 0)
 	cyclicC1 := NewOriginNode(OriginPair{Origin: "a", Pair: p}, 0, 0)
 	cyclicC2 := NewOriginNode(OriginPair{Origin: "b", Pair: p}, 0, 0)
-	cyclicC3 := NewMedianAggregatorNode(p, 0)
+	cyclicC3 := NewMedianAggregatorNode(p, 0, 0)
 	cyclic.AddChild(cyclicC1)
 	cyclic.AddChild(cyclicC2)
 	cyclic.AddChild(cyclicC3)
@@ -49,9 +49,9 @@ func TestDetectCycles(t *testing.T) {
 	cyclicC3.AddChild(cyclic)
 
 	// Graph with references to the same aggregator nodes:
-	r := NewMedianAggregatorNode(p, 0)
-	c1 := NewMedianAggregatorNode(p, 0)
-	c2 := NewMedianAggregatorNode(p, 0)
+	r := NewMedianAggregatorNode(p, 0, 0)
+	c1 := NewMedianAggregatorNode(p, 0, 0)
+	c2 := NewMedianAggregatorNode(p, 0, 0)
 	r.AddChild(c1)
 	r.AddChild(c2)
 	r.AddChild(nonCyclic)
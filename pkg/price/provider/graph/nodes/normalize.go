@@ -0,0 +1,43 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package nodes
+
+import (
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+)
+
+// NewQuoteNormalizerNode returns a node that converts prices of the given node
+// to the quote asset of the given pair. The conversion is done by multiplying
+// the node price by the conversion price, e.g. an A/USDT price is converted
+// to A/USD using the USDT/USD price.
+//
+// The conversion node is an Aggregator, so the conversion rate is taken from
+// a live price model rather than a constant.
+//
+//                                  -- [Origin A/USDT]
+//                                 /
+//  [IndirectAggregatorNode A/USD] -
+//                                 \
+//                                  -- [Aggregator USDT/USD]
+//
+// Errors from both the node and the conversion price are propagated, so the
+// normalized price is never more reliable than any of its inputs.
+func NewQuoteNormalizerNode(pair provider.Pair, node Node, conversion Aggregator) *IndirectAggregatorNode {
+	n := NewIndirectAggregatorNode(pair)
+	n.AddChild(node)
+	n.AddChild(conversion)
+	return n
+}
@@ -0,0 +1,84 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package nodes
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+)
+
+func TestQuoteNormalizerNode_Price(t *testing.T) {
+	btcusdt := provider.Pair{Base: "BTC", Quote: "USDT"}
+	usdtusd := provider.Pair{Base: "USDT", Quote: "USD"}
+	btcusd := provider.Pair{Base: "BTC", Quote: "USD"}
+	n := time.Now()
+
+	o := NewOriginNode(OriginPair{Pair: btcusdt, Origin: "a"}, testTTL, testTTL)
+	c := NewOriginNode(OriginPair{Pair: usdtusd, Origin: "b"}, testTTL, testTTL)
+	conv := NewMedianAggregatorNode(usdtusd, 1, 0)
+	conv.AddChild(c)
+
+	_ = o.Ingest(OriginPrice{
+		PairPrice: PairPrice{Pair: btcusdt, Price: 20000, Bid: 19990, Ask: 20010, Time: n},
+		Origin:    "a",
+	})
+	_ = c.Ingest(OriginPrice{
+		PairPrice: PairPrice{Pair: usdtusd, Price: 0.99, Bid: 0.99, Ask: 0.99, Time: n},
+		Origin:    "b",
+	})
+
+	m := NewQuoteNormalizerNode(btcusd, o, conv)
+	price := m.Price()
+
+	assert.NoError(t, price.Error)
+	assert.Equal(t, btcusd, price.Pair)
+	assert.InDelta(t, 19800, price.Price, 1e-9)
+	assert.InDelta(t, 19790.1, price.Bid, 1e-9)
+	assert.InDelta(t, 19809.9, price.Ask, 1e-9)
+	assert.Len(t, price.OriginPrices, 1)
+	assert.Len(t, price.AggregatorPrices, 1)
+}
+
+func TestQuoteNormalizerNode_Price_ConversionError(t *testing.T) {
+	btcusdt := provider.Pair{Base: "BTC", Quote: "USDT"}
+	usdtusd := provider.Pair{Base: "USDT", Quote: "USD"}
+	btcusd := provider.Pair{Base: "BTC", Quote: "USD"}
+	n := time.Now()
+
+	o := NewOriginNode(OriginPair{Pair: btcusdt, Origin: "a"}, testTTL, testTTL)
+	c := NewOriginNode(OriginPair{Pair: usdtusd, Origin: "b"}, testTTL, testTTL)
+	conv := NewMedianAggregatorNode(usdtusd, 1, 0)
+	conv.AddChild(c)
+
+	_ = o.Ingest(OriginPrice{
+		PairPrice: PairPrice{Pair: btcusdt, Price: 20000, Time: n},
+		Origin:    "a",
+	})
+	_ = c.Ingest(OriginPrice{
+		PairPrice: PairPrice{Pair: usdtusd, Price: 1, Time: n},
+		Origin:    "b",
+		Error:     errors.New("something"),
+	})
+
+	price := NewQuoteNormalizerNode(btcusd, o, conv).Price()
+
+	assert.Error(t, price.Error)
+}
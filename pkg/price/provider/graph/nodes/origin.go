@@ -25,6 +25,10 @@ import (
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
 )
 
+// timeNow is used to check if the price has expired. It can be replaced in
+// tests to simulate the passage of time.
+var timeNow = time.Now
+
 type ErrIncompatiblePair struct {
 	Given    provider.Pair
 	Expected provider.Pair
@@ -153,5 +157,5 @@ func (n *OriginNode) Children() []Node {
 }
 
 func (n *OriginNode) expired() bool {
-	return n.price.Time.Before(time.Now().Add(-1 * n.MaxTTL()))
+	return n.price.Time.Before(timeNow().Add(-1 * n.MaxTTL()))
 }
@@ -0,0 +1,135 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package nodes
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+)
+
+// Outlier describes a source whose price deviates from the aggregated price
+// by more than the configured threshold.
+type Outlier struct {
+	Pair      provider.Pair
+	Source    string
+	Price     float64
+	Aggregate float64
+	Deviation float64 // in percent
+}
+
+// OutlierHook is called by aggregators for every outlier found while
+// calculating a price.
+type OutlierHook interface {
+	Outlier(o Outlier)
+}
+
+// OutlierDetection is implemented by aggregators which can report sources
+// which deviate from the aggregated price.
+type OutlierDetection interface {
+	SetOutlierHook(hook OutlierHook)
+}
+
+// OutlierHooks calls all hooks in the list.
+type OutlierHooks []OutlierHook
+
+// Outlier implements the OutlierHook interface.
+func (h OutlierHooks) Outlier(o Outlier) {
+	for _, hook := range h {
+		hook.Outlier(o)
+	}
+}
+
+// OutlierMetricMessage is the log message used by the LoggerOutlierHook.
+// It can be used to define a Grafana metric.
+const OutlierMetricMessage = "Price outlier"
+
+// LoggerOutlierHook logs a warning for every outlier. Metrics are extracted
+// from logs by the Grafana logger, so the OutlierMetricMessage message and
+// the "pair", "source" and "deviation" fields can be used to define
+// a metric.
+type LoggerOutlierHook struct {
+	log log.Logger
+}
+
+func NewLoggerOutlierHook(logger log.Logger) *LoggerOutlierHook {
+	return &LoggerOutlierHook{log: logger}
+}
+
+// Outlier implements the OutlierHook interface.
+func (h *LoggerOutlierHook) Outlier(o Outlier) {
+	h.log.
+		WithFields(log.Fields{
+			"pair":      o.Pair.String(),
+			"source":    o.Source,
+			"price":     o.Price,
+			"aggregate": o.Aggregate,
+			"deviation": o.Deviation,
+		}).
+		Warn(OutlierMetricMessage)
+}
+
+// sourcePrice is a price used by an aggregator to calculate its price.
+type sourcePrice struct {
+	source string
+	price  float64
+	time   time.Time
+}
+
+// outlierDetector reports sources which deviate from the aggregated price
+// by more than threshold percent. It is disabled if the threshold or
+// the hook is not set.
+//
+// Aggregated prices are calculated every time they are read, so every
+// source price is reported only once, the first time it is used.
+type outlierDetector struct {
+	threshold float64
+	hook      OutlierHook
+
+	mu       sync.Mutex
+	reported map[string]time.Time
+}
+
+func (d *outlierDetector) detect(pair provider.Pair, aggregate float64, prices []sourcePrice) {
+	if d.threshold <= 0 || d.hook == nil || aggregate <= 0 {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.reported == nil {
+		d.reported = map[string]time.Time{}
+	}
+	for _, p := range prices {
+		deviation := math.Abs(p.price-aggregate) / aggregate * 100
+		if deviation <= d.threshold {
+			continue
+		}
+		if t, ok := d.reported[p.source]; ok && t.Equal(p.time) {
+			continue
+		}
+		d.reported[p.source] = p.time
+		d.hook.Outlier(Outlier{
+			Pair:      pair,
+			Source:    p.source,
+			Price:     p.price,
+			Aggregate: aggregate,
+			Deviation: deviation,
+		})
+	}
+}
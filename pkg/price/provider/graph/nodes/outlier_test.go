@@ -0,0 +1,126 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package nodes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/callback"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+)
+
+type outlierRecorder []Outlier
+
+func (r *outlierRecorder) Outlier(o Outlier) {
+	*r = append(*r, o)
+}
+
+func newOutlierTestMedian(threshold float64, hook OutlierHook) *MedianAggregatorNode {
+	p := provider.Pair{Base: "A", Quote: "B"}
+	n := time.Now()
+	m := NewMedianAggregatorNode(p, 1, 0)
+	m.AddChild(newTestOriginNode(p, "a", 9.9, n, nil))
+	m.AddChild(newTestOriginNode(p, "b", 10, n, nil))
+	m.AddChild(newTestOriginNode(p, "c", 10.1, n, nil))
+	m.AddChild(newTestOriginNode(p, "d", 15, n, nil))
+	m.SetOutlierThreshold(threshold)
+	m.SetOutlierHook(hook)
+	return m
+}
+
+func TestMedianAggregatorNode_Outlier(t *testing.T) {
+	var rec outlierRecorder
+	m := newOutlierTestMedian(10, &rec)
+
+	price := m.Price()
+	require.NoError(t, price.Error)
+	assert.Equal(t, 10.05, price.Price)
+
+	// Only the "d" source deviates by more than 10%:
+	require.Len(t, rec, 1)
+	assert.Equal(t, provider.Pair{Base: "A", Quote: "B"}, rec[0].Pair)
+	assert.Equal(t, "d", rec[0].Source)
+	assert.Equal(t, 15.0, rec[0].Price)
+	assert.Equal(t, 10.05, rec[0].Aggregate)
+	assert.InDelta(t, 49.25, rec[0].Deviation, 0.01)
+}
+
+func TestMedianAggregatorNode_Outlier_Disabled(t *testing.T) {
+	var rec outlierRecorder
+
+	// The threshold is not set:
+	m := newOutlierTestMedian(0, &rec)
+	m.Price()
+	assert.Empty(t, rec)
+
+	// All sources are within the threshold:
+	m = newOutlierTestMedian(50, &rec)
+	m.Price()
+	assert.Empty(t, rec)
+
+	// The hook is not set:
+	m = newOutlierTestMedian(10, nil)
+	assert.NotPanics(t, func() { m.Price() })
+}
+
+func TestMedianAggregatorNode_Outlier_ReportedOnce(t *testing.T) {
+	var rec outlierRecorder
+	m := newOutlierTestMedian(10, &rec)
+
+	// Reading the price again must not report the same outlier again:
+	m.Price()
+	m.Price()
+	require.Len(t, rec, 1)
+
+	// A new price from the source is reported:
+	p := provider.Pair{Base: "A", Quote: "B"}
+	for _, c := range m.Children() {
+		if o := c.(*OriginNode); o.OriginPair().Origin == "d" {
+			require.NoError(t, o.Ingest(OriginPrice{
+				PairPrice: PairPrice{Pair: p, Price: 16, Time: time.Now().Add(time.Second)},
+				Origin:    "d",
+			}))
+		}
+	}
+	m.Price()
+	require.Len(t, rec, 2)
+	assert.Equal(t, 16.0, rec[1].Price)
+}
+
+func TestLoggerOutlierHook(t *testing.T) {
+	var msgs []string
+	var fields []log.Fields
+	l := callback.New(log.Debug, func(_ log.Level, f log.Fields, msg string) {
+		msgs = append(msgs, msg)
+		fields = append(fields, f)
+	})
+
+	m := newOutlierTestMedian(10, OutlierHooks{NewLoggerOutlierHook(l)})
+	m.Price()
+
+	require.Len(t, msgs, 1)
+	assert.Equal(t, OutlierMetricMessage, msgs[0])
+	assert.Equal(t, "A/B", fields[0]["pair"])
+	assert.Equal(t, "d", fields[0]["source"])
+	assert.Equal(t, 15.0, fields[0]["price"])
+	assert.Equal(t, 10.05, fields[0]["aggregate"])
+	assert.InDelta(t, 49.25, fields[0]["deviation"], 0.01)
+}
@@ -0,0 +1,102 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package nodes
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// ErrorPolicy defines how errors of child nodes are propagated to the price
+// calculated by an aggregator.
+type ErrorPolicy string
+
+const (
+	// ErrorPolicyLenient ignores errors of child nodes as long as the
+	// aggregator is able to calculate a price from the remaining ones,
+	// e.g. the median aggregator has at least minSources prices. Errors
+	// of ignored children are returned as warnings. This is the default
+	// policy.
+	ErrorPolicyLenient ErrorPolicy = "lenient"
+	// ErrorPolicyStrict fails the aggregated price if any of the child
+	// nodes returns a price with an error.
+	ErrorPolicyStrict ErrorPolicy = "strict"
+	// ErrorPolicyAllOrNothing fails the aggregated price if any of the child
+	// nodes returns a price with an error or with warnings, so the price
+	// is returned only if every source down the graph was fetched
+	// successfully.
+	ErrorPolicyAllOrNothing ErrorPolicy = "all-or-nothing"
+)
+
+// ErrorPropagation is implemented by aggregators which support the
+// configurable error propagation policy.
+type ErrorPropagation interface {
+	SetErrorPolicy(policy ErrorPolicy)
+}
+
+type ErrErrorPolicy struct {
+	Policy ErrorPolicy
+	Failed int
+}
+
+func (e ErrErrorPolicy) Error() string {
+	return fmt.Sprintf(
+		"the %s error policy does not allow failed sources, but %d sources failed",
+		e.Policy,
+		e.Failed,
+	)
+}
+
+// ParseErrorPolicy parses the name of the error policy. An empty name is
+// parsed as the ErrorPolicyLenient policy.
+func ParseErrorPolicy(name string) (ErrorPolicy, error) {
+	switch ErrorPolicy(name) {
+	case "", ErrorPolicyLenient:
+		return ErrorPolicyLenient, nil
+	case ErrorPolicyStrict:
+		return ErrorPolicyStrict, nil
+	case ErrorPolicyAllOrNothing:
+		return ErrorPolicyAllOrNothing, nil
+	}
+	return "", fmt.Errorf("unknown error policy: %s", name)
+}
+
+// apply checks prices of child nodes included in the given price and adds
+// the ErrErrorPolicy error to it if they are not allowed by the policy.
+func (p ErrorPolicy) apply(price AggregatorPrice) AggregatorPrice {
+	if p != ErrorPolicyStrict && p != ErrorPolicyAllOrNothing {
+		return price
+	}
+	failed := 0
+	for _, originPrice := range price.OriginPrices {
+		if originPrice.Error != nil {
+			failed++
+		}
+	}
+	for _, aggregatorPrice := range price.AggregatorPrices {
+		if aggregatorPrice.Error != nil || (p == ErrorPolicyAllOrNothing && aggregatorPrice.Warnings != nil) {
+			failed++
+		}
+	}
+	if price.Parameters != nil {
+		price.Parameters["errorPolicy"] = string(p)
+	}
+	if failed > 0 {
+		price.Error = multierror.Append(price.Error, ErrErrorPolicy{Policy: p, Failed: failed})
+	}
+	return price
+}
@@ -0,0 +1,165 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package nodes
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+)
+
+func TestParseErrorPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    ErrorPolicy
+		wantErr bool
+	}{
+		{name: "", want: ErrorPolicyLenient},
+		{name: "lenient", want: ErrorPolicyLenient},
+		{name: "strict", want: ErrorPolicyStrict},
+		{name: "all-or-nothing", want: ErrorPolicyAllOrNothing},
+		{name: "foo", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseErrorPolicy(tt.name)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestErrorPolicy_MedianAggregatorNode(t *testing.T) {
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	n := time.Now()
+
+	// The first child is a median with one failed source, so it returns
+	// a price with warnings. The last child returns an error.
+	m := NewMedianAggregatorNode(ab, 1, 0)
+	m1 := newTestOriginNode(ab, "a", 10, n, nil)
+	m2 := newTestOriginNode(ab, "b", 0, n, errors.New("exchange is down"))
+	m.AddChild(m1)
+	m.AddChild(m2)
+
+	o1 := newTestOriginNode(ab, "c", 10, n, nil)
+	o2 := newTestOriginNode(ab, "d", 10, n, errors.New("exchange is down"))
+
+	tests := []struct {
+		policy   ErrorPolicy
+		children []Node
+		wantErr  bool
+	}{
+		{policy: ErrorPolicyLenient, children: []Node{m, o1}, wantErr: false},
+		{policy: ErrorPolicyLenient, children: []Node{m, o1, o2}, wantErr: false},
+		{policy: ErrorPolicyStrict, children: []Node{m, o1}, wantErr: false},
+		{policy: ErrorPolicyStrict, children: []Node{m, o1, o2}, wantErr: true},
+		{policy: ErrorPolicyAllOrNothing, children: []Node{m1, o1}, wantErr: false},
+		{policy: ErrorPolicyAllOrNothing, children: []Node{m, o1}, wantErr: true},
+		{policy: ErrorPolicyAllOrNothing, children: []Node{m, o1, o2}, wantErr: true},
+	}
+	for i, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			node := NewMedianAggregatorNode(ab, 1, 0)
+			node.SetErrorPolicy(tt.policy)
+			for _, c := range tt.children {
+				node.AddChild(c)
+			}
+			price := node.Price()
+			assert.Equal(t, float64(10), price.Price)
+			if tt.wantErr {
+				assert.True(t, errors.As(price.Error, &ErrErrorPolicy{}), "case %d", i)
+			} else {
+				assert.NoError(t, price.Error, "case %d", i)
+			}
+		})
+	}
+}
+
+func TestErrorPolicy_IndirectAggregatorNode(t *testing.T) {
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	bc := provider.Pair{Base: "B", Quote: "C"}
+	ac := provider.Pair{Base: "A", Quote: "C"}
+	n := time.Now()
+
+	m := NewMedianAggregatorNode(ab, 1, 0)
+	m.AddChild(newTestOriginNode(ab, "a", 10, n, nil))
+	m.AddChild(newTestOriginNode(ab, "b", 0, n, errors.New("exchange is down")))
+
+	// Errors of children always fail the indirect price:
+	for _, policy := range []ErrorPolicy{ErrorPolicyLenient, ErrorPolicyStrict, ErrorPolicyAllOrNothing} {
+		node := NewIndirectAggregatorNode(ac)
+		node.SetErrorPolicy(policy)
+		node.AddChild(newTestOriginNode(ab, "a", 10, n, nil))
+		node.AddChild(newTestOriginNode(bc, "b", 0, n, errors.New("exchange is down")))
+		assert.Error(t, node.Price().Error)
+	}
+
+	// Warnings of children fail the indirect price only with the
+	// all-or-nothing policy:
+	for _, policy := range []ErrorPolicy{ErrorPolicyLenient, ErrorPolicyStrict, ErrorPolicyAllOrNothing} {
+		node := NewIndirectAggregatorNode(ac)
+		node.SetErrorPolicy(policy)
+		node.AddChild(m)
+		node.AddChild(newTestOriginNode(bc, "c", 2, n, nil))
+		price := node.Price()
+		assert.Equal(t, float64(20), price.Price)
+		if policy == ErrorPolicyAllOrNothing {
+			assert.True(t, errors.As(price.Error, &ErrErrorPolicy{}))
+		} else {
+			assert.NoError(t, price.Error)
+		}
+	}
+}
+
+func TestErrorPolicy_FallbackAggregatorNode(t *testing.T) {
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	n := time.Now()
+
+	for _, policy := range []ErrorPolicy{ErrorPolicyLenient, ErrorPolicyStrict, ErrorPolicyAllOrNothing} {
+		node := NewFallbackAggregatorNode(ab)
+		node.SetErrorPolicy(policy)
+		node.AddChild(newTestOriginNode(ab, "a", 0, n, errors.New("exchange is down")))
+		node.AddChild(newTestOriginNode(ab, "b", 20, n, nil))
+		price := node.Price()
+		assert.Equal(t, float64(20), price.Price)
+		if policy == ErrorPolicyLenient {
+			assert.NoError(t, price.Error)
+			assert.Empty(t, price.Parameters["errorPolicy"])
+		} else {
+			assert.True(t, errors.As(price.Error, &ErrErrorPolicy{}))
+			assert.Equal(t, string(policy), price.Parameters["errorPolicy"])
+		}
+	}
+}
+
+func newTestOriginNode(pair provider.Pair, origin string, price float64, ts time.Time, err error) *OriginNode {
+	n := NewOriginNode(OriginPair{Pair: pair, Origin: origin}, time.Hour, time.Hour)
+	_ = n.Ingest(OriginPrice{
+		PairPrice: PairPrice{Pair: pair, Price: price, Time: ts},
+		Origin:    origin,
+		Error:     err,
+	})
+	return n
+}
@@ -17,6 +17,8 @@ package nodes
 
 import (
 	"fmt"
+	"math/big"
+	"strconv"
 	"time"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
@@ -32,12 +34,39 @@ func (o OriginPair) String() string {
 }
 
 type PairPrice struct {
-	Pair      provider.Pair
-	Price     float64
-	Bid       float64
-	Ask       float64
-	Volume24h float64
-	Time      time.Time
+	Pair  provider.Pair
+	Price float64
+	// ExactPrice is the price calculated with arbitrary precision. It is set
+	// only by aggregators with exact arithmetic enabled. If set, Price is
+	// the closest float64 value.
+	ExactPrice *big.Rat
+	Bid        float64
+	Ask        float64
+	Volume24h  float64
+	Time       time.Time
+}
+
+// exactPrice returns the price as a rational number. If ExactPrice is not
+// set, the shortest decimal representation of Price is used instead of its
+// exact binary value. For prices parsed from decimal strings returned by
+// origins, this is the value reported by the origin.
+func (p PairPrice) exactPrice() *big.Rat {
+	if p.ExactPrice != nil {
+		return p.ExactPrice
+	}
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(p.Price, 'g', -1, 64))
+	if !ok {
+		// Only possible for NaN and infinities.
+		return new(big.Rat)
+	}
+	return r
+}
+
+// ExactArithmetic is implemented by aggregators that may calculate prices
+// using arbitrary precision arithmetic instead of float64. This avoids
+// accumulating rounding errors across multiple aggregation steps.
+type ExactArithmetic interface {
+	SetExactArithmetic(enabled bool)
 }
 
 // OriginPrice represent a price which was sourced directly from an origin.
@@ -64,4 +93,8 @@ type AggregatorPrice struct {
 	// fetching Price. If this list is not empty, then the price value
 	// is not reliable.
 	Error error
+	// Warnings is a list of optional errors that did not prevent the
+	// aggregator from calculating a reliable price, e.g. failures of some
+	// sources when enough other sources were available.
+	Warnings error
 }
@@ -37,8 +37,9 @@ func (e ErrPairNotFound) Error() string {
 // Provider implements the provider.Provider interface. It uses a graph
 // structure to calculate pairs prices.
 type Provider struct {
-	graphs map[provider.Pair]nodes.Aggregator
-	feeder *feeder.Feeder
+	graphs  map[provider.Pair]nodes.Aggregator
+	feeder  *feeder.Feeder
+	aliases provider.Aliases
 }
 
 // NewProvider returns a new Provider instance. If the GetByFeeder is not nil,
@@ -48,6 +49,15 @@ func NewProvider(graph map[provider.Pair]nodes.Aggregator, feeder *feeder.Feeder
 	return &Provider{graphs: graph, feeder: feeder}
 }
 
+// SetAliases sets asset aliases that are resolved to canonical symbols
+// before a price model for a pair is looked up, so the price for XBT/USD
+// is calculated using the BTC/USD model if XBT is an alias of BTC. Prices
+// and models are returned for canonical pairs. It must not be called
+// concurrently with other methods.
+func (g *Provider) SetAliases(aliases provider.Aliases) {
+	g.aliases = aliases
+}
+
 // Models implements the provider.Provider interface.
 func (g *Provider) Models(pairs ...provider.Pair) (map[provider.Pair]*provider.Model, error) {
 	ns, err := g.findNodes(pairs...)
@@ -65,7 +75,7 @@ func (g *Provider) Models(pairs ...provider.Pair) (map[provider.Pair]*provider.M
 
 // Price implements the provider.Provider interface.
 func (g *Provider) Price(pair provider.Pair) (*provider.Price, error) {
-	n, ok := g.graphs[pair]
+	n, ok := g.node(pair)
 	if !ok {
 		return nil, ErrPairNotFound{Pair: pair}
 	}
@@ -112,7 +122,7 @@ func (g *Provider) findNodes(pairs ...provider.Pair) ([]nodes.Node, error) {
 		}
 	} else { // Return for given pairs:
 		for _, p := range pairs {
-			n, ok := g.graphs[p]
+			n, ok := g.node(p)
 			if !ok {
 				return nil, ErrPairNotFound{Pair: p}
 			}
@@ -122,6 +132,22 @@ func (g *Provider) findNodes(pairs ...provider.Pair) ([]nodes.Node, error) {
 	return ns, nil
 }
 
+// node returns the root node for the given pair. If there is no price model
+// for the pair, but there is one for the inverted pair, a node that returns
+// the reciprocal of its price is returned. Aliases are resolved first.
+func (g *Provider) node(pair provider.Pair) (nodes.Aggregator, bool) {
+	pair = g.aliases.Resolve(pair)
+	if n, ok := g.graphs[pair]; ok {
+		return n, true
+	}
+	if n, ok := g.graphs[pair.Inverse()]; ok {
+		i := nodes.NewInvertAggregatorNode(pair)
+		i.AddChild(n)
+		return i, true
+	}
+	return nil, false
+}
+
 func mapGraphNodes(n nodes.Node) *provider.Model {
 	gn := &provider.Model{
 		Type:       strings.TrimLeft(reflect.TypeOf(n).String(), "*"),
@@ -135,10 +161,22 @@ func mapGraphNodes(n nodes.Node) *provider.Model {
 	case *nodes.MedianAggregatorNode:
 		gn.Type = "median"
 		gn.Pair = typedNode.Pair()
+	case *nodes.CircuitBreakerNode:
+		gn.Type = "circuitBreaker"
+		gn.Pair = typedNode.Pair()
+	case *nodes.InvertAggregatorNode:
+		gn.Type = "invert"
+		gn.Pair = typedNode.Pair()
+	case *nodes.FallbackAggregatorNode:
+		gn.Type = "fallback"
+		gn.Pair = typedNode.Pair()
 	case *nodes.OriginNode:
 		gn.Type = "origin"
 		gn.Pair = typedNode.OriginPair().Pair
 		gn.Parameters["origin"] = typedNode.OriginPair().Origin
+	case nodes.Aggregator:
+		// Custom aggregators are described by their type name.
+		gn.Pair = typedNode.Pair()
 	default:
 		panic("unsupported node")
 	}
@@ -160,6 +198,7 @@ func mapGraphPrice(t interface{}) *provider.Price {
 		gt.Type = "aggregator"
 		gt.Pair = typedPrice.Pair
 		gt.Price = typedPrice.Price
+		gt.ExactPrice = typedPrice.ExactPrice
 		gt.Bid = typedPrice.Bid
 		gt.Ask = typedPrice.Ask
 		gt.Volume24h = typedPrice.Volume24h
@@ -167,6 +206,9 @@ func mapGraphPrice(t interface{}) *provider.Price {
 		if typedPrice.Error != nil {
 			gt.Error = typedPrice.Error.Error()
 		}
+		if typedPrice.Warnings != nil {
+			gt.Warning = typedPrice.Warnings.Error()
+		}
 		gt.Parameters = typedPrice.Parameters
 		for _, ct := range typedPrice.OriginPrices {
 			gt.Prices = append(gt.Prices, mapGraphPrice(ct))
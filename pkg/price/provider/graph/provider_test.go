@@ -97,6 +97,7 @@ var (
 			Parameters: map[string]string{
 				"method":                   "median",
 				"minimumSuccessfulSources": "0",
+				"includedSources":          "a, median:A/B",
 			},
 			Pair:      provider.Pair{Base: "A", Quote: "B"},
 			Price:     10,
@@ -122,6 +123,7 @@ var (
 					Parameters: map[string]string{
 						"method":                   "median",
 						"minimumSuccessfulSources": "0",
+						"includedSources":          "a, b",
 					},
 					Pair:      provider.Pair{Base: "A", Quote: "B"},
 					Price:     10,
@@ -163,6 +165,7 @@ var (
 			Parameters: map[string]string{
 				"method":                   "median",
 				"minimumSuccessfulSources": "0",
+				"includedSources":          "x, y",
 			},
 			Pair:      provider.Pair{Base: "X", Quote: "Y"},
 			Price:     10,
@@ -225,16 +228,16 @@ func init() {
 	xy := testPairs["X/Y"]
 	exp := 3600 * time.Second
 
-	abGraph := nodes.NewMedianAggregatorNode(ab, 0)
+	abGraph := nodes.NewMedianAggregatorNode(ab, 0, 0)
 	abc1 := nodes.NewOriginNode(nodes.OriginPair{Origin: "a", Pair: ab}, exp, exp)
 	abc2 := nodes.NewOriginNode(nodes.OriginPair{Origin: "b", Pair: ab}, exp, exp)
-	abc3 := nodes.NewMedianAggregatorNode(ab, 0)
+	abc3 := nodes.NewMedianAggregatorNode(ab, 0, 0)
 	abGraph.AddChild(abc1)
 	abGraph.AddChild(abc3)
 	abc3.AddChild(abc1)
 	abc3.AddChild(abc2)
 
-	xyGraph := nodes.NewMedianAggregatorNode(xy, 0)
+	xyGraph := nodes.NewMedianAggregatorNode(xy, 0, 0)
 	xyc1 := nodes.NewOriginNode(nodes.OriginPair{Origin: "x", Pair: xy}, exp, exp)
 	xyc2 := nodes.NewOriginNode(nodes.OriginPair{Origin: "y", Pair: xy}, exp, exp)
 	xyGraph.AddChild(xyc1)
@@ -323,3 +326,66 @@ func TestGofer_Prices_MissingPair(t *testing.T) {
 
 	assert.True(t, errors.As(err, &ErrPairNotFound{}))
 }
+
+func TestGofer_Price_InvertedPair(t *testing.T) {
+	g := NewProvider(testGraph, testFeeder)
+	ba := testPairs["A/B"].Inverse()
+
+	r, err := g.Price(ba)
+	assert.NoError(t, err)
+	assert.Equal(t, "aggregator", r.Type)
+	assert.Equal(t, "invert", r.Parameters["method"])
+	assert.Equal(t, ba, r.Pair)
+	assert.Equal(t, 0.1, r.Price)
+	assert.Equal(t, float64(1)/11, r.Bid) // Bid and ask are swapped.
+	assert.Equal(t, float64(1)/9, r.Ask)
+	assert.Empty(t, r.Error)
+	assert.Equal(t, []*provider.Price{testPrices["A/B"]}, r.Prices)
+
+	rs, err := g.Prices(ba)
+	assert.NoError(t, err)
+	assert.Equal(t, map[provider.Pair]*provider.Price{ba: r}, rs)
+
+	ms, err := g.Models(ba)
+	assert.NoError(t, err)
+	assert.Equal(t, "invert", ms[ba].Type)
+	assert.Equal(t, []*provider.Model{testModels["A/B"]}, ms[ba].Models)
+}
+
+func TestGofer_Price_InvertedPairWithDirectModel(t *testing.T) {
+	ab := testPairs["A/B"]
+	ba := ab.Inverse()
+	exp := 3600 * time.Second
+
+	baGraph := nodes.NewMedianAggregatorNode(ba, 0, 0)
+	baGraph.AddChild(nodes.NewOriginNode(nodes.OriginPair{Origin: "b", Pair: ba}, exp, exp))
+
+	g := NewProvider(map[provider.Pair]nodes.Aggregator{ab: testGraph[ab], ba: baGraph}, testFeeder)
+
+	// The direct model must be used instead of the inverted one:
+	r, err := g.Price(ba)
+	assert.NoError(t, err)
+	assert.Equal(t, "median", r.Parameters["method"])
+	assert.Equal(t, float64(10), r.Price)
+}
+
+func TestGofer_Price_Alias(t *testing.T) {
+	g := NewProvider(testGraph, testFeeder)
+	g.SetAliases(provider.Aliases{"C": "A", "D": "B"})
+	ab := testPairs["A/B"]
+
+	// Aliases are resolved for both assets:
+	r, err := g.Price(provider.Pair{Base: "C", Quote: "D"})
+	assert.NoError(t, err)
+	assert.Equal(t, testPrices["A/B"], r)
+
+	ms, err := g.Models(provider.Pair{Base: "C", Quote: "B"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[provider.Pair]*provider.Model{ab: testModels["A/B"]}, ms)
+
+	// Aliases are resolved before an inverted model is looked up:
+	r, err = g.Price(provider.Pair{Base: "D", Quote: "C"})
+	assert.NoError(t, err)
+	assert.Equal(t, ab.Inverse(), r.Pair)
+	assert.Equal(t, 0.1, r.Price)
+}
@@ -21,7 +21,9 @@ import (
 	"io"
 	"time"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/origins"
 )
 
 type jsonItem struct {
@@ -31,6 +33,7 @@ type jsonItem struct {
 
 type json struct {
 	ndjson bool
+	fields []string
 	items  []jsonItem
 }
 
@@ -48,6 +51,16 @@ func (j *json) Write(writer io.Writer, item interface{}) error {
 		i = j.handlePrice(typedItem)
 	case *provider.Model:
 		i = j.handleModel(typedItem)
+	case *provider.PairOrigins:
+		i = j.handlePairOrigins(typedItem)
+	case *oracle.Status:
+		i = j.handleOracleStatus(typedItem)
+	case *origins.Timing:
+		i = j.handleOriginTiming(typedItem)
+	case *origins.Check:
+		i = j.handleOriginCheck(typedItem)
+	case *origins.OriginPairs:
+		i = j.handleOriginPairs(typedItem)
 	case error:
 		i = j.handleError(typedItem)
 	default:
@@ -98,7 +111,15 @@ func (j *json) Flush() error {
 	return nil
 }
 
-func (*json) handlePrice(price *provider.Price) interface{} {
+func (j *json) setFields(fields []string) error {
+	j.fields = fields
+	return nil
+}
+
+func (j *json) handlePrice(price *provider.Price) interface{} {
+	if len(j.fields) > 0 {
+		return jsonPriceFields(jsonPriceFromGoferPrice(price), j.fields)
+	}
 	return jsonPriceFromGoferPrice(price)
 }
 
@@ -106,24 +127,124 @@ func (*json) handleModel(node *provider.Model) interface{} {
 	return node.Pair.String()
 }
 
+func (*json) handlePairOrigins(po *provider.PairOrigins) interface{} {
+	origins := po.Origins
+	if origins == nil {
+		origins = []string{}
+	}
+	return jsonPairOrigins{Pair: po.Pair.String(), Origins: origins}
+}
+
+func (*json) handleOriginPairs(op *origins.OriginPairs) interface{} {
+	pairs := make([]string, len(op.Pairs))
+	for i, p := range op.Pairs {
+		pairs[i] = p.String()
+	}
+	return jsonOriginPairs{Origin: op.Origin, Pairs: pairs}
+}
+
+func (*json) handleOracleStatus(status *oracle.Status) interface{} {
+	feeds := make([]string, len(status.Feeds))
+	for i, f := range status.Feeds {
+		feeds[i] = f.String()
+	}
+	return jsonOracleStatus{
+		Address: status.Address.String(),
+		Wat:     status.Wat,
+		Bar:     status.Bar,
+		Age:     status.Age.In(time.UTC),
+		Val:     status.Val.String(),
+		Price:   status.Float64Price(),
+		Feeds:   feeds,
+	}
+}
+
+func (*json) handleOriginTiming(timing *origins.Timing) interface{} {
+	ms := func(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+	j := jsonOriginTiming{
+		Origin:     timing.Origin,
+		Requests:   len(timing.Latencies),
+		AvgLatency: ms(timing.Average()),
+		MaxLatency: ms(timing.Max()),
+		Latencies:  make([]float64, len(timing.Latencies)),
+	}
+	for i, l := range timing.Latencies {
+		j.Latencies[i] = ms(l)
+	}
+	if timing.Err != nil {
+		j.Error = timing.Err.Error()
+	}
+	return j
+}
+
+func (*json) handleOriginCheck(check *origins.Check) interface{} {
+	j := jsonOriginCheck{
+		Origin: check.Origin,
+		Pair:   check.Pair.String(),
+		Price:  check.Price,
+	}
+	if check.Err != nil {
+		j.Error = check.Err.Error()
+	}
+	return j
+}
+
 func (*json) handleError(err error) interface{} {
 	return struct {
-		Error string `json:"error"`
+		Error string `json:"error" yaml:"error"`
 	}{Error: err.Error()}
 }
 
 type jsonPrice struct {
-	Type       string            `json:"type"`
-	Base       string            `json:"base"`
-	Quote      string            `json:"quote"`
-	Price      float64           `json:"price"`
-	Bid        float64           `json:"bid"`
-	Ask        float64           `json:"ask"`
-	Volume24h  float64           `json:"vol24h"`
-	Timestamp  time.Time         `json:"ts"`
-	Parameters map[string]string `json:"params,omitempty"`
-	Prices     []jsonPrice       `json:"prices,omitempty"`
-	Error      string            `json:"error,omitempty"`
+	Type       string            `json:"type" yaml:"type"`
+	Base       string            `json:"base" yaml:"base"`
+	Quote      string            `json:"quote" yaml:"quote"`
+	Price      float64           `json:"price" yaml:"price"`
+	Bid        float64           `json:"bid" yaml:"bid"`
+	Ask        float64           `json:"ask" yaml:"ask"`
+	Volume24h  float64           `json:"vol24h" yaml:"vol24h"`
+	Timestamp  time.Time         `json:"ts" yaml:"ts"`
+	Parameters map[string]string `json:"params,omitempty" yaml:"params,omitempty"`
+	Prices     []jsonPrice       `json:"prices,omitempty" yaml:"prices,omitempty"`
+	Error      string            `json:"error,omitempty" yaml:"error,omitempty"`
+	Warning    string            `json:"warning,omitempty" yaml:"warning,omitempty"`
+}
+
+type jsonPairOrigins struct {
+	Pair    string   `json:"pair" yaml:"pair"`
+	Origins []string `json:"origins" yaml:"origins"`
+}
+
+type jsonOriginPairs struct {
+	Origin string   `json:"origin" yaml:"origin"`
+	Pairs  []string `json:"pairs" yaml:"pairs"`
+}
+
+// jsonOriginTiming contains latencies in milliseconds.
+type jsonOriginTiming struct {
+	Origin     string    `json:"origin" yaml:"origin"`
+	Requests   int       `json:"requests" yaml:"requests"`
+	AvgLatency float64   `json:"avgLatencyMs" yaml:"avgLatencyMs"`
+	MaxLatency float64   `json:"maxLatencyMs" yaml:"maxLatencyMs"`
+	Latencies  []float64 `json:"latenciesMs" yaml:"latenciesMs"`
+	Error      string    `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type jsonOriginCheck struct {
+	Origin string  `json:"origin" yaml:"origin"`
+	Pair   string  `json:"pair" yaml:"pair"`
+	Price  float64 `json:"price" yaml:"price"`
+	Error  string  `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type jsonOracleStatus struct {
+	Address string    `json:"address" yaml:"address"`
+	Wat     string    `json:"wat" yaml:"wat"`
+	Bar     int64     `json:"bar" yaml:"bar"`
+	Age     time.Time `json:"age" yaml:"age"`
+	Val     string    `json:"val" yaml:"val"`
+	Price   float64   `json:"price" yaml:"price"`
+	Feeds   []string  `json:"feeds" yaml:"feeds"`
 }
 
 func jsonPriceFromGoferPrice(t *provider.Price) jsonPrice {
@@ -143,5 +264,55 @@ func jsonPriceFromGoferPrice(t *provider.Price) jsonPrice {
 		Parameters: t.Parameters,
 		Prices:     prices,
 		Error:      t.Error,
+		Warning:    t.Warning,
+	}
+}
+
+// jsonPriceFields returns a map with only the given fields of the price.
+// The fields are also applied to the child prices.
+func jsonPriceFields(p jsonPrice, fields []string) map[string]interface{} {
+	m := map[string]interface{}{}
+	for _, f := range fields {
+		switch f {
+		case "type":
+			m[f] = p.Type
+		case "pair":
+			m[f] = provider.Pair{Base: p.Base, Quote: p.Quote}.String()
+		case "base":
+			m[f] = p.Base
+		case "quote":
+			m[f] = p.Quote
+		case "price":
+			m[f] = p.Price
+		case "bid":
+			m[f] = p.Bid
+		case "ask":
+			m[f] = p.Ask
+		case "vol24h":
+			m[f] = p.Volume24h
+		case "ts":
+			m[f] = p.Timestamp
+		case "params":
+			if len(p.Parameters) > 0 {
+				m[f] = p.Parameters
+			}
+		case "prices":
+			if len(p.Prices) > 0 {
+				var prices []map[string]interface{}
+				for _, c := range p.Prices {
+					prices = append(prices, jsonPriceFields(c, fields))
+				}
+				m[f] = prices
+			}
+		case "error":
+			if p.Error != "" {
+				m[f] = p.Error
+			}
+		case "warning":
+			if p.Warning != "" {
+				m[f] = p.Warning
+			}
+		}
 	}
+	return m
 }
@@ -17,12 +17,15 @@ package marshal
 
 import (
 	"bytes"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/marshal/testutil"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/origins"
 )
 
 func TestJSON_Nodes(t *testing.T) {
@@ -72,6 +75,40 @@ func TestNDJSON_Nodes(t *testing.T) {
 	assert.JSONEq(t, `"C/D"`, string(result[1]))
 }
 
+func TestJSON_PairOrigins(t *testing.T) {
+	b := &bytes.Buffer{}
+	m := newJSON(false)
+
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	cd := provider.Pair{Base: "C", Quote: "D"}
+	pos := testutil.PairOrigins(ab, cd)
+
+	assert.NoError(t, m.Write(b, pos[ab]))
+	assert.NoError(t, m.Write(b, pos[cd]))
+	assert.NoError(t, m.Flush())
+
+	expected := `[{"pair": "A/B", "origins": ["a", "b"]}, {"pair": "C/D", "origins": ["a", "b"]}]`
+
+	assert.JSONEq(t, expected, b.String())
+}
+
+func TestNDJSON_PairOrigins(t *testing.T) {
+	b := &bytes.Buffer{}
+	m := newJSON(true)
+
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	pos := testutil.PairOrigins(ab)
+
+	assert.NoError(t, m.Write(b, pos[ab]))
+	assert.NoError(t, m.Write(b, &provider.PairOrigins{Pair: provider.Pair{Base: "C", Quote: "D"}}))
+	assert.NoError(t, m.Flush())
+
+	result := bytes.Split(b.Bytes(), []byte("\n"))
+
+	assert.JSONEq(t, `{"pair": "A/B", "origins": ["a", "b"]}`, string(result[0]))
+	assert.JSONEq(t, `{"pair": "C/D", "origins": []}`, string(result[1]))
+}
+
 func TestJSON_Prices(t *testing.T) {
 	var err error
 	b := &bytes.Buffer{}
@@ -99,7 +136,8 @@ func TestJSON_Prices(t *testing.T) {
 			  "ts":"1970-01-01T00:00:10Z",
 			  "params":{
 				 "method":"median",
-				 "minimumSuccessfulSources":"1"
+				 "minimumSuccessfulSources":"1",
+				 "includedSources":"a, indirect:A/B, median:A/B"
 			  },
 			  "prices":[
 				 {
@@ -154,7 +192,9 @@ func TestJSON_Prices(t *testing.T) {
 					"ts":"1970-01-01T00:00:10Z",
 					"params":{
 					   "method":"median",
-					   "minimumSuccessfulSources":"1"
+					   "minimumSuccessfulSources":"1",
+					   "includedSources":"a",
+					   "excludedSources":"b (error)"
 					},
 					"prices":[
 					   {
@@ -184,7 +224,8 @@ func TestJSON_Prices(t *testing.T) {
 						  },
 						  "error":"something"
 					   }
-					]
+					],
+					"warning":"1 error occurred:\n\t* the b source failed: something\n\n"
 				 }
 			  ]
 		   }
@@ -193,3 +234,81 @@ func TestJSON_Prices(t *testing.T) {
 
 	assert.JSONEq(t, expected, b.String())
 }
+
+func TestJSON_OracleStatus(t *testing.T) {
+	b := &bytes.Buffer{}
+	m := newJSON(true)
+
+	assert.NoError(t, m.Write(b, testutil.OracleStatus()))
+	assert.NoError(t, m.Flush())
+
+	expected := `
+{
+  "address": "0x1111111111111111111111111111111111111111",
+  "wat": "ETHUSD",
+  "bar": 13,
+  "age": "2020-09-13T12:26:40Z",
+  "val": "1500000000000000000000",
+  "price": 1500,
+  "feeds": [
+    "0x2222222222222222222222222222222222222222",
+    "0x3333333333333333333333333333333333333333"
+  ]
+}`
+
+	assert.JSONEq(t, expected, b.String())
+}
+
+func TestJSON_OriginTiming(t *testing.T) {
+	b := &bytes.Buffer{}
+	m := newJSON(false)
+
+	assert.NoError(t, m.Write(b, &origins.Timing{
+		Origin:    "foo",
+		Latencies: []time.Duration{100 * time.Millisecond, 300 * time.Millisecond},
+		Err:       errors.New("failed"),
+	}))
+	assert.NoError(t, m.Flush())
+
+	assert.JSONEq(t, `[{
+		"origin": "foo",
+		"requests": 2,
+		"avgLatencyMs": 200,
+		"maxLatencyMs": 300,
+		"latenciesMs": [100, 300],
+		"error": "failed"
+	}]`, b.String())
+}
+
+func TestJSON_OriginCheck(t *testing.T) {
+	b := &bytes.Buffer{}
+	m := newJSON(true)
+
+	assert.NoError(t, m.Write(b, &origins.Check{Origin: "foo", Pair: origins.Pair{Base: "A", Quote: "B"}, Price: 1.5}))
+	assert.NoError(t, m.Write(b, &origins.Check{
+		Origin: "bar",
+		Pair:   origins.Pair{Base: "C", Quote: "D"},
+		Err:    errors.New("failed"),
+	}))
+	assert.NoError(t, m.Flush())
+
+	assert.Equal(t, `{"origin":"foo","pair":"A/B","price":1.5}`+"\n"+
+		`{"origin":"bar","pair":"C/D","price":0,"error":"failed"}`+"\n", b.String())
+}
+
+func TestJSON_OriginPairs(t *testing.T) {
+	b := &bytes.Buffer{}
+	m := newJSON(false)
+
+	assert.NoError(t, m.Write(b, &origins.OriginPairs{
+		Origin: "foo",
+		Pairs:  []origins.Pair{{Base: "A", Quote: "B"}, {Base: "C", Quote: "D"}},
+	}))
+	assert.NoError(t, m.Write(b, &origins.OriginPairs{Origin: "bar"}))
+	assert.NoError(t, m.Flush())
+
+	assert.JSONEq(t, `[
+		{"origin": "foo", "pairs": ["A/B", "C/D"]},
+		{"origin": "bar", "pairs": []}
+	]`, b.String())
+}
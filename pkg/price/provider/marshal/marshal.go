@@ -17,10 +17,51 @@ package marshal
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"math"
+	"strings"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
 )
 
+// PriceFields is the list of price fields that can be selected using the
+// Marshal.SetFields method.
+var PriceFields = []string{
+	"type", "pair", "base", "quote", "price", "bid", "ask", "vol24h", "ts", "params", "prices", "error",
+}
+
+// ErrUnknownField is returned by the Marshal.SetFields method if the field
+// is not on the PriceFields list.
+type ErrUnknownField struct {
+	Field string
+}
+
+func (e ErrUnknownField) Error() string {
+	return fmt.Sprintf("unknown field %s, supported fields are: %s", e.Field, strings.Join(PriceFields, ", "))
+}
+
+// ErrFieldsUnsupported is returned by the Marshal.SetFields method if the
+// selected format does not support limiting the price fields.
+var ErrFieldsUnsupported = errors.New("the format does not support selecting fields")
+
+// fieldsSetter is implemented by marshallers that support limiting the
+// price fields included in the output.
+type fieldsSetter interface {
+	setFields(fields []string) error
+}
+
+// precisionSetter is implemented by marshallers that format prices as text
+// and need to know the number of decimal places to print.
+type precisionSetter interface {
+	setPrecision(precision int)
+}
+
+// DefaultPrecision is the default number of decimal places to which prices
+// are rounded before they are formatted.
+const DefaultPrecision = 8
+
 // FormatType describes output format type.
 type FormatType int
 
@@ -29,6 +70,8 @@ const (
 	JSON
 	NDJSON
 	Trace
+	YAML
+	PlainTable
 )
 
 // Marshaller is the interface which must be implemented by different
@@ -42,26 +85,68 @@ type Marshaller interface {
 // on argument passed to the NewMarshal method.
 type Marshal struct {
 	marshaller Marshaller
+	precision  int
 }
 
 // NewMarshal returns new Marshal instance.
 func NewMarshal(format FormatType) (*Marshal, error) {
+	var m Marshaller
 	switch format {
 	case Plain:
-		return &Marshal{marshaller: newPlain()}, nil
+		m = newPlain(false)
+	case PlainTable:
+		m = newPlain(true)
 	case JSON:
-		return &Marshal{marshaller: newJSON(false)}, nil
+		m = newJSON(false)
 	case NDJSON:
-		return &Marshal{marshaller: newJSON(true)}, nil
+		m = newJSON(true)
 	case Trace:
-		return &Marshal{marshaller: newTrace()}, nil
+		m = newTrace()
+	case YAML:
+		m = newYAML()
+	default:
+		return nil, fmt.Errorf("unsupported format")
 	}
 
-	return nil, fmt.Errorf("unsupported format")
+	mar := &Marshal{marshaller: m}
+	mar.SetPrecision(DefaultPrecision)
+	return mar, nil
+}
+
+// SetPrecision sets the number of decimal places to which prices, bids, asks
+// and volumes are rounded. A negative value disables rounding.
+func (m *Marshal) SetPrecision(precision int) {
+	m.precision = precision
+	if ps, ok := m.marshaller.(precisionSetter); ok {
+		ps.setPrecision(precision)
+	}
+}
+
+// SetFields limits the price fields included in the output to the given
+// list, in the given order. An empty list restores the default output.
+// The trace and plain:table formats do not support it, for them the
+// ErrFieldsUnsupported error is returned if the list is not empty.
+func (m *Marshal) SetFields(fields []string) error {
+	for _, f := range fields {
+		if !isPriceField(f) {
+			return ErrUnknownField{Field: f}
+		}
+	}
+	fs, ok := m.marshaller.(fieldsSetter)
+	if !ok {
+		if len(fields) > 0 {
+			return ErrFieldsUnsupported
+		}
+		return nil
+	}
+	return fs.setFields(fields)
 }
 
 // Write implements the Marshaller interface.
 func (m *Marshal) Write(writer io.Writer, item interface{}) error {
+	if price, ok := item.(*provider.Price); ok && price != nil && m.precision >= 0 {
+		item = roundPrice(price, m.precision)
+	}
 	return m.marshaller.Write(writer, item)
 }
 
@@ -92,3 +177,36 @@ func Marshall(format FormatType, items ...interface{}) ([]byte, error) {
 
 	return buf.Bytes(), nil
 }
+
+// roundPrice returns a copy of the price with all values, including values
+// of the child prices, rounded to the given number of decimal places.
+func roundPrice(price *provider.Price, precision int) *provider.Price {
+	p := *price
+	p.Price = round(p.Price, precision)
+	p.Bid = round(p.Bid, precision)
+	p.Ask = round(p.Ask, precision)
+	p.Volume24h = round(p.Volume24h, precision)
+	p.Prices = nil
+	for _, c := range price.Prices {
+		p.Prices = append(p.Prices, roundPrice(c, precision))
+	}
+	return &p
+}
+
+func isPriceField(field string) bool {
+	for _, f := range PriceFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func round(f float64, precision int) float64 {
+	m := math.Pow10(precision)
+	r := math.Round(f*m) / m
+	if math.IsInf(r, 0) || math.IsNaN(r) {
+		return f
+	}
+	return r
+}
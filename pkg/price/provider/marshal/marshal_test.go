@@ -16,23 +16,31 @@
 package marshal
 
 import (
+	"bytes"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
 )
 
 func TestNewMarshaller(t *testing.T) {
 	expectedMap := map[FormatType]interface{}{
-		Plain:  (*plain)(nil),
-		JSON:   (*json)(nil),
-		NDJSON: (*json)(nil),
-		Trace:  (*trace)(nil),
+		Plain:      (*plain)(nil),
+		PlainTable: (*plain)(nil),
+		JSON:       (*json)(nil),
+		NDJSON:     (*json)(nil),
+		Trace:      (*trace)(nil),
 	}
 	formatMap := map[FormatType]string{
-		Plain:  "plain",
-		JSON:   "json",
-		NDJSON: "ndjson",
-		Trace:  "trace",
+		Plain:      "plain",
+		PlainTable: "plain:table",
+		JSON:       "json",
+		NDJSON:     "ndjson",
+		Trace:      "trace",
 	}
 	for ct, st := range formatMap {
 		t.Run(st, func(t *testing.T) {
@@ -45,3 +53,120 @@ func TestNewMarshaller(t *testing.T) {
 		})
 	}
 }
+
+func TestMarshal_Precision(t *testing.T) {
+	price := &provider.Price{
+		Type:      "aggregator",
+		Pair:      provider.Pair{Base: "A", Quote: "B"},
+		Price:     1.23456789123,
+		Bid:       1.23456789123,
+		Ask:       1.23456789123,
+		Volume24h: 1.23456789123,
+		Prices: []*provider.Price{{
+			Type:  "origin",
+			Pair:  provider.Pair{Base: "A", Quote: "B"},
+			Price: 1.23456789123,
+		}},
+	}
+	tests := []struct {
+		format    FormatType
+		precision int
+		contains  string
+	}{
+		{format: Plain, precision: 3, contains: "A/B 1.235\n"},
+		{format: Plain, precision: 10, contains: "A/B 1.2345678912\n"},
+		{format: Plain, precision: -1, contains: "A/B 1.23456789123\n"},
+		{format: PlainTable, precision: 3, contains: "A/B  1.235\n"},
+		{format: JSON, precision: 3, contains: `"price":1.235,"bid":1.235,"ask":1.235,"vol24h":1.235`},
+		{format: NDJSON, precision: 3, contains: `"price":1.235,"bid":1.235,"ask":1.235,"vol24h":1.235`},
+		{format: YAML, precision: 3, contains: "price: 1.235"},
+		{format: Trace, precision: 3, contains: ":1.235, "},
+		{format: JSON, precision: DefaultPrecision, contains: `"price":1.23456789,`},
+		{format: JSON, precision: -1, contains: `"price":1.23456789123,`},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d-%d", tt.format, tt.precision), func(t *testing.T) {
+			m, err := NewMarshal(tt.format)
+			require.NoError(t, err)
+			m.SetPrecision(tt.precision)
+
+			b := &bytes.Buffer{}
+			require.NoError(t, m.Write(b, price))
+			require.NoError(t, m.Flush())
+			assert.Contains(t, b.String(), tt.contains)
+
+			// The original price must not be modified:
+			assert.Equal(t, 1.23456789123, price.Price)
+			assert.Equal(t, 1.23456789123, price.Prices[0].Price)
+		})
+	}
+}
+
+func TestMarshal_PrecisionChildren(t *testing.T) {
+	m, err := NewMarshal(JSON)
+	require.NoError(t, err)
+	m.SetPrecision(2)
+
+	b := &bytes.Buffer{}
+	require.NoError(t, m.Write(b, &provider.Price{
+		Price:  1234.5600000000001,
+		Prices: []*provider.Price{{Price: 1234.5600000000001}},
+	}))
+	require.NoError(t, m.Flush())
+	assert.NotContains(t, b.String(), "1234.5600000000001")
+	assert.Equal(t, 2, strings.Count(b.String(), `"price":1234.56,`))
+}
+
+func TestMarshal_Fields(t *testing.T) {
+	price := &provider.Price{
+		Type:      "aggregator",
+		Pair:      provider.Pair{Base: "A", Quote: "B"},
+		Price:     10,
+		Bid:       9,
+		Ask:       11,
+		Volume24h: 100,
+		Prices: []*provider.Price{{
+			Type:  "origin",
+			Pair:  provider.Pair{Base: "A", Quote: "B"},
+			Price: 10,
+		}},
+	}
+	tests := []struct {
+		format   FormatType
+		fields   []string
+		expected string
+	}{
+		{format: Plain, fields: []string{"pair", "price"}, expected: "A/B 10.00000000\n"},
+		{format: Plain, fields: []string{"price"}, expected: "10.00000000\n"},
+		{format: JSON, fields: []string{"pair", "price"}, expected: `[{"pair":"A/B","price":10}]` + "\n"},
+		{format: NDJSON, fields: []string{"price", "prices"}, expected: `{"price":10,"prices":[{"price":10}]}` + "\n"},
+		{format: YAML, fields: []string{"pair"}, expected: "- pair: A/B\n"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d-%s", tt.format, strings.Join(tt.fields, ",")), func(t *testing.T) {
+			m, err := NewMarshal(tt.format)
+			require.NoError(t, err)
+			require.NoError(t, m.SetFields(tt.fields))
+
+			b := &bytes.Buffer{}
+			require.NoError(t, m.Write(b, price))
+			require.NoError(t, m.Flush())
+			assert.Equal(t, tt.expected, b.String())
+		})
+	}
+}
+
+func TestMarshal_UnknownField(t *testing.T) {
+	m, err := NewMarshal(JSON)
+	require.NoError(t, err)
+	assert.Equal(t, ErrUnknownField{Field: "foo"}, m.SetFields([]string{"price", "foo"}))
+}
+
+func TestMarshal_FieldsUnsupported(t *testing.T) {
+	for _, format := range []FormatType{Trace, PlainTable} {
+		m, err := NewMarshal(format)
+		require.NoError(t, err)
+		assert.ErrorIs(t, m.SetFields([]string{"price"}), ErrFieldsUnsupported)
+		assert.NoError(t, m.SetFields(nil))
+	}
+}
@@ -16,24 +16,37 @@
 package marshal
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/origins"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/maputil"
 )
 
 type plainItem struct {
 	writer io.Writer
 	item   []byte
+	price  *provider.Price // used only in the table mode
 }
 
 type plain struct {
-	items []plainItem
+	items     []plainItem
+	table     bool
+	fields    []string
+	precision int
 }
 
-func newPlain() *plain {
-	return &plain{}
+// newPlain returns a new plain marshaller. If table is true, prices are
+// printed as a table with aligned columns.
+func newPlain(table bool) *plain {
+	return &plain{table: table, precision: DefaultPrecision}
 }
 
 // Write implements the Marshaller interface.
@@ -44,19 +57,36 @@ func (p *plain) Write(writer io.Writer, item interface{}) error {
 		i = p.handlePrice(typedItem)
 	case *provider.Model:
 		i = p.handleModel(typedItem)
+	case *provider.PairOrigins:
+		i = p.handlePairOrigins(typedItem)
+	case *oracle.Status:
+		i = p.handleOracleStatus(typedItem)
+	case *origins.Timing:
+		i = []byte(typedItem.String())
+	case *origins.Check:
+		i = []byte(typedItem.String())
+	case *origins.OriginPairs:
+		i = p.handleOriginPairs(typedItem)
 	case error:
 		i = []byte(fmt.Sprintf("Error: %s", typedItem.Error()))
 	default:
 		return fmt.Errorf("unsupported data type")
 	}
 
-	p.items = append(p.items, plainItem{writer: writer, item: i})
+	pi := plainItem{writer: writer, item: i}
+	if price, ok := item.(*provider.Price); ok && p.table {
+		pi.price = price
+	}
+	p.items = append(p.items, pi)
 	return nil
 }
 
 // Flush implements the Marshaller interface.
 func (p *plain) Flush() error {
 	var err error
+	if p.table {
+		p.alignPrices()
+	}
 	for _, i := range p.items {
 		_, err = i.writer.Write(i.item)
 		if err != nil {
@@ -70,13 +100,164 @@ func (p *plain) Flush() error {
 	return nil
 }
 
-func (*plain) handlePrice(price *provider.Price) []byte {
+func (p *plain) setFields(fields []string) error {
+	if p.table && len(fields) > 0 {
+		return ErrFieldsUnsupported
+	}
+	p.fields = fields
+	return nil
+}
+
+func (p *plain) setPrecision(precision int) {
+	p.precision = precision
+}
+
+func (p *plain) handlePrice(price *provider.Price) []byte {
+	if len(p.fields) > 0 && !p.table {
+		return plainPriceFields(price, p.fields, p.precision)
+	}
 	if price.Error != "" {
 		return []byte(fmt.Sprintf("%s - %s", price.Pair, strings.TrimSpace(price.Error)))
 	}
-	return []byte(fmt.Sprintf("%s %f", price.Pair, price.Price))
+	return []byte(fmt.Sprintf("%s %s", price.Pair, formatFloat(price.Price, p.precision)))
+}
+
+// alignPrices renders prices as a table, where pair names are padded to the
+// same width and prices are aligned to the right.
+func (p *plain) alignPrices() {
+	var pairWidth, priceWidth int
+	for _, i := range p.items {
+		if i.price == nil {
+			continue
+		}
+		if l := len(i.price.Pair.String()); l > pairWidth {
+			pairWidth = l
+		}
+		if l := len(formatThousands(i.price.Price, p.precision)); i.price.Error == "" && l > priceWidth {
+			priceWidth = l
+		}
+	}
+	for n, i := range p.items {
+		if i.price == nil {
+			continue
+		}
+		if i.price.Error != "" {
+			p.items[n].item = []byte(fmt.Sprintf(
+				"%-*s  - %s",
+				pairWidth, i.price.Pair, strings.TrimSpace(i.price.Error),
+			))
+			continue
+		}
+		p.items[n].item = []byte(fmt.Sprintf(
+			"%-*s  %*s",
+			pairWidth, i.price.Pair,
+			priceWidth, formatThousands(i.price.Price, p.precision),
+		))
+	}
+}
+
+// formatFloat formats a number with the given number of decimal places. If
+// prec is negative, the smallest number of digits necessary to represent
+// the value is used.
+func formatFloat(f float64, prec int) string {
+	if prec < 0 {
+		prec = -1
+	}
+	return strconv.FormatFloat(f, 'f', prec, 64)
+}
+
+// formatThousands formats a number with the given precision and with commas
+// as thousands separators.
+func formatThousands(f float64, prec int) string {
+	s := formatFloat(f, prec)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	intPart, fracPart := s, ""
+	if n := strings.IndexByte(s, '.'); n >= 0 {
+		intPart, fracPart = s[:n], s[n:]
+	}
+	b := &strings.Builder{}
+	for n, c := range intPart {
+		if n > 0 && (len(intPart)-n)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	return sign + b.String() + fracPart
+}
+
+// plainPriceFields returns the given fields of the price separated by
+// spaces. Empty fields are skipped. The "prices" field is not supported in
+// the plain format and is ignored.
+func plainPriceFields(price *provider.Price, fields []string, prec int) []byte {
+	var vs []string
+	for _, f := range fields {
+		var v string
+		switch f {
+		case "type":
+			v = price.Type
+		case "pair":
+			v = price.Pair.String()
+		case "base":
+			v = price.Pair.Base
+		case "quote":
+			v = price.Pair.Quote
+		case "price":
+			v = formatFloat(price.Price, prec)
+		case "bid":
+			v = formatFloat(price.Bid, prec)
+		case "ask":
+			v = formatFloat(price.Ask, prec)
+		case "vol24h":
+			v = formatFloat(price.Volume24h, prec)
+		case "ts":
+			v = price.Time.In(time.UTC).Format(time.RFC3339)
+		case "params":
+			var ps []string
+			keys := maputil.Keys(price.Parameters)
+			sort.Strings(keys)
+			for _, k := range keys {
+				ps = append(ps, k+"="+price.Parameters[k])
+			}
+			v = strings.Join(ps, ",")
+		case "error":
+			v = strings.TrimSpace(price.Error)
+		}
+		if v != "" {
+			vs = append(vs, v)
+		}
+	}
+	return []byte(strings.Join(vs, " "))
 }
 
 func (*plain) handleModel(node *provider.Model) []byte {
 	return []byte(node.Pair.String())
 }
+
+func (*plain) handlePairOrigins(po *provider.PairOrigins) []byte {
+	return []byte(fmt.Sprintf("%s %v", po.Pair, po.Origins))
+}
+
+func (*plain) handleOriginPairs(op *origins.OriginPairs) []byte {
+	return []byte(fmt.Sprintf("%s %v", op.Origin, op.Pairs))
+}
+
+func (*plain) handleOracleStatus(status *oracle.Status) []byte {
+	return oracleStatusText(status)
+}
+
+func oracleStatusText(status *oracle.Status) []byte {
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "Address: %s\n", status.Address)
+	fmt.Fprintf(b, "Wat:     %s\n", status.Wat)
+	fmt.Fprintf(b, "Bar:     %d\n", status.Bar)
+	fmt.Fprintf(b, "Age:     %s\n", status.Age.In(time.UTC).Format(time.RFC3339))
+	fmt.Fprintf(b, "Price:   %f\n", status.Float64Price())
+	fmt.Fprintf(b, "Feeds:   %d", len(status.Feeds))
+	for _, f := range status.Feeds {
+		fmt.Fprintf(b, "\n  %s", f)
+	}
+	return b.Bytes()
+}
@@ -17,18 +17,21 @@ package marshal
 
 import (
 	"bytes"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/marshal/testutil"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/origins"
 )
 
 func TestPlain_Nodes(t *testing.T) {
 	var err error
 	b := &bytes.Buffer{}
-	m := newPlain()
+	m := newPlain(false)
 
 	ab := provider.Pair{Base: "A", Quote: "B"}
 	cd := provider.Pair{Base: "C", Quote: "D"}
@@ -51,10 +54,30 @@ C/D
 	assert.Equal(t, expected, b.String())
 }
 
+func TestPlain_PairOrigins(t *testing.T) {
+	b := &bytes.Buffer{}
+	m := newPlain(false)
+
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	cd := provider.Pair{Base: "C", Quote: "D"}
+	pos := testutil.PairOrigins(ab, cd)
+
+	assert.NoError(t, m.Write(b, pos[ab]))
+	assert.NoError(t, m.Write(b, pos[cd]))
+	assert.NoError(t, m.Flush())
+
+	expected := `
+A/B [a b]
+C/D [a b]
+`[1:]
+
+	assert.Equal(t, expected, b.String())
+}
+
 func TestPlain_Prices(t *testing.T) {
 	var err error
 	b := &bytes.Buffer{}
-	m := newPlain()
+	m := newPlain(false)
 
 	ab := provider.Pair{Base: "A", Quote: "B"}
 	cd := provider.Pair{Base: "C", Quote: "D"}
@@ -72,9 +95,114 @@ func TestPlain_Prices(t *testing.T) {
 	assert.NoError(t, err)
 
 	expected := `
-A/B 10.000000
+A/B 10.00000000
 C/D - something
 `[1:]
 
 	assert.Equal(t, expected, b.String())
 }
+
+func TestPlain_OracleStatus(t *testing.T) {
+	b := &bytes.Buffer{}
+	m := newPlain(false)
+
+	assert.NoError(t, m.Write(b, testutil.OracleStatus()))
+	assert.NoError(t, m.Flush())
+
+	expected := `
+Address: 0x1111111111111111111111111111111111111111
+Wat:     ETHUSD
+Bar:     13
+Age:     2020-09-13T12:26:40Z
+Price:   1500.000000
+Feeds:   2
+  0x2222222222222222222222222222222222222222
+  0x3333333333333333333333333333333333333333
+`[1:]
+
+	assert.Equal(t, expected, b.String())
+}
+
+func TestPlain_PricesTable(t *testing.T) {
+	b := &bytes.Buffer{}
+	m := newPlain(true)
+
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	cd := provider.Pair{Base: "CCC", Quote: "DDD"}
+	ef := provider.Pair{Base: "E", Quote: "F"}
+	ns := testutil.Prices(ab, cd, ef)
+	ns[cd].Price = 1234567.891
+	ns[ef].Error = "something"
+
+	assert.NoError(t, m.Write(b, ns[ab]))
+	assert.NoError(t, m.Write(b, ns[cd]))
+	assert.NoError(t, m.Write(b, ns[ef]))
+	assert.NoError(t, m.Flush())
+
+	expected := `
+A/B             10.00000000
+CCC/DDD  1,234,567.89100000
+E/F      - something
+`[1:]
+
+	assert.Equal(t, expected, b.String())
+}
+
+func TestFormatThousands(t *testing.T) {
+	tests := []struct {
+		val  float64
+		prec int
+		want string
+	}{
+		{val: 0, prec: 2, want: "0.00"},
+		{val: 999, prec: 0, want: "999"},
+		{val: 1000, prec: 0, want: "1,000"},
+		{val: 123456.789, prec: 2, want: "123,456.79"},
+		{val: -1234567, prec: 1, want: "-1,234,567.0"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, formatThousands(tt.val, tt.prec))
+	}
+}
+
+func TestPlain_OriginTiming(t *testing.T) {
+	b := &bytes.Buffer{}
+	m := newPlain(false)
+
+	assert.NoError(t, m.Write(b, &origins.Timing{
+		Origin:    "foo",
+		Latencies: []time.Duration{100 * time.Millisecond, 300 * time.Millisecond},
+		Err:       errors.New("failed"),
+	}))
+	assert.NoError(t, m.Flush())
+
+	assert.Equal(t, "foo: 2 requests, avg 200ms, max 300ms, error: failed\n", b.String())
+}
+
+func TestPlain_OriginCheck(t *testing.T) {
+	b := &bytes.Buffer{}
+	m := newPlain(false)
+
+	assert.NoError(t, m.Write(b, &origins.Check{Origin: "foo", Pair: origins.Pair{Base: "A", Quote: "B"}, Price: 1.5}))
+	assert.NoError(t, m.Write(b, &origins.Check{
+		Origin: "bar",
+		Pair:   origins.Pair{Base: "C", Quote: "D"},
+		Err:    errors.New("failed"),
+	}))
+	assert.NoError(t, m.Flush())
+
+	assert.Equal(t, "foo A/B: ok: 1.5\nbar C/D: error: failed\n", b.String())
+}
+
+func TestPlain_OriginPairs(t *testing.T) {
+	b := &bytes.Buffer{}
+	m := newPlain(false)
+
+	assert.NoError(t, m.Write(b, &origins.OriginPairs{
+		Origin: "foo",
+		Pairs:  []origins.Pair{{Base: "A", Quote: "B"}, {Base: "C", Quote: "D"}},
+	}))
+	assert.NoError(t, m.Flush())
+
+	assert.Equal(t, "foo [A/B C/D]\n", b.String())
+}
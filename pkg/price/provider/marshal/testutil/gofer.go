@@ -17,8 +17,11 @@ package testutil
 
 import (
 	"errors"
+	"math/big"
 	"time"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/graph"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/graph/nodes"
@@ -27,13 +30,13 @@ import (
 func Gofer(ps ...provider.Pair) provider.Provider {
 	graphs := map[provider.Pair]nodes.Aggregator{}
 	for _, p := range ps {
-		root := nodes.NewMedianAggregatorNode(p, 1)
+		root := nodes.NewMedianAggregatorNode(p, 1, 0)
 
 		ttl := time.Second * time.Duration(time.Now().Unix()+10)
 		on1 := nodes.NewOriginNode(nodes.OriginPair{Origin: "a", Pair: p}, 0, ttl)
 		on2 := nodes.NewOriginNode(nodes.OriginPair{Origin: "b", Pair: p}, 0, ttl)
 		in := nodes.NewIndirectAggregatorNode(p)
-		mn := nodes.NewMedianAggregatorNode(p, 1)
+		mn := nodes.NewMedianAggregatorNode(p, 1, 0)
 
 		root.AddChild(on1)
 		root.AddChild(in)
@@ -84,6 +87,14 @@ func Models(ps ...provider.Pair) map[provider.Pair]*provider.Model {
 	return ns
 }
 
+func PairOrigins(ps ...provider.Pair) map[provider.Pair]*provider.PairOrigins {
+	pos := map[provider.Pair]*provider.PairOrigins{}
+	for p, m := range Models(ps...) {
+		pos[p] = &provider.PairOrigins{Pair: p, Origins: m.Origins()}
+	}
+	return pos
+}
+
 func Prices(ps ...provider.Pair) map[provider.Pair]*provider.Price {
 	g := Gofer(ps...)
 	ts, err := g.Prices()
@@ -92,3 +103,18 @@ func Prices(ps ...provider.Pair) map[provider.Pair]*provider.Price {
 	}
 	return ts
 }
+
+// OracleStatus returns an example Oracle status.
+func OracleStatus() *oracle.Status {
+	return &oracle.Status{
+		Address: ethereum.HexToAddress("0x1111111111111111111111111111111111111111"),
+		Wat:     "ETHUSD",
+		Bar:     13,
+		Age:     time.Unix(1600000000, 0),
+		Val:     new(big.Int).Mul(big.NewInt(1500), big.NewInt(oracle.PriceMultiplier)),
+		Feeds: []ethereum.Address{
+			ethereum.HexToAddress("0x2222222222222222222222222222222222222222"),
+			ethereum.HexToAddress("0x3333333333333333333333333333333333333333"),
+		},
+	}
+}
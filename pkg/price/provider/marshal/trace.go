@@ -24,7 +24,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/origins"
 )
 
 type traceItem struct {
@@ -48,6 +50,16 @@ func (t *trace) Write(writer io.Writer, item interface{}) error {
 		i = t.handlePrice(typedItem)
 	case *provider.Model:
 		i = t.handleModel(typedItem)
+	case *provider.PairOrigins:
+		i = t.handlePairOrigins(typedItem)
+	case *oracle.Status:
+		i = t.handleOracleStatus(typedItem)
+	case *origins.Timing:
+		i = []byte(typedItem.String() + "\n")
+	case *origins.Check:
+		i = []byte(typedItem.String() + "\n")
+	case *origins.OriginPairs:
+		i = t.handleOriginPairs(typedItem)
 	case error:
 		i = []byte(fmt.Sprintf("Error: %s", typedItem.Error()))
 	default:
@@ -133,6 +145,24 @@ func (t *trace) handleModel(node *provider.Model) []byte {
 	return buf.Bytes()
 }
 
+func (*trace) handlePairOrigins(po *provider.PairOrigins) []byte {
+	buf := bytes.Buffer{}
+	buf.Write([]byte(fmt.Sprintf("Origins for %s:\n", po.Pair)))
+	for _, o := range po.Origins {
+		buf.Write([]byte(fmt.Sprintf("  %s\n", o)))
+	}
+	return buf.Bytes()
+}
+
+func (*trace) handleOriginPairs(op *origins.OriginPairs) []byte {
+	buf := bytes.Buffer{}
+	buf.Write([]byte(fmt.Sprintf("Pairs for %s:\n", op.Origin)))
+	for _, p := range op.Pairs {
+		buf.Write([]byte(fmt.Sprintf("  %s\n", p)))
+	}
+	return buf.Bytes()
+}
+
 // param is used to work with lists of sorted key/value pairs.
 type param struct {
 	key   string
@@ -140,6 +170,10 @@ type param struct {
 }
 
 // mergeKVMap merges map[string]string into []param.
+func (*trace) handleOracleStatus(status *oracle.Status) []byte {
+	return append(oracleStatusText(status), '\n')
+}
+
 func mergeKVMap(target []param, kv map[string]string) []param {
 	for k, v := range kv {
 		target = append(target, param{key: k, value: v})
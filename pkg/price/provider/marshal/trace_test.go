@@ -17,12 +17,15 @@ package marshal
 
 import (
 	"bytes"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/marshal/testutil"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/origins"
 )
 
 func TestTrace_Graph(t *testing.T) {
@@ -55,6 +58,25 @@ Graph for A/B:
 	assert.Equal(t, expected, b.String())
 }
 
+func TestTrace_PairOrigins(t *testing.T) {
+	b := &bytes.Buffer{}
+	m := newTrace()
+
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	pos := testutil.PairOrigins(ab)
+
+	assert.NoError(t, m.Write(b, pos[ab]))
+	assert.NoError(t, m.Flush())
+
+	expected := `
+Origins for A/B:
+  a
+  b
+`[1:]
+
+	assert.Equal(t, expected, b.String())
+}
+
 func TestTrace_Prices(t *testing.T) {
 	disableColors()
 
@@ -73,11 +95,11 @@ func TestTrace_Prices(t *testing.T) {
 
 	expected := `
 Price for A/B:
-───aggregator(method:median, minimumSuccessfulSources:1, pair:A/B, price:10, timestamp:1970-01-01T00:00:10Z)
+───aggregator(includedSources:a, indirect:A/B, median:A/B, method:median, minimumSuccessfulSources:1, pair:A/B, price:10, timestamp:1970-01-01T00:00:10Z)
    ├──origin(origin:a, pair:A/B, price:10, timestamp:1970-01-01T00:00:10Z)
    ├──aggregator(method:indirect, pair:A/B, price:10, timestamp:1970-01-01T00:00:10Z)
    │  └──origin(origin:a, pair:A/B, price:10, timestamp:1970-01-01T00:00:10Z)
-   └──aggregator(method:median, minimumSuccessfulSources:1, pair:A/B, price:10, timestamp:1970-01-01T00:00:10Z)
+   └──aggregator(excludedSources:b (error), includedSources:a, method:median, minimumSuccessfulSources:1, pair:A/B, price:10, timestamp:1970-01-01T00:00:10Z)
       ├──origin(origin:a, pair:A/B, price:10, timestamp:1970-01-01T00:00:10Z)
       └──origin(origin:b, pair:A/B, price:20, timestamp:1970-01-01T00:00:20Z)
             Error: something
@@ -85,3 +107,52 @@ Price for A/B:
 
 	assert.Equal(t, expected, b.String())
 }
+
+func TestTrace_Prices_ContainsAllOrigins(t *testing.T) {
+	disableColors()
+
+	b := &bytes.Buffer{}
+	m := newTrace()
+
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	ts := testutil.Prices(ab)
+
+	assert.NoError(t, m.Write(b, ts[ab]))
+	assert.NoError(t, m.Flush())
+
+	var walk func(p *provider.Price)
+	walk = func(p *provider.Price) {
+		if p.Type == "origin" {
+			assert.Contains(t, b.String(), fmt.Sprintf(
+				"origin(origin:%s, pair:%s, price:%g, timestamp:%s)",
+				p.Parameters["origin"],
+				p.Pair,
+				p.Price,
+				p.Time.In(time.UTC).Format(time.RFC3339Nano),
+			))
+		}
+		for _, c := range p.Prices {
+			walk(c)
+		}
+	}
+	walk(ts[ab])
+}
+
+func TestTrace_OriginPairs(t *testing.T) {
+	b := &bytes.Buffer{}
+	m := newTrace()
+
+	assert.NoError(t, m.Write(b, &origins.OriginPairs{
+		Origin: "foo",
+		Pairs:  []origins.Pair{{Base: "A", Quote: "B"}, {Base: "C", Quote: "D"}},
+	}))
+	assert.NoError(t, m.Flush())
+
+	expected := `
+Pairs for foo:
+  A/B
+  C/D
+`[1:]
+
+	assert.Equal(t, expected, b.String())
+}
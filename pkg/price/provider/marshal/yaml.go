@@ -0,0 +1,98 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package marshal
+
+import (
+	"fmt"
+	"io"
+
+	yamlV3 "gopkg.in/yaml.v3"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/origins"
+)
+
+type yamlItem struct {
+	writer io.Writer
+	item   interface{}
+}
+
+// yaml uses the same data structures as the json marshaller, so both
+// formats contain the same fields.
+type yaml struct {
+	json  *json
+	items []yamlItem
+}
+
+func newYAML() *yaml {
+	return &yaml{json: newJSON(false)}
+}
+
+func (y *yaml) setFields(fields []string) error {
+	return y.json.setFields(fields)
+}
+
+// Write implements the Marshaller interface.
+func (y *yaml) Write(writer io.Writer, item interface{}) error {
+	var i interface{}
+	switch typedItem := item.(type) {
+	case *provider.Price:
+		i = y.json.handlePrice(typedItem)
+	case *provider.Model:
+		i = y.json.handleModel(typedItem)
+	case *provider.PairOrigins:
+		i = y.json.handlePairOrigins(typedItem)
+	case *oracle.Status:
+		i = y.json.handleOracleStatus(typedItem)
+	case *origins.Timing:
+		i = y.json.handleOriginTiming(typedItem)
+	case *origins.Check:
+		i = y.json.handleOriginCheck(typedItem)
+	case *origins.OriginPairs:
+		i = y.json.handleOriginPairs(typedItem)
+	case error:
+		i = y.json.handleError(typedItem)
+	default:
+		return fmt.Errorf("unsupported data type")
+	}
+
+	y.items = append(y.items, yamlItem{writer: writer, item: i})
+	return nil
+}
+
+// Flush implements the Marshaller interface.
+func (y *yaml) Flush() error {
+	var writers []io.Writer
+	items := map[io.Writer][]interface{}{}
+	for _, i := range y.items {
+		if _, ok := items[i.writer]; !ok {
+			writers = append(writers, i.writer)
+		}
+		items[i.writer] = append(items[i.writer], i.item)
+	}
+	for _, w := range writers {
+		enc := yamlV3.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(items[w]); err != nil {
+			return err
+		}
+		if err := enc.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
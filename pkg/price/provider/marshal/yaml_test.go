@@ -0,0 +1,121 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package marshal
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yamlV3 "gopkg.in/yaml.v3"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/marshal/testutil"
+)
+
+func TestYAML_Nodes(t *testing.T) {
+	b := &bytes.Buffer{}
+	m := newYAML()
+
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	cd := provider.Pair{Base: "C", Quote: "D"}
+	ns := testutil.Models(ab, cd)
+
+	assert.NoError(t, m.Write(b, ns[ab]))
+	assert.NoError(t, m.Write(b, ns[cd]))
+	assert.NoError(t, m.Flush())
+
+	expected := `
+- A/B
+- C/D
+`[1:]
+
+	assert.Equal(t, expected, b.String())
+}
+
+func TestYAML_PairOrigins(t *testing.T) {
+	b := &bytes.Buffer{}
+	m := newYAML()
+
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	pos := testutil.PairOrigins(ab)
+
+	assert.NoError(t, m.Write(b, pos[ab]))
+	assert.NoError(t, m.Flush())
+
+	expected := `
+- pair: A/B
+  origins:
+    - a
+    - b
+`[1:]
+
+	assert.Equal(t, expected, b.String())
+}
+
+func TestYAML_Prices(t *testing.T) {
+	b := &bytes.Buffer{}
+	m := newYAML()
+
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	ts := testutil.Prices(ab)
+
+	assert.NoError(t, m.Write(b, ts[ab]))
+	assert.NoError(t, m.Flush())
+
+	// Parse the output and compare it with the original price:
+	var prices []jsonPrice
+	require.NoError(t, yamlV3.Unmarshal(b.Bytes(), &prices))
+	require.Len(t, prices, 1)
+	assertYAMLPrice(t, ts[ab], prices[0])
+}
+
+func TestYAML_OracleStatus(t *testing.T) {
+	b := &bytes.Buffer{}
+	m := newYAML()
+
+	s := testutil.OracleStatus()
+	assert.NoError(t, m.Write(b, s))
+	assert.NoError(t, m.Flush())
+
+	var statuses []jsonOracleStatus
+	require.NoError(t, yamlV3.Unmarshal(b.Bytes(), &statuses))
+	require.Len(t, statuses, 1)
+	assert.Equal(t, s.Address.String(), statuses[0].Address)
+	assert.Equal(t, s.Wat, statuses[0].Wat)
+	assert.Equal(t, s.Bar, statuses[0].Bar)
+	assert.True(t, s.Age.Equal(statuses[0].Age))
+	assert.Equal(t, s.Val.String(), statuses[0].Val)
+	assert.Len(t, statuses[0].Feeds, len(s.Feeds))
+}
+
+func assertYAMLPrice(t *testing.T, expected *provider.Price, actual jsonPrice) {
+	assert.Equal(t, expected.Type, actual.Type)
+	assert.Equal(t, expected.Pair.Base, actual.Base)
+	assert.Equal(t, expected.Pair.Quote, actual.Quote)
+	assert.Equal(t, expected.Price, actual.Price)
+	assert.Equal(t, expected.Bid, actual.Bid)
+	assert.Equal(t, expected.Ask, actual.Ask)
+	assert.Equal(t, expected.Volume24h, actual.Volume24h)
+	assert.True(t, expected.Time.Equal(actual.Timestamp))
+	assert.Equal(t, expected.Parameters, actual.Parameters)
+	assert.Equal(t, expected.Error, actual.Error)
+	require.Len(t, actual.Prices, len(expected.Prices))
+	for i := range expected.Prices {
+		assertYAMLPrice(t, expected.Prices[i], actual.Prices[i])
+	}
+}
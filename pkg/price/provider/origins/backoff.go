@@ -0,0 +1,105 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package origins
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+const (
+	backoffDefaultMin    = time.Second
+	backoffDefaultMax    = time.Minute
+	backoffDefaultFactor = 2
+	backoffDefaultJitter = 0.5
+	backoffDefaultStable = time.Minute
+)
+
+// Backoff calculates delays between reconnection attempts of streaming
+// origins. The delay grows exponentially with every failed attempt, starting
+// from Min up to Max. To avoid many clients reconnecting at the same time,
+// the delay is randomly shortened by up to the Jitter fraction of it.
+type Backoff struct {
+	// Min is the delay before the first reconnection attempt.
+	Min time.Duration
+	// Max is the maximum delay between attempts.
+	Max time.Duration
+	// Factor is the multiplier applied to the delay after every attempt.
+	Factor float64
+	// Jitter is a fraction, between 0 and 1, by which the delay may be
+	// randomly shortened. If zero, delays are not randomized.
+	Jitter float64
+	// MaxRetries is the number of consecutive failed attempts after which
+	// reconnecting is abandoned. If zero, there is no limit.
+	MaxRetries int
+	// Stable is the time for which a connection must stay open to reset
+	// the number of consecutive failed attempts. Connections dropped
+	// earlier are counted as failed attempts.
+	Stable time.Duration
+
+	// rand returns a random number in [0, 1), used in tests.
+	rand func() float64
+}
+
+// DefaultBackoff returns the Backoff used by streaming origins, if not
+// specified otherwise.
+func DefaultBackoff() Backoff {
+	return Backoff{
+		Min:    backoffDefaultMin,
+		Max:    backoffDefaultMax,
+		Factor: backoffDefaultFactor,
+		Jitter: backoffDefaultJitter,
+		Stable: backoffDefaultStable,
+	}
+}
+
+// Delay returns the delay before the given reconnection attempt, counting
+// from zero.
+func (b Backoff) Delay(attempt int) time.Duration {
+	d := float64(b.Min) * math.Pow(math.Max(b.Factor, 1), float64(attempt))
+	if b.Max > 0 && d > float64(b.Max) {
+		d = float64(b.Max)
+	}
+	if b.Jitter > 0 {
+		r := rand.Float64 //nolint:gosec
+		if b.rand != nil {
+			r = b.rand
+		}
+		d -= d * math.Min(b.Jitter, 1) * r()
+	}
+	return time.Duration(d)
+}
+
+// Exhausted returns true if no more reconnection attempts should be made
+// after the given number of consecutive failed attempts.
+func (b Backoff) Exhausted(failures int) bool {
+	return b.MaxRetries > 0 && failures >= b.MaxRetries
+}
+
+// sleep waits for the given duration or until the context is canceled.
+// It returns false if the context was canceled.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
@@ -0,0 +1,47 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package origins
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff_Delay(t *testing.T) {
+	b := Backoff{Min: time.Second, Max: 10 * time.Second, Factor: 2}
+	assert.Equal(t, time.Second, b.Delay(0))
+	assert.Equal(t, 2*time.Second, b.Delay(1))
+	assert.Equal(t, 8*time.Second, b.Delay(3))
+	assert.Equal(t, 10*time.Second, b.Delay(4))
+	assert.Equal(t, 10*time.Second, b.Delay(100))
+
+	// Jitter shortens the delay by a random fraction:
+	b.Jitter = 0.5
+	b.rand = func() float64 { return 0 }
+	assert.Equal(t, 8*time.Second, b.Delay(3))
+	b.rand = func() float64 { return 0.5 }
+	assert.Equal(t, 6*time.Second, b.Delay(3))
+	b.rand = func() float64 { return 0.999 }
+	assert.Greater(t, b.Delay(3), 4*time.Second)
+}
+
+func TestBackoff_Exhausted(t *testing.T) {
+	assert.False(t, Backoff{}.Exhausted(100))
+	assert.False(t, Backoff{MaxRetries: 3}.Exhausted(2))
+	assert.True(t, Backoff{MaxRetries: 3}.Exhausted(3))
+}
@@ -0,0 +1,318 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package origins
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const binanceStreamURL = "wss://stream.binance.com:9443/ws"
+const binanceStreamDefaultMaxAge = 10 * time.Second
+
+type binanceStreamRequest struct {
+	Method string   `json:"method"`
+	Params []string `json:"params"`
+	ID     int      `json:"id"`
+}
+
+type binanceStreamTicker struct {
+	Event     string               `json:"e"`
+	Symbol    string               `json:"s"`
+	LastPrice stringAsFloat64      `json:"c"`
+	BidPrice  stringAsFloat64      `json:"b"`
+	AskPrice  stringAsFloat64      `json:"a"`
+	Volume    stringAsFloat64      `json:"v"`
+	EventTime intAsUnixTimestampMs `json:"E"`
+}
+
+type streamedPrice struct {
+	price    Price
+	received time.Time
+}
+
+// BinanceStream is a streaming origin handler that receives ticker updates
+// from the Binance WebSocket API. The connection is established by the Start
+// method, and new pairs are subscribed to as they are requested. Until Start
+// is called, all prices are fetched using the Fallback handler.
+//
+// If there is no price for a pair, or the price is older than MaxAge, the
+// price is fetched using the Fallback handler. While the connection is down,
+// all streamed prices are considered stale, so the Fallback handler is used
+// for all pairs. Dropped connections are reestablished with delays defined
+// by the Backoff field. After Backoff.MaxRetries consecutive failed attempts,
+// reconnecting is abandoned until the next PullPrices call.
+type BinanceStream struct {
+	URL      string
+	Fallback ExchangeHandler
+	MaxAge   time.Duration
+	Backoff  Backoff
+
+	mu         sync.Mutex
+	ctx        context.Context
+	cancel     context.CancelFunc
+	running    bool
+	conn       *websocket.Conn
+	requestID  int
+	pairs      map[string]Pair
+	subscribed map[string]bool
+	prices     map[string]streamedPrice
+}
+
+// NewBinanceStream creates a new BinanceStream instance. If url is empty,
+// the default Binance stream URL is used. If maxAge is zero, the default value
+// of 10 seconds is used.
+func NewBinanceStream(url string, fallback ExchangeHandler, maxAge time.Duration) *BinanceStream {
+	if url == "" {
+		url = binanceStreamURL
+	}
+	if maxAge == 0 {
+		maxAge = binanceStreamDefaultMaxAge
+	}
+	return &BinanceStream{
+		URL:        url,
+		Fallback:   fallback,
+		MaxAge:     maxAge,
+		Backoff:    DefaultBackoff(),
+		pairs:      make(map[string]Pair),
+		subscribed: make(map[string]bool),
+		prices:     make(map[string]streamedPrice),
+	}
+}
+
+// Start starts maintaining the connection to the stream in the background.
+// The connection is closed, and no more reconnection attempts are made, after
+// the context is canceled or the Close method is called.
+func (b *BinanceStream) Start(ctx context.Context) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.start(ctx)
+}
+
+// PullPrices implements the ExchangeHandler interface.
+func (b *BinanceStream) PullPrices(pairs []Pair) []FetchResult {
+	// Until prices are received from the stream, they are fetched using
+	// the fallback handler.
+	b.subscribe(pairs)
+	return pullStreamedPrices(b, b.Fallback, b.MaxAge, pairs)
+}
+
+// Latest implements the StreamingHandler interface. No price is returned
+// while the stream is disconnected.
+func (b *BinanceStream) Latest(pair Pair) (Price, time.Time, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.conn == nil {
+		return Price{}, time.Time{}, false
+	}
+	p, ok := b.prices[b.localPairName(pair)]
+	if !ok {
+		return Price{}, time.Time{}, false
+	}
+	p.price.Pair = pair
+	return p.price, p.received, true
+}
+
+// Close closes the WebSocket connection and stops reconnecting.
+func (b *BinanceStream) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cancel != nil {
+		b.cancel()
+		b.cancel = nil
+	}
+	b.ctx = nil
+	if b.conn == nil {
+		return nil
+	}
+	err := b.conn.Close()
+	b.conn = nil
+	return err
+}
+
+func (b *BinanceStream) localPairName(pair Pair) string {
+	return pair.Base + pair.Quote
+}
+
+// start starts the connection loop if it is not running. It must be called
+// with the mutex locked.
+func (b *BinanceStream) start(ctx context.Context) {
+	if b.running {
+		return
+	}
+	if b.ctx == nil || b.ctx.Err() != nil {
+		b.ctx, b.cancel = context.WithCancel(ctx)
+	}
+	b.running = true
+	go b.connLoop(b.ctx)
+}
+
+// subscribe adds pairs to the list of subscribed pairs and sends
+// subscription requests for the new ones if the stream is connected.
+// If the connection loop was abandoned after too many failed attempts,
+// it is started again, unless the stream is closed.
+func (b *BinanceStream) subscribe(pairs []Pair) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, pair := range pairs {
+		b.pairs[b.localPairName(pair)] = pair
+	}
+	if b.ctx == nil || b.ctx.Err() != nil {
+		return
+	}
+	if !b.running {
+		b.start(b.ctx)
+		return
+	}
+	if b.conn != nil {
+		// If sending fails, the read loop will notice the broken
+		// connection and the loop will reconnect.
+		_ = b.sendSubscriptions()
+	}
+}
+
+// sendSubscriptions subscribes to ticker updates for pairs that are not
+// subscribed yet. It must be called with the mutex locked.
+func (b *BinanceStream) sendSubscriptions() error {
+	var streams []string
+	for symbol := range b.pairs {
+		if b.subscribed[symbol] {
+			continue
+		}
+		b.subscribed[symbol] = true
+		streams = append(streams, strings.ToLower(symbol)+"@ticker")
+	}
+	if len(streams) == 0 {
+		return nil
+	}
+	sort.Strings(streams)
+	b.requestID++
+	return b.conn.WriteJSON(binanceStreamRequest{
+		Method: "SUBSCRIBE",
+		Params: streams,
+		ID:     b.requestID,
+	})
+}
+
+// connLoop connects to the stream and reads messages until the connection
+// is dropped, then reconnects with delays defined by the Backoff field.
+// It returns when the context is canceled or the number of consecutive
+// failed attempts reaches Backoff.MaxRetries.
+func (b *BinanceStream) connLoop(ctx context.Context) {
+	defer func() {
+		b.mu.Lock()
+		b.running = false
+		b.mu.Unlock()
+	}()
+	failures := 0
+	for {
+		uptime, err := b.connect(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil && uptime >= b.Backoff.Stable {
+			failures = 0
+		} else {
+			failures++
+		}
+		if b.Backoff.Exhausted(failures) {
+			return
+		}
+		attempt := failures - 1
+		if attempt < 0 {
+			attempt = 0
+		}
+		if !sleep(ctx, b.Backoff.Delay(attempt)) {
+			return
+		}
+	}
+}
+
+// connect connects to the stream, subscribes to pairs and reads messages
+// until the connection is dropped. It returns the time for which
+// the connection was open, or an error if connecting or subscribing failed.
+func (b *BinanceStream) connect(ctx context.Context) (time.Duration, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, b.URL, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		b.mu.Lock()
+		if b.conn == conn {
+			b.conn = nil
+		}
+		b.mu.Unlock()
+		_ = conn.Close()
+	}()
+	b.mu.Lock()
+	if ctx.Err() != nil {
+		b.mu.Unlock()
+		return 0, ctx.Err()
+	}
+	b.conn = conn
+	b.subscribed = make(map[string]bool)
+	err = b.sendSubscriptions()
+	b.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	connected := time.Now()
+	b.readLoop(ctx, conn)
+	return time.Since(connected), nil
+}
+
+// readLoop reads messages from the connection until an error occurs or
+// the context is canceled.
+func (b *BinanceStream) readLoop(ctx context.Context, conn *websocket.Conn) {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var t binanceStreamTicker
+		if err := json.Unmarshal(msg, &t); err != nil || t.Event != "24hrTicker" {
+			// Skip responses to subscription requests and unknown messages.
+			continue
+		}
+		b.mu.Lock()
+		b.prices[t.Symbol] = streamedPrice{
+			price: Price{
+				Price:     t.LastPrice.val(),
+				Bid:       t.BidPrice.val(),
+				Ask:       t.AskPrice.val(),
+				Volume24h: t.Volume.val(),
+				Timestamp: t.EventTime.val(),
+			},
+			received: time.Now(),
+		}
+		b.mu.Unlock()
+	}
+}
@@ -0,0 +1,371 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package origins
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/query"
+)
+
+// newBinanceStreamServer returns a mock Binance WebSocket server that
+// responds to every subscription request with a single ticker update for
+// each subscribed stream.
+func newBinanceStreamServer(t *testing.T) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+		for {
+			var req binanceStreamRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			assert.Equal(t, "SUBSCRIBE", req.Method)
+			_ = conn.WriteJSON(map[string]interface{}{"result": nil, "id": req.ID})
+			for _, stream := range req.Params {
+				symbol := strings.ToUpper(strings.TrimSuffix(stream, "@ticker"))
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(`{
+					"e":"24hrTicker","E":1600000000000,"s":"`+symbol+`",
+					"c":"10.5","b":"10.4","a":"10.6","v":"1000"
+				}`))
+			}
+		}
+	}))
+}
+
+func TestBinanceStream(t *testing.T) {
+	srv := newBinanceStreamServer(t)
+	defer srv.Close()
+
+	pool := query.NewMockWorkerPool()
+	pool.MockBody(`[{"symbol":"BTCUSDT","lastPrice":"1","bidPrice":"1","askPrice":"1","volume":"1","closeTime":1}]`)
+
+	pair := Pair{Base: "BTC", Quote: "USDT"}
+	b := NewBinanceStream("ws"+strings.TrimPrefix(srv.URL, "http"), Binance{WorkerPool: pool}, time.Minute)
+	defer b.Close()
+	b.Start(context.Background())
+
+	// The first call subscribes to the stream. Until a price is received,
+	// it is fetched using the fallback handler.
+	crs := b.PullPrices([]Pair{pair})
+	require.Len(t, crs, 1)
+	assert.NoError(t, crs[0].Error)
+	assert.Equal(t, pair, crs[0].Price.Pair)
+
+	require.Eventually(t, func() bool {
+		_, _, ok := b.Latest(pair)
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	crs = b.PullPrices([]Pair{pair})
+	require.Len(t, crs, 1)
+	assert.NoError(t, crs[0].Error)
+	assert.Equal(t, pair, crs[0].Price.Pair)
+	assert.Equal(t, 10.5, crs[0].Price.Price)
+	assert.Equal(t, 10.4, crs[0].Price.Bid)
+	assert.Equal(t, 10.6, crs[0].Price.Ask)
+	assert.Equal(t, 1000.0, crs[0].Price.Volume24h)
+	assert.Equal(t, time.Unix(1600000000, 0), crs[0].Price.Timestamp)
+}
+
+func TestBinanceStream_StalePrice(t *testing.T) {
+	srv := newBinanceStreamServer(t)
+	defer srv.Close()
+
+	pool := query.NewMockWorkerPool()
+	pool.MockBody(`[{"symbol":"BTCUSDT","lastPrice":"1","bidPrice":"1","askPrice":"1","volume":"1","closeTime":1}]`)
+
+	pair := Pair{Base: "BTC", Quote: "USDT"}
+	b := NewBinanceStream("ws"+strings.TrimPrefix(srv.URL, "http"), Binance{WorkerPool: pool}, time.Nanosecond)
+	defer b.Close()
+	b.Start(context.Background())
+
+	b.PullPrices([]Pair{pair})
+	require.Eventually(t, func() bool {
+		_, _, ok := b.Latest(pair)
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	// Streamed price is too old, so the fallback handler must be used.
+	crs := b.PullPrices([]Pair{pair})
+	require.Len(t, crs, 1)
+	assert.NoError(t, crs[0].Error)
+	assert.Equal(t, 1.0, crs[0].Price.Price)
+}
+
+func TestBinanceStream_NoFallback(t *testing.T) {
+	pair := Pair{Base: "BTC", Quote: "USDT"}
+	b := NewBinanceStream("ws://127.0.0.1:0", nil, time.Minute)
+
+	crs := b.PullPrices([]Pair{pair})
+	require.Len(t, crs, 1)
+	assert.ErrorIs(t, crs[0].Error, ErrStalePrice)
+}
+
+// newFlakyStreamServer returns a mock WebSocket server which rejects
+// connection attempts for which the reject function returns true. Accepted
+// connections are handled like by the newBinanceStreamServer server, but
+// they are dropped after the drop function returns true for them. Times of
+// all connection attempts are recorded.
+func newFlakyStreamServer(
+	t *testing.T,
+	reject func(attempt int) bool,
+	drop func(attempt int) bool,
+) (*httptest.Server, func() []time.Time) {
+
+	var mu sync.Mutex
+	var attempts []time.Time
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts = append(attempts, time.Now())
+		attempt := len(attempts)
+		mu.Unlock()
+		if reject(attempt) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+		for {
+			var req binanceStreamRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			for _, stream := range req.Params {
+				symbol := strings.ToUpper(strings.TrimSuffix(stream, "@ticker"))
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(`{
+					"e":"24hrTicker","E":1600000000000,"s":"`+symbol+`",
+					"c":"10.5","b":"10.4","a":"10.6","v":"1000"
+				}`))
+			}
+			if drop(attempt) {
+				return
+			}
+		}
+	}))
+	return srv, func() []time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]time.Time(nil), attempts...)
+	}
+}
+
+func TestBinanceStream_Reconnect(t *testing.T) {
+	// The first connection is dropped right after the subscription:
+	srv, attempts := newFlakyStreamServer(
+		t,
+		func(int) bool { return false },
+		func(attempt int) bool { return attempt == 1 },
+	)
+	defer srv.Close()
+
+	pair := Pair{Base: "BTC", Quote: "USDT"}
+	b := NewBinanceStream("ws"+strings.TrimPrefix(srv.URL, "http"), nil, time.Minute)
+	b.Backoff = Backoff{Min: 100 * time.Millisecond, Factor: 2}
+	defer b.Close()
+	b.Start(context.Background())
+
+	b.PullPrices([]Pair{pair})
+
+	// After the reconnection, pairs must be subscribed again:
+	require.Eventually(t, func() bool {
+		return len(attempts()) == 2
+	}, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		_, _, ok := b.Latest(pair)
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	crs := b.PullPrices([]Pair{pair})
+	require.Len(t, crs, 1)
+	assert.NoError(t, crs[0].Error)
+	assert.Equal(t, 10.5, crs[0].Price.Price)
+
+	// The connection was dropped immediately, so the first reconnection
+	// attempt is made after the minimum delay:
+	a := attempts()
+	assert.GreaterOrEqual(t, a[1].Sub(a[0]), 100*time.Millisecond)
+}
+
+func TestBinanceStream_BackoffSpacing(t *testing.T) {
+	// The first three connection attempts are rejected:
+	srv, attempts := newFlakyStreamServer(
+		t,
+		func(attempt int) bool { return attempt <= 3 },
+		func(int) bool { return false },
+	)
+	defer srv.Close()
+
+	pair := Pair{Base: "BTC", Quote: "USDT"}
+	b := NewBinanceStream("ws"+strings.TrimPrefix(srv.URL, "http"), nil, time.Minute)
+	b.Backoff = Backoff{Min: 50 * time.Millisecond, Max: time.Second, Factor: 2}
+	defer b.Close()
+	b.Start(context.Background())
+
+	// While disconnected, streamed prices are not used:
+	crs := b.PullPrices([]Pair{pair})
+	require.Len(t, crs, 1)
+	assert.ErrorIs(t, crs[0].Error, ErrStalePrice)
+
+	require.Eventually(t, func() bool {
+		_, _, ok := b.Latest(pair)
+		return ok
+	}, 3*time.Second, 10*time.Millisecond)
+
+	// Delays must grow exponentially, starting from the minimum delay:
+	a := attempts()
+	require.Len(t, a, 4)
+	assert.GreaterOrEqual(t, a[1].Sub(a[0]), 50*time.Millisecond)
+	assert.Less(t, a[1].Sub(a[0]), 100*time.Millisecond)
+	assert.GreaterOrEqual(t, a[2].Sub(a[1]), 100*time.Millisecond)
+	assert.GreaterOrEqual(t, a[3].Sub(a[2]), 200*time.Millisecond)
+}
+
+func TestBinanceStream_MaxRetries(t *testing.T) {
+	srv, attempts := newFlakyStreamServer(
+		t,
+		func(int) bool { return true },
+		func(int) bool { return false },
+	)
+	defer srv.Close()
+
+	pool := query.NewMockWorkerPool()
+	pool.MockBody(`[{"symbol":"BTCUSDT","lastPrice":"1","bidPrice":"1","askPrice":"1","volume":"1","closeTime":1}]`)
+
+	pair := Pair{Base: "BTC", Quote: "USDT"}
+	b := NewBinanceStream("ws"+strings.TrimPrefix(srv.URL, "http"), Binance{WorkerPool: pool}, time.Minute)
+	b.Backoff = Backoff{Min: 10 * time.Millisecond, Factor: 1, MaxRetries: 3}
+	defer b.Close()
+	b.Start(context.Background())
+
+	// Prices are fetched using the fallback handler:
+	crs := b.PullPrices([]Pair{pair})
+	require.Len(t, crs, 1)
+	assert.NoError(t, crs[0].Error)
+	assert.Equal(t, 1.0, crs[0].Price.Price)
+
+	// Reconnecting must be abandoned after three attempts:
+	require.Eventually(t, func() bool {
+		return len(attempts()) == 3
+	}, time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	assert.Len(t, attempts(), 3)
+}
+
+func TestBinanceStream_MaxRetries_UnstableConnection(t *testing.T) {
+	// Every connection is dropped right after the subscription:
+	srv, attempts := newFlakyStreamServer(
+		t,
+		func(int) bool { return false },
+		func(int) bool { return true },
+	)
+	defer srv.Close()
+
+	pair := Pair{Base: "BTC", Quote: "USDT"}
+	b := NewBinanceStream("ws"+strings.TrimPrefix(srv.URL, "http"), nil, time.Minute)
+	b.Backoff = Backoff{Min: 10 * time.Millisecond, Factor: 1, MaxRetries: 3, Stable: time.Minute}
+	defer b.Close()
+	b.Start(context.Background())
+	b.PullPrices([]Pair{pair})
+
+	// Connections dropped before they become stable are failed attempts,
+	// so reconnecting must be abandoned:
+	require.Eventually(t, func() bool {
+		return len(attempts()) == 3
+	}, time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	assert.Len(t, attempts(), 3)
+}
+
+func TestBinanceStream_NotStarted(t *testing.T) {
+	srv, attempts := newFlakyStreamServer(
+		t,
+		func(int) bool { return false },
+		func(int) bool { return false },
+	)
+	defer srv.Close()
+
+	pool := query.NewMockWorkerPool()
+	pool.MockBody(`[{"symbol":"BTCUSDT","lastPrice":"1","bidPrice":"1","askPrice":"1","volume":"1","closeTime":1}]`)
+
+	pair := Pair{Base: "BTC", Quote: "USDT"}
+	b := NewBinanceStream("ws"+strings.TrimPrefix(srv.URL, "http"), Binance{WorkerPool: pool}, time.Minute)
+
+	// Without the Start method, the stream must not be connected:
+	crs := b.PullPrices([]Pair{pair})
+	require.Len(t, crs, 1)
+	assert.NoError(t, crs[0].Error)
+	assert.Equal(t, 1.0, crs[0].Price.Price)
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, attempts())
+
+	// After the stream is closed, it must not be reconnected:
+	b.Start(context.Background())
+	require.Eventually(t, func() bool {
+		_, _, ok := b.Latest(pair)
+		return ok
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, b.Close())
+	b.PullPrices([]Pair{pair})
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, attempts(), 1)
+}
+
+func TestBinanceStream_ContextCancel(t *testing.T) {
+	srv, attempts := newFlakyStreamServer(
+		t,
+		func(int) bool { return true },
+		func(int) bool { return false },
+	)
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b := NewBinanceStream("ws"+strings.TrimPrefix(srv.URL, "http"), nil, time.Minute)
+	b.Backoff = Backoff{Min: 10 * time.Millisecond, Factor: 1}
+	b.Start(ctx)
+
+	require.Eventually(t, func() bool {
+		return len(attempts()) >= 2
+	}, time.Second, 10*time.Millisecond)
+
+	// After the context is canceled, no more attempts are made:
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	n := len(attempts())
+	time.Sleep(100 * time.Millisecond)
+	assert.Len(t, attempts(), n)
+
+	// The stream stays disconnected, so streamed prices are not used:
+	crs := b.PullPrices([]Pair{{Base: "BTC", Quote: "USDT"}})
+	require.Len(t, crs, 1)
+	assert.ErrorIs(t, crs[0].Error, ErrStalePrice)
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, attempts(), n)
+}
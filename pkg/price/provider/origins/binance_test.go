@@ -91,6 +91,42 @@ func (suite *BinanceSuite) TestFailOnWrongInput() {
 	fr = suite.origin.Fetch([]Pair{pair})
 	suite.Error(fr[0].Error)
 
+	// Single ticker object instead of a list
+	resp = &query.HTTPResponse{
+		Body: []byte(`
+			{
+			   "symbol":"BTCETH",
+			   "lastPrice":"1",
+			   "bidPrice":"1",
+			   "askPrice":"1",
+			   "volume":"1",
+			   "closeTime":10000
+			}
+		`),
+	}
+	suite.origin.ExchangeHandler.(Binance).Pool().(*query.MockWorkerPool).MockResp(resp)
+	fr = suite.origin.Fetch([]Pair{pair})
+	suite.Error(fr[0].Error)
+
+	// Error during converting bid and ask prices to numbers
+	resp = &query.HTTPResponse{
+		Body: []byte(`
+			[
+			   {
+				  "symbol":"BTCETH",
+				  "lastPrice":"1",
+				  "bidPrice":"abc",
+				  "askPrice":"abc",
+				  "volume":"1",
+				  "closeTime":10000
+			   }
+			]
+		`),
+	}
+	suite.origin.ExchangeHandler.(Binance).Pool().(*query.MockWorkerPool).MockResp(resp)
+	fr = suite.origin.Fetch([]Pair{pair})
+	suite.Error(fr[0].Error)
+
 	// Unable to find a pair
 	resp = &query.HTTPResponse{
 		Body: []byte(`
@@ -17,7 +17,6 @@ package origins
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -120,7 +119,7 @@ func (*Bitfinex) parseTicker(tt []interface{}) bitfinexTicker {
 		case string:
 			t.Symbol = x
 			if i != 0 {
-				t.Error = errors.New("market symbol is not at index 0")
+				t.Error = ErrCall{Origin: "bitfinex", Message: "market symbol is not at index 0"}
 				return t
 			}
 			crc[i] = true
@@ -149,18 +148,18 @@ func (*Bitfinex) parseTicker(tt []interface{}) bitfinexTicker {
 			}
 			crc[i] = true
 		default:
-			t.Error = fmt.Errorf("item at index %d is unexpexted (Type: %T)", i, x)
+			t.Error = ErrCall{Origin: "bitfinex", Message: fmt.Sprintf("item at index %d is unexpected (Type: %T)", i, x)}
 			return t
 		}
 	}
 	expectedItemCount := 11
 	if len(crc) > expectedItemCount {
-		t.Error = fmt.Errorf("too many (%d) items", len(crc)+1)
+		t.Error = ErrCall{Origin: "bitfinex", Message: fmt.Sprintf("too many (%d) items", len(crc))}
 		return t
 	}
 	for i := 0; i <= 10; i++ {
 		if v, ok := crc[i]; !ok || !v {
-			t.Error = fmt.Errorf("item at index %d is missing", i)
+			t.Error = ErrCall{Origin: "bitfinex", Message: fmt.Sprintf("item at index %d is missing", i)}
 			return t
 		}
 	}
@@ -90,6 +90,16 @@ func (suite *BitfinexSuite) TestFailOnWrongInput() {
 	}
 }
 
+func (suite *BitfinexSuite) TestTruncatedTicker() {
+	pair := Pair{Base: "BTC", Quote: "ETH"}
+	resp := &query.HTTPResponse{
+		Body: []byte(`[["tBTCETH",1.01,1.02,1.03,1.04,1.05,1.06]]`),
+	}
+	suite.origin.ExchangeHandler.(Bitfinex).Pool().(*query.MockWorkerPool).MockResp(resp)
+	cr := suite.origin.Fetch([]Pair{pair})
+	suite.Equal(ErrCall{Origin: "bitfinex", Message: "item at index 7 is missing"}, cr[0].Error)
+}
+
 func (suite *BitfinexSuite) TestSuccessResponse() {
 	pair := Pair{Base: "BTC", Quote: "ETH"}
 	resp := &query.HTTPResponse{
@@ -0,0 +1,112 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package origins
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+)
+
+//go:embed chainlink_abi.json
+var chainlinkAggregatorABI string
+
+// Chainlink origin reads the latest answer from Chainlink aggregator
+// contracts. The answer is scaled by the number of decimals of the feed and
+// the time of the last update is used as the price timestamp.
+type Chainlink struct {
+	ethClient ethereum.Client
+	addrs     ContractAddresses
+	abi       abi.ABI
+}
+
+func NewChainlink(cli ethereum.Client, addrs ContractAddresses) (*Chainlink, error) {
+	a, err := abi.JSON(strings.NewReader(chainlinkAggregatorABI))
+	if err != nil {
+		return nil, err
+	}
+	return &Chainlink{
+		ethClient: cli,
+		addrs:     addrs,
+		abi:       a,
+	}, nil
+}
+
+func (s Chainlink) PullPrices(pairs []Pair) []FetchResult {
+	return callSinglePairOrigin(&s, pairs)
+}
+
+func (s Chainlink) callOne(pair Pair) (*Price, error) {
+	contract, inverted, err := s.addrs.AddressByPair(pair)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch the number of decimals of the feed
+	callData, err := s.abi.Pack("decimals")
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack contract args for pair: %s", pair.String())
+	}
+	resp, err := s.ethClient.Call(context.Background(), ethereum.Call{Address: contract, Data: callData})
+	if err != nil {
+		return nil, err
+	}
+	var decimals uint8
+	if err := s.abi.UnpackIntoInterface(&decimals, "decimals", resp); err != nil {
+		return nil, fmt.Errorf("failed to unpack decimals for pair %s: %w", pair.String(), err)
+	}
+
+	// Fetch the latest round
+	callData, err = s.abi.Pack("latestRoundData")
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack contract args for pair: %s", pair.String())
+	}
+	resp, err = s.ethClient.Call(context.Background(), ethereum.Call{Address: contract, Data: callData})
+	if err != nil {
+		return nil, err
+	}
+	round, err := s.abi.Unpack("latestRoundData", resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack latest round for pair %s: %w", pair.String(), err)
+	}
+	answer := round[1].(*big.Int)
+	updatedAt := round[3].(*big.Int)
+	if answer.Sign() <= 0 {
+		return nil, ErrInvalidPrice
+	}
+
+	// Scale the answer by the number of decimals
+	price := new(big.Float).Quo(
+		new(big.Float).SetInt(answer),
+		new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)),
+	)
+	if inverted {
+		price = new(big.Float).Quo(big.NewFloat(1), price)
+	}
+	priceFloat, _ := price.Float64()
+	return &Price{
+		Pair:      pair,
+		Price:     priceFloat,
+		Timestamp: time.Unix(updatedAt.Int64(), 0),
+	}, nil
+}
@@ -0,0 +1,134 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package origins
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	ethereumMocks "github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ChainlinkSuite struct {
+	suite.Suite
+	addresses ContractAddresses
+	client    *ethereumMocks.Client
+	chainlink *Chainlink
+	origin    *BaseExchangeHandler
+}
+
+func (suite *ChainlinkSuite) SetupSuite() {
+	suite.addresses = ContractAddresses{
+		"ETH/USD": "0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419",
+	}
+}
+
+func (suite *ChainlinkSuite) TearDownSuite() {
+	suite.addresses = nil
+}
+
+func (suite *ChainlinkSuite) SetupTest() {
+	var err error
+	suite.client = &ethereumMocks.Client{}
+	suite.chainlink, err = NewChainlink(suite.client, suite.addresses)
+	suite.NoError(err)
+	suite.origin = NewBaseExchangeHandler(*suite.chainlink, nil)
+}
+
+func (suite *ChainlinkSuite) TearDownTest() {
+	suite.client = nil
+	suite.chainlink = nil
+	suite.origin = nil
+}
+
+func (suite *ChainlinkSuite) Origin() Handler {
+	return suite.origin
+}
+
+func TestChainlinkSuite(t *testing.T) {
+	suite.Run(t, new(ChainlinkSuite))
+}
+
+func (suite *ChainlinkSuite) mockRound(decimals uint8, answer int64, updatedAt int64) {
+	decimalsResp, err := suite.chainlink.abi.Methods["decimals"].Outputs.Pack(decimals)
+	suite.Require().NoError(err)
+	roundResp, err := suite.chainlink.abi.Methods["latestRoundData"].Outputs.Pack(
+		big.NewInt(100),
+		big.NewInt(answer),
+		big.NewInt(updatedAt-10),
+		big.NewInt(updatedAt),
+		big.NewInt(100),
+	)
+	suite.Require().NoError(err)
+
+	contract := ethereum.HexToAddress("0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419")
+	suite.client.On(
+		"Call",
+		mock.Anything,
+		ethereum.Call{Address: contract, Data: ethereum.HexToBytes("0x313ce567")},
+	).Return(decimalsResp, nil).Once()
+	suite.client.On(
+		"Call",
+		mock.Anything,
+		ethereum.Call{Address: contract, Data: ethereum.HexToBytes("0xfeaf968c")},
+	).Return(roundResp, nil).Once()
+}
+
+func (suite *ChainlinkSuite) TestSuccessResponse() {
+	suite.mockRound(8, 184512345678, 1650000000)
+
+	results := suite.origin.Fetch([]Pair{{Base: "ETH", Quote: "USD"}})
+	suite.Require().NoError(results[0].Error)
+	suite.Equal(1845.12345678, results[0].Price.Price)
+	suite.Equal(int64(1650000000), results[0].Price.Timestamp.Unix())
+
+	suite.client.AssertNumberOfCalls(suite.T(), "Call", 2)
+}
+
+func (suite *ChainlinkSuite) TestSuccessResponse_Inverted() {
+	suite.mockRound(8, 200000000000, 1650000000)
+
+	results := suite.origin.Fetch([]Pair{{Base: "USD", Quote: "ETH"}})
+	suite.Require().NoError(results[0].Error)
+	suite.Equal(0.0005, results[0].Price.Price)
+	suite.Equal(int64(1650000000), results[0].Price.Timestamp.Unix())
+}
+
+func (suite *ChainlinkSuite) TestInvalidAnswer() {
+	suite.mockRound(8, 0, 1650000000)
+
+	results := suite.origin.Fetch([]Pair{{Base: "ETH", Quote: "USD"}})
+	suite.Equal(ErrInvalidPrice, results[0].Error)
+}
+
+func (suite *ChainlinkSuite) TestCallError() {
+	callErr := errors.New("call failed")
+	suite.client.On("Call", mock.Anything, mock.Anything).Return([]byte(nil), callErr).Once()
+
+	results := suite.origin.Fetch([]Pair{{Base: "ETH", Quote: "USD"}})
+	suite.Equal(callErr, results[0].Error)
+}
+
+func (suite *ChainlinkSuite) TestFailOnWrongPair() {
+	cr := suite.origin.Fetch([]Pair{{Base: "x", Quote: "y"}})
+	suite.Require().EqualError(cr[0].Error, "failed to get contract address for pair: x/y")
+}
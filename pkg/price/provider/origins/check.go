@@ -0,0 +1,35 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package origins
+
+import "fmt"
+
+// Check is the result of a connectivity check for a single pair fetched
+// from an origin.
+type Check struct {
+	Origin string
+	Pair   Pair
+	Price  float64
+	// Err is nil if the origin returned a valid price for the pair.
+	Err error
+}
+
+func (c Check) String() string {
+	if c.Err != nil {
+		return fmt.Sprintf("%s %s: error: %v", c.Origin, c.Pair, c.Err)
+	}
+	return fmt.Sprintf("%s %s: ok: %g", c.Origin, c.Pair, c.Price)
+}
@@ -0,0 +1,101 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package origins
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/query"
+)
+
+const cryptoComBaseURL = "https://api.crypto.com"
+const cryptoComURL = "%s/v2/public/get-ticker?instrument_name=%s"
+
+type cryptoComResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Result  struct {
+		InstrumentName string `json:"instrument_name"`
+		Data           *struct {
+			Latest    float64              `json:"a"`
+			BestBid   float64              `json:"b"`
+			BestAsk   float64              `json:"k"`
+			Volume24H float64              `json:"v"`
+			Timestamp intAsUnixTimestampMs `json:"t"`
+		} `json:"data"`
+	} `json:"result"`
+}
+
+// CryptoCom origin handler
+type CryptoCom struct {
+	WorkerPool query.WorkerPool
+	BaseURL    string
+}
+
+func (c CryptoCom) localPairName(pair Pair) string {
+	return fmt.Sprintf("%s_%s", pair.Base, pair.Quote)
+}
+
+func (c CryptoCom) Pool() query.WorkerPool {
+	return c.WorkerPool
+}
+
+func (c CryptoCom) PullPrices(pairs []Pair) []FetchResult {
+	return callSinglePairOrigin(&c, pairs)
+}
+
+func (c CryptoCom) callOne(pair Pair) (*Price, error) {
+	var err error
+	req := &query.HTTPRequest{
+		URL: buildOriginURL(cryptoComURL, c.BaseURL, cryptoComBaseURL, c.localPairName(pair)),
+	}
+
+	// make query
+	res := c.Pool().Query(req)
+	if res == nil {
+		return nil, ErrEmptyOriginResponse
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	// parse JSON
+	var resp cryptoComResponse
+	err = json.Unmarshal(res.Body, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Crypto.com response: %w", err)
+	}
+
+	if resp.Code != 0 {
+		return nil, ErrCall{Origin: "cryptocom", Code: strconv.Itoa(resp.Code), Message: resp.Message}
+	}
+
+	data := resp.Result.Data
+	if data == nil {
+		return nil, ErrMissingResponseForPair
+	}
+
+	return &Price{
+		Pair:      pair,
+		Price:     data.Latest,
+		Bid:       data.BestBid,
+		Ask:       data.BestAsk,
+		Volume24h: data.Volume24H,
+		Timestamp: data.Timestamp.val(),
+	}, nil
+}
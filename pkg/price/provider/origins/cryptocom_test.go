@@ -0,0 +1,151 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package origins
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/query"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CryptoComSuite struct {
+	suite.Suite
+	origin *BaseExchangeHandler
+}
+
+func (suite *CryptoComSuite) Origin() Handler {
+	return suite.origin
+}
+
+func (suite *CryptoComSuite) SetupSuite() {
+	suite.origin = NewBaseExchangeHandler(CryptoCom{WorkerPool: query.NewMockWorkerPool()}, nil)
+}
+
+func (suite *CryptoComSuite) TearDownTest() {
+	suite.origin.ExchangeHandler.(CryptoCom).Pool().(*query.MockWorkerPool).MockResp(nil)
+}
+
+func (suite *CryptoComSuite) TestLocalPair() {
+	ex := suite.origin.ExchangeHandler.(CryptoCom)
+	suite.EqualValues("BTC_ETH", ex.localPairName(Pair{Base: "BTC", Quote: "ETH"}))
+	suite.EqualValues("ETH_USDT", ex.localPairName(Pair{Base: "ETH", Quote: "USDT"}))
+}
+
+func (suite *CryptoComSuite) TestFailOnWrongInput() {
+	pair := Pair{Base: "BTC", Quote: "ETH"}
+
+	// Wrong pair
+	fr := suite.origin.Fetch([]Pair{{}})
+	suite.Error(fr[0].Error)
+
+	// Nil as a response
+	fr = suite.origin.Fetch([]Pair{pair})
+	suite.Equal(ErrEmptyOriginResponse, fr[0].Error)
+
+	// Error in a response
+	ourErr := fmt.Errorf("error")
+	resp := &query.HTTPResponse{
+		Error: ourErr,
+	}
+	suite.origin.ExchangeHandler.(CryptoCom).Pool().(*query.MockWorkerPool).MockResp(resp)
+	fr = suite.origin.Fetch([]Pair{pair})
+	suite.Equal(ourErr, fr[0].Error)
+
+	// Error during unmarshalling
+	resp = &query.HTTPResponse{
+		Body: []byte(""),
+	}
+	suite.origin.ExchangeHandler.(CryptoCom).Pool().(*query.MockWorkerPool).MockResp(resp)
+	fr = suite.origin.Fetch([]Pair{pair})
+	suite.Error(fr[0].Error)
+
+	// Price is not a number
+	resp = &query.HTTPResponse{
+		Body: []byte(`
+			{
+				"code":0,
+				"method":"public/get-ticker",
+				"result":{
+					"instrument_name":"BTC_ETH",
+					"data":{"i":"BTC_ETH","a":"abc","b":1.1,"k":1.3,"v":10.5,"t":1613580710768}
+				}
+			}
+		`),
+	}
+	suite.origin.ExchangeHandler.(CryptoCom).Pool().(*query.MockWorkerPool).MockResp(resp)
+	fr = suite.origin.Fetch([]Pair{pair})
+	suite.Error(fr[0].Error)
+
+	// Non-zero response code
+	resp = &query.HTTPResponse{
+		Body: []byte(`{"code":10004,"method":"public/get-ticker","message":"BAD_REQUEST"}`),
+	}
+	suite.origin.ExchangeHandler.(CryptoCom).Pool().(*query.MockWorkerPool).MockResp(resp)
+	fr = suite.origin.Fetch([]Pair{pair})
+	suite.Equal(ErrCall{Origin: "cryptocom", Code: "10004", Message: "BAD_REQUEST"}, fr[0].Error)
+
+	// Missing data
+	resp = &query.HTTPResponse{
+		Body: []byte(`{"code":0,"method":"public/get-ticker","result":{"instrument_name":"BTC_ETH"}}`),
+	}
+	suite.origin.ExchangeHandler.(CryptoCom).Pool().(*query.MockWorkerPool).MockResp(resp)
+	fr = suite.origin.Fetch([]Pair{pair})
+	suite.Equal(ErrMissingResponseForPair, fr[0].Error)
+}
+
+func (suite *CryptoComSuite) TestSuccessResponse() {
+	pair := Pair{Base: "BTC", Quote: "ETH"}
+
+	resp := &query.HTTPResponse{
+		Body: []byte(`
+			{
+				"code":0,
+				"method":"public/get-ticker",
+				"result":{
+					"instrument_name":"BTC_ETH",
+					"data":{"i":"BTC_ETH","a":1.2,"b":1.1,"k":1.3,"v":10.5,"h":1.5,"l":1.0,"t":1613580710768}
+				}
+			}
+		`),
+	}
+	suite.origin.ExchangeHandler.(CryptoCom).Pool().(*query.MockWorkerPool).MockResp(resp)
+	fr := suite.origin.Fetch([]Pair{pair})
+
+	suite.Len(fr, 1)
+	suite.NoError(fr[0].Error)
+	suite.Equal(pair, fr[0].Price.Pair)
+	suite.Equal(1.2, fr[0].Price.Price)
+	suite.Equal(1.1, fr[0].Price.Bid)
+	suite.Equal(1.3, fr[0].Price.Ask)
+	suite.Equal(10.5, fr[0].Price.Volume24h)
+	suite.Equal(int64(1613580710), fr[0].Price.Timestamp.Unix())
+}
+
+func (suite *CryptoComSuite) TestRealAPICall() {
+	testRealAPICall(
+		suite,
+		NewBaseExchangeHandler(CryptoCom{WorkerPool: query.NewHTTPWorkerPool(1)}, nil),
+		"ETH",
+		"USDT",
+	)
+}
+
+func TestCryptoComSuite(t *testing.T) {
+	suite.Run(t, new(CryptoComSuite))
+}
@@ -26,7 +26,6 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/params"
 
 	pkgEthereum "github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 )
@@ -34,27 +33,69 @@ import (
 //go:embed curve_abi.json
 var curvePoolABI string
 
+const curveDefaultDecimals = 18
+
+// CurveCoins describes the coins of a Curve pool used for a pair. The first
+// elements of Indices and Decimals refer to the base asset of the pair, and
+// the second ones to the quote asset.
+type CurveCoins struct {
+	// Indices are the indices of coins in the pool (default: 0 and 1).
+	Indices [2]int
+	// Decimals are the numbers of decimals of coins. Zero values are
+	// replaced with 18.
+	Decimals [2]int
+}
+
+// CurvePoolCoins maps pair names, as used in ContractAddresses, to coins of
+// the pool. Pairs without an entry use the first two coins of the pool with
+// 18 decimals.
+type CurvePoolCoins map[string]CurveCoins
+
+func (c CurvePoolCoins) byPair(pair Pair, inverted bool) (baseIndex, quoteIndex, baseDecimals, quoteDecimals int) {
+	name := fmt.Sprintf("%s/%s", pair.Base, pair.Quote)
+	if inverted {
+		name = fmt.Sprintf("%s/%s", pair.Quote, pair.Base)
+	}
+	coins, ok := c[name]
+	if !ok {
+		coins = CurveCoins{Indices: [2]int{0, 1}}
+	}
+	for i, d := range coins.Decimals {
+		if d == 0 {
+			coins.Decimals[i] = curveDefaultDecimals
+		}
+	}
+	if inverted {
+		return coins.Indices[1], coins.Indices[0], coins.Decimals[1], coins.Decimals[0]
+	}
+	return coins.Indices[0], coins.Indices[1], coins.Decimals[0], coins.Decimals[1]
+}
+
 type CurveFinance struct {
-	ethClient                 pkgEthereum.Client
-	addrs                     ContractAddresses
-	abi                       abi.ABI
-	baseIndex, quoteIndex, dx *big.Int
-	blocks                    []int64
+	ethClient pkgEthereum.Client
+	addrs     ContractAddresses
+	coins     CurvePoolCoins
+	abi       abi.ABI
+	blocks    []int64
 }
 
-func NewCurveFinance(cli pkgEthereum.Client, addrs ContractAddresses, blocks []int64) (*CurveFinance, error) {
+func NewCurveFinance(
+	cli pkgEthereum.Client,
+	addrs ContractAddresses,
+	coins CurvePoolCoins,
+	blocks []int64,
+) (*CurveFinance, error) {
+
 	a, err := abi.JSON(strings.NewReader(curvePoolABI))
 	if err != nil {
 		return nil, err
 	}
 	return &CurveFinance{
-		ethClient:  cli,
-		addrs:      addrs,
-		abi:        a,
-		baseIndex:  big.NewInt(0),
-		quoteIndex: big.NewInt(1),
-		dx:         new(big.Int).Mul(big.NewInt(1), big.NewInt(params.Ether)),
-		blocks:     blocks,
+		ethClient: cli,
+		addrs:     addrs,
+		coins:     coins,
+		abi:       a,
+		blocks:    blocks,
 	}, nil
 }
 
@@ -73,24 +114,29 @@ func (s CurveFinance) PullPrices(pairs []Pair) []FetchResult {
 		return pairs[i].String() < pairs[j].String()
 	})
 	var (
-		frs []FetchResult
-		cds []pkgEthereum.Call
+		frs      []FetchResult
+		cds      []pkgEthereum.Call
+		decimals []int
 	)
 	for _, pair := range pairs {
 		contract, inverted, err := s.pairsToContractAddress(pair)
 		if err != nil {
 			return fetchResultListWithErrors(pairs, err)
 		}
-		var callData []byte
-		if !inverted {
-			callData, err = s.abi.Pack("get_dy", s.baseIndex, s.quoteIndex, s.dx)
-		} else {
-			callData, err = s.abi.Pack("get_dy", s.quoteIndex, s.baseIndex, s.dx)
-		}
+		// The exchange rate is the amount of the quote coin received for
+		// a single unit of the base coin.
+		baseIndex, quoteIndex, baseDecimals, quoteDecimals := s.coins.byPair(pair, inverted)
+		callData, err := s.abi.Pack(
+			"get_dy",
+			big.NewInt(int64(baseIndex)),
+			big.NewInt(int64(quoteIndex)),
+			pow10(baseDecimals),
+		)
 		if err != nil {
 			return fetchResultListWithErrors(pairs, err)
 		}
 		cds = append(cds, pkgEthereum.Call{Address: contract, Data: callData})
+		decimals = append(decimals, quoteDecimals)
 	}
 	blockNumber, err := s.ethClient.BlockNumber(context.Background())
 	if err != nil {
@@ -118,7 +164,11 @@ func (s CurveFinance) PullPrices(pairs []Pair) []FetchResult {
 		}
 	}
 	for i, pair := range pairs {
-		price, _ := reduceEtherAverageFloat(resps[i]).Float64()
+		// The reduceEtherAverageFloat function assumes 18 decimals.
+		price, _ := new(big.Float).Quo(
+			new(big.Float).Mul(reduceEtherAverageFloat(resps[i]), new(big.Float).SetInt(pow10(curveDefaultDecimals))),
+			new(big.Float).SetInt(pow10(decimals[i])),
+		).Float64()
 		frs = append(frs, FetchResult{
 			Price: Price{
 				Pair:      pair,
@@ -129,3 +179,7 @@ func (s CurveFinance) PullPrices(pairs []Pair) []FetchResult {
 	}
 	return frs
 }
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
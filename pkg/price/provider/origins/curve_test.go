@@ -38,6 +38,7 @@ type CurveSuite struct {
 func (suite *CurveSuite) SetupSuite() {
 	suite.addresses = ContractAddresses{
 		"ETH/STETH": "0xDC24316b9AE028F1497c275EB9192a3Ea0f67022",
+		"USDC/USDT": "0xbEbc44782C7dB0a1A60Cb6fe97d0b483032FF1C7",
 	}
 }
 func (suite *CurveSuite) TearDownSuite() {
@@ -46,7 +47,10 @@ func (suite *CurveSuite) TearDownSuite() {
 
 func (suite *CurveSuite) SetupTest() {
 	suite.client = &ethereumMocks.Client{}
-	o, err := NewCurveFinance(suite.client, suite.addresses, []int64{0, 10, 20})
+	coins := CurvePoolCoins{
+		"USDC/USDT": {Indices: [2]int{1, 2}, Decimals: [2]int{6, 6}},
+	}
+	o, err := NewCurveFinance(suite.client, suite.addresses, coins, []int64{0, 10, 20})
 	suite.NoError(err)
 	suite.origin = NewBaseExchangeHandler(o, nil)
 }
@@ -158,6 +162,54 @@ func (suite *CurveSuite) TestSuccessResponse_Inverse() {
 	suite.Greater(results2[0].Price.Timestamp.Unix(), int64(0))
 }
 
+func (suite *CurveSuite) TestSuccessResponse_CoinIndices() {
+	suite.client.On(
+		"BlockNumber",
+		mock.Anything,
+	).Return(big.NewInt(100), nil).Once()
+
+	// get_dy(1, 2, 1e6)
+	call := []ethereum.Call{{
+		Address: ethereum.HexToAddress("0xbEbc44782C7dB0a1A60Cb6fe97d0b483032FF1C7"),
+		Data: ethereum.HexToBytes("0x5e0d443f" +
+			"0000000000000000000000000000000000000000000000000000000000000001" +
+			"0000000000000000000000000000000000000000000000000000000000000002" +
+			"00000000000000000000000000000000000000000000000000000000000f4240"),
+	}}
+	for _, dy := range []int64{999000, 1000000, 1001000} {
+		suite.client.On("MultiCall", mock.Anything, call).
+			Return([][]byte{common.BigToHash(big.NewInt(dy)).Bytes()}, nil).Once()
+	}
+
+	results := suite.origin.Fetch([]Pair{{Base: "USDC", Quote: "USDT"}})
+	suite.Require().NoError(results[0].Error)
+	suite.InDelta(1.0, results[0].Price.Price, 1e-9)
+
+	suite.client.AssertNumberOfCalls(suite.T(), "MultiCall", 3)
+}
+
+func (suite *CurveSuite) TestSuccessResponse_CoinIndicesInverse() {
+	suite.client.On(
+		"BlockNumber",
+		mock.Anything,
+	).Return(big.NewInt(100), nil).Once()
+
+	// get_dy(2, 1, 1e6)
+	call := []ethereum.Call{{
+		Address: ethereum.HexToAddress("0xbEbc44782C7dB0a1A60Cb6fe97d0b483032FF1C7"),
+		Data: ethereum.HexToBytes("0x5e0d443f" +
+			"0000000000000000000000000000000000000000000000000000000000000002" +
+			"0000000000000000000000000000000000000000000000000000000000000001" +
+			"00000000000000000000000000000000000000000000000000000000000f4240"),
+	}}
+	suite.client.On("MultiCall", mock.Anything, call).
+		Return([][]byte{common.BigToHash(big.NewInt(998000)).Bytes()}, nil).Times(3)
+
+	results := suite.origin.Fetch([]Pair{{Base: "USDT", Quote: "USDC"}})
+	suite.Require().NoError(results[0].Error)
+	suite.InDelta(0.998, results[0].Price.Price, 1e-9)
+}
+
 func (suite *CurveSuite) TestFailOnWrongPair() {
 	pair := Pair{Base: "x", Quote: "y"}
 	cr := suite.origin.Fetch([]Pair{pair})
@@ -25,3 +25,18 @@ var ErrMissingResponseForPair = fmt.Errorf("no response for pair from origin")
 var ErrInvalidResponseStatus = fmt.Errorf("invalid response status from origin")
 var ErrInvalidPrice = fmt.Errorf("invalid price from origin")
 var ErrUnknownOrigin = errors.New("unknown origin")
+
+// ErrCall is returned when an origin responds with an error, or with
+// a response that has an unexpected structure.
+type ErrCall struct {
+	Origin  string
+	Code    string
+	Message string
+}
+
+func (e ErrCall) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("%s origin call failed: %s", e.Origin, e.Message)
+	}
+	return fmt.Sprintf("%s origin call failed with code %s: %s", e.Origin, e.Code, e.Message)
+}
@@ -33,6 +33,10 @@ type geminiResponse struct {
 	Price string `json:"last"`
 	Ask   string `json:"ask"`
 	Bid   string `json:"bid"`
+	// Volume contains the volume for both assets keyed by their symbols
+	// and the timestamp of the volume in milliseconds, e.g.:
+	// {"BTC": "2.5", "USD": "100000", "timestamp": 1483018200000}
+	Volume map[string]json.RawMessage `json:"volume"`
 }
 
 // Gemini origin handler
@@ -92,12 +96,44 @@ func (g *Gemini) callOne(pair Pair) (*Price, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse bid from gemini origin %s", res.Body)
 	}
+	// Parsing volume and timestamp, which are nested under asset symbols
+	volume, ts, err := g.parseVolume(pair, resp.Volume)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse volume from gemini origin %s", res.Body)
+	}
 	// building Price
 	return &Price{
 		Pair:      pair,
 		Price:     price,
 		Ask:       ask,
 		Bid:       bid,
-		Timestamp: time.Now(),
+		Volume24h: volume,
+		Timestamp: ts,
 	}, nil
 }
+
+// parseVolume returns the base asset volume and the volume timestamp from the
+// volume object. If the volume or timestamp are missing, zero and the current
+// time are returned respectively.
+func (g *Gemini) parseVolume(pair Pair, v map[string]json.RawMessage) (float64, time.Time, error) {
+	var err error
+	var volume float64
+	ts := time.Now()
+	if raw, ok := v[strings.ToUpper(pair.Base)]; ok {
+		var str string
+		if err = json.Unmarshal(raw, &str); err != nil {
+			return 0, ts, err
+		}
+		if volume, err = strconv.ParseFloat(str, 64); err != nil {
+			return 0, ts, err
+		}
+	}
+	if raw, ok := v["timestamp"]; ok {
+		var ms int64
+		if err = json.Unmarshal(raw, &ms); err != nil {
+			return 0, ts, err
+		}
+		ts = time.Unix(0, ms*int64(time.Millisecond))
+	}
+	return volume, ts, nil
+}
@@ -105,6 +105,22 @@ func (suite *GeminiSuite) TestFailOnWrongInput() {
 	suite.origin.ExchangeHandler.(Gemini).Pool().(*query.MockWorkerPool).MockResp(resp)
 	cr = suite.origin.Fetch([]Pair{pair})
 	suite.Error(cr[0].Error)
+
+	// Error parsing volume
+	resp = &query.HTTPResponse{
+		Body: []byte(`{"last":"1","ask":"1","bid":"1","volume":{"BTC":"abc"}}`),
+	}
+	suite.origin.ExchangeHandler.(Gemini).Pool().(*query.MockWorkerPool).MockResp(resp)
+	cr = suite.origin.Fetch([]Pair{pair})
+	suite.Error(cr[0].Error)
+
+	// Error parsing volume timestamp
+	resp = &query.HTTPResponse{
+		Body: []byte(`{"last":"1","ask":"1","bid":"1","volume":{"BTC":"1","timestamp":"abc"}}`),
+	}
+	suite.origin.ExchangeHandler.(Gemini).Pool().(*query.MockWorkerPool).MockResp(resp)
+	cr = suite.origin.Fetch([]Pair{pair})
+	suite.Error(cr[0].Error)
 }
 
 func (suite *GeminiSuite) TestSuccessResponse() {
@@ -121,6 +137,24 @@ func (suite *GeminiSuite) TestSuccessResponse() {
 	suite.Greater(cr[0].Price.Timestamp.Unix(), int64(0))
 }
 
+func (suite *GeminiSuite) TestSuccessResponseWithVolume() {
+	pair := Pair{Base: "ETH", Quote: "USD"}
+	resp := &query.HTTPResponse{
+		Body: []byte(`{
+			"last":"1000",
+			"ask":"1001",
+			"bid":"999",
+			"volume":{"ETH":"12.5","USD":"12500","timestamp":1483018200000}
+		}`),
+	}
+	suite.origin.ExchangeHandler.(Gemini).Pool().(*query.MockWorkerPool).MockResp(resp)
+	cr := suite.origin.Fetch([]Pair{pair})
+	suite.NoError(cr[0].Error)
+	suite.Equal(1000.0, cr[0].Price.Price)
+	suite.Equal(12.5, cr[0].Price.Volume24h)
+	suite.Equal(int64(1483018200), cr[0].Price.Timestamp.Unix())
+}
+
 func (suite *GeminiSuite) TestRealAPICall() {
 	testRealAPICall(
 		suite,
@@ -30,6 +30,7 @@ const huobiURL = "%s/market/tickers"
 
 type huobiResponse struct {
 	Symbol string  `json:"symbol"`
+	Close  float64 `json:"close"`
 	Volume float64 `json:"vol"`
 	Bid    float64 `json:"bid"`
 	Ask    float64 `json:"ask"`
@@ -94,9 +95,15 @@ func (h *Huobi) fetch(pairs []Pair) ([]FetchResult, error) {
 	frs := make([]FetchResult, len(pairs))
 	for i, p := range pairs {
 		if t, has := respMap[h.localPairName(p)]; has {
+			// The close field contains the last trade price. If it is
+			// missing, the mid-price is used instead.
+			price := t.Close
+			if price == 0 {
+				price = (t.Ask + t.Bid) / 2
+			}
 			frs[i] = fetchResult(Price{
 				Pair:      p,
-				Price:     (t.Ask + t.Bid) / 2,
+				Price:     price,
 				Ask:       t.Ask,
 				Bid:       t.Bid,
 				Volume24h: t.Volume,
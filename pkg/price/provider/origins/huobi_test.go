@@ -128,6 +128,22 @@ func (suite *HuobiSuite) TestSuccessResponse() {
 	suite.Equal(1.0, cr[0].Price.Ask)
 	suite.Equal(2.1, cr[0].Price.Bid)
 	suite.Equal(cr[0].Price.Timestamp.Unix(), int64(2))
+	suite.Equal(1.55, cr[0].Price.Price)
+}
+
+func (suite *HuobiSuite) TestSuccessResponseWithClosePrice() {
+	pair := Pair{Base: "ETH", Quote: "USDT"}
+	resp := &query.HTTPResponse{
+		Body: []byte(`{"status":"ok","ts":2000,"data":[{"symbol":"ethusdt","close":1.5,"ask":1.6,"bid":1.4,"vol":3}]}`),
+	}
+	suite.origin.ExchangeHandler.(Huobi).Pool().(*query.MockWorkerPool).MockResp(resp)
+	cr := suite.origin.Fetch([]Pair{pair})
+
+	suite.NoError(cr[0].Error)
+	suite.Equal(1.5, cr[0].Price.Price)
+	suite.Equal(1.6, cr[0].Price.Ask)
+	suite.Equal(1.4, cr[0].Price.Bid)
+	suite.Equal(3.0, cr[0].Price.Volume24h)
 }
 
 func (suite *HuobiSuite) TestRealAPICall() {
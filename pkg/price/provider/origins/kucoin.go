@@ -21,15 +21,22 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/query"
 )
 
 // Kucoin URL
 const kucoinBaseURL = "https://api.kucoin.com"
 const kucoinURL = "%s/api/v1/market/orderbook/level1?symbol=%s"
+const kucoinStatsURL = "%s/api/v1/market/stats?symbol=%s"
+
+// kucoinSuccessCode is the code returned by Kucoin for successful requests.
+const kucoinSuccessCode = "200000"
 
 type kucoinResponse struct {
 	Code string `json:"code"`
+	Msg  string `json:"msg"`
 	Data struct {
 		Time    int64  `json:"time"`
 		Price   string `json:"price"`
@@ -38,10 +45,21 @@ type kucoinResponse struct {
 	} `json:"data"`
 }
 
+type kucoinStatsResponse struct {
+	Code string `json:"code"`
+	Msg  string `json:"msg"`
+	Data struct {
+		Vol string `json:"vol"`
+	} `json:"data"`
+}
+
 // Kucoin origin handler
 type Kucoin struct {
 	WorkerPool query.WorkerPool
 	BaseURL    string
+	// Logger is used to report failed volume requests. If nil, they are
+	// not reported.
+	Logger log.Logger
 }
 
 func (k *Kucoin) localPairName(pair Pair) string {
@@ -52,6 +70,17 @@ func (k *Kucoin) getURL(pair Pair) string {
 	return buildOriginURL(kucoinURL, k.BaseURL, kucoinBaseURL, k.localPairName(pair))
 }
 
+func (k *Kucoin) getStatsURL(pair Pair) string {
+	return buildOriginURL(kucoinStatsURL, k.BaseURL, kucoinBaseURL, k.localPairName(pair))
+}
+
+func (k *Kucoin) logger() log.Logger {
+	if k.Logger == nil {
+		return null.New()
+	}
+	return k.Logger
+}
+
 func (k Kucoin) Pool() query.WorkerPool {
 	return k.WorkerPool
 }
@@ -80,6 +109,9 @@ func (k *Kucoin) callOne(pair Pair) (*Price, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse kucoin response: %w", err)
 	}
+	if resp.Code != kucoinSuccessCode {
+		return nil, ErrCall{Origin: "kucoin", Code: resp.Code, Message: resp.Msg}
+	}
 	// Parsing price from string
 	price, err := strconv.ParseFloat(resp.Data.Price, 64)
 	if err != nil {
@@ -95,13 +127,54 @@ func (k *Kucoin) callOne(pair Pair) (*Price, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse bid from kucoin origin %s", res.Body)
 	}
-	// Parsing volume from string
+	// The level1 endpoint does not return volume, so it is fetched
+	// separately. The volume is not required to calculate the price, so
+	// if it is not available, the price is returned without it.
+	volume, err := k.callVolume(pair)
+	if err != nil {
+		k.logger().
+			WithError(err).
+			WithField("pair", pair.String()).
+			Warn("Unable to fetch the volume from Kucoin, the price is returned without it")
+		volume = 0
+	}
 	// building Price
 	return &Price{
 		Pair:      pair,
 		Timestamp: time.Unix(resp.Data.Time/1000, 0),
 		Price:     price,
-		Ask:       bid,
-		Bid:       ask,
+		Ask:       ask,
+		Bid:       bid,
+		Volume24h: volume,
 	}, nil
 }
+
+func (k *Kucoin) callVolume(pair Pair) (float64, error) {
+	req := &query.HTTPRequest{
+		URL: k.getStatsURL(pair),
+	}
+
+	// make query
+	res := k.Pool().Query(req)
+	if res == nil {
+		return 0, ErrEmptyOriginResponse
+	}
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	// parsing JSON
+	var resp kucoinStatsResponse
+	err := json.Unmarshal(res.Body, &resp)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse kucoin stats response: %w", err)
+	}
+	if resp.Code != kucoinSuccessCode {
+		return 0, ErrCall{Origin: "kucoin", Code: resp.Code, Message: resp.Msg}
+	}
+	// Parsing volume from string
+	volume, err := strconv.ParseFloat(resp.Data.Vol, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse volume from kucoin origin %s", res.Body)
+	}
+	return volume, nil
+}
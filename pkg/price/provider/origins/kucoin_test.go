@@ -19,6 +19,8 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/callback"
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/query"
 
 	"github.com/stretchr/testify/suite"
@@ -47,6 +49,7 @@ func (suite *KucoinSuite) TearDownTest() {
 	if suite.pool != nil {
 		suite.pool = nil
 	}
+	suite.origin.ExchangeHandler.(Kucoin).Pool().(*query.MockWorkerPool).MockResp(nil)
 }
 
 func (suite *KucoinSuite) TestLocalPair() {
@@ -148,17 +151,68 @@ func (suite *KucoinSuite) TestSuccessResponse() {
 				"bestBid":"1.2",
 				"bestBidSize": "0.2866",
 				"bestAsk":"1.3",
-				"bestAskSize":"0.2863"
+				"bestAskSize":"0.2863",
+				"vol":"10.5"
 			}
 		}`),
 	}
+	var urls []string
+	pool := suite.origin.ExchangeHandler.(Kucoin).Pool().(*query.MockWorkerPool)
+	pool.SetRequestAssertions(func(req *query.HTTPRequest) {
+		urls = append(urls, req.URL)
+	})
+	defer pool.SetRequestAssertions(nil)
 	suite.origin.ExchangeHandler.(Kucoin).Pool().(*query.MockWorkerPool).MockResp(resp)
 	cr := suite.origin.Fetch([]Pair{pair})
 	suite.NoError(cr[0].Error)
 	suite.Equal(int64(1596632420), cr[0].Price.Timestamp.Unix())
 	suite.Equal(1.23, cr[0].Price.Price)
-	suite.Equal(1.3, cr[0].Price.Bid)
-	suite.Equal(1.2, cr[0].Price.Ask)
+	suite.Equal(1.2, cr[0].Price.Bid)
+	suite.Equal(1.3, cr[0].Price.Ask)
+	suite.Equal(10.5, cr[0].Price.Volume24h)
+	suite.Equal([]string{
+		"https://api.kucoin.com/api/v1/market/orderbook/level1?symbol=BTC-ETH",
+		"https://api.kucoin.com/api/v1/market/stats?symbol=BTC-ETH",
+	}, urls)
+}
+
+func (suite *KucoinSuite) TestVolumeError() {
+	pair := Pair{Base: "BTC", Quote: "ETH"}
+	resp := &query.HTTPResponse{
+		Body: []byte(`{
+			"code":"200000",
+			"data": {
+				"time":1596632420791,
+				"price":"1.23",
+				"bestBid":"1.2",
+				"bestAsk":"1.3",
+				"vol":"err"
+			}
+		}`),
+	}
+	var msgs []string
+	logger := callback.New(log.Warn, func(_ log.Level, _ log.Fields, msg string) {
+		msgs = append(msgs, msg)
+	})
+	origin := NewBaseExchangeHandler(Kucoin{WorkerPool: query.NewMockWorkerPool(), Logger: logger}, nil)
+	origin.ExchangeHandler.(Kucoin).Pool().(*query.MockWorkerPool).MockResp(resp)
+
+	// A failed volume request should not fail the whole price:
+	cr := origin.Fetch([]Pair{pair})
+	suite.NoError(cr[0].Error)
+	suite.Equal(1.23, cr[0].Price.Price)
+	suite.Equal(0.0, cr[0].Price.Volume24h)
+	suite.Len(msgs, 1)
+}
+
+func (suite *KucoinSuite) TestEnvelopeError() {
+	pair := Pair{Base: "BTC", Quote: "ETH"}
+	resp := &query.HTTPResponse{
+		Body: []byte(`{"code":"400100","msg":"This pair is not provided at present"}`),
+	}
+	suite.origin.ExchangeHandler.(Kucoin).Pool().(*query.MockWorkerPool).MockResp(resp)
+	cr := suite.origin.Fetch([]Pair{pair})
+	suite.Equal(ErrCall{Origin: "kucoin", Code: "400100", Message: "This pair is not provided at present"}, cr[0].Error)
 }
 
 func (suite *KucoinSuite) TestRealAPICall() {
@@ -81,7 +81,7 @@ func (o Okx) callOne(pair Pair) (*Price, error) {
 	}
 
 	if resp.Code != "0" {
-		return nil, fmt.Errorf("okx response code is invalid: %s", resp.Code)
+		return nil, ErrCall{Origin: "okx", Code: resp.Code, Message: resp.Msg}
 	}
 
 	if len(resp.Data) != 1 {
@@ -95,6 +95,22 @@ func (suite *OkxSuite) TestFailOnWrongInput() {
 	suite.origin.ExchangeHandler.(Okx).Pool().(*query.MockWorkerPool).MockResp(resp)
 	fr = suite.origin.Fetch([]Pair{pair})
 	suite.Error(fr[0].Error)
+
+	// Non-zero response code
+	resp = &query.HTTPResponse{
+		Body: []byte(`{"code":"51001","msg":"Instrument ID does not exist","data":[]}`),
+	}
+	suite.origin.ExchangeHandler.(Okx).Pool().(*query.MockWorkerPool).MockResp(resp)
+	fr = suite.origin.Fetch([]Pair{pair})
+	suite.Equal(ErrCall{Origin: "okx", Code: "51001", Message: "Instrument ID does not exist"}, fr[0].Error)
+
+	// Empty data
+	resp = &query.HTTPResponse{
+		Body: []byte(`{"code":"0","msg":"","data":[]}`),
+	}
+	suite.origin.ExchangeHandler.(Okx).Pool().(*query.MockWorkerPool).MockResp(resp)
+	fr = suite.origin.Fetch([]Pair{pair})
+	suite.Equal(ErrMissingResponseForPair, fr[0].Error)
 }
 
 func (suite *OkxSuite) TestSuccessResponse() {
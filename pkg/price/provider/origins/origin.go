@@ -16,7 +16,9 @@
 package origins
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"math/big"
 	"sync"
 	"time"
@@ -69,6 +71,28 @@ func (h BaseExchangeHandler) Fetch(pairs []Pair) []FetchResult {
 	return results
 }
 
+// Start starts the underlying handler if it maintains background
+// connections.
+func (h BaseExchangeHandler) Start(ctx context.Context) {
+	if s, ok := h.ExchangeHandler.(starter); ok {
+		s.Start(ctx)
+	}
+}
+
+// Close closes the underlying handler if it holds any resources.
+func (h BaseExchangeHandler) Close() error {
+	if c, ok := h.ExchangeHandler.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// starter is implemented by handlers that maintain background connections,
+// like streaming origins.
+type starter interface {
+	Start(ctx context.Context)
+}
+
 type ContractAddresses map[string]string
 
 func (c ContractAddresses) ByPair(p Pair) (string, bool, bool) {
@@ -162,6 +186,12 @@ type Price struct {
 	Timestamp time.Time
 }
 
+// OriginPairs is a list of pairs fetched from an origin.
+type OriginPairs struct {
+	Origin string
+	Pairs  []Pair
+}
+
 type FetchResult struct {
 	Price Price
 	Error error
@@ -218,6 +248,31 @@ func (e *Set) Handlers() map[string]Handler {
 	return c
 }
 
+// Start starts handlers that maintain background connections, like
+// streaming origins. They are stopped when the context is canceled or
+// the Close method is called.
+func (e *Set) Start(ctx context.Context) {
+	for _, h := range e.list {
+		if s, ok := h.(starter); ok {
+			s.Start(ctx)
+		}
+	}
+}
+
+// Close closes all handlers that hold resources, like open connections.
+// The Set must not be used after it is closed.
+func (e *Set) Close() error {
+	var err error
+	for name, h := range e.list {
+		if c, ok := h.(io.Closer); ok {
+			if cerr := c.Close(); cerr != nil && err == nil {
+				err = fmt.Errorf("unable to close %s origin: %w", name, cerr)
+			}
+		}
+	}
+	return err
+}
+
 // Fetch makes handler fetch using handlers from the Set structure.
 func (e *Set) Fetch(originPairs map[string][]Pair) map[string][]FetchResult {
 	var mu sync.Mutex
@@ -263,6 +318,7 @@ func DefaultOriginSet(pool query.WorkerPool) *Set {
 		"coinbase":      NewBaseExchangeHandler(CoinbasePro{WorkerPool: pool}, nil),
 		"coinbasepro":   NewBaseExchangeHandler(CoinbasePro{WorkerPool: pool}, nil),
 		"cryptocompare": NewBaseExchangeHandler(CryptoCompare{WorkerPool: pool}, nil),
+		"cryptocom":     NewBaseExchangeHandler(CryptoCom{WorkerPool: pool}, nil),
 		"ddex":          NewBaseExchangeHandler(Ddex{WorkerPool: pool}, nil),
 		"folgory":       NewBaseExchangeHandler(Folgory{WorkerPool: pool}, nil),
 		"gateio":        NewBaseExchangeHandler(Gateio{WorkerPool: pool}, nil),
@@ -283,22 +339,32 @@ type singlePairOrigin interface {
 	callOne(pair Pair) (*Price, error)
 }
 
+// callSinglePairOrigin fetches prices for all given pairs concurrently. The
+// number of simultaneous requests is limited by the origin's worker pool.
+// Results are returned in the same order as the given pairs.
 func callSinglePairOrigin(e singlePairOrigin, pairs []Pair) []FetchResult {
-	crs := make([]FetchResult, 0)
-	for _, pair := range pairs {
-		price, err := e.callOne(pair)
-		if err != nil {
-			crs = append(crs, FetchResult{
-				Price: Price{Pair: pair},
-				Error: err,
-			})
-		} else {
-			crs = append(crs, FetchResult{
-				Price: *price,
-				Error: err,
-			})
-		}
+	var wg sync.WaitGroup
+	crs := make([]FetchResult, len(pairs))
+	wg.Add(len(pairs))
+	for i, pair := range pairs {
+		i, pair := i, pair
+		go func() {
+			defer wg.Done()
+			price, err := e.callOne(pair)
+			if err != nil {
+				crs[i] = FetchResult{
+					Price: Price{Pair: pair},
+					Error: err,
+				}
+			} else {
+				crs[i] = FetchResult{
+					Price: *price,
+					Error: err,
+				}
+			}
+		}()
 	}
+	wg.Wait()
 
 	return crs
 }
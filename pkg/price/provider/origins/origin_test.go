@@ -16,7 +16,12 @@
 package origins
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -94,6 +99,73 @@ func TestOriginsSuite(t *testing.T) {
 	suite.Run(t, new(OriginsSuite))
 }
 
+// newConcurrencyTestServer returns a server that responds to Bitstamp ticker
+// requests with a price equal to the length of the requested pair name. The
+// maximum number of requests handled simultaneously is stored in maxInFlight.
+func newConcurrencyTestServer(delay time.Duration, maxInFlight *int32) *httptest.Server {
+	var inFlight int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(delay)
+		name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		_, _ = fmt.Fprintf(
+			w,
+			`{"ask":"%[1]d","volume":"%[1]d","last":"%[1]d","bid":"%[1]d","timestamp":"1"}`,
+			len(name),
+		)
+	}))
+}
+
+func TestCallSinglePairOriginConcurrently(t *testing.T) {
+	var maxInFlight int32
+	srv := newConcurrencyTestServer(50*time.Millisecond, &maxInFlight)
+	defer srv.Close()
+
+	origin := &Bitstamp{WorkerPool: query.NewHTTPWorkerPool(2), BaseURL: srv.URL}
+	pairs := []Pair{
+		{Base: "A", Quote: "B"},
+		{Base: "AA", Quote: "B"},
+		{Base: "AAA", Quote: "B"},
+		{Base: "AAAA", Quote: "B"},
+		{Base: "AAAAA", Quote: "B"},
+		{Base: "AAAAAA", Quote: "B"},
+	}
+
+	crs := callSinglePairOrigin(origin, pairs)
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&maxInFlight))
+	assert.Len(t, crs, len(pairs))
+	for i, cr := range crs {
+		assert.NoError(t, cr.Error)
+		assert.Equal(t, pairs[i], cr.Price.Pair)
+		assert.Equal(t, float64(len(pairs[i].Base)+len(pairs[i].Quote)), cr.Price.Price)
+	}
+}
+
+func BenchmarkCallSinglePairOrigin(b *testing.B) {
+	var maxInFlight int32
+	srv := newConcurrencyTestServer(time.Millisecond, &maxInFlight)
+	defer srv.Close()
+
+	origin := &Bitstamp{WorkerPool: query.NewHTTPWorkerPool(10), BaseURL: srv.URL}
+	pairs := make([]Pair, 10)
+	for i := range pairs {
+		pairs[i] = Pair{Base: strings.Repeat("A", i+1), Quote: "B"}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		callSinglePairOrigin(origin, pairs)
+	}
+}
+
 type mockExchangeHandler struct{}
 
 func (u mockExchangeHandler) Pool() query.WorkerPool {
@@ -216,3 +288,35 @@ func TestReplacementAndRevertingUsingAliases(t *testing.T) {
 	assert.Equal(t, "BTC", reverted.Base)
 	assert.Equal(t, "WETH", reverted.Quote)
 }
+
+func TestSet_StartClose(t *testing.T) {
+	srv, attempts := newFlakyStreamServer(
+		t,
+		func(int) bool { return false },
+		func(int) bool { return false },
+	)
+	defer srv.Close()
+
+	pair := Pair{Base: "BTC", Quote: "USDT"}
+	stream := NewBinanceStream("ws"+strings.TrimPrefix(srv.URL, "http"), nil, time.Minute)
+	set := NewSet(map[string]Handler{
+		"binance":       NewBaseExchangeHandler(Binance{WorkerPool: query.NewMockWorkerPool()}, nil),
+		"binanceStream": NewBaseExchangeHandler(stream, nil),
+	})
+
+	// Streaming origins are connected by the Start method:
+	set.Start(context.Background())
+	set.Fetch(map[string][]Pair{"binanceStream": {pair}})
+	assert.Eventually(t, func() bool {
+		_, _, ok := stream.Latest(pair)
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	// And disconnected by the Close method:
+	assert.NoError(t, set.Close())
+	_, _, ok := stream.Latest(pair)
+	assert.False(t, ok)
+	set.Fetch(map[string][]Pair{"binanceStream": {pair}})
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, attempts(), 1)
+}
@@ -0,0 +1,68 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package origins
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrStalePrice = errors.New("streamed price is too old")
+
+// StreamingHandler is implemented by origins that push price updates over
+// a persistent connection instead of being polled. Prices received from
+// the stream are kept in memory, so they can be read synchronously during
+// the Fetch call.
+type StreamingHandler interface {
+	ExchangeHandler
+	// Latest returns the most recent price received from the stream for
+	// the given pair and the time at which it was received. The last
+	// returned value is false if no price has been received yet.
+	Latest(pair Pair) (Price, time.Time, bool)
+}
+
+// pullStreamedPrices returns prices received from the stream if they are not
+// older than maxAge. Prices for the remaining pairs are fetched using the
+// fallback handler. If fallback is nil, ErrStalePrice is returned for them.
+func pullStreamedPrices(h StreamingHandler, fallback ExchangeHandler, maxAge time.Duration, pairs []Pair) []FetchResult {
+	var stale []Pair
+	var staleIdx []int
+	crs := make([]FetchResult, len(pairs))
+	for i, pair := range pairs {
+		price, received, ok := h.Latest(pair)
+		if ok && time.Since(received) <= maxAge {
+			crs[i] = fetchResult(price)
+			continue
+		}
+		stale = append(stale, pair)
+		staleIdx = append(staleIdx, i)
+	}
+	if len(stale) == 0 {
+		return crs
+	}
+	var frs []FetchResult
+	if fallback != nil {
+		frs = fallback.PullPrices(stale)
+	} else {
+		frs = fetchResultListWithErrors(stale, ErrStalePrice)
+	}
+	for i, fr := range frs {
+		if i < len(staleIdx) {
+			crs[staleIdx[i]] = fr
+		}
+	}
+	return crs
+}
@@ -0,0 +1,62 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package origins
+
+import (
+	"fmt"
+	"time"
+)
+
+// Timing contains response times of an origin.
+type Timing struct {
+	Origin string
+	// Latencies are the wall times of all HTTP requests made to the origin.
+	// Origins that do not use HTTP, e.g. on-chain ones, have no latencies.
+	Latencies []time.Duration
+	// Err is the first error returned by the origin for any of its pairs.
+	Err error
+}
+
+// Average returns the average request latency.
+func (t Timing) Average() time.Duration {
+	if len(t.Latencies) == 0 {
+		return 0
+	}
+	var sum time.Duration
+	for _, l := range t.Latencies {
+		sum += l
+	}
+	return sum / time.Duration(len(t.Latencies))
+}
+
+// Max returns the highest request latency.
+func (t Timing) Max() time.Duration {
+	var max time.Duration
+	for _, l := range t.Latencies {
+		if l > max {
+			max = l
+		}
+	}
+	return max
+}
+
+func (t Timing) String() string {
+	s := fmt.Sprintf("%s: %d requests, avg %s, max %s", t.Origin, len(t.Latencies), t.Average(), t.Max())
+	if t.Err != nil {
+		s += fmt.Sprintf(", error: %v", t.Err)
+	}
+	return s
+}
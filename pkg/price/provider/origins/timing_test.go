@@ -0,0 +1,36 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package origins
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTiming(t *testing.T) {
+	timing := Timing{
+		Origin:    "foo",
+		Latencies: []time.Duration{100 * time.Millisecond, 300 * time.Millisecond},
+		Err:       errors.New("failed"),
+	}
+	assert.Equal(t, 200*time.Millisecond, timing.Average())
+	assert.Equal(t, 300*time.Millisecond, timing.Max())
+	assert.Equal(t, "foo: 2 requests, avg 200ms, max 300ms, error: failed", timing.String())
+	assert.Equal(t, time.Duration(0), Timing{}.Average())
+}
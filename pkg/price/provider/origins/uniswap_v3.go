@@ -38,14 +38,38 @@ type uniswapV3TokenResponse struct {
 	Symbol string `json:"symbol"`
 }
 
+type uniswapV3DayDataResponse struct {
+	Date    int64           `json:"date"`
+	Volume0 stringAsFloat64 `json:"volumeToken0"`
+	Volume1 stringAsFloat64 `json:"volumeToken1"`
+}
+
 type uniswapV3PairResponse struct {
-	ID      string                 `json:"id"`
-	Price0  stringAsFloat64        `json:"token0Price"`
-	Price1  stringAsFloat64        `json:"token1Price"`
-	Volume0 stringAsFloat64        `json:"volumeToken0"`
-	Volume1 stringAsFloat64        `json:"volumeToken1"`
-	Token0  uniswapV3TokenResponse `json:"token0"`
-	Token1  uniswapV3TokenResponse `json:"token1"`
+	ID      string                     `json:"id"`
+	Price0  stringAsFloat64            `json:"token0Price"`
+	Price1  stringAsFloat64            `json:"token1Price"`
+	Token0  uniswapV3TokenResponse     `json:"token0"`
+	Token1  uniswapV3TokenResponse     `json:"token1"`
+	DayData []uniswapV3DayDataResponse `json:"poolDayData"`
+}
+
+// uniswapV3Day is the length of the subgraph's daily volume buckets in
+// seconds.
+const uniswapV3Day = 24 * 60 * 60
+
+// volumes returns the volume of both pool tokens for the previous day.
+// The subgraph aggregates volume in daily buckets, starting at midnight UTC,
+// so the bucket for the current day contains only a part of the daily
+// volume. The last full day is used instead. If there were no swaps on that
+// day, there is no bucket for it, and the volume is zero.
+func (r uniswapV3PairResponse) volumes(now time.Time) (float64, float64) {
+	prevDay := now.Unix()/uniswapV3Day*uniswapV3Day - uniswapV3Day
+	for _, d := range r.DayData {
+		if d.Date == prevDay {
+			return d.Volume0.val(), d.Volume1.val()
+		}
+	}
+	return 0, 0
 }
 
 type UniswapV3 struct {
@@ -77,10 +101,13 @@ func (u *UniswapV3) callOne(pair Pair) (*Price, error) {
 				id
 				token0Price
 				token1Price
-				volumeToken0
-				volumeToken1
 				token0 { symbol }
 				token1 { symbol }
+				poolDayData(first: 2, orderBy: date, orderDirection: desc) {
+					date
+					volumeToken0
+					volumeToken1
+				}
 			}
 		}
 	`
@@ -124,23 +151,26 @@ func (u *UniswapV3) callOne(pair Pair) (*Price, error) {
 	pair0 := b + "/" + q
 	pair1 := q + "/" + b
 
+	now := time.Now()
 	if r, ok := respMap[pair0]; ok {
+		volume0, _ := r.volumes(now)
 		return &Price{
 			Pair:      pair,
 			Price:     r.Price1.val(),
 			Bid:       r.Price1.val(),
 			Ask:       r.Price1.val(),
-			Volume24h: r.Volume0.val(),
-			Timestamp: time.Now(),
+			Volume24h: volume0,
+			Timestamp: now,
 		}, nil
 	} else if r, ok := respMap[pair1]; ok {
+		_, volume1 := r.volumes(now)
 		return &Price{
 			Pair:      pair,
 			Price:     r.Price0.val(),
 			Bid:       r.Price0.val(),
 			Ask:       r.Price0.val(),
-			Volume24h: r.Volume1.val(),
-			Timestamp: time.Now(),
+			Volume24h: volume1,
+			Timestamp: now,
 		}, nil
 	}
 	return nil, ErrMissingResponseForPair
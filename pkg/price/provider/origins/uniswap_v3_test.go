@@ -18,9 +18,11 @@ package origins
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/query"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -88,8 +90,7 @@ func (suite *UniswapV3Suite) TestFailOnWrongInput() {
 							"id": "0x04916039b1f59d9745bf6e0a21f191d1e0a84287",
 							"token0Price": "",
 							"token1Price": "",
-							"volumeToken0": "31.001552898956395468",
-                			"volumeToken1": "-402.068307058139831158",
+							"poolDayData": [],
 							"token0": {
 								"symbol": "YFI"
 							},
@@ -116,8 +117,7 @@ func (suite *UniswapV3Suite) TestFailOnWrongInput() {
 							"id": "0x04916039b1f59d9745bf6e0a21f191d1e0a84287",
 							"token0Price": "0.06624583662031174276461684468775496",
 							"token1Price": "15.09528826289120164642035869260895",
-							"volumeToken0": "31.001552898956395468",
-                			"volumeToken1": "-402.068307058139831158",
+							"poolDayData": [],
 							"token0": {
 								"symbol": "YFI"
 							},
@@ -136,10 +136,12 @@ func (suite *UniswapV3Suite) TestFailOnWrongInput() {
 }
 
 func (suite *UniswapV3Suite) TestSuccessResponse() {
+	// Start of the current day, volume from the previous day should be used:
+	today := time.Now().Unix() / uniswapV3Day * uniswapV3Day
 	pairYFIWETH := Pair{Base: "YFI", Quote: "ETH"}
 
 	resp := &query.HTTPResponse{
-		Body: []byte(`
+		Body: []byte(fmt.Sprintf(`
 			{
 				"data": {
 					"pools": [
@@ -153,13 +155,15 @@ func (suite *UniswapV3Suite) TestSuccessResponse() {
 								"symbol": "WETH"
 							},
 							"token1Price": "15.0952",
-							"volumeToken0": "31.00155",
-							"volumeToken1": "-402.0683"
+							"poolDayData": [
+								{"date": %d, "volumeToken0": "1.5", "volumeToken1": "20.5"},
+								{"date": %d, "volumeToken0": "31.00155", "volumeToken1": "402.0683"}
+							]
 						}
 					]
 				}
 			}
-		`),
+		`, today, today-uniswapV3Day)),
 	}
 	suite.origin.ExchangeHandler.(UniswapV3).Pool().(*query.MockWorkerPool).MockResp(resp)
 	fr := suite.origin.Fetch([]Pair{pairYFIWETH})
@@ -177,7 +181,7 @@ func (suite *UniswapV3Suite) TestSuccessResponse() {
 
 	pairCRVWETH := Pair{Base: "CRV", Quote: "ETH"}
 	resp1 := &query.HTTPResponse{
-		Body: []byte(`
+		Body: []byte(fmt.Sprintf(`
 			{
 				"data": {
 					"pools": [
@@ -185,8 +189,9 @@ func (suite *UniswapV3Suite) TestSuccessResponse() {
 							"id": "0x58dc5a51fe44589beb22e8ce67720b5bc5378009",
 							"token0Price": "0.0006",
 							"token1Price": "1560.2121",
-							"volumeToken0": "142365.8321",
-							"volumeToken1": "274940368.6801",
+							"poolDayData": [
+								{"date": %d, "volumeToken0": "142365.8321", "volumeToken1": "274940368.6801"}
+							],
 							"token0": {
 								"symbol": "WETH"
 							},
@@ -197,7 +202,7 @@ func (suite *UniswapV3Suite) TestSuccessResponse() {
 					]
 				}
 			}
-		`),
+		`, today-uniswapV3Day)),
 	}
 	suite.origin.ExchangeHandler.(UniswapV3).Pool().(*query.MockWorkerPool).MockResp(resp1)
 	fr1 := suite.origin.Fetch([]Pair{pairCRVWETH})
@@ -212,6 +217,84 @@ func (suite *UniswapV3Suite) TestSuccessResponse() {
 	suite.Equal(0.0006, fr1[0].Price.Ask)
 	suite.Equal(274940368.6801, fr1[0].Price.Volume24h)
 	suite.Greater(fr1[0].Price.Timestamp.Unix(), int64(0))
+
+	// Pools without day data, e.g. with no swaps yet, have no volume
+	resp2 := &query.HTTPResponse{
+		Body: []byte(`
+			{
+				"data": {
+					"pools": [
+						{
+							"id": "0x04916039b1f59d9745bf6e0a21f191d1e0a84287",
+							"token0Price": "0.0662",
+							"token1Price": "15.0952",
+							"token0": {
+								"symbol": "YFI"
+							},
+							"token1": {
+								"symbol": "WETH"
+							},
+							"poolDayData": []
+						}
+					]
+				}
+			}
+		`),
+	}
+	suite.origin.ExchangeHandler.(UniswapV3).Pool().(*query.MockWorkerPool).MockResp(resp2)
+	fr2 := suite.origin.Fetch([]Pair{pairYFIWETH})
+
+	suite.Len(fr2, 1)
+	suite.NoError(fr2[0].Error)
+	suite.Equal(15.0952, fr2[0].Price.Price)
+	suite.Equal(float64(0), fr2[0].Price.Volume24h)
+}
+
+func TestUniswapV3_volumes(t *testing.T) {
+	now := time.Date(2022, 5, 10, 15, 0, 0, 0, time.UTC)
+	today := time.Date(2022, 5, 10, 0, 0, 0, 0, time.UTC).Unix()
+	yesterday := time.Date(2022, 5, 9, 0, 0, 0, 0, time.UTC).Unix()
+	tests := []struct {
+		name    string
+		dayData []uniswapV3DayDataResponse
+		want0   float64
+		want1   float64
+	}{
+		{
+			name: "partial-current-day",
+			dayData: []uniswapV3DayDataResponse{
+				{Date: today, Volume0: 1, Volume1: 2},
+				{Date: yesterday, Volume0: 10, Volume1: 20},
+			},
+			want0: 10,
+			want1: 20,
+		},
+		{
+			name: "no-swaps-today",
+			dayData: []uniswapV3DayDataResponse{
+				{Date: yesterday, Volume0: 10, Volume1: 20},
+			},
+			want0: 10,
+			want1: 20,
+		},
+		{
+			name: "no-swaps-yesterday",
+			dayData: []uniswapV3DayDataResponse{
+				{Date: today, Volume0: 1, Volume1: 2},
+				{Date: yesterday - uniswapV3Day, Volume0: 10, Volume1: 20},
+			},
+		},
+		{
+			name: "no-day-data",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v0, v1 := uniswapV3PairResponse{DayData: tt.dayData}.volumes(now)
+			assert.Equal(t, tt.want0, v0)
+			assert.Equal(t, tt.want1, v1)
+		})
+	}
 }
 
 func (suite *UniswapV3Suite) TestRealAPICall() {
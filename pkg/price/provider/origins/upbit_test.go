@@ -158,6 +158,41 @@ func (suite *UpbitSuite) TestSuccessResponse() {
 	suite.Equal(cr[0].Price.Timestamp.Unix(), int64(2))
 }
 
+func (suite *UpbitSuite) TestMarketCodeOrdering() {
+	ex := suite.origin.ExchangeHandler.(Upbit)
+	btckrw := Pair{Base: "BTC", Quote: "KRW"}
+	ethkrw := Pair{Base: "ETH", Quote: "KRW"}
+
+	// Upbit market codes put the quote asset first:
+	suite.Equal("KRW-BTC", ex.localPairName(btckrw))
+	suite.Equal("KRW-BTC,KRW-ETH", ex.localPairName(btckrw, ethkrw))
+
+	// Prices must be matched with pairs regardless of the response order:
+	resp := &query.HTTPResponse{
+		Body: []byte(`[
+			{"market":"KRW-ETH","trade_price":2000000,"acc_trade_volume_24h":20,"timestamp":2000},
+			{"market":"KRW-BTC","trade_price":30000000,"acc_trade_volume_24h":10,"timestamp":2000}
+		]`),
+	}
+	suite.origin.ExchangeHandler.(Upbit).Pool().(*query.MockWorkerPool).MockResp(resp)
+	cr := suite.origin.Fetch([]Pair{btckrw, ethkrw})
+	suite.Require().Len(cr, 2)
+	suite.NoError(cr[0].Error)
+	suite.NoError(cr[1].Error)
+	suite.Equal(btckrw, cr[0].Price.Pair)
+	suite.Equal(30000000.0, cr[0].Price.Price)
+	suite.Equal(ethkrw, cr[1].Price.Pair)
+	suite.Equal(2000000.0, cr[1].Price.Price)
+
+	// A base-first market code must not be matched:
+	resp = &query.HTTPResponse{
+		Body: []byte(`[{"market":"BTC-KRW","trade_price":30000000,"timestamp":2000}]`),
+	}
+	suite.origin.ExchangeHandler.(Upbit).Pool().(*query.MockWorkerPool).MockResp(resp)
+	cr = suite.origin.Fetch([]Pair{btckrw})
+	suite.Equal(ErrMissingResponseForPair, cr[0].Error)
+}
+
 func (suite *UpbitSuite) TestRealAPICall() {
 	testRealAPICall(
 		suite,
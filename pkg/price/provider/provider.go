@@ -17,6 +17,8 @@ package provider
 
 import (
 	"fmt"
+	"math/big"
+	"sort"
 	"strings"
 	"time"
 )
@@ -42,10 +44,10 @@ type Pair struct {
 }
 
 // NewPair returns a new Pair for given string. The string must be formatted
-// as "BASE/QUOTE".
+// as "BASE/QUOTE". Asset symbols are converted to upper case.
 func NewPair(s string) (Pair, error) {
 	ss := strings.Split(s, "/")
-	if len(ss) != 2 {
+	if len(ss) != 2 || ss[0] == "" || ss[1] == "" {
 		return Pair{}, fmt.Errorf("couldn't parse pair \"%s\"", s)
 	}
 	return Pair{Base: strings.ToUpper(ss[0]), Quote: strings.ToUpper(ss[1])}, nil
@@ -65,6 +67,58 @@ func NewPairs(s ...string) ([]Pair, error) {
 	return r, nil
 }
 
+// ParsePair returns a new Pair for given string. Unlike NewPair, the string
+// may also be formatted as "BASEQUOTE", in which case it is split using the
+// known pairs or their inverses. Aliases are resolved before the split pair
+// is compared with the known pairs, but the returned pair uses the symbols
+// as given. Case is ignored in both forms.
+func ParsePair(s string, known []Pair, aliases Aliases) (Pair, error) {
+	if strings.Contains(s, "/") {
+		return NewPair(s)
+	}
+	var found []Pair
+	u := strings.ToUpper(s)
+	for i := 1; i < len(u); i++ {
+		p := Pair{Base: u[:i], Quote: u[i:]}
+		r := aliases.Resolve(p)
+		for _, k := range known {
+			if (r.Equal(k) || r.Equal(k.Inverse())) && !containsPair(found, p) {
+				found = append(found, p)
+			}
+		}
+	}
+	switch len(found) {
+	case 0:
+		return Pair{}, fmt.Errorf("couldn't parse pair \"%s\": use the BASE/QUOTE format or a known pair", s)
+	case 1:
+		return found[0], nil
+	default:
+		return Pair{}, fmt.Errorf("couldn't parse pair \"%s\": ambiguous, matches %s and %s", s, found[0], found[1])
+	}
+}
+
+// ParsePairs returns a Pair slice for given strings using ParsePair.
+func ParsePairs(known []Pair, aliases Aliases, s ...string) ([]Pair, error) {
+	var r []Pair
+	for _, p := range s {
+		pr, err := ParsePair(p, known, aliases)
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, pr)
+	}
+	return r, nil
+}
+
+func containsPair(ps []Pair, p Pair) bool {
+	for _, c := range ps {
+		if c.Equal(p) {
+			return true
+		}
+	}
+	return false
+}
+
 func (p Pair) Empty() bool {
 	return p.Base == "" && p.Quote == ""
 }
@@ -73,10 +127,52 @@ func (p Pair) Equal(c Pair) bool {
 	return p.Base == c.Base && p.Quote == c.Quote
 }
 
+// Inverse returns the pair with the base and quote assets swapped.
+func (p Pair) Inverse() Pair {
+	return Pair{Base: p.Quote, Quote: p.Base}
+}
+
 func (p Pair) String() string {
 	return fmt.Sprintf("%s/%s", p.Base, p.Quote)
 }
 
+// Aliases maps alternative asset symbols to their canonical symbols, e.g.
+// XBT to BTC. Keys and values must be in upper case.
+type Aliases map[string]string
+
+// NewAliases returns Aliases for the given map of alternative symbols to
+// canonical symbols. Symbols are converted to upper case. An error is
+// returned if a canonical symbol is also an alias, because aliases are not
+// resolved recursively.
+func NewAliases(m map[string]string) (Aliases, error) {
+	a := make(Aliases, len(m))
+	for alias, symbol := range m {
+		alias, symbol = strings.ToUpper(alias), strings.ToUpper(symbol)
+		if alias == "" || symbol == "" {
+			return nil, fmt.Errorf("alias and symbol must not be empty")
+		}
+		a[alias] = symbol
+	}
+	for alias, symbol := range a {
+		if _, ok := a[symbol]; ok {
+			return nil, fmt.Errorf("alias %s refers to %s, which is also an alias", alias, symbol)
+		}
+	}
+	return a, nil
+}
+
+// Resolve returns the pair with aliases of the base and quote assets
+// replaced by their canonical symbols.
+func (a Aliases) Resolve(p Pair) Pair {
+	if s, ok := a[p.Base]; ok {
+		p.Base = s
+	}
+	if s, ok := a[p.Quote]; ok {
+		p.Quote = s
+	}
+	return p
+}
+
 // Model is a simplified representation of a model which is used to calculate
 // asset pair prices. The main purpose of this structure is to help the end
 // user to understand how prices are derived and calculated.
@@ -94,6 +190,34 @@ type Model struct {
 	Models []*Model
 }
 
+// Origins returns sorted, unique names of origins used by the model and all
+// of its sub models.
+func (m *Model) Origins() []string {
+	seen := map[string]bool{}
+	var walk func(m *Model)
+	walk = func(m *Model) {
+		if m.Type == "origin" && m.Parameters["origin"] != "" {
+			seen[m.Parameters["origin"]] = true
+		}
+		for _, c := range m.Models {
+			walk(c)
+		}
+	}
+	walk(m)
+	origins := make([]string, 0, len(seen))
+	for o := range seen {
+		origins = append(origins, o)
+	}
+	sort.Strings(origins)
+	return origins
+}
+
+// PairOrigins lists origins used to calculate the price of a pair.
+type PairOrigins struct {
+	Pair    Pair
+	Origins []string
+}
+
 // Price represents price for a single pair. If the Price was calculated
 // indirectly it will also contain all prices used to calculate the price.
 type Price struct {
@@ -101,12 +225,16 @@ type Price struct {
 	Parameters map[string]string
 	Pair       Pair
 	Price      float64
+	// ExactPrice is the price calculated with arbitrary precision, if exact
+	// arithmetic is enabled. It is nil otherwise.
+	ExactPrice *big.Rat
 	Bid        float64
 	Ask        float64
 	Volume24h  float64
 	Time       time.Time
 	Prices     []*Price
 	Error      string
+	Warning    string
 }
 
 type PriceHook interface {
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewPair(t *testing.T) {
@@ -114,3 +115,118 @@ func TestNewPairs(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePair(t *testing.T) {
+	known := []Pair{{Base: "ETH", Quote: "USD"}, {Base: "BTC", Quote: "USD"}}
+	ethusd := Pair{Base: "ETH", Quote: "USD"}
+	tests := []struct {
+		pair    string
+		want    Pair
+		wantErr bool
+	}{
+		{pair: "ETH/USD", want: ethusd},
+		{pair: "eth/usd", want: ethusd},
+		{pair: "Eth/Usd", want: ethusd},
+		{pair: "ETHUSD", want: ethusd},
+		{pair: "ethusd", want: ethusd},
+		{pair: "EthUsd", want: ethusd},
+		{pair: "usdeth", want: ethusd.Inverse()},
+		{pair: "foo/bar", want: Pair{Base: "FOO", Quote: "BAR"}},
+		{pair: "foobar", wantErr: true},
+		{pair: "ETH/", wantErr: true},
+		{pair: "ETH/USD/", wantErr: true},
+		{pair: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pair, func(t *testing.T) {
+			got, err := ParsePair(tt.pair, known, nil)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), `"`+tt.pair+`"`)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParsePair_Ambiguous(t *testing.T) {
+	_, err := ParsePair("ABC", []Pair{{Base: "A", Quote: "BC"}, {Base: "AB", Quote: "C"}}, nil)
+	assert.Error(t, err)
+}
+
+func TestParsePair_Aliases(t *testing.T) {
+	known := []Pair{{Base: "BTC", Quote: "USD"}}
+	aliases, err := NewAliases(map[string]string{"XBT": "BTC"})
+	require.NoError(t, err)
+	tests := []struct {
+		pair    string
+		want    Pair
+		wantErr bool
+	}{
+		{pair: "XBTUSD", want: Pair{Base: "XBT", Quote: "USD"}},
+		{pair: "xbtusd", want: Pair{Base: "XBT", Quote: "USD"}},
+		{pair: "USDXBT", want: Pair{Base: "USD", Quote: "XBT"}},
+		{pair: "BTCUSD", want: Pair{Base: "BTC", Quote: "USD"}},
+		{pair: "XBT/USD", want: Pair{Base: "XBT", Quote: "USD"}},
+		{pair: "XBTEUR", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pair, func(t *testing.T) {
+			got, err := ParsePair(tt.pair, known, aliases)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParsePairs(t *testing.T) {
+	known := []Pair{{Base: "ETH", Quote: "USD"}}
+	got, err := ParsePairs(known, nil, "ethusd", "BTC/usd")
+	assert.NoError(t, err)
+	assert.Equal(t, []Pair{{Base: "ETH", Quote: "USD"}, {Base: "BTC", Quote: "USD"}}, got)
+
+	_, err = ParsePairs(known, nil, "ethusd", "btcusd")
+	assert.EqualError(t, err, `couldn't parse pair "btcusd": use the BASE/QUOTE format or a known pair`)
+}
+
+func TestNewAliases(t *testing.T) {
+	a, err := NewAliases(map[string]string{"xbt": "btc", "GOLD": "XAU"})
+	assert.NoError(t, err)
+	assert.Equal(t, Aliases{"XBT": "BTC", "GOLD": "XAU"}, a)
+
+	_, err = NewAliases(map[string]string{"XBT": ""})
+	assert.Error(t, err)
+
+	_, err = NewAliases(map[string]string{"A": "B", "B": "C"})
+	assert.Error(t, err)
+}
+
+func TestAliases_Resolve(t *testing.T) {
+	a := Aliases{"XBT": "BTC", "GOLD": "XAU"}
+	assert.Equal(t, Pair{Base: "BTC", Quote: "USD"}, a.Resolve(Pair{Base: "XBT", Quote: "USD"}))
+	assert.Equal(t, Pair{Base: "BTC", Quote: "XAU"}, a.Resolve(Pair{Base: "XBT", Quote: "GOLD"}))
+	assert.Equal(t, Pair{Base: "ETH", Quote: "USD"}, a.Resolve(Pair{Base: "ETH", Quote: "USD"}))
+	assert.Equal(t, Pair{Base: "XBT", Quote: "USD"}, Aliases(nil).Resolve(Pair{Base: "XBT", Quote: "USD"}))
+}
+
+func TestModel_Origins(t *testing.T) {
+	m := &Model{
+		Type: "median",
+		Models: []*Model{
+			{Type: "origin", Parameters: map[string]string{"origin": "b"}},
+			{Type: "indirect", Models: []*Model{
+				{Type: "origin", Parameters: map[string]string{"origin": "a"}},
+				{Type: "origin", Parameters: map[string]string{"origin": "b"}},
+			}},
+			{Type: "reference"},
+		},
+	}
+	assert.Equal(t, []string{"a", "b"}, m.Origins())
+	assert.Empty(t, (&Model{Type: "median"}).Origins())
+}
@@ -0,0 +1,351 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package reload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+)
+
+const LoggerTag = "RELOADABLE_PROVIDER"
+
+const defaultReadyTimeout = time.Minute
+
+// LoadFunc returns a new provider. The context is canceled when the provider
+// is replaced by a new one or when the Provider service is stopped.
+type LoadFunc func(ctx context.Context) (provider.Provider, error)
+
+type Config struct {
+	// Load is used to create the initial provider and a new provider on
+	// every reload.
+	Load LoadFunc
+	// Signals is an optional channel. A reload is performed every time a
+	// signal is received from it.
+	Signals <-chan os.Signal
+	// ReadyTimeout is the maximum time to wait for a new provider to fetch
+	// its first prices before it replaces the current one. If zero,
+	// the default of one minute is used.
+	ReadyTimeout time.Duration
+	Logger       log.Logger
+}
+
+// service is the same interface as supervisor.Service. Providers that
+// implement it are started after they are loaded and stopped after they
+// are replaced.
+type service interface {
+	Start(ctx context.Context) error
+	Wait() chan error
+}
+
+// readier is implemented by providers that fetch prices asynchronously,
+// like graph.AsyncProvider. The returned channel is closed once the first
+// prices are fetched.
+type readier interface {
+	Ready() <-chan struct{}
+}
+
+// subscriber is the same interface as rpc.Subscriber.
+type subscriber interface {
+	Subscribe() (<-chan struct{}, func())
+}
+
+// instance is a loaded provider together with a function that stops it.
+type instance struct {
+	provider provider.Provider
+	stop     func()
+	unsub    func()
+	done     chan struct{}
+}
+
+// close unsubscribes from the provider and stops it.
+func (i *instance) close() {
+	close(i.done)
+	if i.unsub != nil {
+		i.unsub()
+	}
+	i.stop()
+}
+
+// Provider implements the provider.Provider interface. It delegates all
+// calls to a provider that may be replaced at runtime without interrupting
+// its users. If a reload fails, the current provider is kept.
+type Provider struct {
+	ctx          context.Context
+	waitCh       chan error
+	load         LoadFunc
+	sigCh        <-chan os.Signal
+	readyTimeout time.Duration
+	log          log.Logger
+
+	reloadMu sync.Mutex // reloadMu prevents concurrent reloads.
+	mu       sync.RWMutex
+	current  *instance
+	subs     map[chan struct{}]struct{}
+}
+
+// New returns a new instance of the Provider. The initial provider is
+// loaded when the service is started.
+func New(cfg Config) (*Provider, error) {
+	if cfg.Load == nil {
+		return nil, errors.New("load function must not be nil")
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = null.New()
+	}
+	if cfg.ReadyTimeout == 0 {
+		cfg.ReadyTimeout = defaultReadyTimeout
+	}
+	return &Provider{
+		waitCh:       make(chan error),
+		load:         cfg.Load,
+		sigCh:        cfg.Signals,
+		readyTimeout: cfg.ReadyTimeout,
+		log:          cfg.Logger.WithField("tag", LoggerTag),
+		subs:         map[chan struct{}]struct{}{},
+	}, nil
+}
+
+// Start implements the supervisor.Service interface.
+func (p *Provider) Start(ctx context.Context) error {
+	if p.ctx != nil {
+		return errors.New("service can be started only once")
+	}
+	if ctx == nil {
+		return errors.New("context must not be nil")
+	}
+	p.log.Infof("Starting")
+	p.ctx = ctx
+	if err := p.Reload(); err != nil {
+		return err
+	}
+	go p.reloadRoutine()
+	go p.contextCancelHandler()
+	return nil
+}
+
+// Wait implements the supervisor.Service interface.
+func (p *Provider) Wait() chan error {
+	return p.waitCh
+}
+
+// Reload loads a new provider and replaces the current one with it. If the
+// new provider cannot be loaded or started, an error is returned and the
+// current provider remains in use.
+//
+// When the current provider is replaced, the new one must support at least
+// one pair, and if it fetches prices asynchronously, it replaces the current
+// one only after its first prices are fetched, so no prices are lost during
+// the reload.
+func (p *Provider) Reload() error {
+	p.reloadMu.Lock()
+	defer p.reloadMu.Unlock()
+	if p.ctx == nil {
+		return errors.New("service is not started")
+	}
+	if p.ctx.Err() != nil {
+		return errors.New("service is stopped")
+	}
+	p.mu.RLock()
+	replace := p.current != nil
+	p.mu.RUnlock()
+	ctx, ctxCancel := context.WithCancel(p.ctx)
+	prov, err := p.load(ctx)
+	if err != nil {
+		ctxCancel()
+		return fmt.Errorf("unable to load a provider: %w", err)
+	}
+	if replace {
+		pairs, err := prov.Pairs()
+		if err != nil {
+			ctxCancel()
+			return fmt.Errorf("unable to load a provider: %w", err)
+		}
+		if len(pairs) == 0 {
+			ctxCancel()
+			return errors.New("unable to load a provider: no pairs are configured")
+		}
+	}
+	stop := ctxCancel
+	if srv, ok := prov.(service); ok {
+		if err := srv.Start(ctx); err != nil {
+			ctxCancel()
+			return fmt.Errorf("unable to start a provider: %w", err)
+		}
+		stop = func() {
+			ctxCancel()
+			<-srv.Wait()
+		}
+	}
+	if r, ok := prov.(readier); ok && replace {
+		t := time.NewTimer(p.readyTimeout)
+		defer t.Stop()
+		select {
+		case <-r.Ready():
+		case <-t.C:
+			stop()
+			return fmt.Errorf("provider did not fetch prices within %s", p.readyTimeout)
+		case <-p.ctx.Done():
+			stop()
+			return errors.New("service is stopped")
+		}
+	}
+	p.swap(&instance{provider: prov, stop: stop, done: make(chan struct{})})
+	return nil
+}
+
+// Models implements the provider.Provider interface.
+func (p *Provider) Models(pairs ...provider.Pair) (map[provider.Pair]*provider.Model, error) {
+	prov, err := p.provider()
+	if err != nil {
+		return nil, err
+	}
+	return prov.Models(pairs...)
+}
+
+// Price implements the provider.Provider interface.
+func (p *Provider) Price(pair provider.Pair) (*provider.Price, error) {
+	prov, err := p.provider()
+	if err != nil {
+		return nil, err
+	}
+	return prov.Price(pair)
+}
+
+// Prices implements the provider.Provider interface.
+func (p *Provider) Prices(pairs ...provider.Pair) (map[provider.Pair]*provider.Price, error) {
+	prov, err := p.provider()
+	if err != nil {
+		return nil, err
+	}
+	return prov.Prices(pairs...)
+}
+
+// Pairs implements the provider.Provider interface.
+func (p *Provider) Pairs() ([]provider.Pair, error) {
+	prov, err := p.provider()
+	if err != nil {
+		return nil, err
+	}
+	return prov.Pairs()
+}
+
+// Subscribe returns a channel that receives a value every time prices are
+// updated by the current provider and after every successful reload. The
+// returned function must be called to unsubscribe.
+func (p *Provider) Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	p.mu.Lock()
+	p.subs[ch] = struct{}{}
+	p.mu.Unlock()
+	return ch, func() {
+		p.mu.Lock()
+		delete(p.subs, ch)
+		p.mu.Unlock()
+	}
+}
+
+func (p *Provider) provider() (provider.Provider, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.current == nil {
+		return nil, errors.New("provider is not loaded")
+	}
+	return p.current.provider, nil
+}
+
+// swap replaces the current provider with the given one and stops the
+// previous one.
+func (p *Provider) swap(i *instance) {
+	if sub, ok := i.provider.(subscriber); ok {
+		var ch <-chan struct{}
+		ch, i.unsub = sub.Subscribe()
+		go p.forwardRoutine(ch, i.done)
+	}
+	p.mu.Lock()
+	prev := p.current
+	p.current = i
+	p.mu.Unlock()
+	if prev != nil {
+		prev.close()
+	}
+	p.notify()
+}
+
+// notify notifies all subscribers about updated prices.
+func (p *Provider) notify() {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for ch := range p.subs {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// forwardRoutine forwards notifications from the provider's subscription
+// to subscribers of the Provider until the done channel is closed.
+func (p *Provider) forwardRoutine(ch <-chan struct{}, done chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case <-ch:
+			p.notify()
+		}
+	}
+}
+
+func (p *Provider) reloadRoutine() {
+	if p.sigCh == nil {
+		return
+	}
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-p.sigCh:
+			p.log.Info("Reloading")
+			if err := p.Reload(); err != nil {
+				p.log.WithError(err).Error("Unable to reload, the previous configuration is still used")
+				continue
+			}
+			p.log.Info("Reloaded")
+		}
+	}
+}
+
+func (p *Provider) contextCancelHandler() {
+	defer func() { close(p.waitCh) }()
+	defer p.log.Info("Stopped")
+	<-p.ctx.Done()
+	p.reloadMu.Lock()
+	defer p.reloadMu.Unlock()
+	p.mu.RLock()
+	prev := p.current
+	p.mu.RUnlock()
+	if prev != nil {
+		prev.close()
+	}
+}
@@ -0,0 +1,267 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package reload
+
+import (
+	"context"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+)
+
+// testProvider is a provider.Provider that returns pairs from its config.
+type testProvider struct {
+	pairs  []provider.Pair
+	ctx    context.Context
+	waitCh chan error
+}
+
+func (p *testProvider) Models(...provider.Pair) (map[provider.Pair]*provider.Model, error) {
+	return nil, nil
+}
+
+func (p *testProvider) Price(provider.Pair) (*provider.Price, error) {
+	return nil, nil
+}
+
+func (p *testProvider) Prices(...provider.Pair) (map[provider.Pair]*provider.Price, error) {
+	return nil, nil
+}
+
+func (p *testProvider) Pairs() ([]provider.Pair, error) {
+	return p.pairs, nil
+}
+
+func (p *testProvider) Start(ctx context.Context) error {
+	p.ctx = ctx
+	go func() {
+		<-ctx.Done()
+		close(p.waitCh)
+	}()
+	return nil
+}
+
+func (p *testProvider) Wait() chan error {
+	return p.waitCh
+}
+
+// testLoader loads a testProvider from the YAML config stored in the cfg
+// field.
+type testLoader struct {
+	mu        sync.Mutex
+	cfg       string
+	providers []*testProvider
+}
+
+func (l *testLoader) setConfig(cfg string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cfg = cfg
+}
+
+func (l *testLoader) load(_ context.Context) (provider.Provider, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var cfg struct {
+		Pairs []string `yaml:"pairs"`
+	}
+	if err := config.Parse(&cfg, []byte(l.cfg)); err != nil {
+		return nil, err
+	}
+	pairs, err := provider.NewPairs(cfg.Pairs...)
+	if err != nil {
+		return nil, err
+	}
+	p := &testProvider{pairs: pairs, waitCh: make(chan error)}
+	l.providers = append(l.providers, p)
+	return p, nil
+}
+
+func TestProvider_Reload(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	l := &testLoader{cfg: `{"pairs": ["A/B"]}`}
+	p, err := New(Config{Load: l.load})
+	require.NoError(t, err)
+	require.NoError(t, p.Start(ctx))
+
+	pairs, err := p.Pairs()
+	require.NoError(t, err)
+	assert.Equal(t, []provider.Pair{{Base: "A", Quote: "B"}}, pairs)
+
+	// Valid config, the provider must be replaced and the previous one
+	// stopped:
+	l.setConfig(`{"pairs": ["C/D"]}`)
+	require.NoError(t, p.Reload())
+	pairs, err = p.Pairs()
+	require.NoError(t, err)
+	assert.Equal(t, []provider.Pair{{Base: "C", Quote: "D"}}, pairs)
+	assert.Error(t, l.providers[0].ctx.Err())
+	assert.NoError(t, l.providers[1].ctx.Err())
+
+	// Invalid config, the current provider must be kept:
+	l.setConfig(`{"pairs": ["invalid"]}`)
+	require.Error(t, p.Reload())
+	pairs, err = p.Pairs()
+	require.NoError(t, err)
+	assert.Equal(t, []provider.Pair{{Base: "C", Quote: "D"}}, pairs)
+	assert.NoError(t, l.providers[1].ctx.Err())
+
+	// Stopping the service must stop the current provider:
+	ctxCancel()
+	<-p.Wait()
+	assert.Error(t, l.providers[1].ctx.Err())
+}
+
+func TestProvider_ReloadOnSignal(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	sigCh := make(chan os.Signal)
+	l := &testLoader{cfg: `{"pairs": ["A/B"]}`}
+	p, err := New(Config{Load: l.load, Signals: sigCh})
+	require.NoError(t, err)
+	require.NoError(t, p.Start(ctx))
+
+	ch, unsub := p.Subscribe()
+	defer unsub()
+
+	// Invalid config:
+	l.setConfig(`{"pairs": [`)
+	sigCh <- syscall.SIGHUP
+	sigCh <- syscall.SIGHUP // Second signal ensures that the first one was processed.
+	pairs, err := p.Pairs()
+	require.NoError(t, err)
+	assert.Equal(t, []provider.Pair{{Base: "A", Quote: "B"}}, pairs)
+
+	// Valid config:
+	l.setConfig(`{"pairs": ["C/D"]}`)
+	sigCh <- syscall.SIGHUP
+	assert.Eventually(t, func() bool {
+		pairs, err := p.Pairs()
+		return err == nil && len(pairs) == 1 && pairs[0] == provider.Pair{Base: "C", Quote: "D"}
+	}, time.Second, 10*time.Millisecond)
+
+	// Subscribers must be notified about the reload:
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		assert.Fail(t, "subscriber was not notified")
+	}
+}
+
+func TestProvider_NotStarted(t *testing.T) {
+	l := &testLoader{cfg: `{"pairs": ["A/B"]}`}
+	p, err := New(Config{Load: l.load})
+	require.NoError(t, err)
+
+	_, err = p.Pairs()
+	assert.Error(t, err)
+	assert.Error(t, p.Reload())
+}
+
+func TestProvider_Reload_NoPairs(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	l := &testLoader{cfg: `{"pairs": ["A/B"]}`}
+	p, err := New(Config{Load: l.load})
+	require.NoError(t, err)
+	require.NoError(t, p.Start(ctx))
+
+	// A config without pairs, e.g. an empty file, must not replace
+	// the current provider:
+	l.setConfig(`{}`)
+	require.Error(t, p.Reload())
+	pairs, err := p.Pairs()
+	require.NoError(t, err)
+	assert.Equal(t, []provider.Pair{{Base: "A", Quote: "B"}}, pairs)
+	assert.NoError(t, l.providers[0].ctx.Err())
+	assert.Nil(t, l.providers[1].ctx, "the rejected provider must not be started")
+}
+
+// asyncTestProvider is a testProvider that becomes ready after the ready
+// channel is closed.
+type asyncTestProvider struct {
+	*testProvider
+	ready chan struct{}
+}
+
+func (p *asyncTestProvider) Ready() <-chan struct{} {
+	return p.ready
+}
+
+func TestProvider_Reload_Ready(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	var mu sync.Mutex
+	var providers []*asyncTestProvider
+	loaded := func(n int) *asyncTestProvider {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(providers) <= n {
+			return nil
+		}
+		return providers[n]
+	}
+	load := func(ctx context.Context) (provider.Provider, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		p := &asyncTestProvider{
+			testProvider: &testProvider{
+				pairs:  []provider.Pair{{Base: "A", Quote: "B"}},
+				waitCh: make(chan error),
+			},
+			ready: make(chan struct{}),
+		}
+		providers = append(providers, p)
+		return p, nil
+	}
+	p, err := New(Config{Load: load, ReadyTimeout: 100 * time.Millisecond})
+	require.NoError(t, err)
+
+	// The initial provider is used without waiting:
+	require.NoError(t, p.Start(ctx))
+	require.NotNil(t, loaded(0))
+
+	// The current provider must be used until the new one is ready:
+	errCh := make(chan error)
+	go func() { errCh <- p.Reload() }()
+	require.Eventually(t, func() bool {
+		return loaded(1) != nil
+	}, time.Second, 10*time.Millisecond)
+	assert.NoError(t, loaded(0).ctx.Err())
+	close(loaded(1).ready)
+	require.NoError(t, <-errCh)
+	assert.Error(t, loaded(0).ctx.Err())
+	assert.NoError(t, loaded(1).ctx.Err())
+
+	// A provider that is not ready in time must not replace the current one:
+	require.Error(t, p.Reload())
+	require.NotNil(t, loaded(2))
+	assert.NoError(t, loaded(1).ctx.Err())
+	assert.Error(t, loaded(2).ctx.Err())
+}
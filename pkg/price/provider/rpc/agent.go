@@ -22,6 +22,8 @@ import (
 	"net/http"
 	"net/rpc"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/httpserver"
+	"github.com/chronicleprotocol/oracle-suite/pkg/httpserver/middleware"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
 )
@@ -35,7 +37,14 @@ type AgentConfig struct {
 	Network string
 	// Address is used for the rpc.Listener function.
 	Address string
-	Logger  log.Logger
+	// Middlewares are optional middlewares applied to all HTTP handlers
+	// served by the agent, e.g. to add CORS headers.
+	Middlewares []httpserver.Middleware
+	// AllowedOrigins is a list of origins of web pages, other than the
+	// agent's origin, that are allowed to subscribe to prices using
+	// WebSockets. The "*" origin allows all origins.
+	AllowedOrigins []string
+	Logger         log.Logger
 }
 
 // Agent creates and manages an RPC server for remote Provider calls.
@@ -45,6 +54,7 @@ type Agent struct {
 
 	api      *API
 	rpc      *rpc.Server
+	handler  http.Handler
 	listener net.Listener
 	network  string
 	address  string
@@ -53,6 +63,7 @@ type Agent struct {
 
 // NewAgent returns a new Agent instance.
 func NewAgent(cfg AgentConfig) (*Agent, error) {
+	mux := http.NewServeMux()
 	server := &Agent{
 		waitCh: make(chan error),
 		api: &API{
@@ -60,6 +71,7 @@ func NewAgent(cfg AgentConfig) (*Agent, error) {
 			log:      cfg.Logger.WithField("tag", AgentLoggerTag),
 		},
 		rpc:     rpc.NewServer(),
+		handler: mux,
 		network: cfg.Network,
 		address: cfg.Address,
 		log:     cfg.Logger.WithField("tag", AgentLoggerTag),
@@ -69,7 +81,25 @@ func NewAgent(cfg AgentConfig) (*Agent, error) {
 	if err != nil {
 		return nil, err
 	}
+	// The net/rpc package can register its handlers only in the default
+	// ServeMux. Only these paths are forwarded to it, so handlers registered
+	// there by other packages, like net/http/pprof, are not exposed.
 	server.rpc.HandleHTTP(rpc.DefaultRPCPath, rpc.DefaultDebugPath)
+	mux.Handle(rpc.DefaultRPCPath, http.DefaultServeMux)
+	mux.Handle(rpc.DefaultDebugPath, http.DefaultServeMux)
+	mux.Handle(JSONRPCPath, NewJSONRPCHandler(server.api.provider, server.log))
+	prices := NewPricesHandler(server.api.provider, server.log)
+	mux.Handle(PricesPath, (&middleware.Cache{MaxAge: PricesMaxAge}).Handle(prices))
+	if sub, ok := cfg.Provider.(Subscriber); ok {
+		h := NewSubscribeHandler(cfg.Provider, sub, server.log)
+		h.SetAllowedOrigins(cfg.AllowedOrigins)
+		mux.Handle(SubscribePath, h)
+	}
+
+	// Middlewares are called in the order in which they were added:
+	for i := len(cfg.Middlewares) - 1; i >= 0; i-- {
+		server.handler = cfg.Middlewares[i].Handle(server.handler)
+	}
 
 	return server, nil
 }
@@ -93,7 +123,7 @@ func (s *Agent) Start(ctx context.Context) error {
 		return err
 	}
 	go func() {
-		err := http.Serve(s.listener, nil)
+		err := http.Serve(s.listener, s.handler)
 		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			s.log.WithError(err).Error("RPC server crashed")
 		}
@@ -0,0 +1,87 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/httpserver"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+)
+
+func TestAgent_Handler(t *testing.T) {
+	// The agent is created in TestMain.
+	// The pprof handlers are registered in the default ServeMux by the
+	// net/http/pprof package, but they must not be served by the agent:
+	rw := httptest.NewRecorder()
+	agent.handler.ServeHTTP(rw, httptest.NewRequest("GET", httpserver.PprofPath, nil))
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+
+	rw = httptest.NewRecorder()
+	agent.handler.ServeHTTP(rw, httptest.NewRequest("GET", JSONRPCPath, nil))
+	assert.NotEqual(t, http.StatusNotFound, rw.Code)
+}
+
+func TestAgent_PricesCache(t *testing.T) {
+	// The agent is created in TestMain.
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	now := time.Now().UTC().Truncate(time.Second)
+	mockGofer.On("Price", ab).Return(testMedianPrice(ab, now.Add(-time.Minute), now), nil)
+
+	get := func(header, value string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", PricesPath+"A/B", nil)
+		if header != "" {
+			r.Header.Set(header, value)
+		}
+		rw := httptest.NewRecorder()
+		agent.handler.ServeHTTP(rw, r)
+		return rw
+	}
+
+	calls := func() (n int) {
+		for _, c := range mockGofer.Calls {
+			if c.Method == "Price" {
+				n++
+			}
+		}
+		return n
+	}
+
+	before := calls()
+	rw := get("", "")
+	require.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, now.Format(http.TimeFormat), rw.Header().Get("Last-Modified"))
+	assert.Equal(t, "public, max-age=5", rw.Header().Get("Cache-Control"))
+	assert.NotEmpty(t, rw.Header().Get("ETag"))
+
+	// The price must be calculated only once per request:
+	assert.Equal(t, 1, calls()-before)
+
+	// Prices were not updated since the last request:
+	rw = get("If-Modified-Since", now.Format(http.TimeFormat))
+	assert.Equal(t, http.StatusNotModified, rw.Code)
+	assert.Empty(t, rw.Body.String())
+
+	// Prices were updated:
+	rw = get("If-Modified-Since", now.Add(-time.Second).Format(http.TimeFormat))
+	assert.Equal(t, http.StatusOK, rw.Code)
+}
@@ -0,0 +1,267 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/marshal"
+)
+
+// JSONRPCPath is the HTTP path on which the Agent serves JSON-RPC requests.
+const JSONRPCPath = "/jsonrpc"
+
+// maxJSONRPCBodySize is the maximum size of a JSON-RPC request body.
+const maxJSONRPCBodySize = 1 << 20
+
+// Error codes defined by the JSON-RPC 2.0 specification.
+const (
+	JSONRPCParseError     = -32700
+	JSONRPCInvalidRequest = -32600
+	JSONRPCMethodNotFound = -32601
+	JSONRPCInvalidParams  = -32602
+	JSONRPCInternalError  = -32603
+)
+
+// JSONRPCError is an error object returned in JSON-RPC responses.
+type JSONRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *JSONRPCError) Error() string {
+	return fmt.Sprintf("JSON-RPC error %d: %s", e.Code, e.Message)
+}
+
+type jsonRPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type jsonRPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *JSONRPCError   `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// JSONRPCHandler is an HTTP handler that serves prices using the JSON-RPC 2.0
+// protocol. Batch requests are supported. Available methods are:
+//
+//	getPrice(pair)     - returns the price for the given pair
+//	getPrices([pairs]) - returns prices for the given pairs, or for all pairs
+//	                     if the list is empty
+//	listPairs()        - returns all supported pairs
+//
+// Prices are encoded the same way as by the JSON marshaller.
+type JSONRPCHandler struct {
+	provider provider.Provider
+	log      log.Logger
+}
+
+// NewJSONRPCHandler returns a new JSONRPCHandler instance.
+func NewJSONRPCHandler(provider provider.Provider, logger log.Logger) *JSONRPCHandler {
+	return &JSONRPCHandler{
+		provider: provider,
+		log:      logger,
+	}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *JSONRPCHandler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		res.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxJSONRPCBodySize))
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var resp interface{}
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var reqs []json.RawMessage
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			resp = errorResponse(nil, JSONRPCParseError, err.Error())
+		} else if len(reqs) == 0 {
+			resp = errorResponse(nil, JSONRPCInvalidRequest, "empty batch")
+		} else {
+			var resps []*jsonRPCResponse
+			for _, r := range reqs {
+				if rr := h.handle(r); rr != nil {
+					resps = append(resps, rr)
+				}
+			}
+			if len(resps) > 0 {
+				resp = resps
+			}
+		}
+	} else if r := h.handle(body); r != nil {
+		resp = r
+	}
+
+	if resp == nil {
+		// Only notifications were sent, so there is nothing to respond with.
+		res.WriteHeader(http.StatusNoContent)
+		return
+	}
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(res).Encode(resp)
+}
+
+// handle handles a single JSON-RPC request. It returns nil for notifications.
+func (h *JSONRPCHandler) handle(raw json.RawMessage) *jsonRPCResponse {
+	var req jsonRPCRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return errorResponse(nil, JSONRPCParseError, err.Error())
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse(req.ID, JSONRPCInvalidRequest, "invalid request")
+	}
+	h.log.WithField("method", req.Method).Info("JSON-RPC")
+	result, err := h.call(req.Method, req.Params)
+	if req.ID == nil {
+		return nil
+	}
+	if err != nil {
+		var rpcErr *JSONRPCError
+		if errors.As(err, &rpcErr) {
+			return errorResponse(req.ID, rpcErr.Code, rpcErr.Message)
+		}
+		return errorResponse(req.ID, JSONRPCInternalError, err.Error())
+	}
+	return &jsonRPCResponse{JSONRPC: "2.0", Result: result, ID: req.ID}
+}
+
+func (h *JSONRPCHandler) call(method string, params json.RawMessage) (json.RawMessage, error) {
+	switch method {
+	case "getPrice":
+		var pair string
+		if err := decodeParams(params, &pair); err != nil {
+			return nil, err
+		}
+		p, err := provider.NewPair(pair)
+		if err != nil {
+			return nil, &JSONRPCError{Code: JSONRPCInvalidParams, Message: err.Error()}
+		}
+		price, err := h.provider.Price(p)
+		if err != nil {
+			return nil, err
+		}
+		return marshalPrice(price)
+	case "getPrices":
+		var pairs []string
+		if err := decodeParams(params, &pairs); err != nil {
+			return nil, err
+		}
+		ps, err := provider.NewPairs(pairs...)
+		if err != nil {
+			return nil, &JSONRPCError{Code: JSONRPCInvalidParams, Message: err.Error()}
+		}
+		prices, err := h.provider.Prices(ps...)
+		if err != nil {
+			return nil, err
+		}
+		keys := make([]provider.Pair, 0, len(prices))
+		for pair := range prices {
+			keys = append(keys, pair)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+		list := make([]json.RawMessage, len(keys))
+		for i, pair := range keys {
+			if list[i], err = marshalPrice(prices[pair]); err != nil {
+				return nil, err
+			}
+		}
+		return json.Marshal(list)
+	case "listPairs":
+		pairs, err := h.provider.Pairs()
+		if err != nil {
+			return nil, err
+		}
+		list := make([]string, len(pairs))
+		for i, p := range pairs {
+			list[i] = p.String()
+		}
+		sort.Strings(list)
+		return json.Marshal(list)
+	default:
+		return nil, &JSONRPCError{Code: JSONRPCMethodNotFound, Message: fmt.Sprintf("method %s not found", method)}
+	}
+}
+
+// decodeParams decodes a single positional parameter. Missing parameters are
+// left with the zero value.
+func decodeParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 {
+		return nil
+	}
+	var args []json.RawMessage
+	if err := json.Unmarshal(params, &args); err != nil || len(args) > 1 {
+		return &JSONRPCError{Code: JSONRPCInvalidParams, Message: "expected a single positional parameter"}
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(args[0], v); err != nil {
+		return &JSONRPCError{Code: JSONRPCInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// marshalPrice encodes the price using the JSON marshaller.
+func marshalPrice(price *provider.Price) (json.RawMessage, error) {
+	mar, err := marshal.NewMarshal(marshal.NDJSON)
+	if err != nil {
+		return nil, err
+	}
+	// Prices are returned to other programs, so they must not be rounded:
+	mar.SetPrecision(-1)
+	buf := &bytes.Buffer{}
+	if err := mar.Write(buf, price); err != nil {
+		return nil, err
+	}
+	if err := mar.Flush(); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(buf.Bytes()), nil
+}
+
+func errorResponse(id json.RawMessage, code int, message string) *jsonRPCResponse {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return &jsonRPCResponse{
+		JSONRPC: "2.0",
+		Error:   &JSONRPCError{Code: code, Message: message},
+		ID:      id,
+	}
+}
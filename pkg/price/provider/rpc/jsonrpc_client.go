@@ -0,0 +1,167 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+)
+
+var ErrModelsNotSupported = errors.New("price models are not supported by the JSON-RPC client")
+
+// JSONRPCClient implements the provider.Provider interface. It fetches prices
+// from a remote Agent using the JSON-RPC endpoint served by the
+// JSONRPCHandler.
+//
+// Unlike the Provider, it does not have to be started and it does not
+// support the Models method.
+type JSONRPCClient struct {
+	url    string
+	client *http.Client
+}
+
+// NewJSONRPCClient returns a new JSONRPCClient instance. If the URL does not
+// contain a path, the JSONRPCPath is used. If client is nil, the default
+// HTTP client is used.
+func NewJSONRPCClient(endpoint string, client *http.Client) (*JSONRPCClient, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON-RPC server URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid JSON-RPC server URL: unsupported scheme %q", u.Scheme)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = JSONRPCPath
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &JSONRPCClient{url: u.String(), client: client}, nil
+}
+
+// Models implements the provider.Provider interface.
+func (c *JSONRPCClient) Models(_ ...provider.Pair) (map[provider.Pair]*provider.Model, error) {
+	return nil, ErrModelsNotSupported
+}
+
+// Price implements the provider.Provider interface.
+func (c *JSONRPCClient) Price(pair provider.Pair) (*provider.Price, error) {
+	var price jsonRPCPrice
+	if err := c.call("getPrice", pair.String(), &price); err != nil {
+		return nil, err
+	}
+	return price.toPrice(), nil
+}
+
+// Prices implements the provider.Provider interface.
+func (c *JSONRPCClient) Prices(pairs ...provider.Pair) (map[provider.Pair]*provider.Price, error) {
+	list := make([]string, len(pairs))
+	for i, p := range pairs {
+		list[i] = p.String()
+	}
+	var prices []jsonRPCPrice
+	if err := c.call("getPrices", list, &prices); err != nil {
+		return nil, err
+	}
+	res := make(map[provider.Pair]*provider.Price, len(prices))
+	for _, p := range prices {
+		price := p.toPrice()
+		res[price.Pair] = price
+	}
+	return res, nil
+}
+
+// Pairs implements the provider.Provider interface.
+func (c *JSONRPCClient) Pairs() ([]provider.Pair, error) {
+	var list []string
+	if err := c.call("listPairs", nil, &list); err != nil {
+		return nil, err
+	}
+	return provider.NewPairs(list...)
+}
+
+// call sends a JSON-RPC request and decodes the result into res. If param is
+// nil, the request is sent without parameters.
+func (c *JSONRPCClient) call(method string, param interface{}, res interface{}) error {
+	req := map[string]interface{}{"jsonrpc": "2.0", "method": method, "id": 1}
+	if param != nil {
+		req["params"] = []interface{}{param}
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpRes, err := c.client.Post(c.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer httpRes.Body.Close()
+	if httpRes.StatusCode != http.StatusOK {
+		return fmt.Errorf("JSON-RPC server returned unexpected status: %s", httpRes.Status)
+	}
+	var resp jsonRPCResponse
+	if err := json.NewDecoder(httpRes.Body).Decode(&resp); err != nil {
+		return fmt.Errorf("unable to decode JSON-RPC response: %w", err)
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	return json.Unmarshal(resp.Result, res)
+}
+
+// jsonRPCPrice is a price encoded by the JSON marshaller.
+type jsonRPCPrice struct {
+	Type       string            `json:"type"`
+	Base       string            `json:"base"`
+	Quote      string            `json:"quote"`
+	Price      float64           `json:"price"`
+	Bid        float64           `json:"bid"`
+	Ask        float64           `json:"ask"`
+	Volume24h  float64           `json:"vol24h"`
+	Timestamp  time.Time         `json:"ts"`
+	Parameters map[string]string `json:"params"`
+	Prices     []jsonRPCPrice    `json:"prices"`
+	Error      string            `json:"error"`
+	Warning    string            `json:"warning"`
+}
+
+func (p jsonRPCPrice) toPrice() *provider.Price {
+	var prices []*provider.Price
+	for _, c := range p.Prices {
+		prices = append(prices, c.toPrice())
+	}
+	return &provider.Price{
+		Type:       p.Type,
+		Parameters: p.Parameters,
+		Pair:       provider.Pair{Base: p.Base, Quote: p.Quote},
+		Price:      p.Price,
+		Bid:        p.Bid,
+		Ask:        p.Ask,
+		Volume24h:  p.Volume24h,
+		Time:       p.Timestamp,
+		Prices:     prices,
+		Error:      p.Error,
+		Warning:    p.Warning,
+	}
+}
@@ -0,0 +1,91 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/mocks"
+)
+
+func TestJSONRPCClient(t *testing.T) {
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	cd := provider.Pair{Base: "C", Quote: "D"}
+	ts := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	priceAB := &provider.Price{
+		Type:       "median",
+		Pair:       ab,
+		Price:      1.5,
+		Time:       ts,
+		Parameters: map[string]string{"minimumSuccessfulSources": "1"},
+		Prices:     []*provider.Price{{Type: "origin", Pair: ab, Price: 1.5, Time: ts}},
+	}
+	priceCD := &provider.Price{Type: "median", Pair: cd, Price: 2, Time: ts, Error: "failed"}
+
+	gof := &mocks.Provider{}
+	gof.On("Price", ab).Return(priceAB, nil)
+	gof.On("Prices", ab, cd).Return(map[provider.Pair]*provider.Price{ab: priceAB, cd: priceCD}, nil)
+	gof.On("Pairs").Return([]provider.Pair{cd, ab}, nil)
+
+	srv := httptest.NewServer(NewJSONRPCHandler(gof, null.New()))
+	defer srv.Close()
+
+	cli, err := NewJSONRPCClient(srv.URL, nil)
+	require.NoError(t, err)
+
+	price, err := cli.Price(ab)
+	require.NoError(t, err)
+	assert.Equal(t, priceAB, price)
+
+	prices, err := cli.Prices(ab, cd)
+	require.NoError(t, err)
+	assert.Equal(t, map[provider.Pair]*provider.Price{ab: priceAB, cd: priceCD}, prices)
+
+	pairs, err := cli.Pairs()
+	require.NoError(t, err)
+	assert.Equal(t, []provider.Pair{ab, cd}, pairs)
+
+	_, err = cli.Models()
+	assert.ErrorIs(t, err, ErrModelsNotSupported)
+}
+
+func TestJSONRPCClient_Error(t *testing.T) {
+	gof := &mocks.Provider{}
+	gof.On("Pairs").Return([]provider.Pair(nil), errors.New("failed"))
+
+	srv := httptest.NewServer(NewJSONRPCHandler(gof, null.New()))
+	defer srv.Close()
+
+	cli, err := NewJSONRPCClient(srv.URL+JSONRPCPath, nil)
+	require.NoError(t, err)
+
+	_, err = cli.Pairs()
+	var rpcErr *JSONRPCError
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, JSONRPCInternalError, rpcErr.Code)
+	assert.Equal(t, "failed", rpcErr.Message)
+
+	_, err = NewJSONRPCClient("tcp://localhost:8080", nil)
+	assert.Error(t, err)
+}
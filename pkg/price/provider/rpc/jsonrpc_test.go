@@ -0,0 +1,134 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/mocks"
+)
+
+func jsonRPCCall(t *testing.T, h http.Handler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, JSONRPCPath, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestJSONRPCHandler_Batch(t *testing.T) {
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	cd := provider.Pair{Base: "C", Quote: "D"}
+	gof := &mocks.Provider{}
+	gof.On("Price", ab).Return(&provider.Price{Type: "median", Pair: ab, Price: 1.5}, nil)
+	gof.On("Prices", ab, cd).Return(map[provider.Pair]*provider.Price{
+		ab: {Type: "median", Pair: ab, Price: 1.5},
+		cd: {Type: "median", Pair: cd, Price: 2},
+	}, nil)
+	gof.On("Pairs").Return([]provider.Pair{cd, ab}, nil)
+
+	rec := jsonRPCCall(t, NewJSONRPCHandler(gof, null.New()), `[
+		{"jsonrpc": "2.0", "method": "getPrice", "params": ["A/B"], "id": 1},
+		{"jsonrpc": "2.0", "method": "getPrices", "params": [["A/B", "C/D"]], "id": "two"},
+		{"jsonrpc": "2.0", "method": "listPairs", "id": 3},
+		{"jsonrpc": "2.0", "method": "listPairs"}
+	]`)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resps []struct {
+		Result json.RawMessage `json:"result"`
+		Error  *JSONRPCError   `json:"error"`
+		ID     json.RawMessage `json:"id"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resps))
+	require.Len(t, resps, 3) // The notification is not answered.
+
+	assert.JSONEq(t, `1`, string(resps[0].ID))
+	assert.Nil(t, resps[0].Error)
+	var price map[string]interface{}
+	require.NoError(t, json.Unmarshal(resps[0].Result, &price))
+	assert.Equal(t, "A/B", price["base"].(string)+"/"+price["quote"].(string))
+	assert.Equal(t, 1.5, price["price"])
+
+	assert.JSONEq(t, `"two"`, string(resps[1].ID))
+	var prices []map[string]interface{}
+	require.NoError(t, json.Unmarshal(resps[1].Result, &prices))
+	require.Len(t, prices, 2)
+	assert.Equal(t, "A", prices[0]["base"])
+	assert.Equal(t, "C", prices[1]["base"])
+
+	assert.JSONEq(t, `3`, string(resps[2].ID))
+	assert.JSONEq(t, `["A/B", "C/D"]`, string(resps[2].Result))
+}
+
+func TestJSONRPCHandler_Precision(t *testing.T) {
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	gof := &mocks.Provider{}
+	gof.On("Price", ab).Return(&provider.Price{Type: "median", Pair: ab, Price: 0.123456789012}, nil)
+
+	rec := jsonRPCCall(
+		t,
+		NewJSONRPCHandler(gof, null.New()),
+		`{"jsonrpc": "2.0", "method": "getPrice", "params": ["A/B"], "id": 1}`,
+	)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp struct {
+		Result map[string]interface{} `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, 0.123456789012, resp.Result["price"])
+}
+
+func TestJSONRPCHandler_Errors(t *testing.T) {
+	h := NewJSONRPCHandler(&mocks.Provider{}, null.New())
+	tests := []struct {
+		body string
+		want string
+	}{
+		{
+			body: `{"jsonrpc": "2.0", "method": "getVolume", "id": 7}`,
+			want: `{"jsonrpc": "2.0", "error": {"code": -32601, "message": "method getVolume not found"}, "id": 7}`,
+		},
+		{
+			body: `{"jsonrpc": "2.0", "method": "getPrice", "params": ["AB"], "id": 8}`,
+			want: `{"jsonrpc": "2.0", "error": {"code": -32602, "message": "couldn't parse pair \"AB\""}, "id": 8}`,
+		},
+		{
+			body: `{"method": "listPairs", "id": 9}`,
+			want: `{"jsonrpc": "2.0", "error": {"code": -32600, "message": "invalid request"}, "id": 9}`,
+		},
+		{
+			body: `{`,
+			want: `{"jsonrpc": "2.0", "error": {"code": -32700, "message": "unexpected end of JSON input"}, "id": null}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			rec := jsonRPCCall(t, h, tt.body)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, tt.want, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,188 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+)
+
+// PricesMaxAge is the time for which clients and CDNs may cache prices
+// served on the PricesPath. It is short, so cached prices are not much
+// older than prices in the Agent.
+const PricesMaxAge = 5 * time.Second
+
+// PricesPath is the HTTP path on which the Agent serves prices. A single
+// price can be requested by appending the pair to the path, e.g.
+// /v1/prices/BTC/USD.
+const PricesPath = "/v1/prices/"
+
+// PricesHandler is an HTTP handler that serves prices as JSON.
+//
+// Prices are encoded the same way as by the JSON marshaller, with two
+// additional fields: the age, which is the number of seconds since the
+// freshest price used to calculate the price was fetched, and the number of
+// sources that were successfully used to calculate the price.
+//
+// The Last-Modified header is set to the time of the freshest origin price,
+// so the middleware.Cache middleware can handle conditional requests. The
+// ETag cannot be used for that, because the age field makes every response
+// different.
+type PricesHandler struct {
+	provider provider.Provider
+	log      log.Logger
+	now      func() time.Time
+}
+
+// NewPricesHandler returns a new PricesHandler instance.
+func NewPricesHandler(provider provider.Provider, logger log.Logger) *PricesHandler {
+	return &PricesHandler{
+		provider: provider,
+		log:      logger,
+		now:      time.Now,
+	}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *PricesHandler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		res.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	var resp interface{}
+	var modified time.Time
+	if p := strings.TrimPrefix(req.URL.Path, PricesPath); p != "" {
+		pair, err := provider.NewPair(p)
+		if err != nil {
+			res.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		price, err := h.provider.Price(pair)
+		if err != nil {
+			h.log.WithError(err).Warn("Unable to get the price")
+			res.WriteHeader(http.StatusNotFound)
+			return
+		}
+		modified = lastModified(price)
+		if resp, err = h.marshalPrice(price); err != nil {
+			res.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	} else {
+		prices, err := h.provider.Prices()
+		if err != nil {
+			h.log.WithError(err).Warn("Unable to get prices")
+			res.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		pairs := make([]provider.Pair, 0, len(prices))
+		for pair := range prices {
+			pairs = append(pairs, pair)
+		}
+		sort.Slice(pairs, func(i, j int) bool {
+			return pairs[i].String() < pairs[j].String()
+		})
+		list := make([]json.RawMessage, 0, len(pairs))
+		for _, pair := range pairs {
+			if t := lastModified(prices[pair]); t.After(modified) {
+				modified = t
+			}
+			b, err := h.marshalPrice(prices[pair])
+			if err != nil {
+				res.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			list = append(list, b)
+		}
+		resp = list
+	}
+	res.Header().Set("Content-Type", "application/json")
+	if !modified.IsZero() {
+		res.Header().Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+	res.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(res).Encode(resp)
+}
+
+// lastModified returns the time of the freshest origin price used to
+// calculate the given prices, or a zero time if there is no such price.
+func lastModified(prices ...*provider.Price) time.Time {
+	var modified time.Time
+	for _, price := range prices {
+		if t, n := priceSources(price); n > 0 && t.After(modified) {
+			modified = t
+		}
+	}
+	return modified
+}
+
+// marshalPrice marshals the price and adds the age and sources fields.
+func (h *PricesHandler) marshalPrice(price *provider.Price) (json.RawMessage, error) {
+	b, err := marshalPrice(price)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	freshest, sources := priceSources(price)
+	age := 0.0
+	if sources > 0 {
+		age = h.now().Sub(freshest).Seconds()
+	}
+	if fields["age"], err = json.Marshal(age); err != nil {
+		return nil, err
+	}
+	if fields["sources"], err = json.Marshal(sources); err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	if err := json.NewEncoder(buf).Encode(fields); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(buf.Bytes()), nil
+}
+
+// priceSources returns the time of the freshest origin price used to
+// calculate the given price and the number of such origin prices. Origin
+// prices with errors are skipped.
+func priceSources(price *provider.Price) (freshest time.Time, sources int) {
+	if price.Error != "" {
+		return time.Time{}, 0
+	}
+	if price.Type == "origin" {
+		return price.Time, 1
+	}
+	for _, p := range price.Prices {
+		t, n := priceSources(p)
+		if n == 0 {
+			continue
+		}
+		sources += n
+		if t.After(freshest) {
+			freshest = t
+		}
+	}
+	return freshest, sources
+}
@@ -0,0 +1,110 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/mocks"
+)
+
+func testMedianPrice(pair provider.Pair, t1, t2 time.Time) *provider.Price {
+	return &provider.Price{
+		Type:  "aggregator",
+		Pair:  pair,
+		Price: 1.5,
+		Time:  t1,
+		Prices: []*provider.Price{
+			{Type: "origin", Pair: pair, Price: 1, Time: t1},
+			{Type: "origin", Pair: pair, Price: 2, Time: t2},
+			{Type: "origin", Pair: pair, Price: 3, Time: t2.Add(time.Minute), Error: "failed"},
+		},
+	}
+}
+
+func TestPricesHandler(t *testing.T) {
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	now := time.Unix(1000, 0)
+	gof := &mocks.Provider{}
+	h := NewPricesHandler(gof, null.New())
+	h.now = func() time.Time { return now }
+
+	get := func(path string) map[string]interface{} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var price map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &price))
+		return price
+	}
+
+	// The freshest successful price is 10 seconds old, the failed origin is
+	// not counted:
+	gof.On("Price", ab).Return(testMedianPrice(ab, now.Add(-30*time.Second), now.Add(-10*time.Second)), nil).Once()
+	price := get(PricesPath + "A/B")
+	assert.Equal(t, 1.5, price["price"])
+	assert.Equal(t, float64(10), price["age"])
+	assert.Equal(t, float64(2), price["sources"])
+
+	// After a fresh feed, the age is updated:
+	gof.On("Price", ab).Return(testMedianPrice(ab, now.Add(-5*time.Second), now.Add(-2*time.Second)), nil).Once()
+	price = get(PricesPath + "A/B")
+	assert.Equal(t, float64(2), price["age"])
+	assert.Equal(t, float64(2), price["sources"])
+}
+
+func TestPricesHandler_All(t *testing.T) {
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	cd := provider.Pair{Base: "C", Quote: "D"}
+	now := time.Unix(1000, 0)
+	gof := &mocks.Provider{}
+	gof.On("Prices").Return(map[provider.Pair]*provider.Price{
+		cd: {Type: "origin", Pair: cd, Price: 2, Time: now.Add(-time.Second)},
+		ab: testMedianPrice(ab, now.Add(-30*time.Second), now.Add(-10*time.Second)),
+	}, nil)
+	h := NewPricesHandler(gof, null.New())
+	h.now = func() time.Time { return now }
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, PricesPath, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var prices []map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &prices))
+	require.Len(t, prices, 2)
+	assert.Equal(t, "A", prices[0]["base"])
+	assert.Equal(t, float64(10), prices[0]["age"])
+	assert.Equal(t, float64(2), prices[0]["sources"])
+	assert.Equal(t, "C", prices[1]["base"])
+	assert.Equal(t, float64(1), prices[1]["age"])
+	assert.Equal(t, float64(1), prices[1]["sources"])
+}
+
+func TestPricesHandler_InvalidPair(t *testing.T) {
+	h := NewPricesHandler(&mocks.Provider{}, null.New())
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, PricesPath+"AB", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,214 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/httpserver/middleware"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+)
+
+// SubscribePath is the HTTP path on which the Agent serves WebSocket price
+// subscriptions.
+const SubscribePath = "/v1/subscribe"
+
+// subscriberBufferSize is the number of messages that may be queued for
+// a single client. Clients that do not keep up are disconnected.
+const subscriberBufferSize = 16
+
+// subscriberTimeout is the time limit for a client to send the subscription
+// request and for a single message to be written to a client.
+const subscriberTimeout = 10 * time.Second
+
+// Subscriber is implemented by providers that update prices asynchronously.
+type Subscriber interface {
+	// Subscribe returns a channel that receives a value every time prices
+	// are updated and a function that cancels the subscription.
+	Subscribe() (<-chan struct{}, func())
+}
+
+type subscribeRequest struct {
+	Pairs []string `json:"pairs"`
+}
+
+// SubscribeHandler is an HTTP handler that pushes price updates to
+// WebSocket clients.
+//
+// After connecting, a client sends the list of pairs it is interested in,
+// e.g. {"pairs": ["BTC/USD"]}. Then, every time any of these prices is
+// updated, the client receives a JSON array with the updated prices, encoded
+// the same way as by the JSON marshaller.
+//
+// Browsers allow WebSocket connections to any host, so connections from
+// web pages on other origins are rejected, unless their origins are
+// allowed by the SetAllowedOrigins method.
+type SubscribeHandler struct {
+	provider   provider.Provider
+	subscriber Subscriber
+	upgrader   websocket.Upgrader
+	log        log.Logger
+}
+
+// NewSubscribeHandler returns a new SubscribeHandler instance.
+func NewSubscribeHandler(provider provider.Provider, subscriber Subscriber, logger log.Logger) *SubscribeHandler {
+	return &SubscribeHandler{
+		provider:   provider,
+		subscriber: subscriber,
+		log:        logger,
+	}
+}
+
+// SetAllowedOrigins sets origins of web pages, other than the agent's
+// origin, that are allowed to subscribe to prices. The "*" origin allows
+// all origins. It must be called before the handler is used.
+func (h *SubscribeHandler) SetAllowedOrigins(origins []string) {
+	allowed := middleware.AllowedOrigins(origins)
+	h.upgrader.CheckOrigin = func(r *http.Request) bool {
+		return sameOrigin(r) || allowed(r) != ""
+	}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *SubscribeHandler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	conn, err := h.upgrader.Upgrade(res, req, nil)
+	if err != nil {
+		return // Upgrade already responded with an error.
+	}
+	defer conn.Close()
+
+	pairs, err := h.readRequest(conn)
+	if err != nil {
+		_ = conn.SetWriteDeadline(time.Now().Add(subscriberTimeout))
+		_ = conn.WriteJSON(map[string]string{"error": err.Error()})
+		return
+	}
+	updates, unsubscribe := h.subscriber.Subscribe()
+	defer unsubscribe()
+
+	// Clients are not expected to send anything more, so reading is used
+	// only to detect disconnects.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	// Messages are written in a separate goroutine, so a slow client does not
+	// block the loop below. If its queue fills up, the client is dropped.
+	send := make(chan []byte, subscriberBufferSize)
+	defer close(send)
+	go func() {
+		for msg := range send {
+			_ = conn.SetWriteDeadline(time.Now().Add(subscriberTimeout))
+			if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				_ = conn.Close()
+				return
+			}
+		}
+	}()
+
+	last := map[provider.Pair]time.Time{}
+	for {
+		select {
+		case <-closed:
+			return
+		case <-updates:
+			msg, err := h.updatedPrices(pairs, last)
+			if err != nil {
+				h.log.WithError(err).Warn("Unable to get prices for a subscriber")
+				continue
+			}
+			if msg == nil {
+				continue
+			}
+			select {
+			case send <- msg:
+			default:
+				h.log.Warn("Subscriber is too slow, closing the connection")
+				return
+			}
+		}
+	}
+}
+
+// readRequest reads the subscription request from the client.
+func (h *SubscribeHandler) readRequest(conn *websocket.Conn) ([]provider.Pair, error) {
+	var sr subscribeRequest
+	_ = conn.SetReadDeadline(time.Now().Add(subscriberTimeout))
+	if err := conn.ReadJSON(&sr); err != nil {
+		return nil, err
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+	if len(sr.Pairs) == 0 {
+		return nil, errors.New("at least one pair must be given")
+	}
+	return provider.NewPairs(sr.Pairs...)
+}
+
+// updatedPrices returns a message with prices that changed since the last
+// message. The last map is updated with times of returned prices. If no
+// prices changed, nil is returned.
+func (h *SubscribeHandler) updatedPrices(pairs []provider.Pair, last map[provider.Pair]time.Time) ([]byte, error) {
+	prices, err := h.provider.Prices(pairs...)
+	if err != nil {
+		return nil, err
+	}
+	var list []json.RawMessage
+	for _, pair := range pairs {
+		price, ok := prices[pair]
+		if !ok || price.Time.Equal(last[pair]) {
+			continue
+		}
+		bts, err := marshalPrice(price)
+		if err != nil {
+			return nil, err
+		}
+		last[pair] = price.Time
+		list = append(list, bts)
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(list)
+}
+
+// sameOrigin returns true if the request does not have the Origin header,
+// which is the case for clients other than browsers, or if the origin has
+// the same host as the request.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
@@ -0,0 +1,159 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/mocks"
+)
+
+type fakeSubscriber struct {
+	updates      chan struct{}
+	subscribed   chan struct{}
+	unsubscribed chan struct{}
+}
+
+func newFakeSubscriber() *fakeSubscriber {
+	return &fakeSubscriber{
+		updates:      make(chan struct{}),
+		subscribed:   make(chan struct{}),
+		unsubscribed: make(chan struct{}),
+	}
+}
+
+func (f *fakeSubscriber) Subscribe() (<-chan struct{}, func()) {
+	close(f.subscribed)
+	return f.updates, func() { close(f.unsubscribed) }
+}
+
+func waitFor(t *testing.T, ch chan struct{}, msg string) {
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		require.Fail(t, msg)
+	}
+}
+
+func TestSubscribeHandler(t *testing.T) {
+	ab := provider.Pair{Base: "A", Quote: "B"}
+	t1 := time.Unix(1000, 0)
+	t2 := time.Unix(2000, 0)
+	gof := &mocks.Provider{}
+	gof.On("Prices", ab).Return(map[provider.Pair]*provider.Price{ab: {Pair: ab, Price: 1, Time: t1}}, nil).Twice()
+	gof.On("Prices", ab).Return(map[provider.Pair]*provider.Price{ab: {Pair: ab, Price: 2, Time: t2}}, nil).Once()
+	sub := newFakeSubscriber()
+
+	srv := httptest.NewServer(NewSubscribeHandler(gof, sub, null.New()))
+	defer srv.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(subscribeRequest{Pairs: []string{"A/B"}}))
+	waitFor(t, sub.subscribed, "client was not subscribed")
+
+	// The second update does not change the price, so only two messages
+	// are expected:
+	sub.updates <- struct{}{}
+	sub.updates <- struct{}{}
+	sub.updates <- struct{}{}
+	for _, want := range []float64{1, 2} {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		var prices []map[string]interface{}
+		require.NoError(t, conn.ReadJSON(&prices))
+		require.Len(t, prices, 1)
+		assert.Equal(t, "A", prices[0]["base"])
+		assert.Equal(t, want, prices[0]["price"])
+	}
+
+	// Disconnecting the client cancels the subscription:
+	require.NoError(t, conn.Close())
+	waitFor(t, sub.unsubscribed, "client was not unsubscribed")
+}
+
+func TestSubscribeHandler_InvalidPair(t *testing.T) {
+	srv := httptest.NewServer(NewSubscribeHandler(&mocks.Provider{}, newFakeSubscriber(), null.New()))
+	defer srv.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(subscribeRequest{Pairs: []string{"AB"}}))
+	var resp map[string]string
+	require.NoError(t, conn.ReadJSON(&resp))
+	assert.Contains(t, resp["error"], "AB")
+
+	// The connection is closed after the error:
+	_, _, err = conn.ReadMessage()
+	assert.Error(t, err)
+}
+
+func TestSubscribeHandler_NoPairs(t *testing.T) {
+	srv := httptest.NewServer(NewSubscribeHandler(&mocks.Provider{}, newFakeSubscriber(), null.New()))
+	defer srv.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(subscribeRequest{}))
+	var resp map[string]string
+	require.NoError(t, conn.ReadJSON(&resp))
+	assert.NotEmpty(t, resp["error"])
+}
+
+func TestSubscribeHandler_AllowedOrigins(t *testing.T) {
+	h := NewSubscribeHandler(&mocks.Provider{}, newFakeSubscriber(), null.New())
+	h.SetAllowedOrigins([]string{"https://example.com"})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	tests := []struct {
+		origin  string
+		wantErr bool
+	}{
+		{origin: ""},
+		{origin: srv.URL},
+		{origin: "https://example.com"},
+		{origin: "https://other.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			header := http.Header{}
+			if tt.origin != "" {
+				header.Set("Origin", tt.origin)
+			}
+			conn, _, err := websocket.DefaultDialer.Dial(url, header)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			_ = conn.Close()
+		})
+	}
+}
@@ -79,3 +79,21 @@ func TestPriceStore_Feeder(t *testing.T) {
 	assert.Equal(t, testutil.PriceAAABBB2, errutil.Must(ms.GetByFeeder(ctx, "AAABBB", testutil.Address1)))
 	assert.Equal(t, testutil.PriceXXXYYY2, errutil.Must(ms.GetByFeeder(ctx, "XXXYYY", testutil.Address1)))
 }
+
+func TestPriceStore_Add_OnePricePerFeeder(t *testing.T) {
+	ctx := context.Background()
+	ms := NewMemoryStorage()
+
+	// Multiple prices from the same feeder must be counted only once:
+	require.NoError(t, ms.Add(ctx, testutil.Address1, testutil.PriceAAABBB1))
+	require.NoError(t, ms.Add(ctx, testutil.Address1, testutil.PriceAAABBB3))
+	require.NoError(t, ms.Add(ctx, testutil.Address1, testutil.PriceAAABBB2))
+	require.NoError(t, ms.Add(ctx, testutil.Address2, testutil.PriceAAABBB1))
+
+	aaabbb := errutil.Must(ms.GetByAssetPair(ctx, "AAABBB"))
+
+	assert.Len(t, aaabbb, 2)
+	assert.Contains(t, aaabbb, testutil.PriceAAABBB3)
+	assert.Contains(t, aaabbb, testutil.PriceAAABBB1)
+	assert.NotContains(t, aaabbb, testutil.PriceAAABBB2)
+}
@@ -0,0 +1,81 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+)
+
+// Snapshot is a point-in-time copy of all prices held by the price store.
+type Snapshot struct {
+	// Time is the time at which the snapshot was taken.
+	Time time.Time `json:"time"`
+	// Prices is the list of prices sorted by asset pair and feeder.
+	Prices []SnapshotPrice `json:"prices"`
+}
+
+// SnapshotPrice is a price sent by a feeder.
+type SnapshotPrice struct {
+	AssetPair string           `json:"assetPair"`
+	Feeder    ethereum.Address `json:"feeder"`
+	Message   *messages.Price  `json:"message"`
+}
+
+// Snapshot returns a copy of all prices held by the store. It is safe to
+// call the method concurrently with Add.
+func (p *PriceStore) Snapshot(ctx context.Context) (*Snapshot, error) {
+	all, err := p.storage.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s := &Snapshot{Time: time.Now(), Prices: make([]SnapshotPrice, 0, len(all))}
+	for fp, msg := range all {
+		s.Prices = append(s.Prices, SnapshotPrice{AssetPair: fp.AssetPair, Feeder: fp.Feeder, Message: msg})
+	}
+	sort.Slice(s.Prices, func(i, j int) bool {
+		if s.Prices[i].AssetPair != s.Prices[j].AssetPair {
+			return s.Prices[i].AssetPair < s.Prices[j].AssetPair
+		}
+		return s.Prices[i].Feeder.String() < s.Prices[j].Feeder.String()
+	})
+	return s, nil
+}
+
+// Export writes the snapshot of the store to w as JSON.
+func (p *PriceStore) Export(ctx context.Context, w io.Writer) error {
+	s, err := p.Snapshot(ctx)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(s)
+}
+
+// Map returns prices from the snapshot in the same format as the GetAll
+// method of the store.
+func (s *Snapshot) Map() map[FeederPrice]*messages.Price {
+	m := make(map[FeederPrice]*messages.Price, len(s.Prices))
+	for _, p := range s.Prices {
+		m[FeederPrice{AssetPair: p.AssetPair, Feeder: p.Feeder}] = p.Message
+	}
+	return m
+}
@@ -0,0 +1,109 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/store/testutil"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/local"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+)
+
+func newSnapshotTestStore(t *testing.T, ms *MemoryStorage) *PriceStore {
+	ps, err := New(Config{
+		Storage:   ms,
+		Signer:    &mocks.Signer{},
+		Transport: local.New([]byte("test"), 0, nil),
+		Pairs:     []string{"AAABBB", "XXXYYY"},
+	})
+	require.NoError(t, err)
+	return ps
+}
+
+func TestPriceStore_Export(t *testing.T) {
+	ctx := context.Background()
+	ms := NewMemoryStorage()
+	require.NoError(t, ms.Add(ctx, testutil.Address1, testutil.PriceAAABBB1))
+	require.NoError(t, ms.Add(ctx, testutil.Address2, testutil.PriceAAABBB2))
+	require.NoError(t, ms.Add(ctx, testutil.Address1, testutil.PriceXXXYYY1))
+	require.NoError(t, ms.Add(ctx, testutil.Address2, testutil.PriceXXXYYY2))
+	ps := newSnapshotTestStore(t, ms)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, ps.Export(ctx, buf))
+
+	var s Snapshot
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &s))
+	assert.False(t, s.Time.IsZero())
+
+	// Prices are sorted by asset pair and feeder:
+	require.Len(t, s.Prices, 4)
+	assert.Equal(t, "AAABBB", s.Prices[0].AssetPair)
+	assert.Equal(t, testutil.Address1, s.Prices[0].Feeder)
+	assert.Equal(t, "AAABBB", s.Prices[1].AssetPair)
+	assert.Equal(t, testutil.Address2, s.Prices[1].Feeder)
+	assert.Equal(t, "XXXYYY", s.Prices[2].AssetPair)
+	assert.Equal(t, "XXXYYY", s.Prices[3].AssetPair)
+
+	// Exported prices, including ages and signatures, must be equal to
+	// the stored ones:
+	all, err := ps.GetAll(ctx)
+	require.NoError(t, err)
+	exported := s.Map()
+	require.Len(t, exported, len(all))
+	for fp, msg := range all {
+		require.Contains(t, exported, fp)
+		assert.Equal(t, msg.Price, exported[fp].Price)
+	}
+}
+
+func TestPriceStore_Snapshot_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	ps := newSnapshotTestStore(t, NewMemoryStorage())
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_ = ps.storage.Add(ctx, ethereum.Address{byte(i)}, &messages.Price{
+				Price: &oracle.Price{Wat: "AAABBB", Val: big.NewInt(int64(i + 1)), Age: time.Unix(int64(i), 0)},
+			})
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		_, err := ps.Snapshot(ctx)
+		require.NoError(t, err)
+	}
+	wg.Wait()
+
+	s, err := ps.Snapshot(ctx)
+	require.NoError(t, err)
+	assert.Len(t, s.Prices, 100)
+}
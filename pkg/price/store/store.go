@@ -19,9 +19,11 @@ import (
 	"context"
 	"errors"
 	"math/big"
+	"sync/atomic"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/correlation"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
@@ -32,6 +34,8 @@ const LoggerTag = "PRICE_STORE"
 var ErrInvalidSignature = errors.New("received price has an invalid signature")
 var ErrInvalidPrice = errors.New("received price is invalid")
 var ErrUnknownPair = errors.New("received pair is not configured")
+var ErrUnknownFeeder = errors.New("received price is signed by an unknown feeder")
+var ErrInvalidDomain = errors.New("received price is not signed for the configured domain")
 
 // PriceStore contains a list of prices.
 type PriceStore struct {
@@ -40,8 +44,11 @@ type PriceStore struct {
 	signer    ethereum.Signer
 	transport transport.Transport
 	pairs     []string
+	feeds     []ethereum.Address
+	domain    string
 	log       log.Logger
 	waitCh    chan error
+	rejected  uint64
 }
 
 // Config is the configuration for Storage.
@@ -56,6 +63,13 @@ type Config struct {
 	Transport transport.Transport
 	// Pairs is the list of asset pairs which are supported by the store.
 	Pairs []string
+	// Feeds is the list of feeders whose prices are accepted by the store.
+	// If empty, prices from all feeders are accepted.
+	Feeds []ethereum.Address
+	// Domain is the network identifier. If set, only prices signed for
+	// this domain are accepted, which prevents prices signed for another
+	// network from being replayed.
+	Domain string
 	// Logger is a current logger interface used by the PriceStore.
 	// The Logger is required to monitor asynchronous processes.
 	Logger log.Logger
@@ -99,6 +113,8 @@ func New(cfg Config) (*PriceStore, error) {
 		signer:    cfg.Signer,
 		transport: cfg.Transport,
 		pairs:     cfg.Pairs,
+		feeds:     cfg.Feeds,
+		domain:    cfg.Domain,
 		log:       cfg.Logger.WithField("tag", LoggerTag),
 		waitCh:    make(chan error),
 	}, nil
@@ -126,8 +142,21 @@ func (p *PriceStore) Wait() chan error {
 
 // Add adds a new price to the list. If a price from same feeder already
 // exists, the newer one will be used.
+//
+// The price is rejected if its signature is invalid, does not belong to the
+// given feeder, the feeder is not on the list of allowed feeders or the price
+// is not signed for the configured domain.
 func (p *PriceStore) Add(ctx context.Context, from ethereum.Address, msg *messages.Price) error {
-	return p.storage.Add(ctx, from, msg)
+	signer, err := msg.Price.From(p.signer)
+	if err != nil || *signer != from {
+		return p.reject(ctx, msg, ErrInvalidSignature)
+	}
+	return p.add(ctx, from, msg)
+}
+
+// Rejected returns the number of prices rejected by the store.
+func (p *PriceStore) Rejected() uint64 {
+	return atomic.LoadUint64(&p.rejected)
 }
 
 // GetAll returns all prices.
@@ -145,18 +174,39 @@ func (p *PriceStore) GetByFeeder(ctx context.Context, pair string, feeder ethere
 	return p.storage.GetByFeeder(ctx, pair, feeder)
 }
 
-func (p *PriceStore) collectPrice(price *messages.Price) error {
+// add adds a price whose signature has already been verified.
+func (p *PriceStore) add(ctx context.Context, from ethereum.Address, msg *messages.Price) error {
+	if !p.isFeederAllowed(from) {
+		return p.reject(ctx, msg, ErrUnknownFeeder)
+	}
+	if !p.isDomainValid(from, msg) {
+		return p.reject(ctx, msg, ErrInvalidDomain)
+	}
+	return p.storage.Add(ctx, from, msg)
+}
+
+// reject increases the rejected prices counter and returns the given error.
+func (p *PriceStore) reject(ctx context.Context, msg *messages.Price, err error) error {
+	atomic.AddUint64(&p.rejected, 1)
+	correlation.Logger(ctx, p.log).
+		WithError(err).
+		WithFields(msg.Price.Fields(p.signer)).
+		Debug("Price rejected")
+	return err
+}
+
+func (p *PriceStore) collectPrice(ctx context.Context, price *messages.Price) error {
 	from, err := price.Price.From(p.signer)
 	if err != nil {
-		return ErrInvalidSignature
+		return p.reject(ctx, price, ErrInvalidSignature)
 	}
 	if !p.isPairSupported(price.Price.Wat) {
-		return ErrUnknownPair
+		return p.reject(ctx, price, ErrUnknownPair)
 	}
 	if price.Price.Val.Cmp(big.NewInt(0)) <= 0 {
-		return ErrInvalidPrice
+		return p.reject(ctx, price, ErrInvalidPrice)
 	}
-	return p.Add(p.ctx, *from, price)
+	return p.add(ctx, *from, price)
 }
 
 func (p *PriceStore) isPairSupported(pair string) bool {
@@ -168,6 +218,29 @@ func (p *PriceStore) isPairSupported(pair string) bool {
 	return false
 }
 
+func (p *PriceStore) isFeederAllowed(from ethereum.Address) bool {
+	if len(p.feeds) == 0 {
+		return true
+	}
+	for _, f := range p.feeds {
+		if f == from {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *PriceStore) isDomainValid(from ethereum.Address, msg *messages.Price) bool {
+	if p.domain == "" {
+		return true
+	}
+	if msg.Price.Domain != p.domain {
+		return false
+	}
+	signer, err := msg.Price.DomainFrom(p.signer)
+	return err == nil && *signer == from
+}
+
 func (p *PriceStore) priceCollectorRoutine() {
 	for {
 		select {
@@ -191,14 +264,27 @@ func (p *PriceStore) handlePriceMessage(msg transport.ReceivedMessage) {
 		p.log.Error("Unexpected value returned from the transport layer")
 		return
 	}
-	err := p.collectPrice(price)
+	// Log entries related to the price use the correlation ID of the feed
+	// cycle in which the price was created. The ID is supplied by a peer
+	// and is not signed, so malformed IDs are dropped:
+	ctx := p.ctx
+	if correlation.IsValidID(price.CorrelationID) {
+		ctx = correlation.WithID(ctx, price.CorrelationID)
+	}
+	logger := correlation.Logger(ctx, p.log)
+	err := p.collectPrice(ctx, price)
+	if errors.Is(err, ErrInvalidSignature) {
+		if r, ok := p.transport.(transport.Reporter); ok {
+			r.ReportInvalid(msg)
+		}
+	}
 	if err != nil {
-		p.log.
+		logger.
 			WithError(err).
 			WithFields(price.Price.Fields(p.signer)).
 			Warn("Received invalid price")
 	} else {
-		p.log.
+		logger.
 			WithFields(price.Price.Fields(p.signer)).
 			WithField("version", price.Version).
 			Info("Price received")
@@ -17,6 +17,8 @@ package store
 
 import (
 	"context"
+	"errors"
+	"math/big"
 	"testing"
 	"time"
 
@@ -27,7 +29,11 @@ import (
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/store/testutil"
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/errutil"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/callback"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/correlation"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
@@ -80,6 +86,124 @@ func TestStore(t *testing.T) {
 	assert.Contains(t, toOraclePrices(xxxyyy), testutil.PriceXXXYYY2.Price)
 }
 
+func TestStore_Add(t *testing.T) {
+	ctx := context.Background()
+	sig := &mocks.Signer{}
+	tra := local.New([]byte("test"), 0, map[string]transport.Message{messages.PriceV0MessageName: (*messages.Price)(nil)})
+
+	ps, err := New(Config{
+		Signer:    sig,
+		Storage:   NewMemoryStorage(),
+		Transport: tra,
+		Pairs:     []string{"AAABBB"},
+		Feeds:     []ethereum.Address{testutil.Address1},
+		Logger:    null.New(),
+	})
+	require.NoError(t, err)
+
+	sig.On("Recover", testutil.PriceAAABBB1.Price.Signature(), mock.Anything).Return(&testutil.Address1, nil)
+	sig.On("Recover", testutil.PriceAAABBB2.Price.Signature(), mock.Anything).Return(&testutil.Address2, nil)
+	sig.On("Recover", testutil.PriceAAABBB3.Price.Signature(), mock.Anything).Return((*ethereum.Address)(nil), errors.New("invalid signature"))
+
+	// Correctly signed price from an allowed feeder.
+	assert.NoError(t, ps.Add(ctx, testutil.Address1, testutil.PriceAAABBB1))
+
+	// Price signed by a feeder that is not allowed.
+	assert.ErrorIs(t, ps.Add(ctx, testutil.Address2, testutil.PriceAAABBB2), ErrUnknownFeeder)
+
+	// Signature does not belong to the given feeder.
+	assert.ErrorIs(t, ps.Add(ctx, testutil.Address1, testutil.PriceAAABBB2), ErrInvalidSignature)
+
+	// Corrupted signature.
+	assert.ErrorIs(t, ps.Add(ctx, testutil.Address1, testutil.PriceAAABBB3), ErrInvalidSignature)
+
+	prices, err := ps.GetByAssetPair(ctx, "AAABBB")
+	require.NoError(t, err)
+	assert.Equal(t, []*oracle.Price{testutil.PriceAAABBB1.Price}, toOraclePrices(prices))
+	assert.Equal(t, uint64(3), ps.Rejected())
+}
+
+type reportingTransport struct {
+	*local.Local
+	reported []transport.ReceivedMessage
+}
+
+func (r *reportingTransport) ReportInvalid(msg transport.ReceivedMessage) {
+	r.reported = append(r.reported, msg)
+}
+
+func TestStore_ReportInvalid(t *testing.T) {
+	sig := &mocks.Signer{}
+	tra := &reportingTransport{Local: local.New([]byte("test"), 0, nil)}
+
+	ps, err := New(Config{
+		Signer:    sig,
+		Storage:   NewMemoryStorage(),
+		Transport: tra,
+		Pairs:     []string{"AAABBB"},
+		Logger:    null.New(),
+	})
+	require.NoError(t, err)
+
+	sig.On("Recover", testutil.PriceAAABBB1.Price.Signature(), mock.Anything).Return(&testutil.Address1, nil)
+	sig.On("Recover", testutil.PriceAAABBB3.Price.Signature(), mock.Anything).Return((*ethereum.Address)(nil), errors.New("invalid signature"))
+
+	valid := transport.ReceivedMessage{Message: testutil.PriceAAABBB1}
+	invalid := transport.ReceivedMessage{Message: testutil.PriceAAABBB3}
+	ps.ctx = context.Background()
+	ps.handlePriceMessage(valid)
+	ps.handlePriceMessage(invalid)
+	ps.handlePriceMessage(invalid)
+
+	// Only messages with invalid signatures should be reported:
+	assert.Equal(t, []transport.ReceivedMessage{invalid, invalid}, tra.reported)
+}
+
+func TestStore_Domain(t *testing.T) {
+	ctx := context.Background()
+	sig := &mocks.Signer{}
+	tra := local.New([]byte("test"), 0, map[string]transport.Message{messages.PriceV0MessageName: (*messages.Price)(nil)})
+
+	ps, err := New(Config{
+		Signer:    sig,
+		Storage:   NewMemoryStorage(),
+		Transport: tra,
+		Pairs:     []string{"AAABBB"},
+		Domain:    "mainnet",
+		Logger:    null.New(),
+	})
+	require.NoError(t, err)
+
+	newPrice := func(domain string, domainSig ethereum.Signature) *messages.Price {
+		return &messages.Price{Price: &oracle.Price{
+			Wat:       "AAABBB",
+			Val:       big.NewInt(10),
+			Age:       time.Unix(100, 0),
+			V:         1,
+			Domain:    domain,
+			DomainSig: domainSig,
+		}}
+	}
+
+	sig.On("Recover", ethereum.SignatureFromVRS(1, [32]byte{}, [32]byte{}), mock.Anything).Return(&testutil.Address1, nil)
+	sig.On("Recover", ethereum.Signature{1}, mock.Anything).Return(&testutil.Address1, nil)
+	sig.On("Recover", ethereum.Signature{2}, mock.Anything).Return(&testutil.Address2, nil)
+
+	// Price signed for the same network.
+	assert.NoError(t, ps.Add(ctx, testutil.Address1, newPrice("mainnet", ethereum.Signature{1})))
+
+	// Price signed for a different network.
+	assert.ErrorIs(t, ps.Add(ctx, testutil.Address1, newPrice("testnet", ethereum.Signature{1})), ErrInvalidDomain)
+
+	// Price without a domain.
+	assert.ErrorIs(t, ps.Add(ctx, testutil.Address1, newPrice("", ethereum.Signature{})), ErrInvalidDomain)
+
+	// Domain signature does not belong to the feeder.
+	assert.ErrorIs(t, ps.Add(ctx, testutil.Address1, newPrice("mainnet", ethereum.Signature{2})), ErrInvalidDomain)
+
+	assert.Equal(t, uint64(3), ps.Rejected())
+}
+
 func toOraclePrices(ps []*messages.Price) []*oracle.Price {
 	var r []*oracle.Price
 	for _, p := range ps {
@@ -87,3 +211,40 @@ func toOraclePrices(ps []*messages.Price) []*oracle.Price {
 	}
 	return r
 }
+
+func TestStore_CorrelationID(t *testing.T) {
+	sig := &mocks.Signer{}
+	tra := local.New([]byte("test"), 0, nil)
+
+	logs := map[string][]interface{}{}
+	ps, err := New(Config{
+		Signer:    sig,
+		Storage:   NewMemoryStorage(),
+		Transport: tra,
+		Pairs:     []string{"AAABBB"},
+		Logger: callback.New(log.Debug, func(_ log.Level, fields log.Fields, msg string) {
+			logs[msg] = append(logs[msg], fields[correlation.LogField])
+		}),
+	})
+	require.NoError(t, err)
+
+	sig.On("Recover", testutil.PriceAAABBB1.Price.Signature(), mock.Anything).Return(&testutil.Address1, nil)
+	sig.On("Recover", testutil.PriceAAABBB3.Price.Signature(), mock.Anything).Return((*ethereum.Address)(nil), errors.New("invalid signature"))
+
+	valid := *testutil.PriceAAABBB1
+	valid.CorrelationID = "0123456789abcdef"
+	invalid := *testutil.PriceAAABBB3
+	invalid.CorrelationID = "fedcba9876543210"
+	malformed := *testutil.PriceAAABBB1
+	malformed.CorrelationID = "foo\nbar"
+	ps.ctx = context.Background()
+	ps.handlePriceMessage(transport.ReceivedMessage{Message: &valid})
+	ps.handlePriceMessage(transport.ReceivedMessage{Message: &invalid})
+	ps.handlePriceMessage(transport.ReceivedMessage{Message: &malformed})
+
+	// Log entries must contain the correlation ID of the received message,
+	// unless the ID is malformed:
+	assert.Equal(t, []interface{}{"0123456789abcdef", nil}, logs["Price received"])
+	assert.Equal(t, []interface{}{"fedcba9876543210"}, logs["Price rejected"])
+	assert.Equal(t, []interface{}{"fedcba9876543210"}, logs["Received invalid price"])
+}
@@ -48,8 +48,10 @@ func (p *prices) messages() []*messages.Price {
 	return p.prices
 }
 
-// oraclePrices returns oracle prices.
+// oraclePrices returns oracle prices sorted by value, as required by the
+// Oracle contract.
 func (p *prices) oraclePrices() []*oracle.Price {
+	p.sort()
 	var prices []*oracle.Price
 	for _, price := range p.prices {
 		prices = append(prices, price.Price)
@@ -74,6 +76,44 @@ func (p *prices) truncate(n int64) {
 	p.prices = p.prices[0:n]
 }
 
+// truncateDiverse works like truncate, but it prefers prices from different
+// groups, so the remaining prices span as many distinct groups as possible.
+// The group function returns the group of the given price.
+func (p *prices) truncateDiverse(n int64, group func(*messages.Price) string) {
+	if int64(len(p.prices)) <= n {
+		return
+	}
+
+	rand.Shuffle(len(p.prices), func(i, j int) {
+		p.prices[i], p.prices[j] = p.prices[j], p.prices[i]
+	})
+
+	// Move prices from groups that were not seen yet to the beginning of the
+	// list. The rest of the prices remains in random order.
+	seen := map[string]bool{}
+	var first, rest []*messages.Price
+	for _, price := range p.prices {
+		g := group(price)
+		if seen[g] {
+			rest = append(rest, price)
+			continue
+		}
+		seen[g] = true
+		first = append(first, price)
+	}
+
+	p.prices = append(first, rest...)[0:n]
+}
+
+// groups returns the number of distinct groups of prices in the list.
+func (p *prices) groups(group func(*messages.Price) string) int {
+	seen := map[string]bool{}
+	for _, price := range p.prices {
+		seen[group(price)] = true
+	}
+	return len(seen)
+}
+
 // median calculates the median price for all messages in the list.
 func (p *prices) median() *big.Int {
 	count := len(p.prices)
@@ -81,10 +121,7 @@ func (p *prices) median() *big.Int {
 		return big.NewInt(0)
 	}
 
-	sort.Slice(p.prices, func(i, j int) bool {
-		return p.prices[i].Price.Val.Cmp(p.prices[j].Price.Val) < 0
-	})
-
+	p.sort()
 	if count%2 == 0 {
 		m := count / 2
 		x1 := p.prices[m-1].Price.Val
@@ -95,10 +132,19 @@ func (p *prices) median() *big.Int {
 	return p.prices[(count-1)/2].Price.Val
 }
 
+// sort sorts messages by price value in ascending order.
+func (p *prices) sort() {
+	sort.Slice(p.prices, func(i, j int) bool {
+		return p.prices[i].Price.Val.Cmp(p.prices[j].Price.Val) < 0
+	})
+}
+
 // spread calculates the spread between given price and a median price.
-// The spread is returned as percentage points.
+// The spread is returned as percentage points. If the given price is zero or
+// negative, e.g. because the Oracle contract was just deployed, the spread is
+// infinite, so it always exceeds the threshold.
 func (p *prices) spread(price *big.Int) float64 {
-	if len(p.prices) == 0 || price.Cmp(big.NewInt(0)) == 0 {
+	if len(p.prices) == 0 || price.Sign() <= 0 {
 		return math.Inf(1)
 	}
 
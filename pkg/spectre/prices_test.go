@@ -67,6 +67,12 @@ func TestPrices_oraclePrices(t *testing.T) {
 	assert.Contains(t, ps.oraclePrices(), testutil.PriceAAABBB2.Price)
 	assert.Contains(t, ps.oraclePrices(), testutil.PriceAAABBB3.Price)
 	assert.Contains(t, ps.oraclePrices(), testutil.PriceAAABBB4.Price)
+
+	// Prices must be sorted by value:
+	op := ps.oraclePrices()
+	for i := 1; i < len(op); i++ {
+		assert.True(t, op[i-1].Val.Cmp(op[i].Val) <= 0)
+	}
 }
 
 func TestPrices_truncate(t *testing.T) {
@@ -145,6 +151,14 @@ func TestPrices_spread(t *testing.T) {
 			price: 50,
 			want:  50,
 		},
+		{
+			price: 1000,
+			want:  97.5,
+		},
+		{
+			price: -10,
+			want:  math.Inf(1),
+		},
 	}
 	for n, tt := range tests {
 		t.Run("Case:"+strconv.Itoa(n+1), func(t *testing.T) {
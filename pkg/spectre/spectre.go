@@ -19,45 +19,140 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
+	"math/rand"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/correlation"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/store"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
 )
 
 const LoggerTag = "SPECTRE"
 
-type errNotEnoughPricesForQuorum struct {
+// ErrNoQuorum is returned when an Oracle needs to be updated, but there
+// is not enough prices to achieve a quorum.
+type ErrNoQuorum struct {
 	AssetPair string
 }
 
-func (e errNotEnoughPricesForQuorum) Error() string {
+func (e ErrNoQuorum) Error() string {
 	return fmt.Sprintf(
 		"unable to update the Oracle for %s pair, there is not enough prices to achieve a quorum",
 		e.AssetPair,
 	)
 }
 
-type errUnknownAsset struct {
+// ErrUnknownAsset is returned when the asset pair is not configured.
+type ErrUnknownAsset struct {
 	AssetPair string
 }
 
-func (e errUnknownAsset) Error() string {
+func (e ErrUnknownAsset) Error() string {
 	return fmt.Sprintf("pair %s does not exists", e.AssetPair)
 }
 
-type errNoPrices struct {
+// ErrNoPrices is returned when there are no prices for the asset pair in the
+// price store.
+type ErrNoPrices struct {
 	AssetPair string
 }
 
-func (e errNoPrices) Error() string {
+func (e ErrNoPrices) Error() string {
 	return fmt.Sprintf("there is no prices in the priceStore for %s pair", e.AssetPair)
 }
 
+// ErrSpreadTooLow is returned when the Oracle does not need to be updated
+// because it is not expired yet and the spread between the Oracle price
+// and the median of prices is lower than required. It does not indicate a
+// failure.
+type ErrSpreadTooLow struct {
+	AssetPair string
+	Spread    float64
+	MinSpread float64
+}
+
+func (e ErrSpreadTooLow) Error() string {
+	return fmt.Sprintf(
+		"the Oracle for %s pair is not expired and the spread %f is lower than %f",
+		e.AssetPair,
+		e.Spread,
+		e.MinSpread,
+	)
+}
+
+// ErrNoDiversity is returned when an Oracle needs to be updated, but the
+// prices that achieve a quorum come from too few feeder groups.
+type ErrNoDiversity struct {
+	AssetPair string
+	Groups    int
+	MinGroups int
+}
+
+func (e ErrNoDiversity) Error() string {
+	return fmt.Sprintf(
+		"unable to update the Oracle for %s pair, prices come from %d feeder groups but at least %d are required",
+		e.AssetPair,
+		e.Groups,
+		e.MinGroups,
+	)
+}
+
+// ErrPokeTooExpensive is returned when an Oracle is stale, but not expired
+// yet, and the estimated cost of the update exceeds the budget. It does not
+// indicate a failure.
+type ErrPokeTooExpensive struct {
+	AssetPair string
+	Cost      *big.Int
+	MaxCost   *big.Int
+}
+
+func (e ErrPokeTooExpensive) Error() string {
+	return fmt.Sprintf(
+		"the Oracle for %s pair is not expired and the estimated update cost %s wei exceeds %s wei",
+		e.AssetPair,
+		e.Cost,
+		e.MaxCost,
+	)
+}
+
+// ErrCallTimeout is returned when a call to an Oracle contract does not
+// finish within the configured call timeout.
+type ErrCallTimeout struct {
+	AssetPair string
+	Method    string
+	Timeout   time.Duration
+}
+
+func (e ErrCallTimeout) Error() string {
+	return fmt.Sprintf(
+		"the %s call to the Oracle for %s pair did not finish within %s",
+		e.Method,
+		e.AssetPair,
+		e.Timeout,
+	)
+}
+
+// Clock provides the current time to Spectre. It is used to check if prices
+// and Oracles have expired.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the Clock that uses the system time.
+type systemClock struct{}
+
+// Now implements the Clock interface.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
 type Spectre struct {
 	ctx    context.Context
 	mu     sync.Mutex
@@ -65,9 +160,16 @@ type Spectre struct {
 
 	signer     ethereum.Signer
 	priceStore *store.PriceStore
+	batchPoker oracle.BatchPoker
 	interval   time.Duration
+	jitter     float64
+	timeout    time.Duration
 	log        log.Logger
+	clock      Clock
 	pairs      map[string]*Pair
+
+	feederGroups    map[ethereum.Address]string
+	minFeederGroups int
 }
 
 // Config is the configuration for Spectre.
@@ -77,11 +179,33 @@ type Config struct {
 	PriceStore *store.PriceStore
 	// Interval describes how often we should try to update Oracles.
 	Interval time.Duration
+	// IntervalJitter is the maximum fraction of the Interval by which the
+	// first update is randomly delayed. Because every instance picks its own
+	// delay, relayers that share the same configuration do not try to update
+	// Oracles at the same moment. Must be between 0 and 1.
+	IntervalJitter float64
+	// CallTimeout is the time limit for a single call to an Oracle
+	// contract. If a call times out, the ErrCallTimeout error is returned
+	// and Spectre moves on to the next pair. If zero, calls are not limited.
+	CallTimeout time.Duration
 	// Pairs is the list supported pairs by Spectre with their configuration.
 	Pairs []*Pair
+	// BatchPoker is optional. If provided, all Oracles that require an
+	// update are updated in a single transaction.
+	BatchPoker oracle.BatchPoker
+	// FeederGroups maps feeder addresses to operator groups. Feeders that
+	// are not listed belong to their own, separate group.
+	FeederGroups map[ethereum.Address]string
+	// MinFeederGroups is the minimum number of distinct feeder groups that
+	// prices sent to an Oracle must come from. If zero, the diversity of
+	// feeders is not checked.
+	MinFeederGroups int
 	// Logger is a current logger interface used by the Spectre. The Logger is
 	// required to monitor asynchronous processes.
 	Logger log.Logger
+	// Clock is optional. It provides the current time used to check if
+	// prices and Oracles have expired. If nil, the system time is used.
+	Clock Clock
 }
 
 type Pair struct {
@@ -91,7 +215,8 @@ type Pair struct {
 	// required to send update.
 	OracleSpread float64
 	// OracleExpiration is the minimum time difference between the Oracle time
-	// and current time required to send an update.
+	// and current time required to send an update. An expired Oracle is
+	// updated regardless of the spread, as long as a quorum is achieved.
 	OracleExpiration time.Duration
 	// PriceExpiration is the maximum amount of time before price received
 	// from the feeder will be considered as expired.
@@ -99,6 +224,26 @@ type Pair struct {
 	// Median is the instance of the oracle.Median which is the interface for
 	// the Oracle contract.
 	Median oracle.Median
+	// MinQuorum is the minimum number of valid prices required to update
+	// the Oracle. If it is greater than the quorum of the Oracle contract,
+	// it is used as the effective quorum. Only as many prices as required by
+	// the contract are sent to the Oracle, and the spread is calculated on
+	// these prices only. If zero, the contract quorum is used.
+	MinQuorum int64
+	// MaxPokeCost is the maximum estimated cost, in wei, of an update sent
+	// because of the spread. Expired Oracles are updated regardless of the
+	// cost. If nil, or if the Median does not implement the
+	// oracle.PokeEstimator interface, the cost is not checked.
+	MaxPokeCost *big.Int
+}
+
+// quorum returns the effective quorum for the given quorum of the Oracle
+// contract.
+func (p *Pair) quorum(bar int64) int64 {
+	if p.MinQuorum > bar {
+		return p.MinQuorum
+	}
+	return bar
 }
 
 func NewSpectre(cfg Config) (*Spectre, error) {
@@ -108,16 +253,35 @@ func NewSpectre(cfg Config) (*Spectre, error) {
 	if cfg.PriceStore == nil {
 		return nil, errors.New("price store must not be nil")
 	}
+	if cfg.IntervalJitter < 0 || cfg.IntervalJitter > 1 {
+		return nil, errors.New("interval jitter must be between 0 and 1")
+	}
+	if cfg.CallTimeout < 0 {
+		return nil, errors.New("call timeout must not be negative")
+	}
+	if cfg.MinFeederGroups < 0 {
+		return nil, errors.New("minimum number of feeder groups must not be negative")
+	}
 	if cfg.Logger == nil {
 		cfg.Logger = null.New()
 	}
+	if cfg.Clock == nil {
+		cfg.Clock = systemClock{}
+	}
 	r := &Spectre{
 		waitCh:     make(chan error),
 		signer:     cfg.Signer,
 		priceStore: cfg.PriceStore,
+		batchPoker: cfg.BatchPoker,
 		interval:   cfg.Interval,
+		jitter:     cfg.IntervalJitter,
+		timeout:    cfg.CallTimeout,
 		pairs:      make(map[string]*Pair),
 		log:        cfg.Logger.WithField("tag", LoggerTag),
+		clock:      cfg.Clock,
+
+		feederGroups:    cfg.FeederGroups,
+		minFeederGroups: cfg.MinFeederGroups,
 	}
 	for _, p := range cfg.Pairs {
 		r.pairs[p.AssetPair] = p
@@ -145,84 +309,320 @@ func (s *Spectre) Wait() chan error {
 }
 
 // relay tries to update an Oracle contract for given pair. It'll return
-// transaction hash or nil if there is no need to update Oracle.
-func (s *Spectre) relay(assetPair string) (*ethereum.Hash, error) {
+// transaction hash or the ErrSpreadTooLow error if there is no need to
+// update Oracle. The outcome is logged together with the Oracle state and
+// the correlation ID carried by the context.
+func (s *Spectre) relay(ctx context.Context, assetPair string) (*ethereum.Hash, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	logger := correlation.Logger(ctx, s.log)
+
 	pair, ok := s.pairs[assetPair]
 	if !ok {
-		return nil, errUnknownAsset{AssetPair: assetPair}
+		err := ErrUnknownAsset{AssetPair: assetPair}
+		logger.
+			WithFields(log.Fields{"assetPair": assetPair}).
+			WithError(err).
+			Warn("Unable to update Oracle")
+		return nil, err
 	}
 
-	pricesSlice, err := s.priceStore.GetByAssetPair(context.Background(), assetPair)
+	prices, fields, err := s.pricesToPoke(ctx, pair)
+	if errors.As(err, &ErrSpreadTooLow{}) {
+		logger.
+			WithFields(fields).
+			Info("Oracle price is still valid")
+		return nil, err
+	}
+	if errors.As(err, &ErrPokeTooExpensive{}) {
+		logger.
+			WithFields(fields).
+			Info("Oracle update is too expensive")
+		return nil, err
+	}
 	if err != nil {
+		logger.
+			WithFields(fields).
+			WithError(err).
+			Warn("Unable to update Oracle")
 		return nil, err
 	}
 
+	// Send *actual* transaction to the Ethereum network:
+	var tx *ethereum.Hash
+	err = s.call(ctx, assetPair, "poke", func(ctx context.Context) (err error) {
+		tx, err = pair.Median.Poke(ctx, prices, true)
+		return err
+	})
+	if err != nil {
+		logger.
+			WithFields(fields).
+			WithError(err).
+			Warn("Unable to update Oracle")
+		return nil, err
+	}
+	fields["tx"] = tx.String()
+	logger.
+		WithFields(fields).
+		Info("Oracle updated")
+	return tx, nil
+}
+
+// relayBatch tries to update all Oracle contracts that require an update
+// in a single transaction. It'll return transaction hash and the list of
+// updated pairs or nil if there is no need to update any Oracle. Errors for
+// individual pairs are logged.
+func (s *Spectre) relayBatch(ctx context.Context) (*ethereum.Hash, []string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	logger := correlation.Logger(ctx, s.log)
+	var pokes []oracle.Poke
+	var assetPairs []string
+	for assetPair, pair := range s.pairs {
+		prices, fields, err := s.pricesToPoke(ctx, pair)
+		if errors.As(err, &ErrSpreadTooLow{}) {
+			logger.
+				WithFields(fields).
+				Info("Oracle price is still valid")
+			continue
+		}
+		if errors.As(err, &ErrPokeTooExpensive{}) {
+			logger.
+				WithFields(fields).
+				Info("Oracle update is too expensive")
+			continue
+		}
+		if err != nil {
+			logger.
+				WithFields(fields).
+				WithError(err).
+				Warn("Unable to update Oracle")
+			continue
+		}
+		logger.
+			WithFields(fields).
+			Debug("Oracle will be updated in a batch")
+		pokes = append(pokes, oracle.Poke{Address: pair.Median.Address(), Prices: prices})
+		assetPairs = append(assetPairs, assetPair)
+	}
+	if len(pokes) == 0 {
+		return nil, nil, nil
+	}
+
+	// Send *actual* transaction to the Ethereum network:
+	var tx *ethereum.Hash
+	err := s.call(ctx, strings.Join(assetPairs, ", "), "batchPoke", func(ctx context.Context) (err error) {
+		tx, err = s.batchPoker.BatchPoke(ctx, pokes, true)
+		return err
+	})
+	return tx, assetPairs, err
+}
+
+// call calls fn with a context that is canceled once the call timeout is
+// exceeded. If fn fails because of the timeout, the ErrCallTimeout error is
+// returned instead of the original one.
+func (s *Spectre) call(ctx context.Context, assetPair, method string, fn func(ctx context.Context) error) error {
+	if s.timeout == 0 {
+		return fn(ctx)
+	}
+	callCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	err := fn(callCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		return ErrCallTimeout{AssetPair: assetPair, Method: method, Timeout: s.timeout}
+	}
+	return err
+}
+
+// truncate reduces the number of prices in the list to n. If the minimum
+// number of feeder groups is set, prices from different groups are
+// preferred.
+func (s *Spectre) truncate(pricesList *prices, n int64) {
+	if s.minFeederGroups > 0 {
+		pricesList.truncateDiverse(n, s.feederGroup)
+	} else {
+		pricesList.truncate(n)
+	}
+}
+
+// checkPokeCost returns the ErrPokeTooExpensive error if the estimated cost
+// of updating the Oracle with the given prices exceeds the budget of the
+// pair. The estimated cost is added to the log fields.
+func (s *Spectre) checkPokeCost(ctx context.Context, pair *Pair, prices []*oracle.Price, fields log.Fields) error {
+	if pair.MaxPokeCost == nil {
+		return nil
+	}
+	estimator, ok := pair.Median.(oracle.PokeEstimator)
+	if !ok {
+		return nil
+	}
+	var cost *big.Int
+	err := s.call(ctx, pair.AssetPair, "estimatePoke", func(ctx context.Context) (err error) {
+		cost, err = estimator.EstimatePoke(ctx, prices)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	fields["pokeCost"] = cost.String()
+	if cost.Cmp(pair.MaxPokeCost) > 0 {
+		return ErrPokeTooExpensive{AssetPair: pair.AssetPair, Cost: cost, MaxCost: pair.MaxPokeCost}
+	}
+	return nil
+}
+
+// pricesToPoke returns prices that should be sent to the Oracle contract
+// for given pair or the ErrSpreadTooLow error if there is no need to update
+// Oracle. The ErrPokeTooExpensive error is returned if the Oracle is stale,
+// but the update is too expensive. It also returns log fields describing the Oracle state, which
+// are filled as far as the state could be determined, also on errors.
+//
+//nolint:funlen
+func (s *Spectre) pricesToPoke(ctx context.Context, pair *Pair) ([]*oracle.Price, log.Fields, error) {
+	assetPair := pair.AssetPair
+	fields := log.Fields{
+		"assetPair":     assetPair,
+		"oracleAddress": pair.Median.Address().String(),
+	}
+
+	pricesSlice, err := s.priceStore.GetByAssetPair(ctx, assetPair)
+	if err != nil {
+		return nil, fields, err
+	}
+
 	pricesList := newPricesList(pricesSlice)
 	if pricesList == nil || pricesList.len() == 0 {
-		return nil, errNoPrices{AssetPair: assetPair}
+		return nil, fields, ErrNoPrices{AssetPair: assetPair}
 	}
 
-	oracleQuorum, err := pair.Median.Bar(s.ctx)
+	var oracleBar int64
+	err = s.call(ctx, assetPair, "bar", func(ctx context.Context) (err error) {
+		oracleBar, err = pair.Median.Bar(ctx)
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return nil, fields, err
 	}
-	oracleTime, err := pair.Median.Age(s.ctx)
+	oracleQuorum := pair.quorum(oracleBar)
+	fields["quorum"] = oracleQuorum
+	if oracleQuorum != oracleBar {
+		fields["bar"] = oracleBar
+	}
+	var oracleTime time.Time
+	err = s.call(ctx, assetPair, "age", func(ctx context.Context) (err error) {
+		oracleTime, err = pair.Median.Age(ctx)
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return nil, fields, err
 	}
-	oraclePrice, err := pair.Median.Val(s.ctx)
+	fields["age"] = oracleTime.String()
+	var oraclePrice *big.Int
+	err = s.call(ctx, assetPair, "val", func(ctx context.Context) (err error) {
+		oraclePrice, err = pair.Median.Val(ctx)
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return nil, fields, err
 	}
+	fields["val"] = oraclePrice.String()
 
 	// Clear expired prices:
-	pricesList.clearOlderThan(time.Now().Add(-1 * pair.PriceExpiration))
+	now := s.clock.Now()
+	pricesList.clearOlderThan(now.Add(-1 * pair.PriceExpiration))
 	pricesList.clearOlderThan(oracleTime)
 
-	// Use only a minimum prices required to achieve a quorum:
-	pricesList.truncate(oracleQuorum)
+	// The Oracle contract accepts exactly as many prices as its quorum, so
+	// the list is truncated before the spread is calculated to decide on
+	// exactly the prices that will be sent:
+	available := int64(pricesList.len())
+	s.truncate(pricesList, oracleBar)
+	if s.minFeederGroups > 0 {
+		fields["feederGroups"] = pricesList.groups(s.feederGroup)
+	}
 
 	spread := pricesList.spread(oraclePrice)
-	isExpired := oracleTime.Add(pair.OracleExpiration).Before(time.Now())
+	isExpired := oracleTime.Add(pair.OracleExpiration).Before(now)
 	isStale := spread >= pair.OracleSpread
+	fields["prices"] = pricesList.len()
+	if oracleQuorum != oracleBar {
+		fields["availablePrices"] = available
+	}
+	fields["spread"] = spread
+	fields["expired"] = isExpired
+	fields["stale"] = isStale
 
 	// Print logs:
-	s.log.
+	logger := correlation.Logger(ctx, s.log)
+	logger.
+		WithFields(fields).
 		WithFields(log.Fields{
-			"assetPair":        assetPair,
-			"bar":              oracleQuorum,
-			"age":              oracleTime.String(),
-			"val":              oraclePrice.String(),
-			"expired":          isExpired,
-			"stale":            isStale,
 			"oracleExpiration": pair.OracleExpiration.String(),
 			"oracleSpread":     pair.OracleSpread,
-			"timeToExpiration": time.Since(oracleTime).String(),
-			"currentSpread":    spread,
+			"timeToExpiration": now.Sub(oracleTime).String(),
 		}).
 		Debug("Trying to update Oracle")
-	for _, price := range pricesList.oraclePrices() {
-		s.log.
-			WithFields(price.Fields(s.signer)).
+	for _, msg := range pricesList.messages() {
+		// The feed correlation ID links the price to the feed cycle in
+		// which it was created:
+		feedFields := msg.Price.Fields(s.signer)
+		if msg.CorrelationID != "" {
+			feedFields["feedCorrelationID"] = msg.CorrelationID
+		}
+		logger.
+			WithFields(feedFields).
 			Debug("Feed")
 	}
 
 	if isExpired || isStale {
 		// Check if there are enough prices to achieve a quorum:
-		if int64(pricesList.len()) != oracleQuorum {
-			return nil, errNotEnoughPricesForQuorum{AssetPair: assetPair}
+		if available < oracleQuorum || int64(pricesList.len()) != oracleBar {
+			return nil, fields, ErrNoQuorum{AssetPair: assetPair}
 		}
 
-		// Send *actual* transaction to the Ethereum network:
-		tx, err := pair.Median.Poke(s.ctx, pricesList.oraclePrices(), true)
-		return tx, err
+		// Check if prices come from enough distinct feeder groups:
+		if s.minFeederGroups > 0 {
+			if groups := pricesList.groups(s.feederGroup); groups < s.minFeederGroups {
+				return nil, fields, ErrNoDiversity{
+					AssetPair: assetPair,
+					Groups:    groups,
+					MinGroups: s.minFeederGroups,
+				}
+			}
+		}
+
+		// Skip updates caused only by the spread if they are too expensive:
+		oraclePrices := pricesList.oraclePrices()
+		if !isExpired {
+			if err := s.checkPokeCost(ctx, pair, oraclePrices, fields); err != nil {
+				return nil, fields, err
+			}
+		}
+
+		return oraclePrices, fields, nil
 	}
 
 	// There is no need to update Oracle:
-	return nil, nil
+	return nil, fields, ErrSpreadTooLow{
+		AssetPair: assetPair,
+		Spread:    spread,
+		MinSpread: pair.OracleSpread,
+	}
+}
+
+// feederGroup returns the operator group of the feeder that signed the
+// given price. Feeders without a configured group belong to their own group.
+func (s *Spectre) feederGroup(price *messages.Price) string {
+	from, err := price.Price.From(s.signer)
+	if err != nil {
+		return ""
+	}
+	if g, ok := s.feederGroups[*from]; ok {
+		return "group:" + g
+	}
+	return "feeder:" + from.String()
 }
 
 // relayerLoop creates a asynchronous loop which tries to send an update
@@ -232,42 +632,67 @@ func (s *Spectre) relayerLoop() {
 		return
 	}
 
-	ticker := time.NewTicker(s.interval)
 	go func() {
+		// Delay the first tick to spread updates from different instances
+		// across the interval:
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-time.After(jitterDelay(s.interval, s.jitter, rand.Float64)):
+		}
+		ticker := time.NewTicker(s.interval)
 		for {
 			select {
 			case <-s.ctx.Done():
 				ticker.Stop()
 				return
 			case <-ticker.C:
-				for assetPair := range s.pairs {
-					tx, err := s.relay(assetPair)
-
-					// Print log in case of an error:
-					if err != nil {
-						s.log.
-							WithFields(log.Fields{"assetPair": assetPair}).
-							WithError(err).
-							Warn("Unable to update Oracle")
-					}
-					// Print log if there was no need to update prices:
-					if err == nil && tx == nil {
-						s.log.
-							WithFields(log.Fields{"assetPair": assetPair}).
-							Info("Oracle price is still valid")
-					}
-					// Print log if Oracle update transaction was sent:
-					if tx != nil {
-						s.log.
-							WithFields(log.Fields{"assetPair": assetPair, "tx": tx.String()}).
-							Info("Oracle updated")
-					}
+				if s.batchPoker != nil {
+					s.relayAllBatch()
+				} else {
+					s.relayAll()
 				}
 			}
 		}
 	}()
 }
 
+// relayAll tries to update Oracles for all pairs, one by one. The outcome
+// for each pair is logged by the relay method. All log entries of a single
+// call share the same correlation ID.
+func (s *Spectre) relayAll() {
+	ctx := correlation.WithNewID(s.ctx)
+	for assetPair := range s.pairs {
+		_, _ = s.relay(ctx, assetPair)
+	}
+}
+
+// relayAllBatch tries to update Oracles for all pairs in a single
+// transaction. All log entries of a single call share the same correlation
+// ID.
+func (s *Spectre) relayAllBatch() {
+	ctx := correlation.WithNewID(s.ctx)
+	logger := correlation.Logger(ctx, s.log)
+	tx, assetPairs, err := s.relayBatch(ctx)
+	if err != nil {
+		logger.
+			WithFields(log.Fields{"assetPairs": assetPairs}).
+			WithError(err).
+			Warn("Unable to update Oracles")
+	}
+	if tx != nil {
+		logger.
+			WithFields(log.Fields{"assetPairs": assetPairs, "tx": tx.String()}).
+			Info("Oracles updated")
+	}
+}
+
+// jitterDelay returns a random delay between zero and the given fraction of
+// the interval. The rnd function must return a number in the [0, 1) range.
+func jitterDelay(interval time.Duration, jitter float64, rnd func() float64) time.Duration {
+	return time.Duration(float64(interval) * jitter * rnd())
+}
+
 func (s *Spectre) contextCancelHandler() {
 	defer func() { close(s.waitCh) }()
 	defer s.log.Info("Stopped")
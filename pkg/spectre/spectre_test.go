@@ -0,0 +1,680 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package spectre
+
+import (
+	"context"
+	"math/big"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/callback"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/correlation"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
+	oracleGeth "github.com/chronicleprotocol/oracle-suite/pkg/price/oracle/geth"
+	oracleTestutil "github.com/chronicleprotocol/oracle-suite/pkg/price/oracle/testutil"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/store"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/local"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+)
+
+func newTestSpectre(t *testing.T, pair *Pair, vals ...int64) *Spectre {
+	sig := &mocks.Signer{}
+	sig.On("Recover", mock.Anything, mock.Anything).Return(&ethereum.Address{}, nil)
+
+	ms := store.NewMemoryStorage()
+	for i, val := range vals {
+		require.NoError(t, ms.Add(context.Background(), ethereum.Address{byte(i + 1)}, &messages.Price{
+			Price: &oracle.Price{Wat: pair.AssetPair, Val: big.NewInt(val), Age: time.Now()},
+		}))
+	}
+	ps, err := store.New(store.Config{
+		Storage:   ms,
+		Signer:    sig,
+		Transport: local.New([]byte("test"), 0, nil),
+		Pairs:     []string{pair.AssetPair},
+	})
+	require.NoError(t, err)
+
+	s, err := NewSpectre(Config{Signer: sig, PriceStore: ps, Pairs: []*Pair{pair}})
+	require.NoError(t, err)
+	return s
+}
+
+// fakeClock is a Clock whose time is changed only by the advance method.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestSpectre_relay_Clock(t *testing.T) {
+	start := time.Now()
+	clock := &fakeClock{now: start}
+
+	// The Oracle expires exactly one second after the start:
+	median := oracleTestutil.NewSimulatedMedian(ethereum.Address{}, "AAABBB", 3, nil)
+	median.SetState(big.NewInt(100), start.Add(-time.Hour+time.Second))
+	pair := &Pair{
+		AssetPair:        "AAABBB",
+		OracleSpread:     1,
+		OracleExpiration: time.Hour,
+		PriceExpiration:  time.Hour,
+		Median:           median,
+	}
+	s := newTestSpectre(t, pair, 100, 100, 100)
+	s.ctx = context.Background()
+	s.clock = clock
+
+	// The spread is zero, so the Oracle is updated only after it expires:
+	_, err := s.relay(context.Background(), "AAABBB")
+	assert.ErrorAs(t, err, &ErrSpreadTooLow{})
+
+	clock.advance(time.Second)
+	_, err = s.relay(context.Background(), "AAABBB")
+	assert.ErrorAs(t, err, &ErrSpreadTooLow{})
+	assert.Empty(t, median.Pokes())
+
+	clock.advance(time.Nanosecond)
+	_, err = s.relay(context.Background(), "AAABBB")
+	require.NoError(t, err)
+	assert.Len(t, median.Pokes(), 1)
+}
+
+func TestSpectre_relay_Clock_PriceExpiration(t *testing.T) {
+	start := time.Now()
+	clock := &fakeClock{now: start}
+
+	median := oracleTestutil.NewSimulatedMedian(ethereum.Address{}, "AAABBB", 3, nil)
+	median.SetState(big.NewInt(100), start.Add(-2*time.Hour))
+	pair := &Pair{
+		AssetPair:        "AAABBB",
+		OracleSpread:     1,
+		OracleExpiration: time.Hour,
+		PriceExpiration:  time.Hour,
+		Median:           median,
+	}
+	s := newTestSpectre(t, pair, 100, 100, 100)
+	s.ctx = context.Background()
+	s.clock = clock
+
+	// After the price expiration, prices are no longer used:
+	clock.advance(time.Hour + time.Minute)
+	_, err := s.relay(context.Background(), "AAABBB")
+	assert.Equal(t, ErrNoQuorum{AssetPair: "AAABBB"}, err)
+	assert.Empty(t, median.Pokes())
+}
+
+func TestSpectre_pricesToPoke(t *testing.T) {
+	tests := []struct {
+		name      string
+		oracleAge time.Duration
+		oracleVal int64
+		prices    []int64
+		wantErr   error
+	}{
+		{
+			name:      "expired-low-spread",
+			oracleAge: 2 * time.Hour,
+			oracleVal: 100,
+			prices:    []int64{100, 100, 100},
+		},
+		{
+			name:      "not-expired-low-spread",
+			oracleAge: time.Minute,
+			oracleVal: 100,
+			prices:    []int64{100, 100, 100},
+			wantErr:   ErrSpreadTooLow{AssetPair: "AAABBB", Spread: 0, MinSpread: 1},
+		},
+		{
+			name:      "not-expired-high-spread",
+			oracleAge: time.Minute,
+			oracleVal: 100,
+			prices:    []int64{110, 110, 110},
+		},
+		{
+			name:      "expired-no-quorum",
+			oracleAge: 2 * time.Hour,
+			oracleVal: 100,
+			prices:    []int64{100, 100},
+			wantErr:   ErrNoQuorum{AssetPair: "AAABBB"},
+		},
+		{
+			name:      "no-prices",
+			oracleAge: 2 * time.Hour,
+			oracleVal: 100,
+			wantErr:   ErrNoPrices{AssetPair: "AAABBB"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			median := oracleTestutil.NewSimulatedMedian(ethereum.Address{}, "AAABBB", 3, nil)
+			median.SetState(big.NewInt(tt.oracleVal), time.Now().Add(-tt.oracleAge))
+			pair := &Pair{
+				AssetPair:        "AAABBB",
+				OracleSpread:     1,
+				OracleExpiration: time.Hour,
+				PriceExpiration:  time.Hour,
+				Median:           median,
+			}
+			s := newTestSpectre(t, pair, tt.prices...)
+			prices, _, err := s.pricesToPoke(context.Background(), pair)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				assert.Nil(t, prices)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, prices, len(tt.prices))
+		})
+	}
+}
+
+func TestSpectre_relay_UnknownAsset(t *testing.T) {
+	pair := &Pair{AssetPair: "AAABBB", Median: oracleTestutil.NewSimulatedMedian(ethereum.Address{}, "AAABBB", 3, nil)}
+	s := newTestSpectre(t, pair)
+	_, err := s.relay(context.Background(), "XXXYYY")
+	assert.Equal(t, ErrUnknownAsset{AssetPair: "XXXYYY"}, err)
+}
+
+func TestSpectre_relay(t *testing.T) {
+	median := oracleTestutil.NewSimulatedMedian(ethereum.Address{}, "AAABBB", 3, nil)
+	pair := &Pair{
+		AssetPair:        "AAABBB",
+		OracleSpread:     1,
+		OracleExpiration: time.Hour,
+		PriceExpiration:  time.Hour,
+		Median:           median,
+	}
+	s := newTestSpectre(t, pair, 90, 100, 110)
+	s.ctx = context.Background()
+
+	// The Oracle was never updated, so it must be poked:
+	tx, err := s.relay(context.Background(), "AAABBB")
+	require.NoError(t, err)
+	require.NotNil(t, tx)
+	require.Len(t, median.Pokes(), 1)
+	val, _ := median.Val(context.Background())
+	assert.Equal(t, big.NewInt(100), val)
+
+	// All prices are older than the Oracle now, so the quorum cannot be
+	// achieved:
+	_, err = s.relay(context.Background(), "AAABBB")
+	assert.Equal(t, ErrNoQuorum{AssetPair: "AAABBB"}, err)
+	assert.Len(t, median.Pokes(), 1)
+}
+
+func TestSpectre_relay_MinQuorum(t *testing.T) {
+	tests := []struct {
+		name      string
+		minQuorum int64
+		prices    []int64
+		wantErr   error
+	}{
+		{
+			name:      "lower-than-bar",
+			minQuorum: 2,
+			prices:    []int64{90, 100, 110},
+		},
+		{
+			name:      "not-enough-prices",
+			minQuorum: 5,
+			prices:    []int64{90, 100, 110, 120},
+			wantErr:   ErrNoQuorum{AssetPair: "AAABBB"},
+		},
+		{
+			name:      "enough-prices",
+			minQuorum: 5,
+			prices:    []int64{90, 100, 110, 120, 130},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			median := oracleTestutil.NewSimulatedMedian(ethereum.Address{}, "AAABBB", 3, nil)
+			pair := &Pair{
+				AssetPair:        "AAABBB",
+				OracleSpread:     1,
+				OracleExpiration: time.Hour,
+				PriceExpiration:  time.Hour,
+				Median:           median,
+				MinQuorum:        tt.minQuorum,
+			}
+			s := newTestSpectre(t, pair, tt.prices...)
+			s.ctx = context.Background()
+
+			_, err := s.relay(context.Background(), "AAABBB")
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				assert.Empty(t, median.Pokes())
+				return
+			}
+
+			// The Oracle contract accepts exactly bar prices:
+			require.NoError(t, err)
+			require.Len(t, median.Pokes(), 1)
+			assert.Len(t, median.Pokes()[0], 3)
+		})
+	}
+}
+
+func TestSpectre_pricesToPoke_MinQuorumSpread(t *testing.T) {
+	median := oracleTestutil.NewSimulatedMedian(ethereum.Address{}, "AAABBB", 3, nil)
+	median.SetState(big.NewInt(100), time.Now().Add(-time.Minute))
+	pair := &Pair{
+		AssetPair:        "AAABBB",
+		OracleSpread:     1,
+		OracleExpiration: time.Hour,
+		PriceExpiration:  time.Hour,
+		Median:           median,
+		MinQuorum:        5,
+	}
+	s := newTestSpectre(t, pair, 100, 100, 200, 200, 200)
+
+	// The median of all prices is 200, but a random subset of three prices
+	// may have the median of 100. The spread must be calculated on exactly
+	// the prices that are sent:
+	pokes := 0
+	for i := 0; i < 50; i++ {
+		prices, fields, err := s.pricesToPoke(context.Background(), pair)
+		if err != nil {
+			assert.ErrorAs(t, err, &ErrSpreadTooLow{})
+			continue
+		}
+		pokes++
+		require.Len(t, prices, 3)
+		assert.Equal(t, 100.0, fields["spread"])
+		assert.Equal(t, int64(5), fields["availablePrices"])
+		n := 0
+		for _, p := range prices {
+			if p.Val.Int64() == 200 {
+				n++
+			}
+		}
+		assert.GreaterOrEqual(t, n, 2)
+	}
+	assert.NotZero(t, pokes)
+}
+
+func TestPair_quorum(t *testing.T) {
+	assert.Equal(t, int64(3), (&Pair{}).quorum(3))
+	assert.Equal(t, int64(3), (&Pair{MinQuorum: 2}).quorum(3))
+	assert.Equal(t, int64(5), (&Pair{MinQuorum: 5}).quorum(3))
+}
+
+// estimatedMedian is a SimulatedMedian which estimates the poke cost using
+// the geth implementation of the Median contract.
+type estimatedMedian struct {
+	*oracleTestutil.SimulatedMedian
+	estimator oracle.PokeEstimator
+}
+
+func (m *estimatedMedian) EstimatePoke(ctx context.Context, prices []*oracle.Price) (*big.Int, error) {
+	return m.estimator.EstimatePoke(ctx, prices)
+}
+
+func TestSpectre_relay_MaxPokeCost(t *testing.T) {
+	tests := []struct {
+		name      string
+		oracleAge time.Duration
+		wantErr   bool
+	}{
+		{
+			name:      "stale",
+			oracleAge: time.Minute,
+			wantErr:   true,
+		},
+		{
+			name:      "expired",
+			oracleAge: 2 * time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The poke costs 0.01 ETH, which is more than the budget:
+			cli := &mocks.Client{}
+			cli.On("EstimateGas", mock.Anything, mock.Anything).Return(uint64(100000), nil)
+			cli.On("GasPrice", mock.Anything).Return(big.NewInt(100e9), nil)
+
+			median := oracleTestutil.NewSimulatedMedian(ethereum.Address{}, "AAABBB", 3, nil)
+			median.SetState(big.NewInt(50), time.Now().Add(-tt.oracleAge))
+			pair := &Pair{
+				AssetPair:        "AAABBB",
+				OracleSpread:     1,
+				OracleExpiration: time.Hour,
+				PriceExpiration:  time.Hour,
+				Median: &estimatedMedian{
+					SimulatedMedian: median,
+					estimator:       oracleGeth.NewMedian(cli, ethereum.Address{}),
+				},
+				MaxPokeCost: big.NewInt(1e15),
+			}
+			s := newTestSpectre(t, pair, 90, 100, 110)
+			s.ctx = context.Background()
+
+			_, err := s.relay(context.Background(), "AAABBB")
+			if tt.wantErr {
+				assert.Equal(t, ErrPokeTooExpensive{
+					AssetPair: "AAABBB",
+					Cost:      big.NewInt(1e16),
+					MaxCost:   big.NewInt(1e15),
+				}, err)
+				assert.Empty(t, median.Pokes())
+				return
+			}
+
+			// Expired Oracles are updated regardless of the cost:
+			require.NoError(t, err)
+			assert.Len(t, median.Pokes(), 1)
+		})
+	}
+}
+
+func TestSpectre_relay_LogFields(t *testing.T) {
+	median := oracleTestutil.NewSimulatedMedian(ethereum.Address{0x01}, "AAABBB", 3, nil)
+	pair := &Pair{
+		AssetPair:        "AAABBB",
+		OracleSpread:     1,
+		OracleExpiration: time.Hour,
+		PriceExpiration:  time.Hour,
+		Median:           median,
+	}
+	s := newTestSpectre(t, pair, 90, 100, 110)
+	s.ctx = context.Background()
+
+	entries := map[string]log.Fields{}
+	s.log = callback.New(log.Debug, func(_ log.Level, fields log.Fields, msg string) {
+		entries[msg] = fields
+	})
+
+	// The Oracle was never updated, so it must be poked:
+	tx, err := s.relay(context.Background(), "AAABBB")
+	require.NoError(t, err)
+
+	fields, ok := entries["Oracle updated"]
+	require.True(t, ok)
+	assert.Equal(t, "AAABBB", fields["assetPair"])
+	assert.Equal(t, ethereum.Address{0x01}.String(), fields["oracleAddress"])
+	assert.Equal(t, int64(3), fields["quorum"])
+	assert.Equal(t, 3, fields["prices"])
+	assert.Equal(t, tx.String(), fields["tx"])
+	assert.Contains(t, fields, "age")
+	assert.Contains(t, fields, "spread")
+
+	// All prices are older than the Oracle now, so the quorum cannot be
+	// achieved:
+	_, err = s.relay(context.Background(), "AAABBB")
+	require.Error(t, err)
+
+	fields, ok = entries["Unable to update Oracle"]
+	require.True(t, ok)
+	assert.Equal(t, "AAABBB", fields["assetPair"])
+	assert.Equal(t, 0, fields["prices"])
+	assert.Equal(t, ErrNoQuorum{AssetPair: "AAABBB"}.Error(), fields["err"])
+}
+
+func TestSpectre_pricesToPoke_FeederGroups(t *testing.T) {
+	feeders := []ethereum.Address{{0x01}, {0x02}, {0x03}}
+
+	newSpectre := func(t *testing.T, pair *Pair, groups map[ethereum.Address]string, minGroups int) *Spectre {
+		// Every price is signed by a different feeder. Feeders are
+		// identified by the V value of the signature.
+		sig := &mocks.Signer{}
+		ms := store.NewMemoryStorage()
+		for i, feeder := range feeders {
+			feeder := feeder
+			v := byte(i + 1)
+			sig.On("Recover", ethereum.SignatureFromVRS(v, [32]byte{}, [32]byte{}), mock.Anything).Return(&feeder, nil)
+			require.NoError(t, ms.Add(context.Background(), feeder, &messages.Price{
+				Price: &oracle.Price{Wat: pair.AssetPair, Val: big.NewInt(100), Age: time.Now(), V: v},
+			}))
+		}
+		ps, err := store.New(store.Config{
+			Storage:   ms,
+			Signer:    sig,
+			Transport: local.New([]byte("test"), 0, nil),
+			Pairs:     []string{pair.AssetPair},
+		})
+		require.NoError(t, err)
+		s, err := NewSpectre(Config{
+			Signer:          sig,
+			PriceStore:      ps,
+			Pairs:           []*Pair{pair},
+			FeederGroups:    groups,
+			MinFeederGroups: minGroups,
+		})
+		require.NoError(t, err)
+		return s
+	}
+
+	tests := []struct {
+		name      string
+		quorum    int64
+		groups    map[ethereum.Address]string
+		minGroups int
+		wantErr   error
+	}{
+		{
+			name:      "two-groups-required",
+			quorum:    3,
+			groups:    map[ethereum.Address]string{feeders[0]: "a", feeders[1]: "a", feeders[2]: "b"},
+			minGroups: 2,
+		},
+		{
+			name:      "three-groups-required",
+			quorum:    3,
+			groups:    map[ethereum.Address]string{feeders[0]: "a", feeders[1]: "a", feeders[2]: "b"},
+			minGroups: 3,
+			wantErr:   ErrNoDiversity{AssetPair: "AAABBB", Groups: 2, MinGroups: 3},
+		},
+		{
+			// Truncated prices must come from both groups.
+			name:      "truncated",
+			quorum:    2,
+			groups:    map[ethereum.Address]string{feeders[0]: "a", feeders[1]: "a", feeders[2]: "b"},
+			minGroups: 2,
+		},
+		{
+			// Feeders without a group are counted separately.
+			name:      "ungrouped-feeders",
+			quorum:    3,
+			groups:    map[ethereum.Address]string{feeders[0]: "a"},
+			minGroups: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Run several times, because prices are truncated randomly.
+			for i := 0; i < 10; i++ {
+				median := oracleTestutil.NewSimulatedMedian(ethereum.Address{}, "AAABBB", tt.quorum, nil)
+				median.SetState(big.NewInt(100), time.Now().Add(-2*time.Hour))
+				pair := &Pair{
+					AssetPair:        "AAABBB",
+					OracleSpread:     1,
+					OracleExpiration: time.Hour,
+					PriceExpiration:  time.Hour,
+					Median:           median,
+				}
+				s := newSpectre(t, pair, tt.groups, tt.minGroups)
+				prices, _, err := s.pricesToPoke(context.Background(), pair)
+				if tt.wantErr != nil {
+					assert.Equal(t, tt.wantErr, err)
+					assert.Nil(t, prices)
+					continue
+				}
+				require.NoError(t, err)
+				assert.Len(t, prices, int(tt.quorum))
+			}
+		})
+	}
+}
+
+func TestJitterDelay(t *testing.T) {
+	const instances = 1000
+	const buckets = 10
+	interval := time.Minute
+
+	// Without jitter, all instances should start at the same time:
+	assert.Equal(t, time.Duration(0), jitterDelay(interval, 0, rand.Float64))
+
+	// With jitter, delays should be spread across the whole window:
+	var hist [buckets]int
+	for i := 0; i < instances; i++ {
+		d := jitterDelay(interval, 0.5, rand.Float64)
+		require.GreaterOrEqual(t, d, time.Duration(0))
+		require.Less(t, d, interval/2)
+		hist[int(d*buckets/(interval/2))]++
+	}
+	for i, n := range hist {
+		assert.Greater(t, n, instances/buckets/2, "bucket %d", i)
+	}
+}
+
+func TestNewSpectre_InvalidJitter(t *testing.T) {
+	for _, j := range []float64{-0.1, 1.1} {
+		_, err := NewSpectre(Config{
+			Signer:         &mocks.Signer{},
+			PriceStore:     &store.PriceStore{},
+			IntervalJitter: j,
+		})
+		assert.Error(t, err)
+	}
+}
+
+func TestSpectre_relayAll_CorrelationID(t *testing.T) {
+	median := oracleTestutil.NewSimulatedMedian(ethereum.Address{0x01}, "AAABBB", 3, nil)
+	pair := &Pair{
+		AssetPair:        "AAABBB",
+		OracleSpread:     1,
+		OracleExpiration: time.Hour,
+		PriceExpiration:  time.Hour,
+		Median:           median,
+	}
+	s := newTestSpectre(t, pair, 90, 100, 110)
+	s.ctx = context.Background()
+
+	// Prices created in the same feed cycle:
+	prices, err := s.priceStore.GetByAssetPair(context.Background(), "AAABBB")
+	require.NoError(t, err)
+	for _, p := range prices {
+		p.CorrelationID = "feed"
+	}
+
+	ids := map[string]interface{}{}
+	var feedIDs []interface{}
+	s.log = callback.New(log.Debug, func(_ log.Level, fields log.Fields, msg string) {
+		ids[msg] = fields[correlation.LogField]
+		if msg == "Feed" {
+			feedIDs = append(feedIDs, fields["feedCorrelationID"])
+		}
+	})
+
+	// All log entries of a single relay cycle must have the same ID:
+	s.relayAll()
+	require.Contains(t, ids, "Oracle updated")
+	id := ids["Oracle updated"]
+	assert.NotEmpty(t, id)
+	assert.NotEqual(t, "feed", id)
+	assert.Equal(t, id, ids["Trying to update Oracle"])
+	assert.Equal(t, id, ids["Feed"])
+	assert.Equal(t, []interface{}{"feed", "feed", "feed"}, feedIDs)
+
+	// The next cycle must use a different ID:
+	s.relayAll()
+	assert.NotEqual(t, id, ids["Trying to update Oracle"])
+}
+
+func TestSpectre_relayAll_CallTimeout(t *testing.T) {
+	// The Ethereum client never responds until the context is canceled:
+	cli := &mocks.Client{}
+	cli.On("Call", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { <-args.Get(0).(context.Context).Done() }).
+		Return([]byte(nil), context.DeadlineExceeded)
+
+	hung := &Pair{
+		AssetPair:        "AAABBB",
+		OracleSpread:     1,
+		OracleExpiration: time.Hour,
+		PriceExpiration:  time.Hour,
+		Median:           oracleGeth.NewMedian(cli, ethereum.Address{0x01}),
+	}
+	median := oracleTestutil.NewSimulatedMedian(ethereum.Address{0x02}, "CCCDDD", 3, nil)
+	working := &Pair{
+		AssetPair:        "CCCDDD",
+		OracleSpread:     1,
+		OracleExpiration: time.Hour,
+		PriceExpiration:  time.Hour,
+		Median:           median,
+	}
+
+	sig := &mocks.Signer{}
+	sig.On("Recover", mock.Anything, mock.Anything).Return(&ethereum.Address{}, nil)
+	ms := store.NewMemoryStorage()
+	for _, wat := range []string{"AAABBB", "CCCDDD"} {
+		for i, val := range []int64{90, 100, 110} {
+			require.NoError(t, ms.Add(context.Background(), ethereum.Address{byte(i + 1)}, &messages.Price{
+				Price: &oracle.Price{Wat: wat, Val: big.NewInt(val), Age: time.Now()},
+			}))
+		}
+	}
+	ps, err := store.New(store.Config{
+		Storage:   ms,
+		Signer:    sig,
+		Transport: local.New([]byte("test"), 0, nil),
+		Pairs:     []string{"AAABBB", "CCCDDD"},
+	})
+	require.NoError(t, err)
+	s, err := NewSpectre(Config{
+		Signer:      sig,
+		PriceStore:  ps,
+		Pairs:       []*Pair{hung, working},
+		CallTimeout: 50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	s.ctx = context.Background()
+
+	// The hung call must be interrupted:
+	start := time.Now()
+	_, err = s.relay(context.Background(), "AAABBB")
+	assert.Equal(t, ErrCallTimeout{AssetPair: "AAABBB", Method: "bar", Timeout: 50 * time.Millisecond}, err)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// Other Oracles must still be updated:
+	s.relayAll()
+	assert.Len(t, median.Pokes(), 1)
+}
+
+func TestNewSpectre_NegativeCallTimeout(t *testing.T) {
+	_, err := NewSpectre(Config{
+		Signer:      &mocks.Signer{},
+		PriceStore:  &store.PriceStore{},
+		CallTimeout: -time.Second,
+	})
+	assert.Error(t, err)
+}
@@ -60,6 +60,10 @@ type PullPriceResp struct {
 	Price *messages.Price
 }
 
+type ExportPricesResp struct {
+	Snapshot *store.Snapshot
+}
+
 func (n *API) PublishPrice(arg *PublishPriceArg, _ *Nothing) error {
 	n.log.
 		WithFields(arg.Price.Price.Fields(n.signer)).
@@ -142,3 +146,19 @@ func (n *API) PullPrice(arg *PullPriceArg, resp *PullPriceResp) error {
 
 	return nil
 }
+
+func (n *API) ExportPrices(_ *Nothing, resp *ExportPricesResp) error {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), defaultRPCTimeout)
+	defer ctxCancel()
+
+	n.log.Info("Export prices")
+
+	snapshot, err := n.priceStore.Snapshot(ctx)
+	if err != nil {
+		return err
+	}
+
+	*resp = ExportPricesResp{Snapshot: snapshot}
+
+	return nil
+}
@@ -216,3 +216,22 @@ func wait(cond func() bool, timeout time.Duration) {
 		time.Sleep(100 * time.Millisecond)
 	}
 }
+
+func TestClient_ExportPrices(t *testing.T) {
+	var err error
+	var snapshot *store.Snapshot
+
+	err = spire.PublishPrice(testPriceAAABBB)
+	assert.NoError(t, err)
+
+	wait(func() bool {
+		snapshot, err = spire.ExportPrices()
+		return snapshot != nil && len(snapshot.Prices) != 0
+	}, time.Second)
+
+	assert.NoError(t, err)
+	assert.Len(t, snapshot.Prices, 1)
+	assert.Equal(t, "AAABBB", snapshot.Prices[0].AssetPair)
+	assert.Equal(t, testAddress, snapshot.Prices[0].Feeder)
+	assertEqualPrices(t, testPriceAAABBB, snapshot.Prices[0].Message)
+}
@@ -21,6 +21,7 @@ import (
 	"net/rpc"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/store"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
 )
 
@@ -91,6 +92,15 @@ func (c *Client) PullPrice(assetPair string, feeder string) (*messages.Price, er
 	return resp.Price, nil
 }
 
+func (c *Client) ExportPrices() (*store.Snapshot, error) {
+	resp := &ExportPricesResp{}
+	err := c.rpc.Call("API.ExportPrices", Nothing{}, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Snapshot, nil
+}
+
 func (c *Client) contextCancelHandler() {
 	defer func() { close(c.waitCh) }()
 	<-c.ctx.Done()
@@ -0,0 +1,99 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package transport
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+)
+
+// compressionMarker is the first byte of a compressed message, and of
+// a message with a timestamp header (see HeaderTimestamp). Neither JSON
+// nor protobuf encoded messages can start with a zero byte (the protobuf
+// field number 0 is reserved), so messages without the marker are treated
+// as uncompressed. Thanks to that, peers that do not compress messages are
+// still able to communicate with peers that do.
+const compressionMarker byte = 0x00
+
+// Compression algorithms, stored in the byte following the marker.
+const (
+	CompressionGzip byte = 0x01
+)
+
+// DefaultCompressionThreshold is the default size in bytes below which
+// messages are not compressed, because the compression overhead would be
+// larger than the gain.
+const DefaultCompressionThreshold = 512
+
+// MaxDecompressedSize is the maximum size of a decompressed message. It
+// protects against messages that decompress to a very large size.
+const MaxDecompressedSize = 1 * 1024 * 1024 // 1MB
+
+var ErrUnknownCompression = errors.New("unknown message compression algorithm")
+var ErrDecompressedMessageTooLarge = errors.New("decompressed message too large")
+
+// Compress compresses the given message data using gzip and prepends the
+// compression header. Data smaller than the threshold are returned
+// unchanged.
+func Compress(data []byte, threshold int) ([]byte, error) {
+	if len(data) < threshold {
+		return data, nil
+	}
+	buf := &bytes.Buffer{}
+	buf.WriteByte(compressionMarker)
+	buf.WriteByte(CompressionGzip)
+	w := gzip.NewWriter(buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	// If the compression does not reduce the size, it is better to send
+	// the message uncompressed:
+	if buf.Len() >= len(data) {
+		return data, nil
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress returns decompressed message data. If the data does not start
+// with the compression header, they are returned unchanged.
+func Decompress(data []byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != compressionMarker {
+		return data, nil
+	}
+	switch data[1] {
+	case CompressionGzip:
+		r, err := gzip.NewReader(bytes.NewReader(data[2:]))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		out, err := io.ReadAll(io.LimitReader(r, MaxDecompressedSize+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(out) > MaxDecompressedSize {
+			return nil, ErrDecompressedMessageTooLarge
+		}
+		return out, nil
+	default:
+		return nil, ErrUnknownCompression
+	}
+}
@@ -0,0 +1,79 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package transport
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompress_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       []byte
+		compressed bool
+	}{
+		{name: "below-threshold", data: []byte(`{"price":"1"}`), compressed: false},
+		{name: "above-threshold", data: bytes.Repeat([]byte(`{"price":"1"}`), 100), compressed: true},
+		{name: "protobuf", data: append([]byte{0x0a, 0x03}, bytes.Repeat([]byte{0x01}, 1000)...), compressed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Compress(tt.data, DefaultCompressionThreshold)
+			require.NoError(t, err)
+			if tt.compressed {
+				assert.Less(t, len(c), len(tt.data))
+				assert.Equal(t, []byte{compressionMarker, CompressionGzip}, c[0:2])
+			} else {
+				assert.Equal(t, tt.data, c)
+			}
+			d, err := Decompress(c)
+			require.NoError(t, err)
+			assert.Equal(t, tt.data, d)
+		})
+	}
+}
+
+func TestDecompress_Uncompressed(t *testing.T) {
+	// Messages from peers that do not support compression must be passed
+	// through unchanged:
+	for _, data := range [][]byte{nil, {}, []byte(`{"price":"1"}`), {0x0a, 0x01, 0x02}} {
+		d, err := Decompress(data)
+		require.NoError(t, err)
+		assert.Equal(t, data, d)
+	}
+}
+
+func TestDecompress_UnknownAlgorithm(t *testing.T) {
+	_, err := Decompress([]byte{compressionMarker, 0xff, 0x01})
+	assert.ErrorIs(t, err, ErrUnknownCompression)
+}
+
+func TestDecompress_TooLarge(t *testing.T) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte(compressionMarker)
+	buf.WriteByte(CompressionGzip)
+	w := gzip.NewWriter(buf)
+	_, _ = w.Write(make([]byte, MaxDecompressedSize+1))
+	_ = w.Close()
+
+	_, err := Decompress(buf.Bytes())
+	assert.ErrorIs(t, err, ErrDecompressedMessageTooLarge)
+}
@@ -0,0 +1,100 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package transport
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+// DefaultDedupCacheSize is the default number of messages remembered by the
+// Deduplicator.
+const DefaultDedupCacheSize = 10000
+
+// DefaultDedupTTL is the default time for which the Deduplicator remembers
+// a message.
+const DefaultDedupTTL = 5 * time.Minute
+
+// Deduplicator remembers recently seen messages so that transports can drop
+// duplicated messages before they are delivered to subscribers. Messages are
+// identified by a hash of the topic and the message data.
+//
+// The number of remembered messages is limited by the cache size. If the
+// limit is reached, the least recently seen message is forgotten.
+type Deduplicator struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	lru   *list.List
+	items map[[sha256.Size]byte]*list.Element
+	now   func() time.Time
+}
+
+type seenMessage struct {
+	hash [sha256.Size]byte
+	seen time.Time
+}
+
+// NewDeduplicator returns a new Deduplicator instance. If size or ttl are
+// zero or negative, the default values are used.
+func NewDeduplicator(size int, ttl time.Duration) *Deduplicator {
+	if size <= 0 {
+		size = DefaultDedupCacheSize
+	}
+	if ttl <= 0 {
+		ttl = DefaultDedupTTL
+	}
+	return &Deduplicator{
+		size:  size,
+		ttl:   ttl,
+		lru:   list.New(),
+		items: make(map[[sha256.Size]byte]*list.Element),
+		now:   time.Now,
+	}
+}
+
+// Seen returns true if the same message was already seen on the given topic
+// within the TTL. Otherwise, the message is remembered and false is returned.
+func (d *Deduplicator) Seen(topic string, data []byte) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	h := sha256.New()
+	h.Write([]byte(topic))
+	h.Write([]byte{0})
+	h.Write(data)
+	var hash [sha256.Size]byte
+	copy(hash[:], h.Sum(nil))
+
+	now := d.now()
+	if e, ok := d.items[hash]; ok {
+		sm := e.Value.(*seenMessage)
+		if now.Sub(sm.seen) < d.ttl {
+			return true
+		}
+		sm.seen = now
+		d.lru.MoveToFront(e)
+		return false
+	}
+	d.items[hash] = d.lru.PushFront(&seenMessage{hash: hash, seen: now})
+	for d.lru.Len() > d.size {
+		e := d.lru.Back()
+		d.lru.Remove(e)
+		delete(d.items, e.Value.(*seenMessage).hash)
+	}
+	return false
+}
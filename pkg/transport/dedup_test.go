@@ -0,0 +1,53 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package transport
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeduplicator_Seen(t *testing.T) {
+	now := time.Unix(0, 0)
+	d := NewDeduplicator(2, time.Minute)
+	d.now = func() time.Time { return now }
+
+	assert.False(t, d.Seen("foo", []byte("a")))
+	assert.True(t, d.Seen("foo", []byte("a")))
+
+	// The same data on a different topic is a different message:
+	assert.False(t, d.Seen("bar", []byte("a")))
+
+	// Message should be forgotten after the TTL:
+	now = now.Add(time.Minute)
+	assert.False(t, d.Seen("foo", []byte("a")))
+	assert.True(t, d.Seen("foo", []byte("a")))
+}
+
+func TestDeduplicator_Size(t *testing.T) {
+	d := NewDeduplicator(10, time.Minute)
+	for i := 0; i < 11; i++ {
+		assert.False(t, d.Seen("foo", []byte(fmt.Sprint(i))))
+	}
+	// The oldest message should be removed from the cache:
+	assert.False(t, d.Seen("foo", []byte("0")))
+	assert.True(t, d.Seen("foo", []byte("10")))
+	assert.Equal(t, 10, d.lru.Len())
+	assert.Len(t, d.items, 10)
+}
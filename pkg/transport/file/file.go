@@ -0,0 +1,255 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package file
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
+)
+
+const LoggerTag = "FILE_TRANSPORT"
+
+// maxLineSize is the maximum length of a single line in the replay file.
+// Message data are base64 encoded, so a line may be longer than a message.
+const maxLineSize = 2 * transport.DefaultMaxMessageSize
+
+var ErrNotSubscribed = errors.New("topic is not subscribed")
+
+// record is a single line of a file used by the File transport.
+type record struct {
+	Topic  string    `json:"topic"`
+	Time   time.Time `json:"time"`
+	Author []byte    `json:"author"`
+	Data   []byte    `json:"data"`
+}
+
+// File is an implementation of the transport.Transport interface that
+// replays messages recorded in a file. Each line of the file is a JSON
+// object with a topic name, time, author and binary message data.
+//
+// Broadcasted messages are appended to a write file, if one is configured,
+// so the File transport can also be used to record messages which may be
+// replayed later.
+type File struct {
+	mu  sync.Mutex
+	ctx context.Context
+
+	id        []byte
+	readPath  string
+	timing    bool
+	writeFile *os.File
+	waitCh    chan error
+	subs      map[string]*subscription
+	log       log.Logger
+}
+
+type subscription struct {
+	// typ is the structure type to which the message must be unmarshalled.
+	typ reflect.Type
+	// msgs is a channel used to broadcast unmarshalled messages.
+	msgs chan transport.ReceivedMessage
+}
+
+// Config is the configuration for the File transport.
+type Config struct {
+	// ID is the identity returned by the ID method and used as an author
+	// of recorded messages.
+	ID []byte
+	// Topics is a list of subscribed topics. A value of the map a type of
+	// message given as a nil pointer, e.g.: (*Message)(nil).
+	Topics map[string]transport.Message
+	// ReadPath is a path to a file with messages to replay. If empty,
+	// no messages are replayed.
+	ReadPath string
+	// WritePath is a path to a file to which broadcasted messages are
+	// appended. If empty, the Broadcast method does nothing.
+	WritePath string
+	// Timing indicates whether the original time between messages should
+	// be preserved during replay. Otherwise, messages are replayed as fast
+	// as they are consumed.
+	Timing bool
+	// Logger is a custom logger instance. If not provided then null
+	// logger is used.
+	Logger log.Logger
+}
+
+// New returns a new instance of the File transport.
+func New(cfg Config) (*File, error) {
+	if cfg.Logger == nil {
+		cfg.Logger = null.New()
+	}
+	f := &File{
+		id:       cfg.ID,
+		readPath: cfg.ReadPath,
+		timing:   cfg.Timing,
+		waitCh:   make(chan error),
+		subs:     make(map[string]*subscription),
+		log:      cfg.Logger.WithField("tag", LoggerTag),
+	}
+	for topic, typ := range cfg.Topics {
+		f.subs[topic] = &subscription{
+			typ:  reflect.TypeOf(typ).Elem(),
+			msgs: make(chan transport.ReceivedMessage),
+		}
+	}
+	if cfg.WritePath != "" {
+		wf, err := os.OpenFile(cfg.WritePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gomnd
+		if err != nil {
+			return nil, fmt.Errorf("file transport error, unable to open write file: %w", err)
+		}
+		f.writeFile = wf
+	}
+	return f, nil
+}
+
+// Start implements the transport.Transport interface.
+func (f *File) Start(ctx context.Context) error {
+	if f.ctx != nil {
+		return errors.New("service can be started only once")
+	}
+	if ctx == nil {
+		return errors.New("context must not be nil")
+	}
+	f.ctx = ctx
+	if f.readPath != "" {
+		rf, err := os.Open(f.readPath)
+		if err != nil {
+			return fmt.Errorf("file transport error, unable to open read file: %w", err)
+		}
+		go f.replayRoutine(rf)
+	}
+	go f.contextCancelHandler()
+	return nil
+}
+
+// Wait implements the transport.Transport interface.
+func (f *File) Wait() chan error {
+	return f.waitCh
+}
+
+// ID implements the transport.Transport interface.
+func (f *File) ID() []byte {
+	return f.id
+}
+
+// Broadcast implements the transport.Transport interface.
+func (f *File) Broadcast(topic string, message transport.Message) error {
+	if _, ok := f.subs[topic]; !ok {
+		return ErrNotSubscribed
+	}
+	if f.writeFile == nil {
+		return nil
+	}
+	data, err := message.MarshallBinary()
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(record{
+		Topic:  topic,
+		Time:   time.Now(),
+		Author: f.id,
+		Data:   data,
+	})
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, err = f.writeFile.Write(append(line, '\n'))
+	return err
+}
+
+// Messages implements the transport.Transport interface.
+func (f *File) Messages(topic string) chan transport.ReceivedMessage {
+	if sub, ok := f.subs[topic]; ok {
+		return sub.msgs
+	}
+	return nil
+}
+
+// replayRoutine reads messages from the given reader and sends them to
+// the subscription channels. A read error, or a line longer than
+// maxLineSize, ends the replay and is logged.
+func (f *File) replayRoutine(r io.ReadCloser) {
+	defer r.Close()
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+	var last time.Time
+	for s.Scan() {
+		if len(s.Bytes()) == 0 {
+			continue
+		}
+		var rec record
+		if err := json.Unmarshal(s.Bytes(), &rec); err != nil {
+			// It is not possible to determine the topic of a malformed
+			// record, so there is no subscription to report the error to.
+			continue
+		}
+		sub, ok := f.subs[rec.Topic]
+		if !ok {
+			continue
+		}
+		if f.timing && !last.IsZero() && rec.Time.After(last) {
+			select {
+			case <-f.ctx.Done():
+				return
+			case <-time.After(rec.Time.Sub(last)):
+			}
+		}
+		last = rec.Time
+		rm := transport.ReceivedMessage{Author: rec.Author}
+		msg := reflect.New(sub.typ).Interface().(transport.Message)
+		if err := msg.UnmarshallBinary(rec.Data); err != nil {
+			rm.Error = err
+		} else {
+			rm.Message = msg
+		}
+		select {
+		case <-f.ctx.Done():
+			return
+		case sub.msgs <- rm:
+		}
+	}
+	if err := s.Err(); err != nil {
+		f.log.
+			WithError(err).
+			WithField("path", f.readPath).
+			Error("Unable to read the replay file")
+	}
+}
+
+// contextCancelHandler handles context cancellation.
+func (f *File) contextCancelHandler() {
+	defer func() { close(f.waitCh) }()
+	<-f.ctx.Done()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.writeFile != nil {
+		_ = f.writeFile.Close()
+	}
+}
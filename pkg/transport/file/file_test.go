@@ -0,0 +1,155 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/callback"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/store"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/store/testutil"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+)
+
+var testTopics = map[string]transport.Message{messages.PriceV1MessageName: (*messages.Price)(nil)}
+
+func recordPrices(t *testing.T, path string, msgs ...*messages.Price) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	f, err := New(Config{ID: []byte("test"), Topics: testTopics, WritePath: path})
+	require.NoError(t, err)
+	require.NoError(t, f.Start(ctx))
+	for _, msg := range msgs {
+		require.NoError(t, f.Broadcast(messages.PriceV1MessageName, msg.AsV1()))
+	}
+}
+
+func TestFile_Broadcast_NotSubscribed(t *testing.T) {
+	f, err := New(Config{Topics: testTopics})
+	require.NoError(t, err)
+	assert.ErrorIs(t, f.Broadcast("foo", testutil.PriceAAABBB1), ErrNotSubscribed)
+
+	// Without a write file, broadcast does nothing:
+	assert.NoError(t, f.Broadcast(messages.PriceV1MessageName, testutil.PriceAAABBB1))
+}
+
+func TestFile_Replay(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	path := filepath.Join(t.TempDir(), "prices.ndjson")
+	recordPrices(t, path, testutil.PriceAAABBB1, testutil.PriceAAABBB2)
+
+	f, err := New(Config{Topics: testTopics, ReadPath: path})
+	require.NoError(t, err)
+	require.NoError(t, f.Start(ctx))
+
+	for _, expected := range []*messages.Price{testutil.PriceAAABBB1, testutil.PriceAAABBB2} {
+		msg := <-f.Messages(messages.PriceV1MessageName)
+		require.NoError(t, msg.Error)
+		assert.Equal(t, []byte("test"), msg.Author)
+		assert.Equal(t, expected.Price.Wat, msg.Message.(*messages.Price).Price.Wat)
+		assert.Equal(t, expected.Price.Val, msg.Message.(*messages.Price).Price.Val)
+	}
+}
+
+func TestFile_Replay_LineTooLong(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	path := filepath.Join(t.TempDir(), "prices.ndjson")
+	recordPrices(t, path, testutil.PriceAAABBB1)
+	rf, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = rf.WriteString(strings.Repeat("x", maxLineSize+1) + "\n")
+	require.NoError(t, err)
+	require.NoError(t, rf.Close())
+
+	errCh := make(chan string, 1)
+	l := callback.New(log.Debug, func(_ log.Level, fields log.Fields, _ string) {
+		if err, ok := fields["err"].(string); ok {
+			errCh <- err
+		}
+	})
+	f, err := New(Config{Topics: testTopics, ReadPath: path, Logger: l})
+	require.NoError(t, err)
+	require.NoError(t, f.Start(ctx))
+
+	msg := <-f.Messages(messages.PriceV1MessageName)
+	require.NoError(t, msg.Error)
+	select {
+	case err := <-errCh:
+		assert.Contains(t, err, "token too long")
+	case <-time.After(time.Second):
+		t.Fatal("scanner error was not logged")
+	}
+}
+
+func TestFile_ReplayIntoPriceStore(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	path := filepath.Join(t.TempDir(), "prices.ndjson")
+	recordPrices(t, path, testutil.PriceAAABBB1, testutil.PriceAAABBB2, testutil.PriceXXXYYY1)
+
+	sig := &mocks.Signer{}
+	sig.On("Recover", testutil.PriceAAABBB1.Price.Signature(), mock.Anything).Return(&testutil.Address1, nil)
+	sig.On("Recover", testutil.PriceAAABBB2.Price.Signature(), mock.Anything).Return(&testutil.Address2, nil)
+	sig.On("Recover", testutil.PriceXXXYYY1.Price.Signature(), mock.Anything).Return(&testutil.Address1, nil)
+
+	f, err := New(Config{Topics: testTopics, ReadPath: path, Timing: true})
+	require.NoError(t, err)
+	ps, err := store.New(store.Config{
+		Signer:    sig,
+		Storage:   store.NewMemoryStorage(),
+		Transport: f,
+		Pairs:     []string{"AAABBB", "XXXYYY"},
+		Logger:    null.New(),
+	})
+	require.NoError(t, err)
+	require.NoError(t, f.Start(ctx))
+	require.NoError(t, ps.Start(ctx))
+
+	// PriceStore fetches prices asynchronously, so we wait up to 1 second:
+	var aaabbb, xxxyyy []*messages.Price
+	for i := 0; i < 10; i++ {
+		time.Sleep(100 * time.Millisecond)
+		aaabbb, err = ps.GetByAssetPair(ctx, "AAABBB")
+		require.NoError(t, err)
+		xxxyyy, err = ps.GetByAssetPair(ctx, "XXXYYY")
+		require.NoError(t, err)
+		if len(aaabbb) == 2 && len(xxxyyy) == 1 {
+			break
+		}
+	}
+
+	assert.Len(t, aaabbb, 2)
+	assert.Len(t, xxxyyy, 1)
+}
@@ -86,6 +86,15 @@ func DisablePubSub() Options {
 	}
 }
 
+// MaxMessageSize sets the maximum size of a pubsub message. Larger messages
+// are rejected while reading, before they are fully buffered in memory.
+func MaxMessageSize(size int) Options {
+	return func(n *Node) error {
+		n.pubsubOpts = append(n.pubsubOpts, pubsub.WithMaxMessageSize(size))
+		return nil
+	}
+}
+
 // UserAgent sets the libp2p user-agent sent along with the identify protocol.
 func UserAgent(userAgent string) Options {
 	return func(n *Node) error {
@@ -79,6 +79,11 @@ type P2P struct {
 	mode   Mode
 	topics map[string]transport.Message
 	msgCh  map[string]chan transport.ReceivedMessage
+
+	compression bool
+	messageTTL  time.Duration
+	penalties   *penalties
+	dedup       *transport.Deduplicator
 }
 
 // Config is the configuration for the P2P transport.
@@ -112,6 +117,29 @@ type Config struct {
 	// FeedersAddrs is a list of price feeders. Only feeders can create new
 	// messages in the network.
 	FeedersAddrs []ethereum.Address
+	// MaxMessageSize is a map of maximum message sizes in bytes, where the
+	// key is a topic name. Messages larger than the limit are rejected.
+	// For topics that are not in the map, transport.DefaultMaxMessageSize
+	// is used.
+	MaxMessageSize map[string]int
+	// Compression enables gzip compression of broadcasted messages larger
+	// than transport.DefaultCompressionThreshold. Compressed messages are
+	// always accepted, regardless of this option.
+	Compression bool
+	// DedupCacheSize is the maximum number of recently received messages
+	// remembered to drop duplicates. If zero, transport.DefaultDedupCacheSize
+	// is used.
+	DedupCacheSize int
+	// DedupTTL is the time for which received messages are remembered to
+	// drop duplicates. If zero, transport.DefaultDedupTTL is used.
+	DedupTTL time.Duration
+	// MessageTTL is the maximum age of a received message. If not zero,
+	// broadcasted messages are prefixed with the time at which they were
+	// sent, and received messages older than MessageTTL are dropped before
+	// they reach subscribers. Messages without the timestamp are always
+	// accepted. Nodes using older versions of the software are unable to
+	// read messages with the timestamp.
+	MessageTTL time.Duration
 	// Discovery indicates whenever peer discovery should be enabled.
 	// If discovery is disabled, then DirectPeersAddrs must be used
 	// to connect to the network. Always enabled in bootstrap mode.
@@ -167,6 +195,16 @@ func New(cfg Config) (*P2P, error) {
 	}
 
 	logger := cfg.Logger.WithField("tag", LoggerTag)
+	p := &P2P{
+		mode:   cfg.Mode,
+		topics: cfg.Topics,
+		msgCh:  map[string]chan transport.ReceivedMessage{},
+
+		compression: cfg.Compression,
+		messageTTL:  cfg.MessageTTL,
+		penalties:   newPenalties(invalidMessagePenalty, invalidMessagePenaltyLength),
+		dedup:       transport.NewDeduplicator(cfg.DedupCacheSize, cfg.DedupTTL),
+	}
 	opts := []internal.Options{
 		internal.DialTimeout(connectionTimeout),
 		internal.Logger(logger),
@@ -197,10 +235,12 @@ func New(cfg Config) (*P2P, error) {
 		if err != nil {
 			return nil, fmt.Errorf("P2P transport error: invalid event topic scoring parameters: %w", err)
 		}
+		peerParams := *peerScoreParams
+		peerParams.AppSpecificScore = p.penalties.score
 		opts = append(opts,
 			internal.MessageLogger(),
 			internal.RateLimiter(rateLimiterConfig(cfg)),
-			internal.PeerScoring(peerScoreParams, thresholds, func(topic string) *pubsub.TopicScoreParams {
+			internal.PeerScoring(&peerParams, thresholds, func(topic string) *pubsub.TopicScoreParams {
 				if topic == messages.PriceV0MessageName || topic == messages.PriceV1MessageName {
 					return priceTopicScoreParams
 				}
@@ -209,10 +249,11 @@ func New(cfg Config) (*P2P, error) {
 				}
 				return nil
 			}),
-			messageValidator(cfg.Topics, logger), // must be registered before any other validator
+			internal.MaxMessageSize(maxMessageSize(cfg)),
+			messageValidator(cfg.Topics, cfg.MaxMessageSize, cfg.MessageTTL, logger), // must be registered before any other validator
 			feederValidator(cfg.FeedersAddrs, logger),
 			eventValidator(logger),
-			priceValidator(cfg.Signer, logger),
+			priceValidator(cfg.Signer, cfg.FeedersAddrs, p.penalize, logger),
 		)
 		if cfg.MessagePrivKey != nil {
 			opts = append(opts, internal.MessagePrivKey(cfg.MessagePrivKey))
@@ -239,13 +280,20 @@ func New(cfg Config) (*P2P, error) {
 		return nil, fmt.Errorf("P2P transport error, unable to get public ID from private key: %w", err)
 	}
 
-	return &P2P{
-		id:     id,
-		node:   n,
-		mode:   cfg.Mode,
-		topics: cfg.Topics,
-		msgCh:  map[string]chan transport.ReceivedMessage{},
-	}, nil
+	p.id = id
+	p.node = n
+	return p, nil
+}
+
+// maxMessageSize returns the largest message size limit among all topics.
+func maxMessageSize(cfg Config) int {
+	max := transport.DefaultMaxMessageSize
+	for topic := range cfg.Topics {
+		if l := transport.MaxMessageSize(cfg.MaxMessageSize, topic); l > max {
+			max = l
+		}
+	}
+	return max
 }
 
 // Start implements the transport.Transport interface.
@@ -286,6 +334,15 @@ func (p *P2P) Broadcast(topic string, message transport.Message) error {
 	if err != nil {
 		return fmt.Errorf("P2P transport error, unable to marshall message: %w", err)
 	}
+	if p.compression {
+		data, err = transport.Compress(data, transport.DefaultCompressionThreshold)
+		if err != nil {
+			return fmt.Errorf("P2P transport error, unable to compress message: %w", err)
+		}
+	}
+	if p.messageTTL > 0 {
+		data = transport.AddTimestamp(data, time.Now())
+	}
 	return sub.Publish(data)
 }
 
@@ -294,6 +351,30 @@ func (p *P2P) Messages(topic string) chan transport.ReceivedMessage {
 	return p.msgCh[topic]
 }
 
+// ReportInvalid implements the transport.Reporter interface.
+//
+// The peer that delivered the message is penalized. Its score is used by
+// the peer scoring mechanism, so after receiving too many invalid messages,
+// the peer will be graylisted. Once the penalty alone drops the peer below
+// the graylist threshold, it is also disconnected.
+func (p *P2P) ReportInvalid(msg transport.ReceivedMessage) {
+	psMsg, ok := msg.Data.(*pubsub.Message)
+	if !ok || p.mode != ClientMode {
+		return
+	}
+	p.penalize(psMsg.ReceivedFrom)
+}
+
+// penalize adds a penalty to the score of the given peer and disconnects
+// it if the penalty alone drops it below the graylist threshold. It is
+// used by ReportInvalid and by validators that reject invalid messages.
+func (p *P2P) penalize(id peer.ID) {
+	p.penalties.report(id)
+	if p.penalties.score(id) < thresholds.GraylistThreshold {
+		_ = p.node.Host().Network().ClosePeer(id)
+	}
+}
+
 func (p *P2P) subscribe(topic string) error {
 	sub, err := p.node.Subscribe(topic)
 	if err != nil {
@@ -309,6 +390,11 @@ func (p *P2P) messagesLoop(topic string, sub *internal.Subscription) {
 		if !ok {
 			return
 		}
+		// Gossip may deliver the same message more than once, e.g. when it
+		// is published again by a different peer:
+		if p.dedup.Seen(topic, nodeMsg.Data) {
+			continue
+		}
 		if msg, ok := nodeMsg.ValidatorData.(transport.Message); ok {
 			p.msgCh[topic] <- transport.ReceivedMessage{
 				Message: msg,
@@ -18,6 +18,7 @@ package libp2p
 import (
 	"errors"
 	"math"
+	"sync"
 	"time"
 
 	"github.com/libp2p/go-libp2p-core/peer"
@@ -42,8 +43,15 @@ var thresholds = &pubsub.PeerScoreThresholds{
 	OpportunisticGraftThreshold: 0,
 }
 
+// Parameters for the application specific score (P₅). Peers are penalized
+// when they deliver messages that are rejected by the application, e.g.
+// because of an invalid signature. Penalties decay to zero after
+// invalidMessagePenaltyLength.
+const invalidMessagePenalty = 100
+const invalidMessagePenaltyLength = time.Hour
+
 var peerScoreParams = &pubsub.PeerScoreParams{
-	AppSpecificScore:            func(id peer.ID) float64 { return 0 },
+	AppSpecificScore:            func(id peer.ID) float64 { return 0 }, // replaced by penalties.score
 	AppSpecificWeight:           1,
 	IPColocationFactorWeight:    -10,
 	IPColocationFactorThreshold: 2,
@@ -188,3 +196,57 @@ func (p *scoreParams) calculate() (*pubsub.TopicScoreParams, error) {
 func decay(from float64, duration time.Duration) float64 {
 	return math.Pow(decayToZero/from, 1/(float64(duration)/float64(decayInterval)))
 }
+
+// penalties keeps application specific penalties for peers that delivered
+// invalid messages. It is used as the AppSpecificScore function.
+type penalties struct {
+	mu      sync.Mutex
+	penalty float64
+	decay   float64
+	values  map[peer.ID]*penaltyValue
+	now     func() time.Time
+}
+
+type penaltyValue struct {
+	value   float64
+	updated time.Time
+}
+
+func newPenalties(penalty float64, length time.Duration) *penalties {
+	return &penalties{
+		penalty: penalty,
+		decay:   decay(penalty*maxInvalidMsgsPerHour, length),
+		values:  make(map[peer.ID]*penaltyValue),
+		now:     time.Now,
+	}
+}
+
+// report penalizes the given peer for delivering an invalid message.
+func (p *penalties) report(id peer.ID) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	v := p.current(id)
+	p.values[id] = &penaltyValue{value: v + p.penalty, updated: p.now()}
+}
+
+// score returns the application specific score for the given peer.
+func (p *penalties) score(id peer.ID) float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return -p.current(id)
+}
+
+// current returns the decayed penalty value for the given peer. Values that
+// dropped below decayToZero are removed.
+func (p *penalties) current(id peer.ID) float64 {
+	v, ok := p.values[id]
+	if !ok {
+		return 0
+	}
+	value := v.value * math.Pow(p.decay, float64(p.now().Sub(v.updated))/float64(decayInterval))
+	if value < decayToZero {
+		delete(p.values, id)
+		return 0
+	}
+	return value
+}
@@ -20,6 +20,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -63,3 +64,28 @@ func TestScoreParams_calculate(t *testing.T) {
 	assert.InDelta(t, p.maxMessagesPerSecond, pc.MeshMessageDeliveriesCap/p.p3Length.Seconds(), 0.01)
 	assert.InDelta(t, p.maxInvalidMessages, decayToZero*math.Pow(pc.InvalidMessageDeliveriesDecay, p.p4Length.Seconds()/decayInterval.Seconds()*-1), 0.01)
 }
+
+func TestPenalties(t *testing.T) {
+	now := time.Unix(0, 0)
+	p := newPenalties(invalidMessagePenalty, invalidMessagePenaltyLength)
+	p.now = func() time.Time { return now }
+
+	bad := peer.ID("bad")
+	good := peer.ID("good")
+
+	// Repeated invalid messages from a single peer must eventually drop its
+	// score below the graylist threshold:
+	n := 0
+	for ; p.score(bad) >= thresholds.GraylistThreshold; n++ {
+		require.Less(t, n, 1000, "score does not drop below the graylist threshold")
+		p.report(bad)
+	}
+	assert.Equal(t, int(-thresholds.GraylistThreshold/invalidMessagePenalty)+1, n)
+	assert.Equal(t, float64(0), p.score(good))
+
+	// Penalty should decay over time:
+	now = now.Add(invalidMessagePenaltyLength / 2)
+	assert.Greater(t, p.score(bad), thresholds.GraylistThreshold)
+	now = now.Add(invalidMessagePenaltyLength)
+	assert.Equal(t, float64(0), p.score(bad))
+}
@@ -31,7 +31,12 @@ import (
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
 )
 
-func messageValidator(topics map[string]transport.Message, logger log.Logger) internal.Options {
+func messageValidator(
+	topics map[string]transport.Message,
+	limits map[string]int,
+	ttl time.Duration,
+	logger log.Logger,
+) internal.Options {
 	return func(n *internal.Node) error {
 		// Validator actually have two roles in the libp2p: it unmarshalls messages
 		// and then validates them. Unmarshalled message is stored in the
@@ -41,7 +46,32 @@ func messageValidator(topics map[string]transport.Message, logger log.Logger) in
 			if typ, ok := topics[topic]; ok {
 				typRefl := reflect.TypeOf(typ).Elem()
 				msg := reflect.New(typRefl).Interface().(transport.Message)
-				err := msg.UnmarshallBinary(psMsg.Data)
+				if err := checkMessageSize(topic, psMsg.Data, limits); err != nil {
+					logger.
+						WithError(err).
+						WithField("peerID", psMsg.GetFrom().String()).
+						WithField("from", ethkey.PeerIDToAddress(psMsg.GetFrom())).
+						Warn("The message has been rejected, message too large")
+					return pubsub.ValidationReject
+				}
+				produced, data, err := transport.SplitTimestamp(psMsg.Data)
+				if err == nil {
+					if ageErr := transport.CheckMessageAge(topic, produced, time.Now(), ttl); ageErr != nil {
+						logger.
+							WithError(ageErr).
+							WithField("peerID", psMsg.GetFrom().String()).
+							WithField("from", ethkey.PeerIDToAddress(psMsg.GetFrom())).
+							Warn("The message has been ignored, invalid message timestamp")
+						return pubsub.ValidationIgnore
+					}
+					data, err = transport.Decompress(data)
+				}
+				if err == nil {
+					err = checkMessageSize(topic, data, limits)
+				}
+				if err == nil {
+					err = msg.UnmarshallBinary(data)
+				}
 				if err != nil {
 					feedAddr := ethkey.PeerIDToAddress(psMsg.GetFrom())
 					logger.
@@ -59,6 +89,15 @@ func messageValidator(topics map[string]transport.Message, logger log.Logger) in
 	}
 }
 
+// checkMessageSize returns transport.ErrMessageTooLarge if the message data
+// exceeds the limit configured for the topic.
+func checkMessageSize(topic string, data []byte, limits map[string]int) error {
+	if l := transport.MaxMessageSize(limits, topic); len(data) > l {
+		return transport.ErrMessageTooLarge{Topic: topic, Size: len(data), Limit: l}
+	}
+	return nil
+}
+
 func feederValidator(feeders []ethereum.Address, logger log.Logger) internal.Options {
 	return func(n *internal.Node) error {
 		n.AddValidator(func(ctx context.Context, topic string, id peer.ID, psMsg *pubsub.Message) pubsub.ValidationResult {
@@ -83,6 +122,16 @@ func feederValidator(feeders []ethereum.Address, logger log.Logger) internal.Opt
 	}
 }
 
+// isFeeder returns true if the address is on the list of feeders.
+func isFeeder(feeders []ethereum.Address, addr ethereum.Address) bool {
+	for _, f := range feeders {
+		if f == addr {
+			return true
+		}
+	}
+	return false
+}
+
 // eventValidator adds a validator for event messages.
 func eventValidator(logger log.Logger) internal.Options {
 	return func(n *internal.Node) error {
@@ -111,7 +160,17 @@ func eventValidator(logger log.Logger) internal.Options {
 
 // priceValidator adds a validator for price messages. The validator checks if
 // the price message is valid, and if the price is not older than 5 min.
-func priceValidator(signer ethereum.Signer, logger log.Logger) internal.Options {
+//
+// The price must be signed by the author of the libp2p message or by one of
+// the feeders, because feeders may use multiple keys to sign prices. Peers
+// that deliver prices with invalid signatures are reported using the
+// penalize function.
+func priceValidator(
+	signer ethereum.Signer,
+	feeders []ethereum.Address,
+	penalize func(id peer.ID),
+	logger log.Logger,
+) internal.Options {
 	return func(n *internal.Node) error {
 		n.AddValidator(func(ctx context.Context, topic string, id peer.ID, psMsg *pubsub.Message) pubsub.ValidationResult {
 			priceMsg, ok := psMsg.ValidatorData.(*messages.Price)
@@ -131,10 +190,11 @@ func priceValidator(signer ethereum.Signer, logger log.Logger) internal.Options
 					WithField("age", age).
 					WithField("val", val).
 					Warn("The price message has been rejected, invalid signature")
+				penalize(id)
 				return pubsub.ValidationReject
 			}
 			// The libp2p message should be created by the same person who signs the price message:
-			if ethkey.AddressToPeerID(*priceFrom) != psMsg.GetFrom() {
+			if ethkey.AddressToPeerID(*priceFrom) != psMsg.GetFrom() && !isFeeder(feeders, *priceFrom) {
 				logger.
 					WithField("peerID", psMsg.GetFrom().String()).
 					WithField("from", priceFrom.String()).
@@ -142,6 +202,7 @@ func priceValidator(signer ethereum.Signer, logger log.Logger) internal.Options
 					WithField("age", age).
 					WithField("val", val).
 					Warn("The price message has been rejected, the message and price signatures do not match")
+				penalize(id)
 				return pubsub.ValidationReject
 			}
 			// Check when message was created, ignore if older than 5 min, reject if older than 10 min:
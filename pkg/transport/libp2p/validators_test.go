@@ -0,0 +1,59 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package libp2p
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
+)
+
+func TestCheckMessageSize(t *testing.T) {
+	limits := map[string]int{"foo": 4}
+
+	// Oversized message:
+	err := checkMessageSize("foo", []byte("12345"), limits)
+	assert.True(t, errors.As(err, &transport.ErrMessageTooLarge{}))
+
+	// Well-sized message:
+	assert.NoError(t, checkMessageSize("foo", []byte("1234"), limits))
+
+	// Topics without a limit use the default one:
+	assert.NoError(t, checkMessageSize("bar", make([]byte, transport.DefaultMaxMessageSize), limits))
+	assert.Error(t, checkMessageSize("bar", make([]byte, transport.DefaultMaxMessageSize+1), limits))
+}
+
+func TestMaxMessageSize(t *testing.T) {
+	cfg := Config{
+		Topics:         map[string]transport.Message{"foo": nil, "bar": nil},
+		MaxMessageSize: map[string]int{"foo": 2 * transport.DefaultMaxMessageSize},
+	}
+	assert.Equal(t, 2*transport.DefaultMaxMessageSize, maxMessageSize(cfg))
+}
+
+func TestIsFeeder(t *testing.T) {
+	feeders := []ethereum.Address{
+		ethereum.HexToAddress("0x2d800d93b065ce011af83f316cef9f0d005b0aa4"),
+		ethereum.HexToAddress("0x8eb3daaf5cb4138f5f96711c09c0cfd0288a36e9"),
+	}
+	assert.True(t, isFeeder(feeders, ethereum.HexToAddress("0x8eb3daaf5cb4138f5f96711c09c0cfd0288a36e9")))
+	assert.False(t, isFeeder(feeders, ethereum.HexToAddress("0x07a35a1d4b751a818d93aa38e615c0df23064881")))
+	assert.False(t, isFeeder(nil, ethereum.HexToAddress("0x2d800d93b065ce011af83f316cef9f0d005b0aa4")))
+}
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"reflect"
 	"sync"
+	"time"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
 )
@@ -35,15 +36,22 @@ type Local struct {
 	id     []byte
 	waitCh chan error
 	subs   map[string]*subscription
+	dedup  *transport.Deduplicator
+	ttl    time.Duration
+	now    func() time.Time
 }
 
 type subscription struct {
+	// topic is the name of the subscribed topic.
+	topic string
 	// typ is the structure type to which the message must be unmarshalled.
 	typ reflect.Type
 	// rawMsgs is a channel used to broadcast raw message data.
 	rawMsgs chan []byte
 	// msgs is a channel used to broadcast unmarshalled messages.
 	msgs chan transport.ReceivedMessage
+	// maxSize is the maximum size of a message in bytes.
+	maxSize int
 }
 
 // New returns a new instance of the Local structure. The created transport
@@ -57,12 +65,15 @@ func New(id []byte, queue int, topics map[string]transport.Message) *Local {
 		id:     id,
 		waitCh: make(chan error),
 		subs:   make(map[string]*subscription),
+		now:    time.Now,
 	}
 	for topic, typ := range topics {
 		sub := &subscription{
+			topic:   topic,
 			typ:     reflect.TypeOf(typ).Elem(),
 			rawMsgs: make(chan []byte, queue),
 			msgs:    make(chan transport.ReceivedMessage),
+			maxSize: transport.DefaultMaxMessageSize,
 		}
 		l.subs[topic] = sub
 		go l.unmarshallRoutine(sub)
@@ -100,12 +111,51 @@ func (l *Local) Broadcast(topic string, message transport.Message) error {
 		if err != nil {
 			return err
 		}
+		if len(b) > sub.maxSize {
+			return transport.ErrMessageTooLarge{Topic: topic, Size: len(b), Limit: sub.maxSize}
+		}
+		l.mu.RLock()
+		if l.ttl > 0 {
+			b = transport.AddTimestamp(b, l.now())
+		}
+		l.mu.RUnlock()
 		sub.rawMsgs <- b
 		return nil
 	}
 	return ErrNotSubscribed
 }
 
+// SetMaxMessageSize sets the maximum size of a message in bytes for the given
+// topic. Larger messages are rejected by the Broadcast method before they are
+// queued.
+func (l *Local) SetMaxMessageSize(topic string, size int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if sub, ok := l.subs[topic]; ok {
+		sub.maxSize = size
+		return nil
+	}
+	return ErrNotSubscribed
+}
+
+// EnableDeduplication enables dropping of duplicated messages. A message is
+// considered a duplicate if the same message was received on the same topic
+// within the ttl. The size argument limits the number of remembered messages.
+func (l *Local) EnableDeduplication(size int, ttl time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.dedup = transport.NewDeduplicator(size, ttl)
+}
+
+// SetMessageTTL enables dropping of messages older than the given ttl. Sent
+// messages are prefixed with the time at which they were broadcast, and
+// received messages older than the ttl are not delivered to subscribers.
+func (l *Local) SetMessageTTL(ttl time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ttl = ttl
+}
+
 // Messages implements the transport.Transport interface.
 func (l *Local) Messages(topic string) chan transport.ReceivedMessage {
 	l.mu.RLock()
@@ -123,8 +173,19 @@ func (l *Local) unmarshallRoutine(sub *subscription) {
 			return
 		}
 		l.mu.RLock()
+		produced, msg, err := transport.SplitTimestamp(msg)
+		if err == nil && transport.CheckMessageAge(sub.topic, produced, l.now(), l.ttl) != nil {
+			l.mu.RUnlock()
+			continue
+		}
+		if l.dedup != nil && l.dedup.Seen(sub.topic, msg) {
+			l.mu.RUnlock()
+			continue
+		}
 		message := reflect.New(sub.typ).Interface().(transport.Message)
-		err := message.UnmarshallBinary(msg)
+		if err == nil {
+			err = message.UnmarshallBinary(msg)
+		}
 		sub.msgs <- transport.ReceivedMessage{
 			Message: message,
 			Author:  l.id,
@@ -17,7 +17,10 @@ package local
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -60,3 +63,51 @@ func TestLocal_Messages(t *testing.T) {
 	assert.NoError(t, l.Broadcast("foo", &testMsg{Val: "bar"}))
 	assert.Equal(t, &testMsg{Val: "bar"}, (<-l.Messages("foo")).Message)
 }
+
+func TestLocal_MaxMessageSize(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	l := New([]byte("test"), 1, map[string]transport.Message{"foo": (*testMsg)(nil)})
+	_ = l.Start(ctx)
+	assert.NoError(t, l.SetMaxMessageSize("foo", 8))
+	assert.ErrorIs(t, l.SetMaxMessageSize("bar", 8), ErrNotSubscribed)
+
+	// Oversized message must be rejected before it is queued:
+	err := l.Broadcast("foo", &testMsg{Val: strings.Repeat("a", 9)})
+	assert.True(t, errors.As(err, &transport.ErrMessageTooLarge{}))
+
+	// Well-sized message must pass:
+	assert.NoError(t, l.Broadcast("foo", &testMsg{Val: strings.Repeat("a", 8)}))
+	assert.Equal(t, &testMsg{Val: strings.Repeat("a", 8)}, (<-l.Messages("foo")).Message)
+}
+
+func TestLocal_MessageTTL(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	l := New([]byte("test"), 2, map[string]transport.Message{"foo": (*testMsg)(nil)})
+	l.SetMessageTTL(time.Minute)
+	_ = l.Start(ctx)
+
+	// A message produced two minutes ago must be dropped:
+	l.subs["foo"].rawMsgs <- transport.AddTimestamp([]byte("old"), time.Now().Add(-2*time.Minute))
+	assert.NoError(t, l.Broadcast("foo", &testMsg{Val: "new"}))
+	assert.Equal(t, &testMsg{Val: "new"}, (<-l.Messages("foo")).Message)
+}
+
+func TestLocal_Deduplication(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	l := New([]byte("test"), 3, map[string]transport.Message{"foo": (*testMsg)(nil)})
+	l.EnableDeduplication(10, time.Minute)
+	_ = l.Start(ctx)
+
+	// The same message is broadcast twice, but should be received only once:
+	assert.NoError(t, l.Broadcast("foo", &testMsg{Val: "bar"}))
+	assert.NoError(t, l.Broadcast("foo", &testMsg{Val: "bar"}))
+	assert.NoError(t, l.Broadcast("foo", &testMsg{Val: "baz"}))
+	assert.Equal(t, &testMsg{Val: "bar"}, (<-l.Messages("foo")).Message)
+	assert.Equal(t, &testMsg{Val: "baz"}, (<-l.Messages("foo")).Message)
+}
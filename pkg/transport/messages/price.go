@@ -41,6 +41,11 @@ type Price struct {
 	Trace   json.RawMessage `json:"trace"`             // TODO: allow data in any format, not just JSON
 	Version string          `json:"version,omitempty"` // TODO: this should move to some meta field e.g. `feedVersion`
 
+	// CorrelationID is an optional identifier of the feed cycle in which
+	// the price was created. It is used only to correlate log entries of
+	// feeders and relayers, and it is not signed.
+	CorrelationID string `json:"correlationID,omitempty"`
+
 	// messageVersion is the version of the message. The value 0 corresponds to
 	// the price/v0 and 1 to the price/v1 message. Both messages contain the
 	// same data but the price/v1 uses protobuf to encode the data. After full
@@ -74,10 +79,16 @@ func (p *Price) MarshallBinary() ([]byte, error) {
 			StarkPK: p.Price.StarkPK,
 			Trace:   p.Trace,
 			Version: p.Version,
+			Domain:  p.Price.Domain,
+
+			CorrelationID: p.CorrelationID,
 		}
 		if p.Price.Val != nil {
 			pbPrice.Val = p.Price.Val.Bytes()
 		}
+		if p.Price.Domain != "" {
+			pbPrice.DomainVrs = p.Price.DomainSig.Bytes()
+		}
 		data, err := proto.Marshal(pbPrice)
 		if err != nil {
 			return nil, err
@@ -127,9 +138,14 @@ func (p *Price) UnmarshallBinary(data []byte) error {
 			StarkR:  msg.StarkR,
 			StarkS:  msg.StarkS,
 			StarkPK: msg.StarkPK,
+			Domain:  msg.Domain,
+		}
+		if len(msg.DomainVrs) > 0 {
+			p.Price.DomainSig = ethereum.SignatureFromBytes(msg.DomainVrs)
 		}
 		p.Trace = msg.Trace
 		p.Version = msg.Version
+		p.CorrelationID = msg.CorrelationID
 	case 0:
 		if err := p.Unmarshall(data); err != nil {
 			return err
@@ -168,17 +184,21 @@ func (p *Price) copy() *Price {
 	c := &Price{
 		messageVersion: p.messageVersion,
 		Price: &oracle.Price{
-			Wat:     p.Price.Wat,
-			Age:     p.Price.Age,
-			V:       p.Price.V,
-			R:       p.Price.R,
-			S:       p.Price.S,
-			StarkR:  p.Price.StarkR,
-			StarkS:  p.Price.StarkS,
-			StarkPK: p.Price.StarkPK,
+			Wat:       p.Price.Wat,
+			Age:       p.Price.Age,
+			V:         p.Price.V,
+			R:         p.Price.R,
+			S:         p.Price.S,
+			StarkR:    p.Price.StarkR,
+			StarkS:    p.Price.StarkS,
+			StarkPK:   p.Price.StarkPK,
+			Domain:    p.Price.Domain,
+			DomainSig: p.Price.DomainSig,
 		},
 		Trace:   p.Trace,
 		Version: p.Version,
+
+		CorrelationID: p.CorrelationID,
 	}
 	if p.Price.Val != nil {
 		c.Price.Val = new(big.Int).Set(p.Price.Val)
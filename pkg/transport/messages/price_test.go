@@ -26,7 +26,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
 )
 
 func TestPrice_Marshalling(t *testing.T) {
@@ -94,6 +96,52 @@ func TestPrice_Marshalling(t *testing.T) {
 			}).AsV0(),
 			wantErr: false,
 		},
+		// With domain as V0:
+		{
+			price: (&Price{
+				Price: &oracle.Price{
+					Wat:       "AAABBB",
+					Val:       big.NewInt(10),
+					Age:       time.Unix(100, 0),
+					Domain:    "mainnet",
+					DomainSig: ethereum.Signature{6},
+				},
+				Version: "0.0.1",
+			}).AsV0(),
+			wantErr: false,
+		},
+		// With domain as V1:
+		{
+			price: (&Price{
+				Price: &oracle.Price{
+					Wat:       "AAABBB",
+					Val:       big.NewInt(10),
+					Age:       time.Unix(100, 0),
+					Domain:    "mainnet",
+					DomainSig: ethereum.Signature{6},
+				},
+				Version: "0.0.1",
+			}).AsV1(),
+			wantErr: false,
+		},
+		// With correlation ID as V0:
+		{
+			price: (&Price{
+				Price:         &oracle.Price{Wat: "AAABBB", Val: big.NewInt(10), Age: time.Unix(100, 0)},
+				Version:       "0.0.1",
+				CorrelationID: "foo",
+			}).AsV0(),
+			wantErr: false,
+		},
+		// With correlation ID as V1:
+		{
+			price: (&Price{
+				Price:         &oracle.Price{Wat: "AAABBB", Val: big.NewInt(10), Age: time.Unix(100, 0)},
+				Version:       "0.0.1",
+				CorrelationID: "foo",
+			}).AsV1(),
+			wantErr: false,
+		},
 		// Without trace:
 		{
 			price: &Price{
@@ -178,7 +226,10 @@ func TestPrice_Marshalling(t *testing.T) {
 				assert.Equal(t, tt.price.Price.StarkR, price.Price.StarkR)
 				assert.Equal(t, tt.price.Price.StarkS, price.Price.StarkS)
 				assert.Equal(t, tt.price.Price.StarkPK, price.Price.StarkPK)
+				assert.Equal(t, tt.price.Price.Domain, price.Price.Domain)
+				assert.Equal(t, tt.price.Price.DomainSig, price.Price.DomainSig)
 				assert.Equal(t, tt.price.Version, price.Version)
+				assert.Equal(t, tt.price.CorrelationID, price.CorrelationID)
 
 				if tt.price.messageVersion == 0 && tt.price.Trace == nil {
 					assert.Equal(t, json.RawMessage("null"), price.Trace)
@@ -189,3 +240,39 @@ func TestPrice_Marshalling(t *testing.T) {
 		})
 	}
 }
+
+func TestPrice_Compression(t *testing.T) {
+	for _, price := range []*Price{
+		(&Price{
+			Price: &oracle.Price{Wat: "AAABBB", Val: big.NewInt(10), Age: time.Unix(100, 0)},
+			Trace: json.RawMessage(`{"trace":"` + strings.Repeat("a", 2048) + `"}`),
+		}).AsV0(),
+		(&Price{
+			Price: &oracle.Price{Wat: "AAABBB", Val: big.NewInt(10), Age: time.Unix(100, 0)},
+			Trace: json.RawMessage(`{"trace":"` + strings.Repeat("a", 2048) + `"}`),
+		}).AsV1(),
+	} {
+		data, err := price.MarshallBinary()
+		require.NoError(t, err)
+
+		compressed, err := transport.Compress(data, transport.DefaultCompressionThreshold)
+		require.NoError(t, err)
+		require.Less(t, len(compressed), len(data))
+
+		// Compressed message must be decoded after decompression:
+		decompressed, err := transport.Decompress(compressed)
+		require.NoError(t, err)
+		msg := &Price{}
+		require.NoError(t, msg.UnmarshallBinary(decompressed))
+		assert.Equal(t, price.Price.Wat, msg.Price.Wat)
+		assert.Equal(t, price.Trace, msg.Trace)
+
+		// Uncompressed messages must still be understood by a decoder that
+		// supports compression:
+		decompressed, err = transport.Decompress(data)
+		require.NoError(t, err)
+		msg = &Price{}
+		require.NoError(t, msg.UnmarshallBinary(decompressed))
+		assert.Equal(t, price.Trace, msg.Trace)
+	}
+}
@@ -0,0 +1,234 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package multi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
+)
+
+const LoggerTag = "MULTI_TRANSPORT"
+
+// ErrBroadcastFailed is returned by the Broadcast method if a message could
+// not be sent using any of the transports.
+type ErrBroadcastFailed struct {
+	Topic  string
+	Errors []error
+}
+
+func (e ErrBroadcastFailed) Error() string {
+	s := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		s[i] = err.Error()
+	}
+	return fmt.Sprintf("unable to broadcast a message to the %s topic: %s", e.Topic, strings.Join(s, ", "))
+}
+
+// Multi is an implementation of the transport.Transport interface that uses
+// several transports at once. Messages are broadcast using all transports,
+// and messages received from all transports are merged into a single
+// channel per topic. Because the same message is usually delivered by more
+// than one transport, duplicates are dropped.
+//
+// A failure of one transport does not affect the others.
+type Multi struct {
+	ctx context.Context
+
+	waitCh     chan error
+	forwardWG  sync.WaitGroup
+	transports []transport.Transport
+	msgs       map[string]chan transport.ReceivedMessage
+	dedup      *transport.Deduplicator
+	log        log.Logger
+}
+
+// Config is the configuration for the Multi transport.
+type Config struct {
+	// Transports is the list of multiplexed transports. Transports are
+	// started and stopped by the Multi transport.
+	Transports []transport.Transport
+	// Topics is a list of subscribed topics. A value of the map a type of
+	// message given as a nil pointer, e.g.: (*Message)(nil).
+	Topics map[string]transport.Message
+	// DedupCacheSize is the maximum number of remembered messages used to
+	// drop duplicates. If zero, transport.DefaultDedupCacheSize is used.
+	DedupCacheSize int
+	// DedupTTL is the time for which a message is remembered. If zero,
+	// transport.DefaultDedupTTL is used.
+	DedupTTL time.Duration
+	// Logger is a custom logger instance. If not provided then null
+	// logger is used.
+	Logger log.Logger
+}
+
+// New returns a new instance of the Multi transport.
+func New(cfg Config) (*Multi, error) {
+	if len(cfg.Transports) == 0 {
+		return nil, errors.New("at least one transport must be provided")
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = null.New()
+	}
+	m := &Multi{
+		waitCh:     make(chan error),
+		transports: cfg.Transports,
+		msgs:       make(map[string]chan transport.ReceivedMessage),
+		dedup:      transport.NewDeduplicator(cfg.DedupCacheSize, cfg.DedupTTL),
+		log:        cfg.Logger.WithField("tag", LoggerTag),
+	}
+	for topic := range cfg.Topics {
+		m.msgs[topic] = make(chan transport.ReceivedMessage)
+	}
+	return m, nil
+}
+
+// Start implements the transport.Transport interface.
+func (m *Multi) Start(ctx context.Context) error {
+	if m.ctx != nil {
+		return errors.New("service can be started only once")
+	}
+	if ctx == nil {
+		return errors.New("context must not be nil")
+	}
+	m.ctx = ctx
+	for i, t := range m.transports {
+		if err := t.Start(ctx); err != nil {
+			return fmt.Errorf("unable to start the transport #%d: %w", i, err)
+		}
+	}
+	for topic, ch := range m.msgs {
+		wg := &sync.WaitGroup{}
+		for _, t := range m.transports {
+			wg.Add(1)
+			go m.forwardRoutine(wg, topic, t.Messages(topic), ch)
+		}
+		m.forwardWG.Add(1)
+		go func(ch chan transport.ReceivedMessage) {
+			defer m.forwardWG.Done()
+			wg.Wait()
+			close(ch)
+		}(ch)
+	}
+	go m.waitRoutine()
+	return nil
+}
+
+// Wait implements the transport.Transport interface.
+func (m *Multi) Wait() chan error {
+	return m.waitCh
+}
+
+// ID implements the transport.Transport interface. It returns the ID of the
+// first transport.
+func (m *Multi) ID() []byte {
+	return m.transports[0].ID()
+}
+
+// Broadcast implements the transport.Transport interface. The message is
+// sent using all transports. An error is returned only if none of them was
+// able to send the message.
+func (m *Multi) Broadcast(topic string, message transport.Message) error {
+	var errs []error
+	for i, t := range m.transports {
+		if err := t.Broadcast(topic, message); err != nil {
+			m.log.
+				WithError(err).
+				WithFields(log.Fields{"topic": topic, "transport": i}).
+				Warn("Unable to broadcast a message")
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == len(m.transports) {
+		return ErrBroadcastFailed{Topic: topic, Errors: errs}
+	}
+	return nil
+}
+
+// Messages implements the transport.Transport interface.
+func (m *Multi) Messages(topic string) chan transport.ReceivedMessage {
+	return m.msgs[topic]
+}
+
+// forwardRoutine forwards messages from a single transport to the merged
+// channel, dropping messages already received from another transport. It
+// stops when the context is canceled, even if the transport never closes
+// its channel.
+func (m *Multi) forwardRoutine(
+	wg *sync.WaitGroup,
+	topic string,
+	in chan transport.ReceivedMessage,
+	out chan transport.ReceivedMessage,
+) {
+	defer wg.Done()
+	if in == nil {
+		return
+	}
+	for {
+		var msg transport.ReceivedMessage
+		select {
+		case <-m.ctx.Done():
+			return
+		case r, ok := <-in:
+			if !ok {
+				return
+			}
+			msg = r
+		}
+		if msg.Error == nil && msg.Message != nil {
+			b, err := msg.Message.MarshallBinary()
+			if err == nil && m.dedup.Seen(topic, b) {
+				continue
+			}
+		}
+		select {
+		case <-m.ctx.Done():
+			return
+		case out <- msg:
+		}
+	}
+}
+
+// waitRoutine waits until all transports and forwarding routines are
+// stopped. Errors returned by transports are logged, but they do not stop
+// other transports.
+func (m *Multi) waitRoutine() {
+	defer func() { close(m.waitCh) }()
+	wg := &sync.WaitGroup{}
+	for i, t := range m.transports {
+		wg.Add(1)
+		go func(i int, t transport.Transport) {
+			defer wg.Done()
+			for err := range t.Wait() {
+				if err != nil {
+					m.log.
+						WithError(err).
+						WithField("transport", i).
+						Error("Transport error")
+				}
+			}
+		}(i, t)
+	}
+	wg.Wait()
+	m.forwardWG.Wait()
+}
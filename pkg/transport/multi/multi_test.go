@@ -0,0 +1,187 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package multi
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/local"
+)
+
+type testMsg struct {
+	Val string
+}
+
+func (t *testMsg) MarshallBinary() ([]byte, error) {
+	return []byte(t.Val), nil
+}
+
+func (t *testMsg) UnmarshallBinary(bytes []byte) error {
+	t.Val = string(bytes)
+	return nil
+}
+
+// failingTransport is a transport that is unable to broadcast messages.
+type failingTransport struct {
+	*local.Local
+}
+
+func (f *failingTransport) Broadcast(string, transport.Message) error {
+	return errors.New("broadcast failed")
+}
+
+var topics = map[string]transport.Message{"foo": (*testMsg)(nil)}
+
+// spyTransport records broadcast messages instead of sending them.
+type spyTransport struct {
+	*local.Local
+	mu   sync.Mutex
+	msgs []transport.Message
+}
+
+func (s *spyTransport) Broadcast(_ string, msg transport.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.msgs = append(s.msgs, msg)
+	return nil
+}
+
+// openTransport is a transport that never closes its message channels,
+// like the file transport.
+type openTransport struct {
+	*local.Local
+	ch chan transport.ReceivedMessage
+}
+
+func (o *openTransport) Messages(string) chan transport.ReceivedMessage {
+	return o.ch
+}
+
+func TestMulti_Broadcast(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	t1 := &spyTransport{Local: local.New([]byte("t1"), 1, topics)}
+	t2 := &spyTransport{Local: local.New([]byte("t2"), 1, topics)}
+	m, err := New(Config{Transports: []transport.Transport{t1, t2}, Topics: topics})
+	require.NoError(t, err)
+	require.NoError(t, m.Start(ctx))
+
+	// The message must be sent using both transports:
+	require.NoError(t, m.Broadcast("foo", &testMsg{Val: "bar"}))
+	assert.Equal(t, []transport.Message{&testMsg{Val: "bar"}}, t1.msgs)
+	assert.Equal(t, []transport.Message{&testMsg{Val: "bar"}}, t2.msgs)
+}
+
+func TestMulti_Broadcast_Failure(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	t1 := &failingTransport{Local: local.New([]byte("t1"), 1, topics)}
+	t2 := local.New([]byte("t2"), 1, topics)
+	m, err := New(Config{Transports: []transport.Transport{t1, t2}, Topics: topics})
+	require.NoError(t, err)
+	require.NoError(t, m.Start(ctx))
+
+	// A failure of one transport must not affect the other one:
+	require.NoError(t, m.Broadcast("foo", &testMsg{Val: "bar"}))
+	assert.Equal(t, &testMsg{Val: "bar"}, (<-m.Messages("foo")).Message)
+
+	// If all transports fail, an error is returned:
+	m, err = New(Config{Transports: []transport.Transport{t1}, Topics: topics})
+	require.NoError(t, err)
+	assert.True(t, errors.As(m.Broadcast("foo", &testMsg{Val: "bar"}), &ErrBroadcastFailed{}))
+}
+
+func TestMulti_Messages(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	t1 := local.New([]byte("t1"), 2, topics)
+	t2 := local.New([]byte("t2"), 2, topics)
+	m, err := New(Config{Transports: []transport.Transport{t1, t2}, Topics: topics})
+	require.NoError(t, err)
+	require.NoError(t, m.Start(ctx))
+
+	// The same message is delivered by both transports, and a different
+	// message only by the second one:
+	require.NoError(t, t1.Broadcast("foo", &testMsg{Val: "a"}))
+	require.NoError(t, t2.Broadcast("foo", &testMsg{Val: "a"}))
+	require.NoError(t, t2.Broadcast("foo", &testMsg{Val: "b"}))
+
+	var vals []string
+	timeout := time.After(500 * time.Millisecond)
+loop:
+	for {
+		select {
+		case msg := <-m.Messages("foo"):
+			vals = append(vals, msg.Message.(*testMsg).Val)
+		case <-timeout:
+			break loop
+		}
+	}
+	assert.ElementsMatch(t, []string{"a", "b"}, vals)
+}
+
+func TestMulti_Wait(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+
+	t1 := local.New([]byte("t1"), 1, topics)
+	t2 := local.New([]byte("t2"), 1, topics)
+	m, err := New(Config{Transports: []transport.Transport{t1, t2}, Topics: topics})
+	require.NoError(t, err)
+	require.NoError(t, m.Start(ctx))
+
+	// After the context is canceled, the merged channels and the wait
+	// channel must be closed:
+	ctxCancel()
+	_, ok := <-m.Messages("foo")
+	assert.False(t, ok)
+	_, ok = <-m.Wait()
+	assert.False(t, ok)
+}
+
+func TestMulti_Wait_OpenChannels(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+
+	t1 := &openTransport{Local: local.New([]byte("t1"), 1, topics), ch: make(chan transport.ReceivedMessage)}
+	m, err := New(Config{Transports: []transport.Transport{t1}, Topics: topics})
+	require.NoError(t, err)
+	require.NoError(t, m.Start(ctx))
+
+	// Channels that are never closed by the transport must not prevent
+	// the Multi transport from stopping:
+	ctxCancel()
+	select {
+	case <-m.Wait():
+	case <-time.After(time.Second):
+		assert.Fail(t, "the wait channel was not closed")
+	}
+	select {
+	case _, ok := <-m.Messages("foo"):
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		assert.Fail(t, "the messages channel was not closed")
+	}
+}
@@ -0,0 +1,115 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package transport
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// HeaderTimestamp is stored in the byte following the compressionMarker in
+// messages prefixed with the time at which they were produced. The timestamp
+// is encoded as the number of nanoseconds since the Unix epoch in the next
+// 8 bytes. The rest of the message may be compressed.
+const HeaderTimestamp byte = 0x02
+
+// timestampHeaderLen is the length of the timestamp header.
+const timestampHeaderLen = 10
+
+// maxClockSkew is the maximum time by which a message may be produced after
+// it has been received. It allows small differences between node clocks.
+const maxClockSkew = 5 * time.Second
+
+var ErrInvalidTimestampHeader = errors.New("invalid message timestamp header")
+
+// ErrMessageExpired is returned when a message is older than the configured
+// message TTL.
+type ErrMessageExpired struct {
+	Topic string
+	Age   time.Duration
+	TTL   time.Duration
+}
+
+func (e ErrMessageExpired) Error() string {
+	return fmt.Sprintf(
+		"the message for the %s topic is %s old, which exceeds the TTL of %s",
+		e.Topic,
+		e.Age,
+		e.TTL,
+	)
+}
+
+// ErrMessageFromFuture is returned when a message is produced further in
+// the future than the allowed clock skew.
+type ErrMessageFromFuture struct {
+	Topic string
+	Ahead time.Duration
+}
+
+func (e ErrMessageFromFuture) Error() string {
+	return fmt.Sprintf(
+		"the message for the %s topic is produced %s in the future, which exceeds the allowed clock skew of %s",
+		e.Topic,
+		e.Ahead,
+		maxClockSkew,
+	)
+}
+
+// AddTimestamp prepends the header with the time at which the message was
+// produced to the given message data.
+func AddTimestamp(data []byte, t time.Time) []byte {
+	out := make([]byte, timestampHeaderLen+len(data))
+	out[0] = compressionMarker
+	out[1] = HeaderTimestamp
+	binary.BigEndian.PutUint64(out[2:timestampHeaderLen], uint64(t.UnixNano()))
+	copy(out[timestampHeaderLen:], data)
+	return out
+}
+
+// SplitTimestamp returns the time at which the message was produced and the
+// message data without the timestamp header. If the data does not start with
+// the header, the zero time is returned, and the data is returned unchanged.
+func SplitTimestamp(data []byte) (time.Time, []byte, error) {
+	if len(data) < 2 || data[0] != compressionMarker || data[1] != HeaderTimestamp {
+		return time.Time{}, data, nil
+	}
+	if len(data) < timestampHeaderLen {
+		return time.Time{}, nil, ErrInvalidTimestampHeader
+	}
+	ts := int64(binary.BigEndian.Uint64(data[2:timestampHeaderLen]))
+	return time.Unix(0, ts), data[timestampHeaderLen:], nil
+}
+
+// CheckMessageAge returns ErrMessageExpired if a message produced at the
+// given time is older than the TTL, or ErrMessageFromFuture if it is
+// produced more than a few seconds in the future, so the TTL cannot be
+// bypassed by a future timestamp. Messages without a timestamp and all
+// messages when the TTL is zero are accepted.
+func CheckMessageAge(topic string, produced, now time.Time, ttl time.Duration) error {
+	if ttl <= 0 || produced.IsZero() {
+		return nil
+	}
+	age := now.Sub(produced)
+	if age > ttl {
+		return ErrMessageExpired{Topic: topic, Age: age, TTL: ttl}
+	}
+	if -age > maxClockSkew {
+		return ErrMessageFromFuture{Topic: topic, Ahead: -age}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package transport
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestamp(t *testing.T) {
+	ts := time.Unix(1600000000, 123)
+	data := AddTimestamp([]byte("foo"), ts)
+
+	produced, msg, err := SplitTimestamp(data)
+	require.NoError(t, err)
+	assert.True(t, ts.Equal(produced))
+	assert.Equal(t, []byte("foo"), msg)
+
+	// Timestamp must be added before compression:
+	compressed, err := Compress([]byte("foo"), 0)
+	require.NoError(t, err)
+	_, msg, err = SplitTimestamp(AddTimestamp(compressed, ts))
+	require.NoError(t, err)
+	msg, err = Decompress(msg)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("foo"), msg)
+}
+
+func TestSplitTimestamp_WithoutHeader(t *testing.T) {
+	produced, msg, err := SplitTimestamp([]byte("foo"))
+	require.NoError(t, err)
+	assert.True(t, produced.IsZero())
+	assert.Equal(t, []byte("foo"), msg)
+
+	_, _, err = SplitTimestamp([]byte{compressionMarker, HeaderTimestamp, 1})
+	assert.ErrorIs(t, err, ErrInvalidTimestampHeader)
+}
+
+func TestCheckMessageAge(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	ttl := time.Minute
+
+	assert.NoError(t, CheckMessageAge("foo", now.Add(-ttl), now, ttl))
+	assert.NoError(t, CheckMessageAge("foo", time.Time{}, now, ttl))
+	assert.NoError(t, CheckMessageAge("foo", now.Add(-time.Hour), now, 0))
+
+	err := CheckMessageAge("foo", now.Add(-ttl-time.Second), now, ttl)
+	assert.True(t, errors.As(err, &ErrMessageExpired{}))
+}
+
+func TestCheckMessageAge_Future(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	ttl := time.Minute
+
+	// Small clock differences are allowed:
+	assert.NoError(t, CheckMessageAge("foo", now.Add(maxClockSkew), now, ttl))
+
+	err := CheckMessageAge("foo", now.Add(maxClockSkew+time.Second), now, ttl)
+	assert.True(t, errors.As(err, &ErrMessageFromFuture{}))
+	err = CheckMessageAge("foo", now.Add(time.Hour), now, ttl)
+	assert.True(t, errors.As(err, &ErrMessageFromFuture{}))
+
+	// Without the TTL, timestamps are not checked:
+	assert.NoError(t, CheckMessageAge("foo", now.Add(time.Hour), now, 0))
+}
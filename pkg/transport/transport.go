@@ -15,7 +15,41 @@
 
 package transport
 
-import "context"
+import (
+	"context"
+	"fmt"
+)
+
+// DefaultMaxMessageSize is the default maximum size of a message in bytes,
+// used for topics without a configured limit.
+const DefaultMaxMessageSize = 1 * 1024 * 1024 // 1MB
+
+// ErrMessageTooLarge is returned when the size of a message exceeds the
+// limit configured for its topic.
+type ErrMessageTooLarge struct {
+	Topic string
+	Size  int
+	Limit int
+}
+
+func (e ErrMessageTooLarge) Error() string {
+	return fmt.Sprintf(
+		"the message for the %s topic is %d bytes long, which exceeds the limit of %d bytes",
+		e.Topic,
+		e.Size,
+		e.Limit,
+	)
+}
+
+// MaxMessageSize returns the maximum message size for the given topic.
+// If the limit is not defined in the limits map, DefaultMaxMessageSize
+// is returned.
+func MaxMessageSize(limits map[string]int, topic string) int {
+	if l, ok := limits[topic]; ok && l > 0 {
+		return l
+	}
+	return DefaultMaxMessageSize
+}
 
 // ReceivedMessage contains a Message received from Transport with
 // an additional data.
@@ -37,6 +71,16 @@ type Message interface {
 	UnmarshallBinary([]byte) error
 }
 
+// Reporter is an optional interface that may be implemented by a Transport.
+// It allows to report messages that were found to be invalid after they were
+// delivered, e.g. because of an invalid signature, so that the transport can
+// penalize peers that sent them.
+type Reporter interface {
+	// ReportInvalid reports a message received from the Messages channel
+	// as invalid.
+	ReportInvalid(msg ReceivedMessage)
+}
+
 // Transport is the interface for different implementations of a
 // publish–subscribe messaging solutions for the Oracle network.
 type Transport interface {
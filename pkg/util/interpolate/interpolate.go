@@ -28,6 +28,9 @@ import (
 //
 // - Variables may have a default value separated by -, eq. ${VAR-default}.
 //
+// - If the default value is separated by :-, eq. ${VAR:-default}, it is also
+// used when the variable is set but empty.
+//
 // - To include a literal $, escape it with a backslash, eq. \$.
 //
 // - If a variable is not closed, it is treated as a literal.
@@ -46,6 +49,9 @@ func Parse(s string) Parsed {
 //
 // - Variables may have a default value separated by -, eq. %{VAR-default}.
 //
+// - If the default value is separated by :-, eq. %{VAR:-default}, it is also
+// used when the variable is set but empty.
+//
 // - To include a literal %, escape it with a backslash, eq. \%.
 //
 // - If a variable is not closed, it is treated as a literal.
@@ -59,9 +65,10 @@ func ParsePercent(s string) Parsed {
 type Parsed []part
 
 type Variable struct {
-	Name       string // Name of the variable.
-	Default    string // Default value if the variable is not set.
-	HasDefault bool   // True if the variable has a default value.
+	Name           string // Name of the variable.
+	Default        string // Default value if the variable is not set.
+	HasDefault     bool   // True if the variable has a default value.
+	DefaultIfEmpty bool   // True if the default value is also used for empty variables.
 }
 
 // Interpolate replaces variables in the string based on the mapping function.
@@ -104,42 +111,46 @@ const (
 	tokenPercentVarBegin = "%{"
 	tokenVarEnd          = "}"
 	tokenDefaultVal      = "-"
+	tokenDefaultValEmpty = ":-"
 	tokenBackslash       = "\\"
 )
 
 func dollarParser(s string) parser {
 	return parser{
-		in:              s,
-		out:             make([]part, 0, 1),
-		tokenBackslash:  tokenBackslash,
-		tokenVarBegin:   tokenDollarVarBegin,
-		tokenVarEnd:     tokenVarEnd,
-		tokenDefaultVal: tokenDefaultVal,
+		in:                   s,
+		out:                  make([]part, 0, 1),
+		tokenBackslash:       tokenBackslash,
+		tokenVarBegin:        tokenDollarVarBegin,
+		tokenVarEnd:          tokenVarEnd,
+		tokenDefaultVal:      tokenDefaultVal,
+		tokenDefaultValEmpty: tokenDefaultValEmpty,
 	}
 }
 
 func percentParser(s string) parser {
 	return parser{
-		in:              s,
-		out:             make([]part, 0, 1),
-		tokenBackslash:  tokenBackslash,
-		tokenVarBegin:   tokenPercentVarBegin,
-		tokenVarEnd:     tokenVarEnd,
-		tokenDefaultVal: tokenDefaultVal,
+		in:                   s,
+		out:                  make([]part, 0, 1),
+		tokenBackslash:       tokenBackslash,
+		tokenVarBegin:        tokenPercentVarBegin,
+		tokenVarEnd:          tokenVarEnd,
+		tokenDefaultVal:      tokenDefaultVal,
+		tokenDefaultValEmpty: tokenDefaultValEmpty,
 	}
 }
 
 type parser struct {
-	in              string
-	out             Parsed
-	pos             int
-	litBuf          strings.Builder
-	varBuf          strings.Builder
-	defBuf          strings.Builder
-	tokenBackslash  string
-	tokenVarBegin   string
-	tokenVarEnd     string
-	tokenDefaultVal string
+	in                   string
+	out                  Parsed
+	pos                  int
+	litBuf               strings.Builder
+	varBuf               strings.Builder
+	defBuf               strings.Builder
+	tokenBackslash       string
+	tokenVarBegin        string
+	tokenVarEnd          string
+	tokenDefaultVal      string
+	tokenDefaultValEmpty string
 }
 
 func (p *parser) parse() {
@@ -168,6 +179,7 @@ func (p *parser) parseBackslash() {
 func (p *parser) parseVariable() {
 	pos := p.pos
 	def := false
+	defIfEmpty := false
 	p.varBuf.Reset()
 	p.defBuf.Reset()
 	for p.hasNext() {
@@ -177,19 +189,29 @@ func (p *parser) parseVariable() {
 				continue
 			}
 			p.varBuf.WriteByte(p.nextByte())
+		case p.nextToken(p.tokenDefaultValEmpty):
+			def = true
+			defIfEmpty = true
+			p.parseDefault()
 		case p.nextToken(p.tokenDefaultVal):
 			def = true
 			p.parseDefault()
 		case p.nextToken(p.tokenVarEnd):
 			p.appendVariable(Variable{
-				Name:       p.varBuf.String(),
-				Default:    p.defBuf.String(),
-				HasDefault: def,
+				Name:           p.varBuf.String(),
+				Default:        p.defBuf.String(),
+				HasDefault:     def,
+				DefaultIfEmpty: defIfEmpty,
 			})
 			return
 		default:
 			// Add all characters to the first character that may start the token.
-			p.varBuf.WriteString(p.nextBytesUntilAnyOf(p.tokenVarEnd[0], p.tokenDefaultVal[0], p.tokenBackslash[0]))
+			p.varBuf.WriteString(p.nextBytesUntilAnyOf(
+				p.tokenVarEnd[0],
+				p.tokenDefaultVal[0],
+				p.tokenDefaultValEmpty[0],
+				p.tokenBackslash[0],
+			))
 		}
 	}
 	// Variable not closed. Treat the whole thing as a literal.
@@ -61,6 +61,21 @@ func TestParse(t *testing.T) {
 			want:    "[baz]",
 			parsers: []parserFunc{ParsePercent},
 		},
+		{
+			str:     "${bar:-baz}",
+			want:    "[baz]",
+			parsers: []parserFunc{Parse},
+		},
+		{
+			str:     "%{bar:-baz}",
+			want:    "[baz]",
+			parsers: []parserFunc{ParsePercent},
+		},
+		{
+			str:     "${bar:baz}",
+			want:    "[bar:baz]",
+			parsers: []parserFunc{Parse},
+		},
 		{
 			str:     "${bar\\-baz}",
 			want:    "[bar-baz]",
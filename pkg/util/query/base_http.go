@@ -16,22 +16,77 @@
 package query
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
+	"math/rand"
+	"net"
 	"net/http"
+	"strconv"
 	"time"
 )
 
 // Default retry amount
 const defaultRetry = 3
 
-// Default delay between retries
-const defaultDelayBetweenRetries = 1 * time.Second
+// Default delay before the first retry
+const defaultDelayBetweenRetries = 500 * time.Millisecond
+
+// Default maximum delay between retries
+const defaultMaxDelayBetweenRetries = 5 * time.Second
+
+// Default jitter applied to delays between retries
+const defaultRetryJitter = 0.2
 
 // Default timeout for HTTP Request
 const defaultTimeoutInSeconds = 5
 
+// DefaultRetryPolicy is the retry policy used by MakeHTTPRequest and by worker
+// pools created with NewHTTPWorkerPool.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: defaultRetry,
+	BaseDelay:   defaultDelayBetweenRetries,
+	MaxDelay:    defaultMaxDelayBetweenRetries,
+	Jitter:      defaultRetryJitter,
+}
+
+// httpTransport is used by HTTP clients to perform requests. If nil,
+// http.DefaultTransport is used. It may be replaced in tests.
+var httpTransport http.RoundTripper
+
+// RetryPolicy describes how failed HTTP requests are retried. Only network
+// errors and responses with the 5xx or 429 status codes are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry. Every next delay is
+	// doubled.
+	BaseDelay time.Duration
+	// MaxDelay is the maximum delay between attempts. If a server asks to
+	// wait longer using the Retry-After header, the request is not retried.
+	// Zero means no limit.
+	MaxDelay time.Duration
+	// Jitter is a fraction of the delay by which the delay may be randomly
+	// increased or decreased. It must be between 0 and 1.
+	Jitter float64
+}
+
+// delay returns the delay before the given retry attempt, starting from 1.
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := float64(p.BaseDelay) * math.Pow(2, float64(attempt-1))
+	if p.MaxDelay > 0 && d > float64(p.MaxDelay) {
+		d = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		d += d * p.Jitter * (2*rand.Float64() - 1) //nolint:gosec
+	}
+	return time.Duration(d)
+}
+
 // HTTPRequest default HTTP Request structure
 type HTTPRequest struct {
 	URL     string
@@ -40,6 +95,9 @@ type HTTPRequest struct {
 	Retry   int
 	Timeout time.Duration
 	Body    io.Reader
+	// Context is used to cancel the request and retries. If nil,
+	// context.Background is used.
+	Context context.Context
 }
 
 // HTTPResponse default query engine response
@@ -48,12 +106,38 @@ type HTTPResponse struct {
 	Error error
 }
 
+// ErrHTTPStatus is returned when a server responds with an unexpected
+// status code.
+type ErrHTTPStatus struct {
+	URL        string
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+func (e ErrHTTPStatus) Error() string {
+	return fmt.Sprintf("failed to make HTTP request to %s, got %d status code", e.URL, e.StatusCode)
+}
+
 // MakeHTTPRequest makes HTTP request to given `url` with `headers` and in case of error
 // it will retry request `retry` amount of times. And only after it (if it's still error) error will be returned.
-// Automatically timeout between requests will be calculated using `random`.
+// Retries are made using the DefaultRetryPolicy.
 // Note for `timeout` waiting this function uses `time.Sleep()` so it will block execution flow.
 // Better to be used in go-routine.
 func MakeHTTPRequest(r *HTTPRequest) *HTTPResponse {
+	return MakeHTTPRequestWithRetryPolicy(r, DefaultRetryPolicy)
+}
+
+// MakeHTTPRequestWithRetryPolicy works like MakeHTTPRequest but retries failed
+// requests according to the given policy. If the `Retry` field of the request
+// is set, it overrides the maximum number of attempts from the policy.
+func MakeHTTPRequestWithRetryPolicy(r *HTTPRequest, p RetryPolicy) *HTTPResponse {
+	return makeHTTPRequest(r, p, nil)
+}
+
+// makeHTTPRequest works like MakeHTTPRequestWithRetryPolicy but performs
+// requests using the given transport. If the transport is nil, httpTransport
+// is used.
+func makeHTTPRequest(r *HTTPRequest, p RetryPolicy, t http.RoundTripper) *HTTPResponse {
 	if r == nil {
 		return &HTTPResponse{
 			Error: fmt.Errorf("failed to make HTTP request to `nil`"),
@@ -62,22 +146,49 @@ func MakeHTTPRequest(r *HTTPRequest) *HTTPResponse {
 
 	// Check for non set Retry
 	if r.Retry == 0 {
-		r.Retry = defaultRetry
+		r.Retry = p.MaxAttempts
+	}
+	if r.Retry <= 0 {
+		r.Retry = 1
+	}
+	if r.Context == nil {
+		r.Context = context.Background()
+	}
+
+	// The body has to be read into the memory, otherwise it would not be
+	// possible to send it again.
+	var reqBody []byte
+	if r.Body != nil {
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return &HTTPResponse{Error: err}
+		}
+		reqBody = b
 	}
 
-	step := 1
 	var res []byte
 	var err error
 
-	for step <= r.Retry {
-		res, err = doMakeHTTPRequest(r)
-		if err != nil {
-			time.Sleep(defaultDelayBetweenRetries)
-			step++
-			continue
+	for step := 1; step <= r.Retry; step++ {
+		if reqBody != nil {
+			r.Body = bytes.NewReader(reqBody)
+		}
+		res, err = doMakeHTTPRequest(r, t)
+		if err == nil || step == r.Retry || !isRetryable(r.Context, err) {
+			break
+		}
+		delay := p.delay(step)
+		var statusErr ErrHTTPStatus
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+			if p.MaxDelay > 0 && statusErr.RetryAfter > p.MaxDelay {
+				break
+			}
+			delay = statusErr.RetryAfter
+		}
+		if !sleep(r.Context, delay) {
+			err = r.Context.Err()
+			break
 		}
-		// All ok no `err` received
-		break
 	}
 
 	return &HTTPResponse{
@@ -86,7 +197,7 @@ func MakeHTTPRequest(r *HTTPRequest) *HTTPResponse {
 	}
 }
 
-func doMakeHTTPRequest(r *HTTPRequest) ([]byte, error) {
+func doMakeHTTPRequest(r *HTTPRequest, t http.RoundTripper) ([]byte, error) {
 	if r == nil {
 		return nil, fmt.Errorf("failed to make HTTP request to `nil`")
 	}
@@ -99,11 +210,19 @@ func doMakeHTTPRequest(r *HTTPRequest) ([]byte, error) {
 	if r.Timeout == time.Duration(0) {
 		r.Timeout = defaultTimeoutInSeconds * time.Second
 	}
+	ctx := r.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
 
+	if t == nil {
+		t = httpTransport
+	}
 	client := &http.Client{
-		Timeout: r.Timeout,
+		Transport: t,
+		Timeout:   r.Timeout,
 	}
-	req, err := http.NewRequest(r.Method, r.URL, r.Body)
+	req, err := http.NewRequestWithContext(ctx, r.Method, r.URL, r.Body)
 	if err != nil {
 		return nil, err
 	}
@@ -117,11 +236,61 @@ func doMakeHTTPRequest(r *HTTPRequest) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	if resp.StatusCode != 200 && resp.StatusCode != 201 {
-		return nil, fmt.Errorf("failed to make HTTP request to %s, got %d status code", r.URL, resp.StatusCode)
-	}
 
 	defer resp.Body.Close()
 
+	if resp.StatusCode != 200 && resp.StatusCode != 201 {
+		return nil, ErrHTTPStatus{
+			URL:        r.URL,
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
 	return ioutil.ReadAll(resp.Body)
 }
+
+// isRetryable returns true if the request that failed with the given error
+// may be retried.
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var statusErr ErrHTTPStatus
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 || statusErr.StatusCode == http.StatusTooManyRequests
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// parseRetryAfter parses the value of the Retry-After header, which may be
+// either a number of seconds or an HTTP date. It returns zero if the value
+// is empty or invalid.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if s, err := strconv.Atoi(v); err == nil && s > 0 {
+		return time.Duration(s) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// sleep waits for the given duration. It returns false if the context was
+// canceled before the duration elapsed.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
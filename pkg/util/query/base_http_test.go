@@ -16,9 +16,11 @@
 package query
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -53,7 +55,7 @@ func (suite *MakeRequestSuite) TestMakingRequest() {
 	}))
 
 	assert.NotNil(suite.T(), suite.server)
-	data, err := doMakeHTTPRequest(&HTTPRequest{URL: suite.server.URL})
+	data, err := doMakeHTTPRequest(&HTTPRequest{URL: suite.server.URL}, nil)
 
 	assert.NoError(suite.T(), err)
 	assert.EqualValues(suite.T(), []byte(serverResponse), data)
@@ -67,7 +69,7 @@ func (suite *MakeRequestSuite) TestMakingRequestToNotFound() {
 	}))
 
 	assert.NotNil(suite.T(), suite.server)
-	data, err := doMakeHTTPRequest(&HTTPRequest{URL: suite.server.URL})
+	data, err := doMakeHTTPRequest(&HTTPRequest{URL: suite.server.URL}, nil)
 
 	assert.Error(suite.T(), err)
 	assert.Empty(suite.T(), data)
@@ -87,7 +89,7 @@ func (suite *MakeRequestSuite) TestMakingRequestWithHeaders() {
 		URL:     suite.server.URL,
 		Headers: headers,
 	}
-	data, err := doMakeHTTPRequest(r)
+	data, err := doMakeHTTPRequest(r, nil)
 
 	assert.NoError(suite.T(), err)
 	assert.EqualValues(suite.T(), []byte(serverResponse), data)
@@ -106,7 +108,7 @@ func (suite *MakeRequestSuite) TestMakingPOSTRequest() {
 		URL:    suite.server.URL,
 		Method: "POST",
 	}
-	data, err := doMakeHTTPRequest(r)
+	data, err := doMakeHTTPRequest(r, nil)
 
 	assert.NoError(suite.T(), err)
 	assert.EqualValues(suite.T(), []byte(serverResponse), data)
@@ -119,7 +121,7 @@ func (suite *MakeRequestSuite) TestMakeHTTPRequestWithRetryFails() {
 		assert.EqualValues(suite.T(), requiredHeaderValue, req.Header.Get(requiredHeaderKey))
 		calls++
 		// Send response to be tested.
-		rw.WriteHeader(404)
+		rw.WriteHeader(500)
 	}))
 
 	assert.NotNil(suite.T(), suite.server)
@@ -145,7 +147,7 @@ func (suite *MakeRequestSuite) TestMakeHTTPRequestWithRetry() {
 		// Send response to be tested.
 		// Successonly on 3rd call
 		if calls < 3 {
-			rw.WriteHeader(404)
+			rw.WriteHeader(503)
 		} else {
 			rw.Write([]byte(serverResponse))
 		}
@@ -165,6 +167,109 @@ func (suite *MakeRequestSuite) TestMakeHTTPRequestWithRetry() {
 	assert.EqualValues(suite.T(), 3, calls)
 }
 
+func (suite *MakeRequestSuite) TestMakeHTTPRequestDoesNotRetryClientErrors() {
+	calls := 0
+	// Start a local HTTP server
+	suite.server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		rw.WriteHeader(404)
+	}))
+
+	res := MakeHTTPRequest(&HTTPRequest{URL: suite.server.URL, Retry: 3})
+
+	var statusErr ErrHTTPStatus
+	assert.ErrorAs(suite.T(), res.Error, &statusErr)
+	assert.Equal(suite.T(), 404, statusErr.StatusCode)
+	assert.EqualValues(suite.T(), 1, calls)
+}
+
+func (suite *MakeRequestSuite) TestMakeHTTPRequestRespectsRetryAfter() {
+	calls := 0
+	// Start a local HTTP server
+	suite.server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		if calls == 1 {
+			rw.Header().Set("Retry-After", "1")
+			rw.WriteHeader(429)
+		} else {
+			rw.Write([]byte(serverResponse))
+		}
+	}))
+
+	t := time.Now()
+	res := MakeHTTPRequestWithRetryPolicy(
+		&HTTPRequest{URL: suite.server.URL},
+		RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond},
+	)
+
+	assert.NoError(suite.T(), res.Error)
+	assert.EqualValues(suite.T(), []byte(serverResponse), res.Body)
+	assert.EqualValues(suite.T(), 2, calls)
+	assert.GreaterOrEqual(suite.T(), time.Since(t), time.Second)
+}
+
+func (suite *MakeRequestSuite) TestMakeHTTPRequestRetryAfterExceedsMaxDelay() {
+	calls := 0
+	// Start a local HTTP server
+	suite.server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		rw.Header().Set("Retry-After", "60")
+		rw.WriteHeader(429)
+	}))
+
+	res := MakeHTTPRequestWithRetryPolicy(
+		&HTTPRequest{URL: suite.server.URL},
+		RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Second},
+	)
+
+	assert.Error(suite.T(), res.Error)
+	assert.EqualValues(suite.T(), 1, calls)
+}
+
+func (suite *MakeRequestSuite) TestMakeHTTPRequestCanceled() {
+	calls := 0
+	// Start a local HTTP server
+	suite.server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		rw.WriteHeader(500)
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	res := MakeHTTPRequestWithRetryPolicy(
+		&HTTPRequest{URL: suite.server.URL, Context: ctx},
+		RetryPolicy{MaxAttempts: 10, BaseDelay: time.Minute},
+	)
+
+	assert.ErrorIs(suite.T(), res.Error, context.DeadlineExceeded)
+	assert.EqualValues(suite.T(), 1, calls)
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	assert.Equal(t, time.Second, p.delay(1))
+	assert.Equal(t, 2*time.Second, p.delay(2))
+	assert.Equal(t, 4*time.Second, p.delay(3))
+	assert.Equal(t, 5*time.Second, p.delay(4))
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := p.delay(1)
+		assert.GreaterOrEqual(t, d, 500*time.Millisecond)
+		assert.LessOrEqual(t, d, 1500*time.Millisecond)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, time.Duration(0), parseRetryAfter(""))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("invalid"))
+	assert.Equal(t, 5*time.Second, parseRetryAfter("5"))
+	d := parseRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	assert.Greater(t, d, 58*time.Second)
+	assert.LessOrEqual(t, d, time.Minute)
+}
+
 // In order for 'go test' to run this suite, we need to create
 // a normal test function and pass our suite to suite.Run
 func TestMakeRequestSuite(t *testing.T) {
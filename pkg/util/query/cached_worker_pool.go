@@ -0,0 +1,162 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package query
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CachedWorkerPool is a WorkerPool wrapper that caches successful responses
+// for a short time. Identical requests that are made simultaneously share
+// a single upstream call.
+//
+// Requests are identical if they have the same method, URL, headers and
+// body. Expired responses are evicted from the cache periodically, at most
+// once per TTL, while handling requests.
+type CachedWorkerPool struct {
+	mu        sync.Mutex
+	pool      WorkerPool
+	ttl       time.Duration
+	cache     map[string]cachedHTTPResponse
+	inFlight  map[string]*inFlightHTTPRequest
+	lastEvict time.Time
+}
+
+type cachedHTTPResponse struct {
+	response *HTTPResponse
+	expires  time.Time
+}
+
+type inFlightHTTPRequest struct {
+	wg       sync.WaitGroup
+	response *HTTPResponse
+}
+
+// NewCachedWorkerPool creates a new CachedWorkerPool instance. Responses are
+// cached for the duration defined by the ttl argument.
+func NewCachedWorkerPool(pool WorkerPool, ttl time.Duration) *CachedWorkerPool {
+	return &CachedWorkerPool{
+		pool:      pool,
+		ttl:       ttl,
+		cache:     make(map[string]cachedHTTPResponse),
+		inFlight:  make(map[string]*inFlightHTTPRequest),
+		lastEvict: time.Now(),
+	}
+}
+
+// Query implements the WorkerPool interface.
+func (c *CachedWorkerPool) Query(req *HTTPRequest) *HTTPResponse {
+	if req == nil {
+		return c.pool.Query(req)
+	}
+	key, req, err := cacheKey(req)
+	if err != nil {
+		return &HTTPResponse{Error: err}
+	}
+
+	c.mu.Lock()
+	c.evict()
+	if r, ok := c.cache[key]; ok {
+		if time.Now().Before(r.expires) {
+			c.mu.Unlock()
+			return r.response
+		}
+		delete(c.cache, key)
+	}
+	if f, ok := c.inFlight[key]; ok {
+		c.mu.Unlock()
+		f.wg.Wait()
+		return f.response
+	}
+	f := &inFlightHTTPRequest{}
+	f.wg.Add(1)
+	c.inFlight[key] = f
+	c.mu.Unlock()
+
+	f.response = c.pool.Query(req)
+
+	c.mu.Lock()
+	delete(c.inFlight, key)
+	if f.response != nil && f.response.Error == nil {
+		c.cache[key] = cachedHTTPResponse{
+			response: f.response,
+			expires:  time.Now().Add(c.ttl),
+		}
+	}
+	c.mu.Unlock()
+	f.wg.Done()
+
+	return f.response
+}
+
+// evict removes expired responses from the cache. To avoid iterating over
+// the cache on every request, it does nothing if the last eviction was
+// less than TTL ago. It must be called with the mutex locked.
+func (c *CachedWorkerPool) evict() {
+	now := time.Now()
+	if now.Sub(c.lastEvict) < c.ttl {
+		return
+	}
+	c.lastEvict = now
+	for k, r := range c.cache {
+		if !now.Before(r.expires) {
+			delete(c.cache, k)
+		}
+	}
+}
+
+// cacheKey returns the key that identifies the request in the cache. If
+// the request has a body, it is read to build the key, so the returned
+// request, which must be used instead of the given one, has a copy of it.
+func cacheKey(req *HTTPRequest) (string, *HTTPRequest, error) {
+	method := req.Method
+	if method == "" {
+		method = "GET"
+	}
+	headers := make([]string, 0, len(req.Headers))
+	for k, v := range req.Headers {
+		headers = append(headers, k+": "+v)
+	}
+	sort.Strings(headers)
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return "", nil, err
+		}
+		r := *req
+		r.Body = bytes.NewReader(body)
+		req = &r
+	}
+
+	b := &strings.Builder{}
+	b.WriteString(method)
+	b.WriteByte(' ')
+	b.WriteString(req.URL)
+	for _, h := range headers {
+		b.WriteByte('\n')
+		b.WriteString(h)
+	}
+	b.WriteString("\n\n")
+	b.Write(body)
+	return b.String(), req, nil
+}
@@ -0,0 +1,135 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package query
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingWorkerPool struct {
+	calls int32
+	delay time.Duration
+}
+
+func (p *countingWorkerPool) Query(req *HTTPRequest) *HTTPResponse {
+	atomic.AddInt32(&p.calls, 1)
+	time.Sleep(p.delay)
+	return &HTTPResponse{Body: []byte(req.URL)}
+}
+
+func TestCachedWorkerPool_SingleFlight(t *testing.T) {
+	const n = 10
+	upstream := &countingWorkerPool{delay: 50 * time.Millisecond}
+	wp := NewCachedWorkerPool(upstream, time.Second)
+
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			res := wp.Query(&HTTPRequest{URL: "http://example.com/ticker"})
+			assert.Equal(t, "http://example.com/ticker", string(res.Body))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&upstream.calls))
+}
+
+func TestCachedWorkerPool_TTL(t *testing.T) {
+	upstream := &countingWorkerPool{}
+	wp := NewCachedWorkerPool(upstream, 50*time.Millisecond)
+
+	wp.Query(&HTTPRequest{URL: "http://example.com/a"})
+	wp.Query(&HTTPRequest{URL: "http://example.com/a"})
+	assert.Equal(t, int32(1), atomic.LoadInt32(&upstream.calls))
+
+	// Different URL must not be served from the cache.
+	wp.Query(&HTTPRequest{URL: "http://example.com/b"})
+	assert.Equal(t, int32(2), atomic.LoadInt32(&upstream.calls))
+
+	// Expired entries must be fetched again.
+	time.Sleep(60 * time.Millisecond)
+	wp.Query(&HTTPRequest{URL: "http://example.com/a"})
+	assert.Equal(t, int32(3), atomic.LoadInt32(&upstream.calls))
+}
+
+func TestCachedWorkerPool_Key(t *testing.T) {
+	var bodies []string
+	mock := NewMockWorkerPool()
+	mock.MockResp(&HTTPResponse{})
+	mock.SetRequestAssertions(func(req *HTTPRequest) {
+		var b []byte
+		if req.Body != nil {
+			b, _ = io.ReadAll(req.Body)
+		}
+		bodies = append(bodies, string(b))
+	})
+	wp := NewCachedWorkerPool(mock, time.Second)
+
+	reqs := []*HTTPRequest{
+		{URL: "http://example.com/a"},
+		{URL: "http://example.com/a", Method: "GET"},
+		{URL: "http://example.com/a", Method: "POST"},
+		{URL: "http://example.com/a", Method: "POST", Body: strings.NewReader("a")},
+		{URL: "http://example.com/a", Method: "POST", Body: strings.NewReader("a")},
+		{URL: "http://example.com/a", Method: "POST", Body: strings.NewReader("b")},
+		{URL: "http://example.com/a", Headers: map[string]string{"X-Api-Key": "a"}},
+		{URL: "http://example.com/a", Headers: map[string]string{"X-Api-Key": "a"}},
+		{URL: "http://example.com/a", Headers: map[string]string{"X-Api-Key": "b"}},
+	}
+	for _, req := range reqs {
+		wp.Query(req)
+	}
+
+	// Only requests with the same method, URL, headers and body should
+	// be served from the cache. Bodies must be passed to the upstream pool
+	// unchanged.
+	assert.Equal(t, []string{"", "", "a", "b", "", ""}, bodies)
+}
+
+func TestCachedWorkerPool_Evict(t *testing.T) {
+	upstream := &countingWorkerPool{}
+	wp := NewCachedWorkerPool(upstream, 50*time.Millisecond)
+
+	wp.Query(&HTTPRequest{URL: "http://example.com/a"})
+	wp.Query(&HTTPRequest{URL: "http://example.com/b"})
+	assert.Len(t, wp.cache, 2)
+
+	// Expired responses should be evicted by any later request:
+	time.Sleep(60 * time.Millisecond)
+	wp.Query(&HTTPRequest{URL: "http://example.com/c"})
+	assert.Len(t, wp.cache, 1)
+}
+
+func TestCachedWorkerPool_ErrorsAreNotCached(t *testing.T) {
+	mock := NewMockWorkerPool()
+	mock.MockResp(&HTTPResponse{Error: assert.AnError})
+	calls := 0
+	mock.SetRequestAssertions(func(*HTTPRequest) { calls++ })
+	wp := NewCachedWorkerPool(mock, time.Second)
+
+	wp.Query(&HTTPRequest{URL: "http://example.com/a"})
+	wp.Query(&HTTPRequest{URL: "http://example.com/a"})
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,73 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package query
+
+import (
+	"context"
+)
+
+// ContextWorkerPool is a WorkerPool wrapper that binds all requests to the
+// given context. Once the context is canceled, pending requests are
+// interrupted and new ones fail immediately with the context error.
+type ContextWorkerPool struct {
+	pool WorkerPool
+	ctx  context.Context
+}
+
+// NewContextWorkerPool creates a new ContextWorkerPool instance.
+func NewContextWorkerPool(ctx context.Context, pool WorkerPool) *ContextWorkerPool {
+	return &ContextWorkerPool{
+		pool: pool,
+		ctx:  ctx,
+	}
+}
+
+// Query implements the WorkerPool interface.
+func (c *ContextWorkerPool) Query(req *HTTPRequest) *HTTPResponse {
+	if req == nil {
+		return c.pool.Query(req)
+	}
+	if err := c.ctx.Err(); err != nil {
+		return &HTTPResponse{Error: err}
+	}
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	defer cancel()
+	if req.Context != nil {
+		// Cancel the request if either of the contexts is done.
+		go func() {
+			select {
+			case <-req.Context.Done():
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	// The request is copied to avoid modifying the one given by the caller.
+	r := *req
+	r.Context = ctx
+
+	resCh := make(chan *HTTPResponse, 1)
+	go func() { resCh <- c.pool.Query(&r) }()
+
+	select {
+	case res := <-resCh:
+		return res
+	case <-ctx.Done():
+		return &HTTPResponse{Error: ctx.Err()}
+	}
+}
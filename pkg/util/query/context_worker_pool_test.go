@@ -0,0 +1,67 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package query
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextWorkerPool_Cancel(t *testing.T) {
+	done := make(chan struct{})
+	defer close(done)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wp := NewContextWorkerPool(ctx, NewHTTPWorkerPoolWithRetryPolicy(1, RetryPolicy{MaxAttempts: 1}))
+
+	// Cancel the context while the request is in progress:
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	res := wp.Query(&HTTPRequest{URL: srv.URL, Timeout: time.Minute})
+	require.NotNil(t, res)
+	assert.ErrorIs(t, res.Error, context.Canceled)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// Requests made after cancellation fail immediately:
+	res = wp.Query(&HTTPRequest{URL: srv.URL})
+	assert.ErrorIs(t, res.Error, context.Canceled)
+}
+
+func TestContextWorkerPool_Query(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	wp := NewContextWorkerPool(context.Background(), NewHTTPWorkerPool(1))
+	res := wp.Query(&HTTPRequest{URL: srv.URL})
+	require.NoError(t, res.Error)
+	assert.Equal(t, []byte("ok"), res.Body)
+}
@@ -0,0 +1,50 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package query
+
+// HeaderWorkerPool is a WorkerPool wrapper that adds static headers, like
+// API keys, to every request. Headers already set in a request take
+// precedence over the static ones.
+type HeaderWorkerPool struct {
+	pool    WorkerPool
+	headers map[string]string
+}
+
+// NewHeaderWorkerPool creates a new HeaderWorkerPool instance.
+func NewHeaderWorkerPool(pool WorkerPool, headers map[string]string) *HeaderWorkerPool {
+	return &HeaderWorkerPool{
+		pool:    pool,
+		headers: headers,
+	}
+}
+
+// Query implements the WorkerPool interface.
+func (h *HeaderWorkerPool) Query(req *HTTPRequest) *HTTPResponse {
+	if req == nil {
+		return h.pool.Query(req)
+	}
+
+	// The request is copied to avoid modifying the one given by the caller.
+	r := *req
+	r.Headers = make(map[string]string, len(h.headers)+len(req.Headers))
+	for k, v := range h.headers {
+		r.Headers[k] = v
+	}
+	for k, v := range req.Headers {
+		r.Headers[k] = v
+	}
+	return h.pool.Query(&r)
+}
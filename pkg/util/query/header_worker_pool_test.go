@@ -0,0 +1,47 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package query
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderWorkerPool(t *testing.T) {
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+	}))
+	defer srv.Close()
+
+	wp := NewHeaderWorkerPool(NewHTTPWorkerPool(1), map[string]string{
+		"X-MBX-APIKEY": "secret",
+		"Accept":       "text/plain",
+	})
+	req := &HTTPRequest{URL: srv.URL, Headers: map[string]string{"Accept": "application/json"}}
+	res := wp.Query(req)
+
+	require.NoError(t, res.Error)
+	assert.Equal(t, "secret", header.Get("X-MBX-APIKEY"))
+	assert.Equal(t, "application/json", header.Get("Accept"))
+
+	// The original request must not be modified:
+	assert.Equal(t, map[string]string{"Accept": "application/json"}, req.Headers)
+}
@@ -0,0 +1,105 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package query
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestHTTPWorkerPool_RetryTransientErrors(t *testing.T) {
+	const price = `{"price":"42.1"}`
+
+	calls := 0
+	httpTransport = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		switch calls {
+		case 1:
+			return nil, syscall.ECONNRESET
+		case 2:
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+			}, nil
+		default:
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(price)),
+			}, nil
+		}
+	})
+	defer func() { httpTransport = nil }()
+
+	wp := NewHTTPWorkerPoolWithRetryPolicy(1, RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		Jitter:      0.5,
+	})
+	res := wp.Query(&HTTPRequest{URL: "http://example.com/ticker"})
+
+	assert.NoError(t, res.Error)
+	assert.Equal(t, price, string(res.Body))
+	assert.Equal(t, 3, calls)
+}
+
+func TestHTTPWorkerPool_Proxy(t *testing.T) {
+	const price = `{"price":"42.1"}`
+
+	var proxied *http.Request
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r
+		_, _ = w.Write([]byte(price))
+	}))
+	defer proxy.Close()
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	proxyURL.User = url.UserPassword("user", "pass")
+
+	wp, err := NewHTTPWorkerPoolWithProxy(1, proxyURL.String())
+	require.NoError(t, err)
+	res := wp.Query(&HTTPRequest{URL: "http://example.com/ticker"})
+
+	require.NoError(t, res.Error)
+	require.NotNil(t, proxied)
+	assert.Equal(t, price, string(res.Body))
+	assert.Equal(t, "example.com", proxied.Host)
+	assert.Equal(t, "http://example.com/ticker", proxied.RequestURI)
+	assert.Equal(t, "Basic dXNlcjpwYXNz", proxied.Header.Get("Proxy-Authorization"))
+}
+
+func TestHTTPWorkerPool_InvalidProxy(t *testing.T) {
+	for _, proxyURL := range []string{"ftp://127.0.0.1:21", "socks5://", "://"} {
+		t.Run(proxyURL, func(t *testing.T) {
+			_, err := NewHTTPWorkerPoolWithProxy(1, proxyURL)
+			assert.ErrorAs(t, err, &ErrInvalidProxy{})
+		})
+	}
+}
@@ -0,0 +1,89 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package query
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ErrTimeout is returned when a request was not completed before the timeout
+// configured in the TimeoutWorkerPool.
+type ErrTimeout struct {
+	URL     string
+	Timeout time.Duration
+}
+
+func (e ErrTimeout) Error() string {
+	return fmt.Sprintf("request to %s was not completed within %s", e.URL, e.Timeout)
+}
+
+// Unwrap returns context.DeadlineExceeded, so it is possible to check for
+// the timeout using errors.Is.
+func (e ErrTimeout) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// TimeoutWorkerPool is a WorkerPool wrapper that limits the total time of
+// a request, including the time spent waiting for a free worker and all
+// retries. When the timeout is reached, the ErrTimeout error is returned
+// immediately, without waiting for the underlying worker pool.
+type TimeoutWorkerPool struct {
+	pool    WorkerPool
+	timeout time.Duration
+}
+
+// NewTimeoutWorkerPool creates a new TimeoutWorkerPool instance.
+func NewTimeoutWorkerPool(pool WorkerPool, timeout time.Duration) *TimeoutWorkerPool {
+	return &TimeoutWorkerPool{
+		pool:    pool,
+		timeout: timeout,
+	}
+}
+
+// Query implements the WorkerPool interface.
+func (t *TimeoutWorkerPool) Query(req *HTTPRequest) *HTTPResponse {
+	if req == nil {
+		return t.pool.Query(req)
+	}
+	parent := req.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, t.timeout)
+	defer cancel()
+
+	// The request is copied to avoid modifying the one given by the caller.
+	r := *req
+	r.Context = ctx
+
+	resCh := make(chan *HTTPResponse, 1)
+	go func() { resCh <- t.pool.Query(&r) }()
+
+	select {
+	case res := <-resCh:
+		if res != nil && res.Error != nil && ctx.Err() == context.DeadlineExceeded {
+			return &HTTPResponse{Error: ErrTimeout{URL: req.URL, Timeout: t.timeout}}
+		}
+		return res
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return &HTTPResponse{Error: ErrTimeout{URL: req.URL, Timeout: t.timeout}}
+		}
+		return &HTTPResponse{Error: ctx.Err()}
+	}
+}
@@ -0,0 +1,72 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package query
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeoutWorkerPool_Timeout(t *testing.T) {
+	// The underlying pool ignores the context to make sure that the timeout
+	// does not depend on it:
+	upstream := &countingWorkerPool{delay: time.Second}
+	wp := NewTimeoutWorkerPool(upstream, 50*time.Millisecond)
+
+	start := time.Now()
+	res := wp.Query(&HTTPRequest{URL: "http://example.com/ticker"})
+
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, ErrTimeout{URL: "http://example.com/ticker", Timeout: 50 * time.Millisecond}, res.Error)
+	assert.True(t, errors.Is(res.Error, context.DeadlineExceeded))
+}
+
+func TestTimeoutWorkerPool_HTTPTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	wp := NewTimeoutWorkerPool(NewHTTPWorkerPool(1), 50*time.Millisecond)
+
+	start := time.Now()
+	res := wp.Query(&HTTPRequest{URL: srv.URL})
+
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.True(t, errors.As(res.Error, &ErrTimeout{}))
+}
+
+func TestTimeoutWorkerPool_NoTimeout(t *testing.T) {
+	upstream := &countingWorkerPool{delay: 10 * time.Millisecond}
+	wp := NewTimeoutWorkerPool(upstream, time.Second)
+
+	req := &HTTPRequest{URL: "http://example.com/ticker"}
+	res := wp.Query(req)
+
+	assert.NoError(t, res.Error)
+	assert.Equal(t, "http://example.com/ticker", string(res.Body))
+	// The request given by the caller must not be modified:
+	assert.Nil(t, req.Context)
+}
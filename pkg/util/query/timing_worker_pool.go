@@ -0,0 +1,58 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package query
+
+import (
+	"sync"
+	"time"
+)
+
+// TimingWorkerPool is a WorkerPool wrapper that measures the wall time of
+// every request, including the time spent waiting for a free worker and
+// all retries.
+type TimingWorkerPool struct {
+	mu      sync.Mutex
+	pool    WorkerPool
+	timings []time.Duration
+	now     func() time.Time
+}
+
+// NewTimingWorkerPool creates a new TimingWorkerPool instance.
+func NewTimingWorkerPool(pool WorkerPool) *TimingWorkerPool {
+	return &TimingWorkerPool{
+		pool: pool,
+		now:  time.Now,
+	}
+}
+
+// Query implements the WorkerPool interface.
+func (t *TimingWorkerPool) Query(req *HTTPRequest) *HTTPResponse {
+	start := t.now()
+	res := t.pool.Query(req)
+	d := t.now().Sub(start)
+	t.mu.Lock()
+	t.timings = append(t.timings, d)
+	t.mu.Unlock()
+	return res
+}
+
+// Timings returns the wall time of all completed requests in the order in
+// which they were completed.
+func (t *TimingWorkerPool) Timings() []time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]time.Duration(nil), t.timings...)
+}
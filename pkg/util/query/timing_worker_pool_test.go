@@ -0,0 +1,41 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimingWorkerPool(t *testing.T) {
+	mock := NewMockWorkerPool()
+	mock.MockBody("ok")
+	wp := NewTimingWorkerPool(mock)
+
+	// The fake clock advances by 5 seconds on every call:
+	now := time.Unix(0, 0)
+	wp.now = func() time.Time {
+		now = now.Add(5 * time.Second)
+		return now
+	}
+
+	res := wp.Query(&HTTPRequest{URL: "http://example.com/a"})
+	assert.Equal(t, []byte("ok"), res.Body)
+	wp.Query(&HTTPRequest{URL: "http://example.com/b"})
+	assert.Equal(t, []time.Duration{5 * time.Second, 5 * time.Second}, wp.Timings())
+}